@@ -0,0 +1,319 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/cache"
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/layer"
+	"github.com/gorilla/mux"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type (
+	// Layer is a part of layer.Client used by admin API.
+	Layer interface {
+		FlushCache(name string) error
+		GetBucketInfo(ctx context.Context, name string) (*data.BucketInfo, error)
+		ListMultipartUploads(ctx context.Context, p *layer.ListMultipartUploadsParams) (*layer.ListMultipartUploadsInfo, error)
+		GetBucketUsage(ctx context.Context, bktInfo *data.BucketInfo) (*data.BucketUsage, error)
+		ReconcileBucketUsage(ctx context.Context, bktInfo *data.BucketInfo) (*data.BucketUsage, error)
+	}
+
+	// Gateway provides gateway operations available via admin API.
+	Gateway interface {
+		// Settings returns effective configuration.
+		Settings() map[string]interface{}
+		ReloadResolvers() error
+		ReloadTLS() error
+		// CacheStats returns statistics of caches with topKeys biggest entries of every cache.
+		CacheStats(topKeys int) []cache.Stats
+	}
+
+	// Config contains dependencies of admin API.
+	Config struct {
+		// Key is a public key of the gateway, admin tokens must be signed by the corresponding private key.
+		Key      *keys.PublicKey
+		Layer    Layer
+		Gateway  Gateway
+		LogLevel zap.AtomicLevel
+	}
+
+	handler struct {
+		log *zap.Logger
+		cfg *Config
+	}
+
+	errorResponse struct {
+		Error string `json:"error"`
+	}
+
+	statusResponse struct {
+		Status string `json:"status"`
+	}
+
+	logLevel struct {
+		Level string `json:"level"`
+	}
+
+	upload struct {
+		Key      string    `json:"key"`
+		UploadID string    `json:"upload_id"`
+		Owner    string    `json:"owner"`
+		Created  time.Time `json:"created"`
+	}
+
+	bucketUsage struct {
+		Bucket string `json:"bucket"`
+		data.BucketUsage
+	}
+)
+
+const (
+	bearerPrefix = "Bearer "
+	redacted     = "<redacted>"
+	statusOK     = "ok"
+
+	// defaultTopKeys is a default number of the biggest cache entries in cache statistics.
+	defaultTopKeys = 10
+
+	// maxUploads is big enough to list all active uploads of a bucket.
+	maxUploads = math.MaxInt32
+)
+
+// sensitiveSettings contains parts of setting names which values mustn't be dumped.
+var sensitiveSettings = []string{"passphrase", "password", "secret"}
+
+// NewHandler creates admin API handler. Requests must be made either with
+// verified TLS client certificate or with admin token signed by the gateway key.
+func NewHandler(log *zap.Logger, cfg *Config) http.Handler {
+	h := &handler{log: log, cfg: cfg}
+
+	r := mux.NewRouter()
+	r.Use(h.authMiddleware)
+
+	r.Methods(http.MethodGet).Path("/caches").HandlerFunc(h.cacheStats)
+	r.Methods(http.MethodPost).Path("/caches/{name}/flush").HandlerFunc(h.flushCache)
+	r.Methods(http.MethodGet).Path("/config").HandlerFunc(h.dumpConfig)
+	r.Methods(http.MethodGet).Path("/buckets/{bucket}/multipart").HandlerFunc(h.listMultipartUploads)
+	r.Methods(http.MethodGet).Path("/buckets/{bucket}/usage").HandlerFunc(h.getBucketUsage)
+	r.Methods(http.MethodPost).Path("/buckets/{bucket}/usage/reconcile").HandlerFunc(h.reconcileBucketUsage)
+	r.Methods(http.MethodPost).Path("/resolvers/reload").HandlerFunc(h.reloadResolvers)
+	r.Methods(http.MethodPost).Path("/tls/reload").HandlerFunc(h.reloadTLS)
+	r.Methods(http.MethodGet).Path("/log/level").HandlerFunc(h.getLogLevel)
+	r.Methods(http.MethodPut).Path("/log/level").HandlerFunc(h.setLogLevel)
+
+	return r
+}
+
+func (h *handler) authMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		authHeader := r.Header.Get("Authorization")
+		if !strings.HasPrefix(authHeader, bearerPrefix) {
+			h.writeError(w, http.StatusUnauthorized, ErrInvalidToken)
+			return
+		}
+
+		if err := CheckToken(strings.TrimPrefix(authHeader, bearerPrefix), h.cfg.Key, time.Now()); err != nil {
+			h.writeError(w, http.StatusUnauthorized, err)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (h *handler) flushCache(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+
+	names := []string{name}
+	if name == "all" {
+		names = layer.CachesNames
+	}
+
+	for _, cacheName := range names {
+		if err := h.cfg.Layer.FlushCache(cacheName); err != nil {
+			h.writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	h.log.Info("caches are flushed", zap.Strings("caches", names))
+	h.writeJSON(w, statusResponse{Status: statusOK})
+}
+
+func (h *handler) cacheStats(w http.ResponseWriter, r *http.Request) {
+	topKeys := defaultTopKeys
+	if top := r.URL.Query().Get("top"); top != "" {
+		var err error
+		if topKeys, err = strconv.Atoi(top); err != nil || topKeys < 0 {
+			h.writeError(w, http.StatusBadRequest, fmt.Errorf("invalid top '%s'", top))
+			return
+		}
+	}
+
+	h.writeJSON(w, h.cfg.Gateway.CacheStats(topKeys))
+}
+
+func (h *handler) dumpConfig(w http.ResponseWriter, _ *http.Request) {
+	h.writeJSON(w, redactSettings(h.cfg.Gateway.Settings()))
+}
+
+func (h *handler) listMultipartUploads(w http.ResponseWriter, r *http.Request) {
+	bktInfo, err := h.cfg.Layer.GetBucketInfo(r.Context(), mux.Vars(r)["bucket"])
+	if err != nil {
+		h.writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	uploadsInfo, err := h.cfg.Layer.ListMultipartUploads(r.Context(), &layer.ListMultipartUploadsParams{
+		Bkt:        bktInfo,
+		Prefix:     r.URL.Query().Get("prefix"),
+		MaxUploads: maxUploads,
+	})
+	if err != nil {
+		h.writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	uploads := make([]upload, len(uploadsInfo.Uploads))
+	for i, info := range uploadsInfo.Uploads {
+		uploads[i] = upload{
+			Key:      info.Key,
+			UploadID: info.UploadID,
+			Owner:    info.Owner.EncodeToString(),
+			Created:  info.Created,
+		}
+	}
+
+	h.writeJSON(w, uploads)
+}
+
+func (h *handler) getBucketUsage(w http.ResponseWriter, r *http.Request) {
+	h.bucketUsage(w, r, h.cfg.Layer.GetBucketUsage)
+}
+
+func (h *handler) reconcileBucketUsage(w http.ResponseWriter, r *http.Request) {
+	h.bucketUsage(w, r, h.cfg.Layer.ReconcileBucketUsage)
+}
+
+func (h *handler) bucketUsage(w http.ResponseWriter, r *http.Request,
+	usageFn func(context.Context, *data.BucketInfo) (*data.BucketUsage, error)) {
+	bktInfo, err := h.cfg.Layer.GetBucketInfo(r.Context(), mux.Vars(r)["bucket"])
+	if err != nil {
+		h.writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	usage, err := usageFn(r.Context(), bktInfo)
+	if err != nil {
+		h.writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	h.writeJSON(w, bucketUsage{Bucket: bktInfo.Name, BucketUsage: *usage})
+}
+
+func (h *handler) reloadResolvers(w http.ResponseWriter, _ *http.Request) {
+	if err := h.cfg.Gateway.ReloadResolvers(); err != nil {
+		h.writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	h.log.Info("resolvers are reloaded")
+	h.writeJSON(w, statusResponse{Status: statusOK})
+}
+
+func (h *handler) reloadTLS(w http.ResponseWriter, _ *http.Request) {
+	if err := h.cfg.Gateway.ReloadTLS(); err != nil {
+		h.writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	h.log.Info("tls certificates are reloaded")
+	h.writeJSON(w, statusResponse{Status: statusOK})
+}
+
+func (h *handler) getLogLevel(w http.ResponseWriter, _ *http.Request) {
+	h.writeJSON(w, logLevel{Level: h.cfg.LogLevel.Level().String()})
+}
+
+func (h *handler) setLogLevel(w http.ResponseWriter, r *http.Request) {
+	var req logLevel
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var lvl zapcore.Level
+	if err := lvl.UnmarshalText([]byte(req.Level)); err != nil {
+		h.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	h.cfg.LogLevel.SetLevel(lvl)
+	h.log.Info("log level is changed", zap.Stringer("level", lvl))
+	h.writeJSON(w, logLevel{Level: lvl.String()})
+}
+
+func (h *handler) writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		h.log.Error("could not write admin response", zap.Error(err))
+	}
+}
+
+func (h *handler) writeError(w http.ResponseWriter, status int, err error) {
+	h.log.Error("admin request failed", zap.Int("status", status), zap.Error(err))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err = json.NewEncoder(w).Encode(errorResponse{Error: err.Error()}); err != nil {
+		h.log.Error("could not write admin response", zap.Error(err))
+	}
+}
+
+// redactSettings replaces values of sensitive settings.
+func redactSettings(settings map[string]interface{}) map[string]interface{} {
+	res := make(map[string]interface{}, len(settings))
+	for key, value := range settings {
+		res[key] = redactValue(key, value)
+	}
+	return res
+}
+
+func redactValue(key string, value interface{}) interface{} {
+	lowerKey := strings.ToLower(key)
+	for _, sensitive := range sensitiveSettings {
+		if strings.Contains(lowerKey, sensitive) {
+			return redacted
+		}
+	}
+
+	switch val := value.(type) {
+	case map[string]interface{}:
+		return redactSettings(val)
+	case []interface{}:
+		res := make([]interface{}, len(val))
+		for i := range val {
+			res[i] = redactValue("", val[i])
+		}
+		return res
+	default:
+		return value
+	}
+}
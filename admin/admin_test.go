@@ -0,0 +1,225 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/cache"
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/layer"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type layerMock struct {
+	flushed    []string
+	uploads    []*layer.UploadInfo
+	usage      data.BucketUsage
+	reconciled bool
+}
+
+func (l *layerMock) FlushCache(name string) error {
+	if name == "unknown" {
+		return errors.New("unknown cache")
+	}
+	l.flushed = append(l.flushed, name)
+	return nil
+}
+
+func (l *layerMock) GetBucketInfo(_ context.Context, name string) (*data.BucketInfo, error) {
+	return &data.BucketInfo{Name: name}, nil
+}
+
+func (l *layerMock) ListMultipartUploads(context.Context, *layer.ListMultipartUploadsParams) (*layer.ListMultipartUploadsInfo, error) {
+	return &layer.ListMultipartUploadsInfo{Uploads: l.uploads}, nil
+}
+
+func (l *layerMock) GetBucketUsage(context.Context, *data.BucketInfo) (*data.BucketUsage, error) {
+	usage := l.usage
+	return &usage, nil
+}
+
+func (l *layerMock) ReconcileBucketUsage(context.Context, *data.BucketInfo) (*data.BucketUsage, error) {
+	l.reconciled = true
+	usage := l.usage
+	return &usage, nil
+}
+
+type gatewayMock struct {
+	resolversReloaded bool
+	topKeys           int
+}
+
+func (g *gatewayMock) Settings() map[string]interface{} {
+	return map[string]interface{}{
+		"wallet": map[string]interface{}{
+			"path":       "/path/to/wallet",
+			"passphrase": "pwd",
+		},
+		"listen_domains": []interface{}{"s3.example"},
+	}
+}
+
+func (g *gatewayMock) ReloadResolvers() error {
+	g.resolversReloaded = true
+	return nil
+}
+
+func (g *gatewayMock) ReloadTLS() error {
+	return errors.New("tls disabled")
+}
+
+func (g *gatewayMock) CacheStats(topKeys int) []cache.Stats {
+	g.topKeys = topKeys
+	return []cache.Stats{{Name: "objects", Entries: 1, Hits: 2}}
+}
+
+func TestAdminAPI(t *testing.T) {
+	key, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+
+	lm := &layerMock{
+		uploads: []*layer.UploadInfo{{Key: "obj", UploadID: "id"}},
+		usage:   data.BucketUsage{Bytes: 10, Objects: 1, Versions: 2},
+	}
+	gm := &gatewayMock{}
+	lvl := zap.NewAtomicLevelAt(zap.InfoLevel)
+
+	h := NewHandler(zap.NewNop(), &Config{
+		Key:      key.PublicKey(),
+		Layer:    lm,
+		Gateway:  gm,
+		LogLevel: lvl,
+	})
+
+	token, err := NewToken(key, time.Minute)
+	require.NoError(t, err)
+
+	do := func(method, path, body, token string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(method, path, strings.NewReader(body))
+		if token != "" {
+			r.Header.Set("Authorization", bearerPrefix+token)
+		}
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	t.Run("unauthorized", func(t *testing.T) {
+		w := do(http.MethodGet, "/config", "", "")
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+
+		anotherKey, err := keys.NewPrivateKey()
+		require.NoError(t, err)
+		anotherToken, err := NewToken(anotherKey, time.Minute)
+		require.NoError(t, err)
+
+		w = do(http.MethodGet, "/config", "", anotherToken)
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("flush cache", func(t *testing.T) {
+		w := do(http.MethodPost, "/caches/objects/flush", "", token)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, []string{"objects"}, lm.flushed)
+
+		w = do(http.MethodPost, "/caches/unknown/flush", "", token)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+
+		lm.flushed = nil
+		w = do(http.MethodPost, "/caches/all/flush", "", token)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, layer.CachesNames, lm.flushed)
+	})
+
+	t.Run("cache stats", func(t *testing.T) {
+		w := do(http.MethodGet, "/caches", "", token)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, defaultTopKeys, gm.topKeys)
+
+		var stats []cache.Stats
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&stats))
+		require.Equal(t, []cache.Stats{{Name: "objects", Entries: 1, Hits: 2}}, stats)
+
+		w = do(http.MethodGet, "/caches?top=3", "", token)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, 3, gm.topKeys)
+
+		w = do(http.MethodGet, "/caches?top=-1", "", token)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("dump config", func(t *testing.T) {
+		w := do(http.MethodGet, "/config", "", token)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var settings struct {
+			Wallet map[string]string `json:"wallet"`
+		}
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&settings))
+		require.Equal(t, redacted, settings.Wallet["passphrase"])
+		require.Equal(t, "/path/to/wallet", settings.Wallet["path"])
+	})
+
+	t.Run("multipart uploads", func(t *testing.T) {
+		w := do(http.MethodGet, "/buckets/bucket/multipart", "", token)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var uploads []upload
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&uploads))
+		require.Len(t, uploads, 1)
+		require.Equal(t, "id", uploads[0].UploadID)
+	})
+
+	t.Run("bucket usage", func(t *testing.T) {
+		w := do(http.MethodGet, "/buckets/bucket/usage", "", token)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var usage bucketUsage
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&usage))
+		require.Equal(t, bucketUsage{Bucket: "bucket", BucketUsage: lm.usage}, usage)
+		require.False(t, lm.reconciled)
+
+		w = do(http.MethodPost, "/buckets/bucket/usage/reconcile", "", token)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.True(t, lm.reconciled)
+	})
+
+	t.Run("reload", func(t *testing.T) {
+		w := do(http.MethodPost, "/resolvers/reload", "", token)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.True(t, gm.resolversReloaded)
+
+		w = do(http.MethodPost, "/tls/reload", "", token)
+		require.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	t.Run("log level", func(t *testing.T) {
+		w := do(http.MethodPut, "/log/level", `{"level":"debug"}`, token)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, zap.DebugLevel, lvl.Level())
+
+		w = do(http.MethodPut, "/log/level", `{"level":"unknown"}`, token)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestToken(t *testing.T) {
+	key, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+
+	token, err := NewToken(key, time.Minute)
+	require.NoError(t, err)
+
+	require.NoError(t, CheckToken(token, key.PublicKey(), time.Now()))
+	require.ErrorIs(t, CheckToken(token, key.PublicKey(), time.Now().Add(time.Hour)), ErrExpiredToken)
+	require.ErrorIs(t, CheckToken(token+"a", key.PublicKey(), time.Now()), ErrInvalidToken)
+	require.ErrorIs(t, CheckToken("token", key.PublicKey(), time.Now()), ErrInvalidToken)
+}
@@ -0,0 +1,73 @@
+package admin
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+)
+
+type tokenPayload struct {
+	IssuedAt   int64 `json:"iat"`
+	Expiration int64 `json:"exp"`
+}
+
+var (
+	// ErrInvalidToken is returned when admin token is malformed or signed by an unknown key.
+	ErrInvalidToken = errors.New("invalid admin token")
+	// ErrExpiredToken is returned when admin token lifetime is over.
+	ErrExpiredToken = errors.New("admin token is expired")
+)
+
+// NewToken creates admin token signed by the gateway key.
+func NewToken(key *keys.PrivateKey, lifetime time.Duration) (string, error) {
+	now := time.Now()
+	payload, err := json.Marshal(tokenPayload{
+		IssuedAt:   now.Unix(),
+		Expiration: now.Add(lifetime).Unix(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("marshal token: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(payload) + "." +
+		base64.RawURLEncoding.EncodeToString(key.Sign(payload)), nil
+}
+
+// CheckToken checks that admin token is signed by the key and isn't expired at the moment now.
+func CheckToken(token string, key *keys.PublicKey, now time.Time) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return ErrInvalidToken
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return ErrInvalidToken
+	}
+
+	hash := sha256.Sum256(payload)
+	if !key.Verify(signature, hash[:]) {
+		return ErrInvalidToken
+	}
+
+	var tkn tokenPayload
+	if err = json.Unmarshal(payload, &tkn); err != nil {
+		return ErrInvalidToken
+	}
+
+	if now.Unix() > tkn.Expiration {
+		return ErrExpiredToken
+	}
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"bytes"
 	"context"
 	"crypto/hmac"
 	"crypto/sha256"
@@ -38,8 +39,14 @@ type (
 
 	// Box contains access box and additional info.
 	Box struct {
-		AccessBox  *accessbox.Box
-		ClientTime time.Time
+		AccessBox   *accessbox.Box
+		AccessKeyID string
+		ClientTime  time.Time
+		// SessionClaims is set for temporary credentials.
+		SessionClaims *tokens.SessionClaims
+		// UploadClaims is set for requests authenticated by the upload token,
+		// the request must be checked to be in the scope of the claims.
+		UploadClaims *tokens.UploadClaims
 	}
 
 	center struct {
@@ -67,6 +74,11 @@ const (
 	accessKeyPartsNum  = 2
 	authHeaderPartsNum = 6
 	maxFormSizeMemory  = 50 * 1048576 // 50 MB
+	maxSTSBodySize     = 1048576      // 1 MB
+
+	// stsService is a name of the service in credentials scope of STS requests.
+	// Payload of such requests is signed, so it must be hashed to check the signature.
+	stsService = "sts"
 
 	AmzAlgorithm     = "X-Amz-Algorithm"
 	AmzCredential    = "X-Amz-Credential"
@@ -74,6 +86,8 @@ const (
 	AmzSignedHeaders = "X-Amz-SignedHeaders"
 	AmzExpires       = "X-Amz-Expires"
 	AmzDate          = "X-Amz-Date"
+	AmzSecurityToken = "X-Amz-Security-Token"
+	TusUploadToken   = "tus-token"
 	AuthorizationHdr = "Authorization"
 	ContentTypeHdr   = "Content-Type"
 )
@@ -141,6 +155,10 @@ func (c *center) Authenticate(r *http.Request) (*Box, error) {
 	)
 
 	queryValues := r.URL.Query()
+	if token := queryValues.Get(TusUploadToken); token != "" {
+		return c.checkUploadToken(r, token)
+	}
+
 	if queryValues.Get(AmzAlgorithm) == "AWS4-HMAC-SHA256" {
 		creds := strings.Split(queryValues.Get(AmzCredential), "/")
 		if len(creds) != 5 || creds[4] != "aws4_request" {
@@ -195,12 +213,28 @@ func (c *center) Authenticate(r *http.Request) (*Box, error) {
 		return nil, fmt.Errorf("get box: %w", err)
 	}
 
+	securityToken := r.Header.Get(AmzSecurityToken)
+	if authHdr.IsPresigned {
+		securityToken = queryValues.Get(AmzSecurityToken)
+	}
+	secret, claims, err := getSecret(securityToken, box)
+	if err != nil {
+		return nil, err
+	}
+
+	var body io.ReadSeeker
+	if authHdr.Service == stsService && !authHdr.IsPresigned {
+		if body, err = readRequestBody(r); err != nil {
+			return nil, err
+		}
+	}
+
 	clonedRequest := cloneRequest(r, authHdr)
-	if err = c.checkSign(authHdr, box, clonedRequest, signatureDateTime); err != nil {
+	if err = c.checkSign(authHdr, secret, clonedRequest, body, signatureDateTime); err != nil {
 		return nil, err
 	}
 
-	result := &Box{AccessBox: box}
+	result := &Box{AccessBox: box, AccessKeyID: authHdr.AccessKeyID, SessionClaims: claims}
 	if needClientTime {
 		result.ClientTime = signatureDateTime
 	}
@@ -256,7 +290,10 @@ func (c *center) checkFormData(r *http.Request) (*Box, error) {
 		return nil, fmt.Errorf("get box: %w", err)
 	}
 
-	secret := box.Gate.AccessKey
+	secret, claims, err := getSecret(MultipartFormValue(r, "x-amz-security-token"), box)
+	if err != nil {
+		return nil, err
+	}
 	service, region := submatches["service"], submatches["region"]
 
 	signature := signStr(secret, service, region, signatureDateTime, policy)
@@ -264,7 +301,82 @@ func (c *center) checkFormData(r *http.Request) (*Box, error) {
 		return nil, apiErrors.GetAPIError(apiErrors.ErrSignatureDoesNotMatch)
 	}
 
-	return &Box{AccessBox: box}, nil
+	return &Box{AccessBox: box, AccessKeyID: submatches["access_key_id"], SessionClaims: claims}, nil
+}
+
+// checkUploadToken authenticates the request to the resumable upload by the upload token
+// issued on the upload creation instead of the request signature.
+func (c *center) checkUploadToken(r *http.Request, token string) (*Box, error) {
+	claims, err := tokens.ParseUploadToken(token, time.Now())
+	if errors.Is(err, tokens.ErrExpiredUploadToken) {
+		return nil, apiErrors.GetAPIError(apiErrors.ErrExpiredToken)
+	} else if err != nil {
+		return nil, apiErrors.GetAPIError(apiErrors.ErrInvalidToken)
+	}
+
+	if err = c.checkAccessKeyID(claims.AccessKeyID); err != nil {
+		return nil, err
+	}
+
+	authHdr := &authHeader{AccessKeyID: claims.AccessKeyID}
+	addr, err := authHdr.getAddress()
+	if err != nil {
+		return nil, err
+	}
+
+	box, err := c.cli.GetBox(r.Context(), addr)
+	if err != nil {
+		return nil, fmt.Errorf("get box: %w", err)
+	}
+
+	if err = tokens.CheckUploadToken(box.Gate.AccessKey, token); err != nil {
+		return nil, apiErrors.GetAPIError(apiErrors.ErrInvalidToken)
+	}
+
+	return &Box{AccessBox: box, AccessKeyID: claims.AccessKeyID, SessionClaims: claims.Session, UploadClaims: claims}, nil
+}
+
+// getSecret returns the secret access key to check the request signature.
+// Temporary credentials must be used with the session token which the secret is derived from,
+// the secret of their access box isn't known to the client.
+func getSecret(token string, box *accessbox.Box) (string, *tokens.SessionClaims, error) {
+	if !box.Temporary {
+		if token != "" {
+			return "", nil, apiErrors.GetAPIError(apiErrors.ErrInvalidToken)
+		}
+		return box.Gate.AccessKey, nil, nil
+	}
+	if token == "" {
+		return "", nil, apiErrors.GetAPIError(apiErrors.ErrMissingSecurityHeader)
+	}
+
+	claims, err := tokens.ParseSessionToken(token, time.Now())
+	if errors.Is(err, tokens.ErrExpiredSessionToken) {
+		return "", nil, apiErrors.GetAPIError(apiErrors.ErrExpiredToken)
+	} else if err != nil {
+		return "", nil, apiErrors.GetAPIError(apiErrors.ErrInvalidToken)
+	}
+
+	return tokens.DeriveSessionSecret(box.Gate.AccessKey, token), claims, nil
+}
+
+// readRequestBody reads the request payload to check its signature
+// and restores the request body to be read by the handler.
+func readRequestBody(r *http.Request) (io.ReadSeeker, error) {
+	if r.Body == nil {
+		return nil, nil
+	}
+
+	payload, err := io.ReadAll(io.LimitReader(r.Body, maxSTSBodySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("read request body: %w", err)
+	}
+	if len(payload) > maxSTSBodySize {
+		return nil, apiErrors.GetAPIError(apiErrors.ErrEntityTooLarge)
+	}
+
+	r.Body = io.NopCloser(bytes.NewReader(payload))
+	return bytes.NewReader(payload), nil
 }
 
 func cloneRequest(r *http.Request, authHeader *authHeader) *http.Request {
@@ -288,8 +400,8 @@ func cloneRequest(r *http.Request, authHeader *authHeader) *http.Request {
 	return otherRequest
 }
 
-func (c *center) checkSign(authHeader *authHeader, box *accessbox.Box, request *http.Request, signatureDateTime time.Time) error {
-	awsCreds := credentials.NewStaticCredentials(authHeader.AccessKeyID, box.Gate.AccessKey, "")
+func (c *center) checkSign(authHeader *authHeader, secret string, request *http.Request, body io.ReadSeeker, signatureDateTime time.Time) error {
+	awsCreds := credentials.NewStaticCredentials(authHeader.AccessKeyID, secret, "")
 	signer := v4.NewSigner(awsCreds)
 
 	var signature string
@@ -307,7 +419,7 @@ func (c *center) checkSign(authHeader *authHeader, box *accessbox.Box, request *
 		signature = request.URL.Query().Get(AmzSignature)
 	} else {
 		signer.DisableURIPathEscaping = true
-		if _, err := signer.Sign(request, nil, authHeader.Service, authHeader.Region, signatureDateTime); err != nil {
+		if _, err := signer.Sign(request, body, authHeader.Service, authHeader.Region, signatureDateTime); err != nil {
 			return fmt.Errorf("failed to sign temporary HTTP request: %w", err)
 		}
 		signature = c.reg.GetSubmatches(request.Header.Get(AuthorizationHdr))["v4_signature"]
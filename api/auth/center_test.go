@@ -1,11 +1,22 @@
 package auth
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/errors"
+	"github.com/TrueCloudLab/frostfs-s3-gw/creds/accessbox"
+	"github.com/TrueCloudLab/frostfs-s3-gw/creds/tokens"
+	cid "github.com/TrueCloudLab/frostfs-sdk-go/container/id"
+	oid "github.com/TrueCloudLab/frostfs-sdk-go/object/id"
+	oidtest "github.com/TrueCloudLab/frostfs-sdk-go/object/id/test"
+	"github.com/TrueCloudLab/frostfs-sdk-go/user"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/stretchr/testify/require"
 )
 
@@ -99,3 +110,91 @@ func TestSignature(t *testing.T) {
 	signature := signStr(secret, "s3", "us-east-1", signTime, strToSign)
 	require.Equal(t, "dfbe886241d9e369cf4b329ca0f15eb27306c97aa1022cc0bb5a914c4ef87634", signature)
 }
+
+func TestGetSecret(t *testing.T) {
+	box := &accessbox.Box{Gate: &accessbox.GateData{AccessKey: "box-secret"}}
+
+	secret, claims, err := getSecret("", box)
+	require.NoError(t, err)
+	require.Equal(t, "box-secret", secret)
+	require.Nil(t, claims)
+
+	token, derived, err := tokens.NewSessionToken("box-secret", tokens.SessionClaims{Expiration: time.Now().Add(time.Hour).Unix()})
+	require.NoError(t, err)
+
+	_, _, err = getSecret(token, box)
+	require.Equal(t, errors.GetAPIError(errors.ErrInvalidToken), err)
+
+	box.Temporary = true
+	_, _, err = getSecret("", box)
+	require.Equal(t, errors.GetAPIError(errors.ErrMissingSecurityHeader), err)
+
+	secret, claims, err = getSecret(token, box)
+	require.NoError(t, err)
+	require.Equal(t, derived, secret)
+	require.NotNil(t, claims)
+
+	expired, _, err := tokens.NewSessionToken("box-secret", tokens.SessionClaims{Expiration: time.Now().Add(-time.Hour).Unix()})
+	require.NoError(t, err)
+	_, _, err = getSecret(expired, box)
+	require.Equal(t, errors.GetAPIError(errors.ErrExpiredToken), err)
+}
+
+type credentialsMock struct {
+	boxes map[string]*accessbox.Box
+}
+
+func (m credentialsMock) GetBox(_ context.Context, addr oid.Address) (*accessbox.Box, error) {
+	box, ok := m.boxes[addr.EncodeToString()]
+	if !ok {
+		return nil, fmt.Errorf("box not found")
+	}
+	return box, nil
+}
+
+func (m credentialsMock) Put(context.Context, cid.ID, user.ID, *accessbox.AccessBox, uint64, ...*keys.PublicKey) (oid.Address, error) {
+	return oid.Address{}, fmt.Errorf("not implemented")
+}
+
+func TestCheckUploadToken(t *testing.T) {
+	addr := oidtest.Address()
+	accessKeyID := strings.ReplaceAll(addr.EncodeToString(), "/", "0")
+	box := &accessbox.Box{Gate: &accessbox.GateData{AccessKey: "box-secret"}}
+	c := &center{cli: credentialsMock{boxes: map[string]*accessbox.Box{addr.EncodeToString(): box}}}
+
+	newRequest := func(token string) *http.Request {
+		return httptest.NewRequest(http.MethodPatch, "/bucket/object?tus=upload&"+TusUploadToken+"="+token, nil)
+	}
+
+	claims := tokens.UploadClaims{
+		AccessKeyID: accessKeyID,
+		Bucket:      "bucket",
+		Key:         "object",
+		UploadID:    "upload",
+		Expiration:  time.Now().Add(time.Hour).Unix(),
+		Session:     &tokens.SessionClaims{Expiration: time.Now().Add(time.Hour).Unix(), Subject: "subject"},
+	}
+	token, err := tokens.NewUploadToken("box-secret", claims)
+	require.NoError(t, err)
+
+	res, err := c.Authenticate(newRequest(token))
+	require.NoError(t, err)
+	require.Equal(t, box, res.AccessBox)
+	require.Equal(t, accessKeyID, res.AccessKeyID)
+	require.Equal(t, claims, *res.UploadClaims)
+	require.Equal(t, claims.Session, res.SessionClaims)
+
+	forged, err := tokens.NewUploadToken("another-secret", claims)
+	require.NoError(t, err)
+	_, err = c.Authenticate(newRequest(forged))
+	require.Equal(t, errors.GetAPIError(errors.ErrInvalidToken), err)
+
+	claims.Expiration = time.Now().Add(-time.Hour).Unix()
+	expired, err := tokens.NewUploadToken("box-secret", claims)
+	require.NoError(t, err)
+	_, err = c.Authenticate(newRequest(expired))
+	require.Equal(t, errors.GetAPIError(errors.ErrExpiredToken), err)
+
+	_, err = c.Authenticate(newRequest("invalid"))
+	require.Equal(t, errors.GetAPIError(errors.ErrInvalidToken), err)
+}
@@ -0,0 +1,283 @@
+package oidc
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	_ "crypto/sha256" // register SHA-256 for crypto.Hash
+	_ "crypto/sha512" // register SHA-384 and SHA-512 for crypto.Hash
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// Verifier validates OIDC JWTs using keys of trusted providers.
+	Verifier struct {
+		mu        sync.RWMutex
+		providers map[string]*provider
+	}
+
+	// ProviderConfig describes a trusted identity provider.
+	ProviderConfig struct {
+		// Issuer must be equal to 'iss' claim of tokens.
+		Issuer string
+		// Audience contains allowed values of 'aud' claim. Empty list means any audience.
+		Audience []string
+		// JWKSPath is a path to JWKS file or directory with JWKS files.
+		JWKSPath string
+	}
+
+	// Claims contains validated claims of a token.
+	Claims struct {
+		Issuer    string
+		Subject   string
+		Audience  []string
+		ExpiresAt time.Time
+		raw       map[string]interface{}
+	}
+
+	provider struct {
+		audience []string
+		keys     []*jwk
+	}
+
+	header struct {
+		Algorithm string `json:"alg"`
+		KeyID     string `json:"kid"`
+	}
+)
+
+// leeway is an allowed clock skew between the gateway and the identity provider.
+const leeway = time.Minute
+
+var (
+	// ErrInvalidToken is returned when a token is malformed or its signature is invalid.
+	ErrInvalidToken = errors.New("invalid token")
+	// ErrExpiredToken is returned when a token lifetime is over or hasn't started yet.
+	ErrExpiredToken = errors.New("token is expired")
+	// ErrUntrustedIssuer is returned when a token is issued by an unknown provider.
+	ErrUntrustedIssuer = errors.New("untrusted issuer")
+	// ErrInvalidAudience is returned when a token is issued for another audience.
+	ErrInvalidAudience = errors.New("invalid audience")
+)
+
+// NewVerifier creates a verifier for tokens of the providers.
+func NewVerifier(providers []ProviderConfig) (*Verifier, error) {
+	v := &Verifier{}
+	if err := v.Update(providers); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Update reloads providers and their keys.
+func (v *Verifier) Update(configs []ProviderConfig) error {
+	providers := make(map[string]*provider, len(configs))
+	for _, cfg := range configs {
+		keys, err := loadJWKS(cfg.JWKSPath)
+		if err != nil {
+			return fmt.Errorf("load jwks of '%s': %w", cfg.Issuer, err)
+		}
+		providers[cfg.Issuer] = &provider{audience: cfg.Audience, keys: keys}
+	}
+
+	v.mu.Lock()
+	v.providers = providers
+	v.mu.Unlock()
+
+	return nil
+}
+
+// Verify checks token signature, issuer, audience and lifetime at the moment now.
+func (v *Verifier) Verify(token string, now time.Time) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidToken
+	}
+
+	var hdr header
+	if err := decodeSegment(parts[0], &hdr); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	var raw map[string]interface{}
+	if err := decodeSegment(parts[1], &raw); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	claims := &Claims{raw: raw}
+	claims.Issuer, _ = raw["iss"].(string)
+	claims.Subject, _ = raw["sub"].(string)
+	claims.Audience = claims.Values("aud")
+
+	v.mu.RLock()
+	prov, ok := v.providers[claims.Issuer]
+	v.mu.RUnlock()
+	if !ok {
+		return nil, ErrUntrustedIssuer
+	}
+
+	if err = prov.verifySignature(hdr, []byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return nil, err
+	}
+
+	exp, ok := numericClaim(raw, "exp")
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+	claims.ExpiresAt = time.Unix(exp, 0)
+	if now.After(claims.ExpiresAt.Add(leeway)) {
+		return nil, ErrExpiredToken
+	}
+	if nbf, ok := numericClaim(raw, "nbf"); ok && now.Add(leeway).Before(time.Unix(nbf, 0)) {
+		return nil, ErrExpiredToken
+	}
+
+	if !prov.allowsAudience(claims.Audience) {
+		return nil, ErrInvalidAudience
+	}
+
+	return claims, nil
+}
+
+// Values returns values of the claim. Array claims produce several values,
+// strings, numbers and booleans produce a single one.
+func (c *Claims) Values(name string) []string {
+	switch val := c.raw[name].(type) {
+	case string:
+		return []string{val}
+	case float64:
+		return []string{strconv.FormatFloat(val, 'f', -1, 64)}
+	case bool:
+		return []string{strconv.FormatBool(val)}
+	case []interface{}:
+		res := make([]string, 0, len(val))
+		for _, item := range val {
+			if str, ok := item.(string); ok {
+				res = append(res, str)
+			}
+		}
+		return res
+	default:
+		return nil
+	}
+}
+
+// Value returns the first value of the claim.
+func (c *Claims) Value(name string) (string, bool) {
+	values := c.Values(name)
+	if len(values) == 0 {
+		return "", false
+	}
+	return values[0], true
+}
+
+// Match checks that any value of the claim matches the pattern (see path.Match).
+func (c *Claims) Match(name, pattern string) bool {
+	for _, val := range c.Values(name) {
+		if ok, _ := path.Match(pattern, val); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *provider) allowsAudience(audience []string) bool {
+	if len(p.audience) == 0 {
+		return true
+	}
+
+	for _, allowed := range p.audience {
+		for _, aud := range audience {
+			if aud == allowed {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (p *provider) verifySignature(hdr header, data, signature []byte) error {
+	hashFunc, err := algorithmHash(hdr.Algorithm)
+	if err != nil {
+		return err
+	}
+
+	h := hashFunc.New()
+	h.Write(data)
+	digest := h.Sum(nil)
+
+	for _, key := range p.keys {
+		if hdr.KeyID != "" && key.KeyID != "" && hdr.KeyID != key.KeyID {
+			continue
+		}
+		if key.Algorithm != "" && key.Algorithm != hdr.Algorithm {
+			continue
+		}
+		if verify(hdr.Algorithm, key.publicKey, hashFunc, digest, signature) {
+			return nil
+		}
+	}
+
+	return ErrInvalidToken
+}
+
+func verify(alg string, key crypto.PublicKey, hashFunc crypto.Hash, digest, signature []byte) bool {
+	switch pub := key.(type) {
+	case *rsa.PublicKey:
+		if !strings.HasPrefix(alg, "RS") {
+			return false
+		}
+		return rsa.VerifyPKCS1v15(pub, hashFunc, digest, signature) == nil
+	case *ecdsa.PublicKey:
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if !strings.HasPrefix(alg, "ES") || len(signature) != 2*size {
+			return false
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		return ecdsa.Verify(pub, digest, r, s)
+	default:
+		return false
+	}
+}
+
+func algorithmHash(alg string) (crypto.Hash, error) {
+	switch alg {
+	case "RS256", "ES256":
+		return crypto.SHA256, nil
+	case "RS384", "ES384":
+		return crypto.SHA384, nil
+	case "RS512", "ES512":
+		return crypto.SHA512, nil
+	default:
+		return 0, fmt.Errorf("%w: unsupported algorithm '%s'", ErrInvalidToken, alg)
+	}
+}
+
+func numericClaim(raw map[string]interface{}, name string) (int64, bool) {
+	val, ok := raw[name].(float64)
+	return int64(val), ok
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,139 @@
+package oidc
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testIssuer = "https://issuer.example"
+
+func TestVerifier(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	writeJWKS(t, filepath.Join(dir, "rsa.json"), map[string]string{
+		"kty": "RSA", "kid": "rsa", "n": encodeBigInt(rsaKey.N), "e": encodeBigInt(big.NewInt(int64(rsaKey.E))),
+	})
+	writeJWKS(t, filepath.Join(dir, "ec.json"), map[string]string{
+		"kty": "EC", "kid": "ec", "crv": "P-256", "x": encodeBigInt(ecKey.X), "y": encodeBigInt(ecKey.Y),
+	})
+
+	verifier, err := NewVerifier([]ProviderConfig{{Issuer: testIssuer, Audience: []string{"s3"}, JWKSPath: dir}})
+	require.NoError(t, err)
+
+	now := time.Now()
+	validClaims := map[string]interface{}{
+		"iss":    testIssuer,
+		"sub":    "project:ci",
+		"aud":    []string{"s3", "other"},
+		"exp":    now.Add(time.Hour).Unix(),
+		"groups": []string{"dev", "ci"},
+		"bucket": "ci-",
+	}
+
+	t.Run("rsa", func(t *testing.T) {
+		claims, err := verifier.Verify(signRS256(t, rsaKey, "rsa", validClaims), now)
+		require.NoError(t, err)
+		require.Equal(t, "project:ci", claims.Subject)
+		require.True(t, claims.Match("groups", "c*"))
+		require.False(t, claims.Match("groups", "prod"))
+		val, ok := claims.Value("bucket")
+		require.True(t, ok)
+		require.Equal(t, "ci-", val)
+	})
+
+	t.Run("ec", func(t *testing.T) {
+		_, err := verifier.Verify(signES256(t, ecKey, "ec", validClaims), now)
+		require.NoError(t, err)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		_, err := verifier.Verify(signRS256(t, rsaKey, "rsa", validClaims), now.Add(2*time.Hour))
+		require.ErrorIs(t, err, ErrExpiredToken)
+	})
+
+	t.Run("invalid audience", func(t *testing.T) {
+		_, err := verifier.Verify(signRS256(t, rsaKey, "rsa", withClaim(validClaims, "aud", "other")), now)
+		require.ErrorIs(t, err, ErrInvalidAudience)
+	})
+
+	t.Run("untrusted issuer", func(t *testing.T) {
+		_, err := verifier.Verify(signRS256(t, rsaKey, "rsa", withClaim(validClaims, "iss", "https://another")), now)
+		require.ErrorIs(t, err, ErrUntrustedIssuer)
+	})
+
+	t.Run("invalid signature", func(t *testing.T) {
+		anotherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+
+		_, err = verifier.Verify(signRS256(t, anotherKey, "rsa", validClaims), now)
+		require.ErrorIs(t, err, ErrInvalidToken)
+
+		_, err = verifier.Verify("invalid.token", now)
+		require.ErrorIs(t, err, ErrInvalidToken)
+	})
+}
+
+func withClaim(claims map[string]interface{}, key string, value interface{}) map[string]interface{} {
+	res := make(map[string]interface{}, len(claims))
+	for k, v := range claims {
+		res[k] = v
+	}
+	res[key] = value
+	return res
+}
+
+func writeJWKS(t *testing.T, file string, key map[string]string) {
+	data, err := json.Marshal(map[string]interface{}{"keys": []interface{}{key}})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(file, data, 0600))
+}
+
+func encodeBigInt(i *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(i.Bytes())
+}
+
+func signingInput(t *testing.T, alg, kid string, claims map[string]interface{}) (string, []byte) {
+	hdr, err := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	require.NoError(t, err)
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+
+	input := base64.RawURLEncoding.EncodeToString(hdr) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(input))
+	return input, digest[:]
+}
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, kid string, claims map[string]interface{}) string {
+	input, digest := signingInput(t, "RS256", kid, claims)
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest)
+	require.NoError(t, err)
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func signES256(t *testing.T, key *ecdsa.PrivateKey, kid string, claims map[string]interface{}) string {
+	input, digest := signingInput(t, "ES256", kid, claims)
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest)
+	require.NoError(t, err)
+
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
@@ -5,13 +5,12 @@ import (
 	"time"
 
 	"github.com/TrueCloudLab/frostfs-sdk-go/user"
-	"github.com/bluele/gcache"
 	"go.uber.org/zap"
 )
 
 // AccessControlCache provides lru cache for objects.
 type AccessControlCache struct {
-	cache  gcache.Cache
+	cache  *store
 	logger *zap.Logger
 }
 
@@ -33,8 +32,7 @@ func DefaultAccessControlConfig(logger *zap.Logger) *Config {
 
 // NewAccessControlCache creates an object of AccessControlCache.
 func NewAccessControlCache(config *Config) *AccessControlCache {
-	gc := gcache.New(config.Size).LRU().Expiration(config.Lifetime).Build()
-	return &AccessControlCache{cache: gc, logger: config.Logger}
+	return &AccessControlCache{cache: newStore("accesscontrol", config), logger: config.Logger}
 }
 
 // Get returns true if such key exists.
@@ -67,3 +65,8 @@ func (o *AccessControlCache) Delete(owner user.ID, key string) bool {
 func cacheKey(owner user.ID, key string) string {
 	return owner.EncodeToString() + key
 }
+
+// Purge removes all entries from cache.
+func (o *AccessControlCache) Purge() {
+	o.cache.Purge()
+}
@@ -6,7 +6,6 @@ import (
 
 	"github.com/TrueCloudLab/frostfs-s3-gw/creds/accessbox"
 	oid "github.com/TrueCloudLab/frostfs-sdk-go/object/id"
-	"github.com/bluele/gcache"
 	"go.uber.org/zap"
 )
 
@@ -14,7 +13,7 @@ type (
 	// AccessBoxCache stores an access box by its address.
 	AccessBoxCache struct {
 		logger *zap.Logger
-		cache  gcache.Cache
+		cache  *store
 	}
 
 	// Config stores expiration params for cache.
@@ -22,6 +21,8 @@ type (
 		Size     int
 		Lifetime time.Duration
 		Logger   *zap.Logger
+		// Observer collects statistics of the cache, optional.
+		Observer *Observer
 	}
 )
 
@@ -43,9 +44,7 @@ func DefaultAccessBoxConfig(logger *zap.Logger) *Config {
 
 // NewAccessBoxCache creates an object of BucketCache.
 func NewAccessBoxCache(config *Config) *AccessBoxCache {
-	gc := gcache.New(config.Size).LRU().Expiration(config.Lifetime).Build()
-
-	return &AccessBoxCache{cache: gc, logger: config.Logger}
+	return &AccessBoxCache{cache: newStore("accessbox", config), logger: config.Logger}
 }
 
 // Get returns a cached object.
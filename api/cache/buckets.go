@@ -5,13 +5,12 @@ import (
 	"time"
 
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
-	"github.com/bluele/gcache"
 	"go.uber.org/zap"
 )
 
 // BucketCache contains cache with objects and the lifetime of cache entries.
 type BucketCache struct {
-	cache  gcache.Cache
+	cache  *store
 	logger *zap.Logger
 }
 
@@ -33,8 +32,7 @@ func DefaultBucketConfig(logger *zap.Logger) *Config {
 
 // NewBucketCache creates an object of BucketCache.
 func NewBucketCache(config *Config) *BucketCache {
-	gc := gcache.New(config.Size).LRU().Expiration(config.Lifetime).Build()
-	return &BucketCache{cache: gc, logger: config.Logger}
+	return &BucketCache{cache: newStore("buckets", config), logger: config.Logger}
 }
 
 // Get returns a cached object.
@@ -63,3 +61,8 @@ func (o *BucketCache) Put(bkt *data.BucketInfo) error {
 func (o *BucketCache) Delete(key string) bool {
 	return o.cache.Remove(key)
 }
+
+// Purge removes all entries from cache.
+func (o *BucketCache) Purge() {
+	o.cache.Purge()
+}
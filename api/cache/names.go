@@ -5,7 +5,6 @@ import (
 	"time"
 
 	oid "github.com/TrueCloudLab/frostfs-sdk-go/object/id"
-	"github.com/bluele/gcache"
 	"go.uber.org/zap"
 )
 
@@ -13,7 +12,7 @@ import (
 // This cache contains mapping nice names to object addresses.
 // Key is bucketName+objectName.
 type ObjectsNameCache struct {
-	cache  gcache.Cache
+	cache  *store
 	logger *zap.Logger
 }
 
@@ -35,8 +34,7 @@ func DefaultObjectsNameConfig(logger *zap.Logger) *Config {
 
 // NewObjectsNameCache creates an object of ObjectsNameCache.
 func NewObjectsNameCache(config *Config) *ObjectsNameCache {
-	gc := gcache.New(config.Size).LRU().Expiration(config.Lifetime).Build()
-	return &ObjectsNameCache{cache: gc, logger: config.Logger}
+	return &ObjectsNameCache{cache: newStore("names", config), logger: config.Logger}
 }
 
 // Get returns a cached object. Returns nil if value is missing.
@@ -65,3 +63,8 @@ func (o *ObjectsNameCache) Put(key string, address oid.Address) error {
 func (o *ObjectsNameCache) Delete(key string) bool {
 	return o.cache.Remove(key)
 }
+
+// Purge removes all entries from cache.
+func (o *ObjectsNameCache) Purge() {
+	o.cache.Purge()
+}
@@ -6,13 +6,12 @@ import (
 
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
 	oid "github.com/TrueCloudLab/frostfs-sdk-go/object/id"
-	"github.com/bluele/gcache"
 	"go.uber.org/zap"
 )
 
 // ObjectsCache provides lru cache for objects.
 type ObjectsCache struct {
-	cache  gcache.Cache
+	cache  *store
 	logger *zap.Logger
 }
 
@@ -34,8 +33,7 @@ func DefaultObjectsConfig(logger *zap.Logger) *Config {
 
 // New creates an object of ObjectHeadersCache.
 func New(config *Config) *ObjectsCache {
-	gc := gcache.New(config.Size).LRU().Expiration(config.Lifetime).Build()
-	return &ObjectsCache{cache: gc, logger: config.Logger}
+	return &ObjectsCache{cache: newStore("objects", config), logger: config.Logger}
 }
 
 // GetObject returns a cached object info.
@@ -64,3 +62,8 @@ func (o *ObjectsCache) PutObject(obj *data.ExtendedObjectInfo) error {
 func (o *ObjectsCache) Delete(address oid.Address) bool {
 	return o.cache.Remove(address)
 }
+
+// Purge removes all entries from cache.
+func (o *ObjectsCache) Purge() {
+	o.cache.Purge()
+}
@@ -8,7 +8,6 @@ import (
 
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
 	cid "github.com/TrueCloudLab/frostfs-sdk-go/container/id"
-	"github.com/bluele/gcache"
 	"go.uber.org/zap"
 )
 
@@ -28,7 +27,7 @@ import (
 type (
 	// ObjectsListCache contains cache for ListObjects and ListObjectVersions.
 	ObjectsListCache struct {
-		cache  gcache.Cache
+		cache  *store
 		logger *zap.Logger
 	}
 
@@ -37,6 +36,8 @@ type (
 		cid        cid.ID
 		prefix     string
 		latestOnly bool
+		// directory means that the entry contains children of the directory with the prefix path.
+		directory bool
 	}
 )
 
@@ -57,13 +58,16 @@ func DefaultObjectsListConfig(logger *zap.Logger) *Config {
 }
 
 func (k *ObjectsListKey) String() string {
-	return k.cid.EncodeToString() + k.prefix + strconv.FormatBool(k.latestOnly)
+	key := k.cid.EncodeToString() + k.prefix + strconv.FormatBool(k.latestOnly)
+	if k.directory {
+		key += "/dir"
+	}
+	return key
 }
 
 // NewObjectsListCache is a constructor which creates an object of ListObjectsCache with the given lifetime of entries.
 func NewObjectsListCache(config *Config) *ObjectsListCache {
-	gc := gcache.New(config.Size).LRU().Expiration(config.Lifetime).Build()
-	return &ObjectsListCache{cache: gc, logger: config.Logger}
+	return &ObjectsListCache{cache: newStore("list", config), logger: config.Logger}
 }
 
 // GetVersions returns a list of ObjectInfo.
@@ -88,6 +92,28 @@ func (l *ObjectsListCache) PutVersions(key ObjectsListKey, versions []*data.Node
 	return l.cache.Set(key, versions)
 }
 
+// GetDirectoryChildren returns children of the directory.
+func (l *ObjectsListCache) GetDirectoryChildren(key ObjectsListKey) *data.TreeDirectoryChildren {
+	entry, err := l.cache.Get(key)
+	if err != nil {
+		return nil
+	}
+
+	result, ok := entry.(*data.TreeDirectoryChildren)
+	if !ok {
+		l.logger.Warn("invalid cache entry type", zap.String("actual", fmt.Sprintf("%T", entry)),
+			zap.String("expected", fmt.Sprintf("%T", result)))
+		return nil
+	}
+
+	return result
+}
+
+// PutDirectoryChildren puts children of the directory to cache.
+func (l *ObjectsListCache) PutDirectoryChildren(key ObjectsListKey, children *data.TreeDirectoryChildren) error {
+	return l.cache.Set(key, children)
+}
+
 // CleanCacheEntriesContainingObject deletes entries containing specified object.
 func (l *ObjectsListCache) CleanCacheEntriesContainingObject(objectName string, cnr cid.ID) {
 	keys := l.cache.Keys(true)
@@ -114,3 +140,19 @@ func CreateObjectsListCacheKey(cnr cid.ID, prefix string, latestOnly bool) Objec
 
 	return p
 }
+
+// CreateDirectoryChildrenCacheKey returns ObjectsListKey of children of the directory with the given path.
+// Such entries are cleaned with lists of objects with the same prefix.
+func CreateDirectoryChildrenCacheKey(cnr cid.ID, dirPath string, latestOnly bool) ObjectsListKey {
+	return ObjectsListKey{
+		cid:        cnr,
+		prefix:     dirPath,
+		latestOnly: latestOnly,
+		directory:  true,
+	}
+}
+
+// Purge removes all entries from cache.
+func (l *ObjectsListCache) Purge() {
+	l.cache.Purge()
+}
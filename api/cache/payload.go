@@ -0,0 +1,278 @@
+package cache
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	oid "github.com/TrueCloudLab/frostfs-sdk-go/object/id"
+	"go.uber.org/zap"
+)
+
+type (
+	// PayloadCache stores payloads of small objects on a local disk.
+	// Cached payloads are checked against payload hash on every read.
+	PayloadCache struct {
+		logger        *zap.Logger
+		dir           string
+		maxSize       uint64
+		maxObjectSize uint64
+
+		mu      sync.Mutex
+		size    uint64
+		entries map[string]*list.Element
+		// order contains entries from the least recently used.
+		order *list.List
+	}
+
+	// PayloadConfig stores params of the payload cache.
+	PayloadConfig struct {
+		Dir           string
+		MaxSize       uint64
+		MaxObjectSize uint64
+		Logger        *zap.Logger
+	}
+
+	payloadEntry struct {
+		name string
+		size uint64
+	}
+)
+
+const (
+	// DefaultPayloadCacheMaxSize is a default maximum total size of cached payloads.
+	DefaultPayloadCacheMaxSize = 1 << 30
+	// DefaultPayloadCacheMaxObjectSize is a default maximum size of cached object payload.
+	DefaultPayloadCacheMaxObjectSize = 1 << 20
+
+	payloadTempSuffix = ".tmp"
+)
+
+// ErrPayloadHashMismatch is returned if payload doesn't correspond to the expected hash.
+var ErrPayloadHashMismatch = errors.New("payload hash mismatch")
+
+// NewPayloadCache creates PayloadCache and loads entries stored in the directory.
+func NewPayloadCache(config *PayloadConfig) (*PayloadCache, error) {
+	if err := os.MkdirAll(config.Dir, 0700); err != nil {
+		return nil, fmt.Errorf("create payload cache dir: %w", err)
+	}
+
+	c := &PayloadCache{
+		logger:        config.Logger,
+		dir:           config.Dir,
+		maxSize:       config.MaxSize,
+		maxObjectSize: config.MaxObjectSize,
+		entries:       make(map[string]*list.Element),
+		order:         list.New(),
+	}
+
+	if err := c.load(); err != nil {
+		return nil, fmt.Errorf("load payload cache: %w", err)
+	}
+
+	return c, nil
+}
+
+// MaxObjectSize returns maximum size of payload which can be cached.
+func (c *PayloadCache) MaxObjectSize() uint64 {
+	return c.maxObjectSize
+}
+
+// Get returns cached payload of the object if it corresponds to the hex encoded sha256 hash.
+func (c *PayloadCache) Get(addr oid.Address, hash string) ([]byte, bool) {
+	name := payloadFileName(addr)
+
+	c.mu.Lock()
+	el, ok := c.entries[name]
+	if ok {
+		c.order.MoveToBack(el)
+	}
+	c.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+
+	payload, err := os.ReadFile(filepath.Join(c.dir, name))
+	if err != nil {
+		c.logger.Warn("couldn't read cached payload", zap.Stringer("address", addr), zap.Error(err))
+		c.Delete(addr)
+		return nil, false
+	}
+
+	if err = checkPayloadHash(payload, hash); err != nil {
+		c.logger.Warn("invalid cached payload", zap.Stringer("address", addr), zap.Error(err))
+		c.Delete(addr)
+		return nil, false
+	}
+
+	return payload, true
+}
+
+// Put stores the payload if it corresponds to the hex encoded sha256 hash and evicts
+// the least recently used payloads if the cache size exceeds the limit.
+func (c *PayloadCache) Put(addr oid.Address, hash string, payload []byte) error {
+	size := uint64(len(payload))
+	if size > c.maxObjectSize || size > c.maxSize {
+		return fmt.Errorf("payload is too big to be cached: %d", size)
+	}
+
+	if err := checkPayloadHash(payload, hash); err != nil {
+		return err
+	}
+
+	name := payloadFileName(addr)
+	tmp, err := os.CreateTemp(c.dir, name+".*"+payloadTempSuffix)
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+
+	if _, err = tmp.Write(payload); err == nil {
+		err = tmp.Close()
+	} else {
+		_ = tmp.Close()
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), filepath.Join(c.dir, name))
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("write payload: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[name]; ok {
+		c.removeEntry(el)
+	}
+	c.entries[name] = c.order.PushBack(&payloadEntry{name: name, size: size})
+	c.size += size
+
+	for c.size > c.maxSize {
+		el := c.order.Front()
+		c.removeEntry(el)
+		if err = os.Remove(filepath.Join(c.dir, el.Value.(*payloadEntry).name)); err != nil && !os.IsNotExist(err) {
+			c.logger.Warn("couldn't remove cached payload", zap.Error(err))
+		}
+	}
+
+	return nil
+}
+
+// Delete removes the cached payload of the object.
+func (c *PayloadCache) Delete(addr oid.Address) {
+	name := payloadFileName(addr)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[name]
+	if !ok {
+		return
+	}
+
+	c.removeEntry(el)
+	if err := os.Remove(filepath.Join(c.dir, name)); err != nil && !os.IsNotExist(err) {
+		c.logger.Warn("couldn't remove cached payload", zap.Stringer("address", addr), zap.Error(err))
+	}
+}
+
+// Size returns total size of cached payloads.
+func (c *PayloadCache) Size() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.size
+}
+
+func (c *PayloadCache) removeEntry(el *list.Element) {
+	entry := el.Value.(*payloadEntry)
+	c.order.Remove(el)
+	delete(c.entries, entry.name)
+	c.size -= entry.size
+}
+
+// load restores index of payloads cached before restart, the oldest files are considered the least recently used.
+func (c *PayloadCache) load() error {
+	dirEntries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return err
+	}
+
+	type file struct {
+		name    string
+		size    uint64
+		modTime int64
+	}
+
+	files := make([]file, 0, len(dirEntries))
+	for _, dirEntry := range dirEntries {
+		name := dirEntry.Name()
+		path := filepath.Join(c.dir, name)
+
+		if dirEntry.IsDir() {
+			continue
+		}
+
+		info, err := dirEntry.Info()
+		if err != nil {
+			return err
+		}
+
+		if strings.HasSuffix(name, payloadTempSuffix) || !isPayloadFileName(name) || uint64(info.Size()) > c.maxObjectSize {
+			if err = os.Remove(path); err != nil {
+				return err
+			}
+			continue
+		}
+
+		files = append(files, file{name: name, size: uint64(info.Size()), modTime: info.ModTime().UnixNano()})
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime < files[j].modTime
+	})
+
+	for _, f := range files {
+		c.entries[f.name] = c.order.PushBack(&payloadEntry{name: f.name, size: f.size})
+		c.size += f.size
+	}
+
+	for c.size > c.maxSize {
+		el := c.order.Front()
+		c.removeEntry(el)
+		if err = os.Remove(filepath.Join(c.dir, el.Value.(*payloadEntry).name)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func payloadFileName(addr oid.Address) string {
+	return addr.Container().EncodeToString() + "_" + addr.Object().EncodeToString()
+}
+
+func isPayloadFileName(name string) bool {
+	cnrID, objID, ok := strings.Cut(name, "_")
+	if !ok {
+		return false
+	}
+
+	var addr oid.Address
+	return addr.DecodeString(cnrID+"/"+objID) == nil
+}
+
+func checkPayloadHash(payload []byte, hash string) error {
+	sum := sha256.Sum256(payload)
+	if hex.EncodeToString(sum[:]) != hash {
+		return ErrPayloadHashMismatch
+	}
+	return nil
+}
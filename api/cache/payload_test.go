@@ -0,0 +1,66 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	oidtest "github.com/TrueCloudLab/frostfs-sdk-go/object/id/test"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestPayloadCache(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &PayloadConfig{Dir: dir, MaxSize: 10, MaxObjectSize: 5, Logger: zap.NewNop()}
+
+	c, err := NewPayloadCache(cfg)
+	require.NoError(t, err)
+
+	hash := func(payload []byte) string {
+		sum := sha256.Sum256(payload)
+		return hex.EncodeToString(sum[:])
+	}
+
+	addr1, addr2, addr3 := oidtest.Address(), oidtest.Address(), oidtest.Address()
+
+	require.Error(t, c.Put(addr1, hash([]byte("too big")), []byte("too big")))
+	require.ErrorIs(t, c.Put(addr1, hash([]byte("other")), []byte("abcd")), ErrPayloadHashMismatch)
+
+	require.NoError(t, c.Put(addr1, hash([]byte("abcd")), []byte("abcd")))
+	require.NoError(t, c.Put(addr2, hash([]byte("efgh")), []byte("efgh")))
+
+	payload, ok := c.Get(addr1, hash([]byte("abcd")))
+	require.True(t, ok)
+	require.Equal(t, []byte("abcd"), payload)
+
+	// addr2 is the least recently used
+	require.NoError(t, c.Put(addr3, hash([]byte("ijk")), []byte("ijk")))
+	_, ok = c.Get(addr2, hash([]byte("efgh")))
+	require.False(t, ok)
+	require.Equal(t, uint64(7), c.Size())
+
+	t.Run("integrity", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, payloadFileName(addr3)), []byte("xyz"), 0600))
+		_, ok = c.Get(addr3, hash([]byte("ijk")))
+		require.False(t, ok)
+		require.Equal(t, uint64(4), c.Size())
+	})
+
+	t.Run("reload", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "garbage"), []byte("garbage"), 0600))
+
+		c, err = NewPayloadCache(cfg)
+		require.NoError(t, err)
+		require.Equal(t, uint64(4), c.Size())
+
+		payload, ok = c.Get(addr1, hash([]byte("abcd")))
+		require.True(t, ok)
+		require.Equal(t, []byte("abcd"), payload)
+
+		_, err = os.Stat(filepath.Join(dir, "garbage"))
+		require.True(t, os.IsNotExist(err))
+	})
+}
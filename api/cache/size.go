@@ -0,0 +1,86 @@
+package cache
+
+import (
+	"unsafe"
+
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
+	"github.com/TrueCloudLab/frostfs-s3-gw/creds/accessbox"
+	oid "github.com/TrueCloudLab/frostfs-sdk-go/object/id"
+)
+
+const (
+	// pointerSize is a size of pointers to cached structures.
+	pointerSize = int64(unsafe.Sizeof(uintptr(0)))
+	// mapEntryOverhead is an approximate overhead of the map entry.
+	mapEntryOverhead = 16
+	// defaultEntrySize is a size of values which size can't be estimated.
+	defaultEntrySize = 256
+	// accessBoxSize is an approximate size of the access box with bearer and session tokens.
+	accessBoxSize = 4096
+)
+
+// estimateSize returns approximate memory size of the cached key or value.
+func estimateSize(v interface{}) int64 {
+	switch val := v.(type) {
+	case string:
+		return int64(len(val))
+	case bool:
+		return 1
+	case oid.Address:
+		return int64(unsafe.Sizeof(val))
+	case *oid.Address:
+		return pointerSize + int64(unsafe.Sizeof(*val))
+	case ObjectsListKey:
+		return int64(unsafe.Sizeof(val)) + int64(len(val.prefix))
+	case *data.ExtendedObjectInfo:
+		return pointerSize + int64(unsafe.Sizeof(*val)) + objectInfoSize(val.ObjectInfo) + nodeVersionSize(val.NodeVersion)
+	case *data.ObjectInfo:
+		return objectInfoSize(val)
+	case []*data.NodeVersion:
+		size := int64(unsafe.Sizeof(val))
+		for _, version := range val {
+			size += nodeVersionSize(version)
+		}
+		return size
+	case *data.BucketInfo:
+		return pointerSize + int64(unsafe.Sizeof(*val)) + int64(len(val.Name)+len(val.LocationConstraint))
+	case *data.BucketSettings:
+		return pointerSize + int64(unsafe.Sizeof(*val)) + int64(len(val.Versioning))
+	case *data.LockInfo:
+		return pointerSize + int64(unsafe.Sizeof(*val))
+	case map[string]string:
+		return mapSize(val)
+	case *accessbox.Box:
+		return accessBoxSize
+	default:
+		return defaultEntrySize
+	}
+}
+
+func objectInfoSize(info *data.ObjectInfo) int64 {
+	if info == nil {
+		return 0
+	}
+	return pointerSize + int64(unsafe.Sizeof(*info)) +
+		int64(len(info.Bucket)+len(info.Name)+len(info.ContentType)+len(info.HashSum)) +
+		mapSize(info.Headers)
+}
+
+func nodeVersionSize(version *data.NodeVersion) int64 {
+	if version == nil {
+		return 0
+	}
+	size := pointerSize + int64(unsafe.Sizeof(*version)) + int64(len(version.ETag)+len(version.FilePath))
+	if version.DeleteMarker != nil {
+		size += pointerSize + int64(unsafe.Sizeof(*version.DeleteMarker))
+	}
+	return size
+}
+
+func mapSize(m map[string]string) int64 {
+	size := int64(unsafe.Sizeof(m))
+	for k, v := range m {
+		size += int64(len(k)+len(v)) + mapEntryOverhead
+	}
+	return size
+}
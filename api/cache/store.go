@@ -0,0 +1,302 @@
+package cache
+
+import (
+	"container/list"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/bluele/gcache"
+)
+
+type (
+	// Stats contains statistics of the cache.
+	Stats struct {
+		Name    string `json:"name"`
+		Entries int    `json:"entries"`
+		// Bytes is an estimated size of cached keys and values.
+		Bytes     int64      `json:"bytes"`
+		Hits      uint64     `json:"hits"`
+		Misses    uint64     `json:"misses"`
+		Evictions uint64     `json:"evictions"`
+		TopKeys   []KeyStats `json:"top_keys,omitempty"`
+	}
+
+	// KeyStats contains estimated size of the cache entry.
+	KeyStats struct {
+		Key   string `json:"key"`
+		Bytes int64  `json:"bytes"`
+	}
+
+	// Observer collects statistics of caches and evicts entries across caches
+	// if their total size exceeds the memory budget.
+	Observer struct {
+		budget int64
+
+		mu     sync.Mutex
+		stores []*store
+	}
+
+	// store is an LRU cache with statistics and size accounting.
+	store struct {
+		// counters are accessed atomically, keep them 64-bit aligned
+		hits      uint64
+		misses    uint64
+		evictions uint64
+		bytes     int64
+
+		name     string
+		cache    gcache.Cache
+		observer *Observer
+
+		mu sync.Mutex
+		// entries are ordered by the time of the last write.
+		entries  map[interface{}]*list.Element
+		order    *list.List
+		deleting map[interface{}]struct{}
+	}
+
+	storeEntry struct {
+		key  interface{}
+		size int64
+	}
+)
+
+// NewObserver creates Observer. Zero memory budget means no limit.
+func NewObserver(memoryBudget int64) *Observer {
+	return &Observer{budget: memoryBudget}
+}
+
+// Stats returns statistics of all observed caches with topKeys biggest entries of every cache.
+func (o *Observer) Stats(topKeys int) []Stats {
+	o.mu.Lock()
+	stores := make([]*store, len(o.stores))
+	copy(stores, o.stores)
+	o.mu.Unlock()
+
+	res := make([]Stats, len(stores))
+	for i, s := range stores {
+		res[i] = s.stats(topKeys)
+	}
+	return res
+}
+
+func (o *Observer) add(s *store) {
+	o.mu.Lock()
+	o.stores = append(o.stores, s)
+	o.mu.Unlock()
+}
+
+// enforceBudget evicts the oldest entries of the biggest cache until the total size fits the budget.
+func (o *Observer) enforceBudget() {
+	if o.budget <= 0 {
+		return
+	}
+
+	o.mu.Lock()
+	stores := make([]*store, len(o.stores))
+	copy(stores, o.stores)
+	o.mu.Unlock()
+
+	for {
+		var total int64
+		var biggest *store
+		for _, s := range stores {
+			size := s.size()
+			total += size
+			if biggest == nil || size > biggest.size() {
+				biggest = s
+			}
+		}
+
+		if total <= o.budget || biggest == nil || !biggest.evictOldest() {
+			return
+		}
+	}
+}
+
+func newStore(name string, config *Config) *store {
+	s := &store{
+		name:     name,
+		observer: config.Observer,
+		entries:  make(map[interface{}]*list.Element),
+		order:    list.New(),
+		deleting: make(map[interface{}]struct{}),
+	}
+
+	s.cache = gcache.New(config.Size).LRU().Expiration(config.Lifetime).
+		AddedFunc(s.added).
+		EvictedFunc(s.evicted).
+		Build()
+
+	if s.observer != nil {
+		s.observer.add(s)
+	}
+
+	return s
+}
+
+// Get returns a cached value and counts hit or miss.
+func (s *store) Get(key interface{}) (interface{}, error) {
+	value, err := s.cache.Get(key)
+	if err != nil {
+		atomic.AddUint64(&s.misses, 1)
+		return nil, err
+	}
+
+	atomic.AddUint64(&s.hits, 1)
+	return value, nil
+}
+
+// Set puts the value to the cache and evicts entries of observed caches if memory budget is exceeded.
+func (s *store) Set(key, value interface{}) error {
+	if err := s.cache.Set(key, value); err != nil {
+		return err
+	}
+
+	if s.observer != nil {
+		s.observer.enforceBudget()
+	}
+	return nil
+}
+
+// Remove deletes the entry, it isn't counted as eviction.
+func (s *store) Remove(key interface{}) bool {
+	s.mu.Lock()
+	s.deleting[key] = struct{}{}
+	s.mu.Unlock()
+
+	removed := s.cache.Remove(key)
+	if !removed {
+		s.mu.Lock()
+		delete(s.deleting, key)
+		s.mu.Unlock()
+	}
+
+	return removed
+}
+
+// Keys returns keys of the cache.
+func (s *store) Keys(checkExpired bool) []interface{} {
+	return s.cache.Keys(checkExpired)
+}
+
+// Purge removes all entries, they aren't counted as evictions.
+func (s *store) Purge() {
+	// entries are removed without EvictedFunc
+	s.cache.Purge()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries = make(map[interface{}]*list.Element)
+	s.order.Init()
+	atomic.StoreInt64(&s.bytes, 0)
+}
+
+func (s *store) size() int64 {
+	return atomic.LoadInt64(&s.bytes)
+}
+
+// added is called by gcache under its lock, so it's consistent with evicted.
+func (s *store) added(key, value interface{}) {
+	size := estimateSize(key) + estimateSize(value)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.entries[key]; ok {
+		entry := el.Value.(*storeEntry)
+		atomic.AddInt64(&s.bytes, size-entry.size)
+		entry.size = size
+		s.order.MoveToBack(el)
+		return
+	}
+
+	s.entries[key] = s.order.PushBack(&storeEntry{key: key, size: size})
+	atomic.AddInt64(&s.bytes, size)
+}
+
+// evicted is called by gcache under its lock when entry is removed, evicted or expired.
+func (s *store) evicted(key, _ interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.entries[key]; ok {
+		atomic.AddInt64(&s.bytes, -el.Value.(*storeEntry).size)
+		s.order.Remove(el)
+		delete(s.entries, key)
+	}
+
+	if _, ok := s.deleting[key]; ok {
+		delete(s.deleting, key)
+		return
+	}
+	atomic.AddUint64(&s.evictions, 1)
+}
+
+// evictOldest removes the least recently written entry. Returns false if the cache is empty.
+func (s *store) evictOldest() bool {
+	s.mu.Lock()
+	el := s.order.Front()
+	if el == nil {
+		s.mu.Unlock()
+		return false
+	}
+	key := el.Value.(*storeEntry).key
+	s.mu.Unlock()
+
+	if !s.cache.Remove(key) {
+		// entry isn't in gcache anymore, forget it to not evict it again
+		s.evicted(key, nil)
+	}
+	return true
+}
+
+func (s *store) stats(topKeys int) Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := Stats{
+		Name:      s.name,
+		Entries:   len(s.entries),
+		Bytes:     s.size(),
+		Hits:      atomic.LoadUint64(&s.hits),
+		Misses:    atomic.LoadUint64(&s.misses),
+		Evictions: atomic.LoadUint64(&s.evictions),
+	}
+
+	if topKeys <= 0 {
+		return res
+	}
+
+	entries := make([]*storeEntry, 0, len(s.entries))
+	for el := s.order.Front(); el != nil; el = el.Next() {
+		entries = append(entries, el.Value.(*storeEntry))
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].size > entries[j].size
+	})
+	if len(entries) > topKeys {
+		entries = entries[:topKeys]
+	}
+
+	res.TopKeys = make([]KeyStats, len(entries))
+	for i, entry := range entries {
+		res.TopKeys[i] = KeyStats{Key: keyString(entry.key), Bytes: entry.size}
+	}
+
+	return res
+}
+
+func keyString(key interface{}) string {
+	switch k := key.(type) {
+	case ObjectsListKey:
+		return k.String()
+	case fmt.Stringer:
+		return k.String()
+	default:
+		return fmt.Sprint(key)
+	}
+}
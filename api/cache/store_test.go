@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestStoreStats(t *testing.T) {
+	observer := NewObserver(0)
+	s := newStore("test", &Config{Size: 2, Lifetime: time.Minute, Logger: zap.NewNop(), Observer: observer})
+
+	require.NoError(t, s.Set("a", "value"))
+	require.NoError(t, s.Set("b", "big value"))
+
+	_, err := s.Get("a")
+	require.NoError(t, err)
+	_, err = s.Get("c")
+	require.Error(t, err)
+
+	// "b" is the least recently used
+	require.NoError(t, s.Set("c", "value"))
+	require.True(t, s.Remove("c"))
+
+	stats := observer.Stats(1)
+	require.Len(t, stats, 1)
+	require.Equal(t, Stats{
+		Name:      "test",
+		Entries:   1,
+		Bytes:     estimateSize("a") + estimateSize("value"),
+		Hits:      1,
+		Misses:    1,
+		Evictions: 1,
+		TopKeys:   []KeyStats{{Key: "a", Bytes: estimateSize("a") + estimateSize("value")}},
+	}, stats[0])
+
+	s.Purge()
+	stats = observer.Stats(0)
+	require.Zero(t, stats[0].Entries)
+	require.Zero(t, stats[0].Bytes)
+}
+
+func TestObserverMemoryBudget(t *testing.T) {
+	value := strings.Repeat("v", 100)
+	entrySize := estimateSize("k0") + estimateSize(value)
+
+	observer := NewObserver(3 * entrySize)
+	cfg := &Config{Size: 10, Lifetime: time.Minute, Logger: zap.NewNop(), Observer: observer}
+	first, second := newStore("first", cfg), newStore("second", cfg)
+
+	require.NoError(t, first.Set("k0", value))
+	require.NoError(t, first.Set("k1", value))
+	require.NoError(t, second.Set("k2", value))
+	require.NoError(t, second.Set("k3", value))
+
+	// the oldest entry of the biggest cache is evicted
+	_, err := first.Get("k0")
+	require.Error(t, err)
+	_, err = first.Get("k1")
+	require.NoError(t, err)
+
+	var total int64
+	for _, stat := range observer.Stats(0) {
+		total += stat.Bytes
+	}
+	require.LessOrEqual(t, total, 3*entrySize)
+	require.Equal(t, uint64(1), first.stats(0).Evictions)
+}
@@ -5,7 +5,6 @@ import (
 	"time"
 
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
-	"github.com/bluele/gcache"
 	"go.uber.org/zap"
 )
 
@@ -13,7 +12,7 @@ import (
 // This cache contains "system" objects (bucket versioning settings, tagging object etc.).
 // Key is bucketName+systemFilePath.
 type SystemCache struct {
-	cache  gcache.Cache
+	cache  *store
 	logger *zap.Logger
 }
 
@@ -35,8 +34,7 @@ func DefaultSystemConfig(logger *zap.Logger) *Config {
 
 // NewSystemCache creates an object of SystemCache.
 func NewSystemCache(config *Config) *SystemCache {
-	gc := gcache.New(config.Size).LRU().Expiration(config.Lifetime).Build()
-	return &SystemCache{cache: gc, logger: config.Logger}
+	return &SystemCache{cache: newStore("system", config), logger: config.Logger}
 }
 
 // GetObject returns a cached object.
@@ -165,3 +163,8 @@ func (o *SystemCache) PutTagging(key string, tagSet map[string]string) error {
 func (o *SystemCache) Delete(key string) bool {
 	return o.cache.Remove(key)
 }
+
+// Purge removes all entries from cache.
+func (o *SystemCache) Purge() {
+	o.cache.Purge()
+}
@@ -1,7 +1,9 @@
 package data
 
 import (
+	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	cid "github.com/TrueCloudLab/frostfs-sdk-go/container/id"
@@ -16,7 +18,11 @@ const (
 // NodeVersion represent node from tree service.
 type NodeVersion struct {
 	BaseNodeVersion
-	DeleteMarker  *DeleteMarkerInfo
+	DeleteMarker *DeleteMarkerInfo
+	Copy         *CopyInfo
+	// Segments are payload objects appended to the payload of the version object (see AppendObject),
+	// only OID and Size of segments are set.
+	Segments      []*PartInfo
 	IsUnversioned bool
 }
 
@@ -24,6 +30,21 @@ func (v NodeVersion) IsDeleteMarker() bool {
 	return v.DeleteMarker != nil
 }
 
+// TreeDirectory is an intermediate node of the version tree.
+// It groups objects which have the same path prefix.
+type TreeDirectory struct {
+	// ID is node id in tree service.
+	ID uint64
+	// Path is a path of the directory with the trailing separator, it is empty for the root.
+	Path string
+}
+
+// TreeDirectoryChildren are versions of objects and subdirectories of the directory.
+type TreeDirectoryChildren struct {
+	Versions []*NodeVersion
+	Dirs     []*TreeDirectory
+}
+
 // DeleteMarkerInfo is used to save object info if node in the tree service is delete marker.
 // We need this information because the "delete marker" object is no longer stored in FrostFS.
 type DeleteMarkerInfo struct {
@@ -31,6 +52,16 @@ type DeleteMarkerInfo struct {
 	Owner   user.ID
 }
 
+// CopyInfo is used to save object info if node in the tree service references the payload object
+// of another version (the version was created by CopyObject without copying the payload).
+// Attributes of the object header which don't describe the payload are overridden by it.
+type CopyInfo struct {
+	Created time.Time
+	Owner   user.ID
+	// Headers are user headers (including Content-Type) of the version.
+	Headers map[string]string
+}
+
 // ExtendedObjectInfo contains additional node info to be able to sort versions by timestamp.
 type ExtendedObjectInfo struct {
 	ObjectInfo  *ObjectInfo
@@ -93,6 +124,50 @@ func (p *PartInfo) ToHeaderString() string {
 	return strconv.Itoa(p.Number) + "-" + strconv.FormatInt(p.Size, 10) + "-" + p.ETag
 }
 
+// SegmentsToString forms representation of appended segments to use in the tree node
+// and S3-Appended-Segments header: comma separated `<oid>-<size>` pairs.
+func SegmentsToString(segments []*PartInfo) string {
+	var sb strings.Builder
+	for i, segment := range segments {
+		if i != 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(segment.OID.EncodeToString())
+		sb.WriteByte('-')
+		sb.WriteString(strconv.FormatInt(segment.Size, 10))
+	}
+	return sb.String()
+}
+
+// ParseSegments parses appended segments formed by SegmentsToString.
+func ParseSegments(value string) ([]*PartInfo, error) {
+	if len(value) == 0 {
+		return nil, nil
+	}
+
+	items := strings.Split(value, ",")
+	segments := make([]*PartInfo, len(items))
+	for i, item := range items {
+		idStr, sizeStr, ok := strings.Cut(item, "-")
+		if !ok {
+			return nil, fmt.Errorf("invalid segment '%s'", item)
+		}
+
+		segment := &PartInfo{Number: i + 1}
+		if err := segment.OID.DecodeString(idStr); err != nil {
+			return nil, fmt.Errorf("invalid segment oid '%s': %w", idStr, err)
+		}
+		size, err := strconv.ParseInt(sizeStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid segment size '%s': %w", sizeStr, err)
+		}
+		segment.Size = size
+		segments[i] = segment
+	}
+
+	return segments, nil
+}
+
 // LockInfo is lock information to create appropriate tree node.
 type LockInfo struct {
 	id uint64
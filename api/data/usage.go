@@ -0,0 +1,77 @@
+package data
+
+type (
+	// BucketUsage stores storage usage of the bucket.
+	BucketUsage struct {
+		// Bytes is a logical size of all object versions.
+		Bytes int64 `json:"bytes"`
+		// Objects is a number of objects which latest version isn't a delete marker.
+		Objects int64 `json:"objects"`
+		// Versions is a number of object versions excluding delete markers.
+		Versions int64 `json:"versions"`
+		// DeleteMarkers is a number of delete markers.
+		DeleteMarkers int64 `json:"delete_markers"`
+		// MultipartBytes is a size of uploaded parts of in-progress multipart uploads.
+		MultipartBytes int64 `json:"multipart_bytes"`
+	}
+
+	// BucketUsageInfo stores the last known usage of the bucket.
+	BucketUsageInfo struct {
+		Bucket *BucketInfo
+		Usage  BucketUsage
+	}
+)
+
+// Add adds delta to the usage.
+func (u *BucketUsage) Add(delta BucketUsage) {
+	u.Bytes += delta.Bytes
+	u.Objects += delta.Objects
+	u.Versions += delta.Versions
+	u.DeleteMarkers += delta.DeleteMarkers
+	u.MultipartBytes += delta.MultipartBytes
+}
+
+// Sub returns the difference between the usage and the other one.
+func (u BucketUsage) Sub(other BucketUsage) BucketUsage {
+	return BucketUsage{
+		Bytes:          u.Bytes - other.Bytes,
+		Objects:        u.Objects - other.Objects,
+		Versions:       u.Versions - other.Versions,
+		DeleteMarkers:  u.DeleteMarkers - other.DeleteMarkers,
+		MultipartBytes: u.MultipartBytes - other.MultipartBytes,
+	}
+}
+
+// IsZero checks if all counters are zero.
+func (u BucketUsage) IsZero() bool {
+	return u == BucketUsage{}
+}
+
+// UsageOfVersions calculates usage of the object versions. Versions of different objects can be mixed.
+func UsageOfVersions(versions []*NodeVersion) BucketUsage {
+	var usage BucketUsage
+	latest := make(map[string]*NodeVersion, len(versions))
+
+	for _, version := range versions {
+		if version.IsDeleteMarker() {
+			usage.DeleteMarkers++
+		} else {
+			usage.Versions++
+			usage.Bytes += version.Size
+		}
+
+		last, ok := latest[version.FilePath]
+		if !ok || last.Timestamp < version.Timestamp ||
+			last.Timestamp == version.Timestamp && last.ID < version.ID {
+			latest[version.FilePath] = version
+		}
+	}
+
+	for _, version := range latest {
+		if !version.IsDeleteMarker() {
+			usage.Objects++
+		}
+	}
+
+	return usage
+}
@@ -0,0 +1,37 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsageOfVersions(t *testing.T) {
+	version := func(id uint64, name string, timestamp uint64, size int64) *NodeVersion {
+		return &NodeVersion{BaseNodeVersion: BaseNodeVersion{ID: id, FilePath: name, Timestamp: timestamp, Size: size}}
+	}
+	deleteMarker := func(id uint64, name string, timestamp uint64) *NodeVersion {
+		v := version(id, name, timestamp, 0)
+		v.DeleteMarker = &DeleteMarkerInfo{}
+		return v
+	}
+
+	usage := UsageOfVersions([]*NodeVersion{
+		version(1, "a", 1, 10),
+		version(2, "a", 2, 20),
+		version(3, "b", 1, 5),
+		deleteMarker(4, "b", 2),
+		deleteMarker(5, "c", 1),
+		version(6, "c", 1, 7), // same timestamp, greater ID
+	})
+
+	require.Equal(t, BucketUsage{
+		Bytes:         42,
+		Objects:       2,
+		Versions:      4,
+		DeleteMarkers: 2,
+	}, usage)
+
+	require.True(t, UsageOfVersions(nil).IsZero())
+	require.True(t, usage.Sub(usage).IsZero())
+}
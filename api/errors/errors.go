@@ -49,6 +49,12 @@ const (
 	ErrInvalidMetadataDirective
 	ErrInvalidTaggingDirective
 	ErrInvalidCopyDest
+	ErrInvalidRenameDest
+	ErrRenameDestinationExists
+	ErrInvalidWriteOffset
+	ErrUploadOffsetMismatch
+	ErrUploadChecksumMismatch
+	ErrUnsupportedMediaType
 	ErrInvalidPolicyDocument
 	ErrInvalidObjectState
 	ErrMalformedXML
@@ -118,6 +124,7 @@ const (
 	ErrMaximumExpires
 	ErrSlowDown
 	ErrInvalidPrefixMarker
+	ErrInvalidVersionIDMarker
 	ErrBadRequest
 	ErrKeyTooLongError
 	ErrInvalidBucketObjectLockConfiguration
@@ -155,6 +162,9 @@ const (
 
 	ErrNoAccessKey
 	ErrInvalidToken
+	ErrExpiredToken
+	ErrInvalidIdentityToken
+	ErrIDPRejectedClaim
 
 	// Bucket notification related errors.
 	ErrNotificationNotEnabled
@@ -285,6 +295,43 @@ var errorCodes = errorCodeMap{
 		Description:    "This copy request is illegal because it is trying to copy an object to itself without changing the object's metadata, storage class, website redirect location or encryption attributes.",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	ErrInvalidRenameDest: {
+		ErrCode:        ErrInvalidRenameDest,
+		Code:           "InvalidRequest",
+		Description:    "This rename request is illegal because the destination is the same as the source, is inside the source prefix or only one of them is a prefix.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrRenameDestinationExists: {
+		ErrCode:        ErrRenameDestinationExists,
+		Code:           "DestinationAlreadyExists",
+		Description:    "The destination prefix of the rename request already contains objects.",
+		HTTPStatusCode: http.StatusConflict,
+	},
+	ErrInvalidWriteOffset: {
+		ErrCode:        ErrInvalidWriteOffset,
+		Code:           "InvalidWriteOffset",
+		Description:    "The write offset value that you specified does not match the current object size.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrUploadOffsetMismatch: {
+		ErrCode:        ErrUploadOffsetMismatch,
+		Code:           "OffsetMismatch",
+		Description:    "The Upload-Offset value that you specified does not match the current offset of the upload.",
+		HTTPStatusCode: http.StatusConflict,
+	},
+	ErrUploadChecksumMismatch: {
+		ErrCode:     ErrUploadChecksumMismatch,
+		Code:        "ChecksumMismatch",
+		Description: "The Upload-Checksum value that you specified does not match the checksum of the received data.",
+		// tus protocol uses the custom status code
+		HTTPStatusCode: 460,
+	},
+	ErrUnsupportedMediaType: {
+		ErrCode:        ErrUnsupportedMediaType,
+		Code:           "UnsupportedMediaType",
+		Description:    "The Content-Type of the request is not supported.",
+		HTTPStatusCode: http.StatusUnsupportedMediaType,
+	},
 	ErrInvalidCopySource: {
 		ErrCode:        ErrInvalidCopySource,
 		Code:           "InvalidArgument",
@@ -777,6 +824,12 @@ var errorCodes = errorCodeMap{
 		Description:    "Invalid marker prefix combination",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	ErrInvalidVersionIDMarker: {
+		ErrCode:        ErrInvalidVersionIDMarker,
+		Code:           "InvalidArgument",
+		Description:    "A version-id marker cannot be specified without a key marker.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 	ErrBadRequest: {
 		ErrCode:        ErrBadRequest,
 		Code:           "BadRequest",
@@ -1092,6 +1145,24 @@ var errorCodes = errorCodeMap{
 		Description:    "The security token included in the request is invalid",
 		HTTPStatusCode: http.StatusForbidden,
 	},
+	ErrExpiredToken: {
+		ErrCode:        ErrExpiredToken,
+		Code:           "ExpiredToken",
+		Description:    "The provided token has expired",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidIdentityToken: {
+		ErrCode:        ErrInvalidIdentityToken,
+		Code:           "InvalidIdentityToken",
+		Description:    "The web identity token that was passed could not be validated",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrIDPRejectedClaim: {
+		ErrCode:        ErrIDPRejectedClaim,
+		Code:           "IDPRejectedClaim",
+		Description:    "The web identity token claims don't allow to assume the role",
+		HTTPStatusCode: http.StatusForbidden,
+	},
 
 	// S3 extensions.
 	ErrContentSHA256Mismatch: {
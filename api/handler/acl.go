@@ -19,6 +19,7 @@ import (
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/errors"
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/layer"
+	"github.com/TrueCloudLab/frostfs-s3-gw/audit"
 	"github.com/TrueCloudLab/frostfs-sdk-go/eacl"
 	"github.com/TrueCloudLab/frostfs-sdk-go/object"
 	oid "github.com/TrueCloudLab/frostfs-sdk-go/object/id"
@@ -235,17 +236,26 @@ func (h *handler) PutBucketACLHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if _, err = h.updateBucketACL(r, astBucket, bktInfo, token); err != nil {
+	prevACL, _, err := h.updateBucketACL(r, astBucket, bktInfo, token)
+	if err != nil {
 		h.logAndSendError(w, "could not update bucket acl", reqInfo, err)
 		return
 	}
+
+	h.audit(r.Context(), &audit.Event{
+		Before: auditSummary(h.encodeBucketACL(bktInfo.Name, prevACL)),
+		After:  auditSummary(list),
+	})
+
 	w.WriteHeader(http.StatusOK)
 }
 
-func (h *handler) updateBucketACL(r *http.Request, astChild *ast, bktInfo *data.BucketInfo, sessionToken *session.Container) (bool, error) {
+// updateBucketACL merges the ast with the current bucket eACL and puts the result if it's changed.
+// The eACL before the update is returned.
+func (h *handler) updateBucketACL(r *http.Request, astChild *ast, bktInfo *data.BucketInfo, sessionToken *session.Container) (*layer.BucketACL, bool, error) {
 	bucketACL, err := h.obj.GetBucketACL(r.Context(), bktInfo)
 	if err != nil {
-		return false, fmt.Errorf("could not get bucket eacl: %w", err)
+		return nil, false, fmt.Errorf("could not get bucket eacl: %w", err)
 	}
 
 	parentAst := tableToAst(bucketACL.EACL, bktInfo.Name)
@@ -259,12 +269,12 @@ func (h *handler) updateBucketACL(r *http.Request, astChild *ast, bktInfo *data.
 
 	resAst, updated := mergeAst(parentAst, astChild)
 	if !updated {
-		return false, nil
+		return bucketACL, false, nil
 	}
 
 	table, err := astToTable(resAst)
 	if err != nil {
-		return false, fmt.Errorf("could not translate ast to table: %w", err)
+		return nil, false, fmt.Errorf("could not translate ast to table: %w", err)
 	}
 
 	p := &layer.PutBucketACLParams{
@@ -274,10 +284,10 @@ func (h *handler) updateBucketACL(r *http.Request, astChild *ast, bktInfo *data.
 	}
 
 	if err = h.obj.PutBucketACL(r.Context(), p); err != nil {
-		return false, fmt.Errorf("could not put bucket acl: %w", err)
+		return nil, false, fmt.Errorf("could not put bucket acl: %w", err)
 	}
 
-	return true, nil
+	return bucketACL, true, nil
 }
 
 func (h *handler) GetObjectACLHandler(w http.ResponseWriter, r *http.Request) {
@@ -369,7 +379,7 @@ func (h *handler) PutObjectACLHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	updated, err := h.updateBucketACL(r, astObject, bktInfo, token)
+	prevACL, updated, err := h.updateBucketACL(r, astObject, bktInfo, token)
 	if err != nil {
 		h.logAndSendError(w, "could not update bucket acl", reqInfo, err)
 		return
@@ -385,6 +395,13 @@ func (h *handler) PutObjectACLHandler(w http.ResponseWriter, r *http.Request) {
 			h.log.Error("couldn't send notification: %w", zap.Error(err))
 		}
 	}
+
+	h.audit(r.Context(), &audit.Event{
+		VersionID: objInfo.VersionID(),
+		Before:    auditSummary(h.encodeObjectACL(prevACL, reqInfo.BucketName, objInfo.VersionID())),
+		After:     auditSummary(list),
+	})
+
 	w.WriteHeader(http.StatusOK)
 }
 
@@ -453,10 +470,16 @@ func (h *handler) PutBucketPolicyHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if _, err = h.updateBucketACL(r, astPolicy, bktInfo, token); err != nil {
+	prevACL, _, err := h.updateBucketACL(r, astPolicy, bktInfo, token)
+	if err != nil {
 		h.logAndSendError(w, "could not update bucket acl", reqInfo, err)
 		return
 	}
+
+	h.audit(r.Context(), &audit.Event{
+		Before: auditSummary(astToPolicy(tableToAst(prevACL.EACL, reqInfo.BucketName))),
+		After:  auditSummary(bktPolicy),
+	})
 }
 
 func parseACLHeaders(header http.Header, key *keys.PublicKey) (*AccessControlPolicy, error) {
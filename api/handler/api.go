@@ -29,6 +29,13 @@ type (
 		DefaultMaxAge      int
 		NotificatorEnabled bool
 		CopiesNumber       uint32
+		// STS is nil if STS API is disabled.
+		STS *STSConfig
+		// Auditor is nil if audit log is disabled.
+		Auditor Auditor
+		// TusTokenLifetime is the lifetime of tokens to access resumable uploads,
+		// DefaultTusTokenLifetime is used if it's not positive.
+		TusTokenLifetime time.Duration
 	}
 
 	PlacementPolicy interface {
@@ -42,6 +49,8 @@ const (
 	DefaultPolicy = "REP 3"
 	// DefaultCopiesNumber is a default number of object copies that is enough to consider put successful if it's not set in config.
 	DefaultCopiesNumber uint32 = 0
+	// DefaultTusTokenLifetime is a default lifetime of tokens to access resumable uploads.
+	DefaultTusTokenLifetime = 24 * time.Hour
 )
 
 var _ api.Handler = (*handler)(nil)
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/TrueCloudLab/frostfs-s3-gw/api"
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/errors"
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/layer"
+	"github.com/TrueCloudLab/frostfs-s3-gw/audit"
+	"go.uber.org/zap"
+)
+
+// AppendObjectHandler appends the request payload to the object as S3 Express One Zone does:
+// the X-Amz-Write-Offset-Bytes header must be equal to the current size of the object.
+// The object is created if it doesn't exist and the offset is zero.
+func (h *handler) AppendObjectHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	offset, err := strconv.ParseUint(r.Header.Get(api.AmzWriteOffsetBytes), 10, 64)
+	if err != nil {
+		h.logAndSendError(w, "invalid write offset", reqInfo, errors.GetAPIError(errors.ErrInvalidArgument))
+		return
+	}
+	if r.ContentLength <= 0 {
+		h.logAndSendError(w, "empty or chunked append payload", reqInfo, errors.GetAPIError(errors.ErrMissingContentLength))
+		return
+	}
+
+	if containsACLHeaders(r) || len(r.Header.Get(api.AmzTagging)) != 0 {
+		h.logAndSendError(w, "acl and tagging of appended objects", reqInfo, errors.GetAPIError(errors.ErrNotImplemented))
+		return
+	}
+
+	encryptionParams, err := formEncryptionParams(r)
+	if err != nil {
+		h.logAndSendError(w, "invalid sse headers", reqInfo, err)
+		return
+	}
+	if encryptionParams.Enabled() {
+		h.logAndSendError(w, "encryption of appended objects", reqInfo, errors.GetAPIError(errors.ErrNotImplemented))
+		return
+	}
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket objInfo", reqInfo, err)
+		return
+	}
+
+	metadata := parseMetadata(r)
+	if contentType := r.Header.Get(api.ContentType); len(contentType) > 0 {
+		metadata[api.ContentType] = contentType
+	}
+
+	copiesNumber, err := getCopiesNumberOrDefault(metadata, h.cfg.CopiesNumber)
+	if err != nil {
+		h.logAndSendError(w, "invalid copies number", reqInfo, err)
+		return
+	}
+
+	settings, err := h.obj.GetBucketSettings(r.Context(), bktInfo)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket settings", reqInfo, err)
+		return
+	}
+
+	extendedObjInfo, err := h.obj.AppendObject(r.Context(), &layer.AppendObjectParams{
+		BktInfo:      bktInfo,
+		Object:       reqInfo.ObjectName,
+		Offset:       offset,
+		Size:         r.ContentLength,
+		Reader:       r.Body,
+		Header:       metadata,
+		CopiesNumber: copiesNumber,
+	})
+	if err != nil {
+		_, err2 := io.Copy(io.Discard, r.Body)
+		err3 := r.Body.Close()
+		h.logAndSendError(w, "could not append object", reqInfo, err, zap.Errors("body close errors", []error{err2, err3}))
+		return
+	}
+	objInfo := extendedObjInfo.ObjectInfo
+
+	s := &SendNotificationParams{
+		Event:            EventObjectCreatedPut,
+		NotificationInfo: data.NotificationInfoFromObject(objInfo),
+		BktInfo:          bktInfo,
+		ReqInfo:          reqInfo,
+	}
+	if err = h.sendNotifications(r.Context(), s); err != nil {
+		h.log.Error("couldn't send notification: %w", zap.Error(err))
+	}
+
+	if settings.VersioningEnabled() {
+		w.Header().Set(api.AmzVersionID, objInfo.VersionID())
+	}
+
+	h.audit(r.Context(), &audit.Event{
+		Object:    objInfo.Name,
+		VersionID: objInfo.VersionID(),
+		Before:    "size=" + strconv.FormatUint(offset, 10),
+		After:     "etag=" + objInfo.HashSum + ",size=" + strconv.FormatInt(objInfo.Size, 10),
+	})
+
+	w.Header().Set(api.ETag, objInfo.HashSum)
+	api.WriteSuccessResponseHeadersOnly(w)
+}
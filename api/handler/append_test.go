@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TrueCloudLab/frostfs-s3-gw/api"
+	apiErrors "github.com/TrueCloudLab/frostfs-s3-gw/api/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppendObject(t *testing.T) {
+	tc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-for-append", "log"
+	createTestBucket(tc, bktName)
+
+	appendObject(t, tc, bktName, objName, "0", "abc", http.StatusOK)
+	appendObject(t, tc, bktName, objName, "3", "def", http.StatusOK)
+
+	w := appendObject(t, tc, bktName, objName, "5", "ghi", http.StatusBadRequest)
+	assertS3Error(t, w, apiErrors.GetAPIError(apiErrors.ErrInvalidWriteOffset))
+	appendObject(t, tc, bktName, objName, "", "ghi", http.StatusBadRequest)
+
+	w, r := prepareTestRequest(tc, bktName, objName, nil)
+	tc.Handler().HeadObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Equal(t, "6", w.Header().Get(api.ContentLength))
+	for key := range w.Header() {
+		require.NotContains(t, key, "Appended")
+	}
+
+	require.Equal(t, "abcdef", string(getObjectRange(t, tc, bktName, objName, 0, 5)))
+	require.Equal(t, "cd", string(getObjectRange(t, tc, bktName, objName, 2, 3)))
+
+	// the copy of the appended object gets the combined payload
+	copyObject(t, tc, bktName, objName, "copy", CopyMeta{}, http.StatusOK)
+	require.Equal(t, "abcdef", string(getObjectRange(t, tc, bktName, "copy", 0, 5)))
+	appendObject(t, tc, bktName, "copy", "6", "ghi", http.StatusOK)
+	require.Equal(t, "abcdefghi", string(getObjectRange(t, tc, bktName, "copy", 0, 8)))
+	require.Equal(t, "abcdef", string(getObjectRange(t, tc, bktName, objName, 0, 5)))
+}
+
+func appendObject(t *testing.T, tc *handlerContext, bktName, objName, offset, content string, status int) *httptest.ResponseRecorder {
+	w, r := prepareTestPayloadRequest(tc, bktName, objName, bytes.NewReader([]byte(content)))
+	if len(offset) != 0 {
+		r.Header.Set(api.AmzWriteOffsetBytes, offset)
+	}
+	r.ContentLength = int64(len(content))
+	tc.Handler().AppendObjectHandler(w, r)
+	assertStatus(t, w, status)
+	if status == http.StatusOK {
+		require.NotEmpty(t, w.Header().Get(api.ETag))
+	}
+	return w
+}
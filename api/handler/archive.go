@@ -0,0 +1,278 @@
+package handler
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/TrueCloudLab/frostfs-s3-gw/api"
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/errors"
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/layer"
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/layer/encryption"
+	"go.uber.org/zap"
+)
+
+const (
+	archiveZip   = "zip"
+	archiveTarGz = "tar.gz"
+
+	// archivePageSize is the number of objects listed at once while the archive is streamed.
+	archivePageSize = 1000
+)
+
+// archiveWriter writes entries of the archive streamed to the client.
+type archiveWriter interface {
+	// CreateEntry adds the file to the archive, the content is written to the returned writer.
+	CreateEntry(name string, objInfo *data.ObjectInfo, size int64) (io.Writer, error)
+	Close() error
+}
+
+type zipArchive struct {
+	w *zip.Writer
+}
+
+func (a *zipArchive) CreateEntry(name string, objInfo *data.ObjectInfo, _ int64) (io.Writer, error) {
+	// zip writer switches to Zip64 format by itself when the entry or the archive exceeds 4GiB
+	return a.w.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Store,
+		Modified: objInfo.Created,
+	})
+}
+
+func (a *zipArchive) Close() error {
+	return a.w.Close()
+}
+
+type tarGzArchive struct {
+	gz *gzip.Writer
+	w  *tar.Writer
+}
+
+func (a *tarGzArchive) CreateEntry(name string, objInfo *data.ObjectInfo, size int64) (io.Writer, error) {
+	if err := a.w.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Size:     size,
+		Mode:     0644,
+		ModTime:  objInfo.Created,
+		Format:   tar.FormatPAX,
+	}); err != nil {
+		return nil, err
+	}
+	return a.w, nil
+}
+
+func (a *tarGzArchive) Close() error {
+	if err := a.w.Close(); err != nil {
+		return err
+	}
+	return a.gz.Close()
+}
+
+// lazyEntry creates the archive entry on the first write, so the object which can't be read
+// (e.g. because of access rules) doesn't leave the empty entry in the archive.
+type lazyEntry struct {
+	create func() (io.Writer, error)
+	w      io.Writer
+}
+
+func (e *lazyEntry) Write(p []byte) (int, error) {
+	if err := e.open(); err != nil {
+		return 0, err
+	}
+	return e.w.Write(p)
+}
+
+func (e *lazyEntry) open() (err error) {
+	if e.w == nil {
+		e.w, err = e.create()
+	}
+	return err
+}
+
+func newArchiveWriter(format string, w io.Writer) archiveWriter {
+	if format == archiveZip {
+		return &zipArchive{w: zip.NewWriter(w)}
+	}
+
+	gz := gzip.NewWriter(w)
+	return &tarGzArchive{gz: gz, w: tar.NewWriter(gz)}
+}
+
+// GetArchiveHandler streams objects with the specified prefix as ZIP or tar.gz archive.
+// The archive is built on the fly, objects which can't be read by the requester are skipped.
+func (h *handler) GetArchiveHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+	queryValues := reqInfo.URL.Query()
+
+	format := queryValues.Get("archive")
+	if format != archiveZip && format != archiveTarGz {
+		h.logAndSendError(w, "unknown archive format", reqInfo, errors.GetAPIError(errors.ErrInvalidArgument))
+		return
+	}
+	prefix := queryValues.Get("prefix")
+
+	encryptionParams, err := formEncryptionParams(r)
+	if err != nil {
+		h.logAndSendError(w, "invalid sse headers", reqInfo, err)
+		return
+	}
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	params := &layer.ListObjectsParamsV2{
+		ListObjectsParamsCommon: layer.ListObjectsParamsCommon{
+			BktInfo: bktInfo,
+			MaxKeys: archivePageSize,
+			Prefix:  prefix,
+		},
+	}
+
+	// the first page is listed before the response is started to report errors properly
+	list, err := h.obj.ListObjectsV2(r.Context(), params)
+	if err != nil {
+		h.logAndSendError(w, "could not list objects", reqInfo, err)
+		return
+	}
+
+	w.Header().Set(api.ContentType, archiveContentType(format))
+	w.Header().Set(api.ContentDisposition, fmt.Sprintf("attachment; filename=%q", archiveName(bktInfo.Name, prefix)+"."+format))
+	w.WriteHeader(http.StatusOK)
+
+	archive := newArchiveWriter(format, w)
+	// entries are named relative to the "directory" of the prefix
+	base := prefix[:strings.LastIndex(prefix, "/")+1]
+	var count int
+
+	for {
+		for _, objInfo := range list.Objects {
+			name, ok := archiveEntryName(objInfo.Name, base)
+			if !ok {
+				continue
+			}
+
+			added, err := h.writeArchiveEntry(r, archive, bktInfo, objInfo, name, encryptionParams)
+			if err != nil {
+				// the response is already started, so the client gets the broken archive
+				h.log.Error("could not write archive entry", zap.String("request_id", reqInfo.RequestID),
+					zap.String("bucket", bktInfo.Name), zap.String("object", objInfo.Name), zap.Error(err))
+				return
+			}
+			if added {
+				count++
+			}
+		}
+
+		if !list.IsTruncated {
+			break
+		}
+
+		params.ContinuationToken = list.NextContinuationToken
+		if list, err = h.obj.ListObjectsV2(r.Context(), params); err != nil {
+			h.log.Error("could not list objects", zap.String("request_id", reqInfo.RequestID),
+				zap.String("bucket", bktInfo.Name), zap.Error(err))
+			return
+		}
+	}
+
+	if err = archive.Close(); err != nil {
+		h.log.Error("could not close archive", zap.String("request_id", reqInfo.RequestID), zap.Error(err))
+		return
+	}
+
+	h.log.Debug("archive is sent",
+		zap.String("bucket", bktInfo.Name),
+		zap.String("prefix", prefix),
+		zap.String("format", format),
+		zap.Int("count", count))
+}
+
+// writeArchiveEntry writes the object to the archive. Returns false if the object is skipped
+// because it can't be read by the requester. The error means the archive is broken.
+func (h *handler) writeArchiveEntry(r *http.Request, archive archiveWriter, bktInfo *data.BucketInfo,
+	objInfo *data.ObjectInfo, name string, encryptionParams encryption.Params) (bool, error) {
+	reqInfo := api.GetReqInfo(r.Context())
+	encInfo := layer.FormEncryptionInfo(objInfo.Headers)
+
+	var enc encryption.Params
+	size := objInfo.Size
+	if encInfo.Enabled {
+		if err := encryptionParams.MatchObjectEncryption(encInfo); err != nil {
+			h.log.Debug("skip encrypted object", zap.String("request_id", reqInfo.RequestID),
+				zap.String("object", objInfo.Name), zap.Error(err))
+			return false, nil
+		}
+		enc = encryptionParams
+
+		var err error
+		if size, err = strconv.ParseInt(objInfo.Headers[layer.AttributeDecryptedSize], 10, 64); err != nil {
+			h.log.Debug("skip object with invalid decrypted size", zap.String("request_id", reqInfo.RequestID),
+				zap.String("object", objInfo.Name), zap.Error(err))
+			return false, nil
+		}
+	}
+
+	entry := &lazyEntry{create: func() (io.Writer, error) {
+		return archive.CreateEntry(name, objInfo, size)
+	}}
+
+	err := h.obj.GetObject(r.Context(), &layer.GetObjectParams{
+		ObjectInfo: objInfo,
+		Writer:     entry,
+		BucketInfo: bktInfo,
+		Encryption: enc,
+	})
+	if err != nil {
+		if entry.w != nil {
+			return false, err
+		}
+		// payload reader is initialized before anything is written, so access errors come here
+		h.log.Debug("skip object which can't be read", zap.String("request_id", reqInfo.RequestID),
+			zap.String("object", objInfo.Name), zap.Error(err))
+		return false, nil
+	}
+
+	// empty objects are never written
+	return true, entry.open()
+}
+
+// archiveEntryName forms the name of the archive entry from the object name.
+// Objects which can't be safely extracted are skipped.
+func archiveEntryName(objName, base string) (string, bool) {
+	name := strings.TrimPrefix(objName, base)
+	if len(name) == 0 || strings.HasSuffix(name, "/") || path.IsAbs(name) {
+		return "", false
+	}
+	for _, elem := range strings.Split(name, "/") {
+		if elem == ".." {
+			return "", false
+		}
+	}
+	return name, true
+}
+
+func archiveName(bktName, prefix string) string {
+	if name := path.Base(strings.TrimSuffix(prefix, "/")); name != "." && name != "/" && len(prefix) != 0 {
+		return name
+	}
+	return bktName
+}
+
+func archiveContentType(format string) string {
+	if format == archiveZip {
+		return "application/zip"
+	}
+	return "application/gzip"
+}
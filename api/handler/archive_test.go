@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetArchive(t *testing.T) {
+	tc := prepareHandlerContext(t)
+
+	bktName := "bucket-for-archive"
+	createTestBucket(tc, bktName)
+	putObjectContent(tc, bktName, "dir/a", "content-a")
+	putObjectContent(tc, bktName, "dir/sub/b", "content-b")
+	putObjectContent(tc, bktName, "dir/empty", "")
+	putObjectContent(tc, bktName, "dir/../escape", "escape")
+	putObjectContent(tc, bktName, "other", "other")
+
+	expected := map[string]string{"a": "content-a", "sub/b": "content-b", "empty": ""}
+
+	w := getArchive(t, tc, bktName, archiveZip, "dir/", nil)
+	require.Equal(t, `attachment; filename="dir.zip"`, w.Header().Get("Content-Disposition"))
+	require.Equal(t, expected, readZip(t, w.Body.Bytes()))
+
+	w = getArchive(t, tc, bktName, archiveTarGz, "dir/", nil)
+	require.Equal(t, expected, readTarGz(t, w.Body.Bytes()))
+
+	w = getArchive(t, tc, bktName, archiveZip, "", nil)
+	require.Equal(t, `attachment; filename="bucket-for-archive.zip"`, w.Header().Get("Content-Disposition"))
+	// the object which name escapes the archive root is skipped
+	require.Len(t, readZip(t, w.Body.Bytes()), 4)
+
+	query := make(url.Values)
+	query.Set("archive", "rar")
+	w, r := prepareTestFullRequest(tc, bktName, "", query, nil)
+	tc.Handler().GetArchiveHandler(w, r)
+	assertStatus(t, w, http.StatusBadRequest)
+}
+
+func TestGetArchiveEncrypted(t *testing.T) {
+	tc := prepareHandlerContext(t)
+
+	bktName := "bucket-for-archive-encrypted"
+	createTestBucket(tc, bktName)
+	putObjectContent(tc, bktName, "plain", "plain")
+	putEncryptedObject(t, tc, bktName, "encrypted", "encrypted")
+
+	// encrypted objects are skipped without the key
+	w := getArchive(t, tc, bktName, archiveZip, "", nil)
+	require.Equal(t, map[string]string{"plain": "plain"}, readZip(t, w.Body.Bytes()))
+
+	w = getArchive(t, tc, bktName, archiveTarGz, "", setEncryptHeaders)
+	require.Equal(t, map[string]string{"plain": "plain", "encrypted": "encrypted"}, readTarGz(t, w.Body.Bytes()))
+}
+
+func TestArchiveEntryName(t *testing.T) {
+	for _, tc := range []struct {
+		object, base, name string
+		ok                 bool
+	}{
+		{object: "dir/a", base: "dir/", name: "a", ok: true},
+		{object: "dir/sub/a", base: "", name: "dir/sub/a", ok: true},
+		{object: "dir/", base: "dir/"},
+		{object: "dir/sub/", base: "dir/"},
+		{object: "/a", base: ""},
+		{object: "dir/../a", base: ""},
+	} {
+		name, ok := archiveEntryName(tc.object, tc.base)
+		require.Equal(t, tc.ok, ok, tc.object)
+		require.Equal(t, tc.name, name, tc.object)
+	}
+}
+
+func getArchive(t *testing.T, tc *handlerContext, bktName, format, prefix string, modify func(*http.Request)) *httptest.ResponseRecorder {
+	query := make(url.Values)
+	query.Set("archive", format)
+	query.Set("prefix", prefix)
+
+	w, r := prepareTestFullRequest(tc, bktName, "", query, nil)
+	if modify != nil {
+		modify(r)
+	}
+	tc.Handler().GetArchiveHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	return w
+}
+
+func readZip(t *testing.T, data []byte) map[string]string {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+
+	res := make(map[string]string)
+	for _, file := range reader.File {
+		rc, err := file.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+		res[file.Name] = string(content)
+	}
+	return res
+}
+
+func readTarGz(t *testing.T, data []byte) map[string]string {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	reader := tar.NewReader(gz)
+
+	res := make(map[string]string)
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		content, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		res[header.Name] = string(content)
+	}
+	return res
+}
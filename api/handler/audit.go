@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/TrueCloudLab/frostfs-s3-gw/api"
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/layer"
+	"github.com/TrueCloudLab/frostfs-s3-gw/audit"
+	"github.com/TrueCloudLab/frostfs-sdk-go/bearer"
+)
+
+// Auditor records mutating operations to the audit log.
+type Auditor interface {
+	Audit(ctx context.Context, event *audit.Event)
+}
+
+// audit fills the event with request details and records it if audit log is enabled.
+// Bucket and object are taken from the request if they aren't set in the event.
+func (h *handler) audit(ctx context.Context, event *audit.Event) {
+	if h.cfg.Auditor == nil {
+		return
+	}
+
+	if reqInfo := api.GetReqInfo(ctx); reqInfo != nil {
+		event.API = reqInfo.API
+		event.RequestID = reqInfo.RequestID
+		event.SourceIP = reqInfo.RemoteHost
+		if event.Bucket == "" {
+			event.Bucket = reqInfo.BucketName
+		}
+		if event.Object == "" {
+			event.Object = reqInfo.ObjectName
+		}
+	}
+
+	if box, err := layer.GetBoxData(ctx); err == nil && box.Gate.BearerToken != nil {
+		event.Actor = bearer.ResolveIssuer(*box.Gate.BearerToken).EncodeToString()
+	}
+
+	h.cfg.Auditor.Audit(ctx, event)
+}
+
+// auditSummary encodes configuration to be used as Before or After field of the audit event.
+func auditSummary(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil || string(data) == "null" {
+		return ""
+	}
+	return string(data)
+}
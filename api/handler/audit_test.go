@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/TrueCloudLab/frostfs-s3-gw/api"
+	"github.com/TrueCloudLab/frostfs-s3-gw/audit"
+	"github.com/stretchr/testify/require"
+)
+
+type auditorMock struct {
+	events []*audit.Event
+}
+
+func (a *auditorMock) Audit(_ context.Context, event *audit.Event) {
+	a.events = append(a.events, event)
+}
+
+func TestAuditMutatingRequests(t *testing.T) {
+	hc := prepareHandlerContext(t)
+	auditor := &auditorMock{}
+	hc.h.cfg.Auditor = auditor
+
+	bktName, objName := "bucket-for-audit", "object"
+	createTestBucket(hc, bktName)
+
+	putBucketVersioning(t, hc, bktName, true)
+	require.Len(t, auditor.events, 1)
+	require.Equal(t, bktName, auditor.events[0].Bucket)
+	require.Equal(t, "versioning=Unversioned", auditor.events[0].Before)
+	require.Equal(t, "versioning=Enabled", auditor.events[0].After)
+	require.Equal(t, hc.owner.EncodeToString(), auditor.events[0].Actor)
+
+	putObject(t, hc, bktName, objName)
+	require.Len(t, auditor.events, 2)
+	require.Equal(t, objName, auditor.events[1].Object)
+	require.NotEmpty(t, auditor.events[1].VersionID)
+
+	_, isDeleteMarker := deleteObject(t, hc, bktName, objName, emptyVersion)
+	require.True(t, isDeleteMarker)
+	require.Len(t, auditor.events, 3)
+	require.Equal(t, objName, auditor.events[2].Object)
+	require.Contains(t, auditor.events[2].After, "delete_marker=")
+
+	// failed requests aren't audited
+	deleteBucket(t, hc, bktName, http.StatusConflict)
+	require.Len(t, auditor.events, 3)
+}
+
+func TestAuditConfigurationChanges(t *testing.T) {
+	hc := prepareHandlerContext(t)
+	auditor := &auditorMock{}
+	hc.h.cfg.Auditor = auditor
+
+	bktName, objName := "bucket-for-audit-config", "object"
+	box, _ := createAccessBox(t)
+	createBucket(t, hc, bktName, box)
+	putObject(t, hc, bktName, objName)
+	auditor.events = nil
+
+	putBucketACL(t, hc, bktName, box, map[string]string{api.AmzACL: "public-read"})
+	require.Len(t, auditor.events, 1)
+	require.NotEmpty(t, auditor.events[0].Before)
+	require.NotEmpty(t, auditor.events[0].After)
+	require.NotEqual(t, auditor.events[0].Before, auditor.events[0].After)
+
+	putObjectTagging(t, hc, bktName, objName, map[string]string{"key": "old"})
+	putObjectTagging(t, hc, bktName, objName, map[string]string{"key": "new"})
+	require.Len(t, auditor.events, 3)
+	require.Equal(t, objName, auditor.events[2].Object)
+	require.NotEmpty(t, auditor.events[2].VersionID)
+	require.Equal(t, `{"key":"old"}`, auditor.events[2].Before)
+	require.Equal(t, `{"key":"new"}`, auditor.events[2].After)
+
+	w, r := prepareTestRequest(hc, bktName, objName, nil)
+	hc.Handler().DeleteObjectTaggingHandler(w, r)
+	assertStatus(t, w, http.StatusNoContent)
+	require.Len(t, auditor.events, 4)
+	require.Equal(t, `{"key":"new"}`, auditor.events[3].Before)
+	require.Empty(t, auditor.events[3].After)
+}
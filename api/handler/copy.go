@@ -11,6 +11,7 @@ import (
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/errors"
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/layer"
+	"github.com/TrueCloudLab/frostfs-s3-gw/audit"
 	"github.com/TrueCloudLab/frostfs-sdk-go/session"
 	"go.uber.org/zap"
 )
@@ -182,6 +183,9 @@ func (h *handler) CopyObjectHandler(w http.ResponseWriter, r *http.Request) {
 		Header:      metadata,
 		Encryption:  encryptionParams,
 		CopiesNuber: copiesNumber,
+		// object ACL is bound to the object id, so the payload can't be shared with the source
+		RewritePayload:  containsACL,
+		ReplaceMetadata: isCopyingToItself(reqInfo, srcBucket, srcObject) && args.MetadataDirective == replaceDirective,
 	}
 
 	params.Lock, err = formObjectLock(r.Context(), dstBktInfo, settings.LockConfiguration, r.Header)
@@ -253,13 +257,25 @@ func (h *handler) CopyObjectHandler(w http.ResponseWriter, r *http.Request) {
 		h.log.Error("couldn't send notification: %w", zap.Error(err))
 	}
 
+	h.audit(r.Context(), &audit.Event{
+		Bucket:    dstObjInfo.Bucket,
+		Object:    dstObjInfo.Name,
+		VersionID: dstObjInfo.VersionID(),
+		Before:    "source=" + srcBucket + "/" + srcObject,
+		After:     "etag=" + dstObjInfo.HashSum,
+	})
+
 	if encryptionParams.Enabled() {
 		addSSECHeaders(w.Header(), r.Header)
 	}
 }
 
+func isCopyingToItself(reqInfo *api.ReqInfo, srcBucket string, srcObject string) bool {
+	return reqInfo.BucketName == srcBucket && reqInfo.ObjectName == srcObject
+}
+
 func isCopyingToItselfForbidden(reqInfo *api.ReqInfo, srcBucket string, srcObject string, settings *data.BucketSettings, args *copyObjectArgs) bool {
-	if reqInfo.BucketName != srcBucket || reqInfo.ObjectName != srcObject {
+	if !isCopyingToItself(reqInfo, srcBucket, srcObject) {
 		return false
 	}
 
@@ -63,6 +63,61 @@ func TestCopyToItself(t *testing.T) {
 	copyObject(t, tc, bktName, objName, objName, copyMeta, http.StatusOK)
 }
 
+func TestCopyToItselfMetadataOnly(t *testing.T) {
+	tc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-for-copy-metadata", "object-for-copy-metadata"
+	createTestBucket(tc, bktName)
+	putObjectContent(tc, bktName, objName, "content")
+	objects := listOIDsFromMockedFrostFS(t, tc, bktName)
+
+	copyMeta := CopyMeta{MetadataDirective: replaceDirective, Metadata: map[string]string{"key": "val"}}
+	copyObject(t, tc, bktName, objName, objName, copyMeta, http.StatusOK)
+	require.ElementsMatch(t, objects, listOIDsFromMockedFrostFS(t, tc, bktName))
+	checkObjectMetadata(t, tc, bktName, objName, "key", "val")
+
+	putBucketVersioning(t, tc, bktName, true)
+	putObjectContent(tc, bktName, objName, "content")
+	objects = listOIDsFromMockedFrostFS(t, tc, bktName)
+
+	// metadata of the latest version is updated in place
+	copyMeta.Metadata["key"] = "new"
+	copyObject(t, tc, bktName, objName, objName, copyMeta, http.StatusOK)
+	require.ElementsMatch(t, objects, listOIDsFromMockedFrostFS(t, tc, bktName))
+	require.Len(t, listVersions(t, tc, bktName).Version, 2)
+	checkObjectMetadata(t, tc, bktName, objName, "key", "new")
+	require.Equal(t, "tent", string(getObjectRange(t, tc, bktName, objName, 3, 6)))
+}
+
+func TestCopyEncryptedToItselfMetadataOnly(t *testing.T) {
+	tc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-for-copy-encrypted", "object-for-copy-encrypted"
+	createTestBucket(tc, bktName)
+	putEncryptedObject(t, tc, bktName, objName, "content")
+	objects := listOIDsFromMockedFrostFS(t, tc, bktName)
+
+	w, r := prepareTestRequest(tc, bktName, objName, nil)
+	r.Header.Set(api.AmzCopySource, bktName+"/"+objName)
+	r.Header.Set(api.AmzMetadataDirective, replaceDirective)
+	r.Header.Set(api.MetadataPrefix+"key", "val")
+	setEncryptHeaders(r)
+	tc.Handler().CopyObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.ElementsMatch(t, objects, listOIDsFromMockedFrostFS(t, tc, bktName))
+
+	content, header := getEncryptedObject(t, tc, bktName, objName)
+	require.Equal(t, "content", string(content))
+	require.Equal(t, []string{"val"}, header[api.MetadataPrefix+"key"])
+}
+
+func checkObjectMetadata(t *testing.T, tc *handlerContext, bktName, objName, key, val string) {
+	w, r := prepareTestRequest(tc, bktName, objName, nil)
+	tc.Handler().HeadObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Equal(t, []string{val}, w.Header()[api.MetadataPrefix+key])
+}
+
 func copyObject(t *testing.T, tc *handlerContext, bktName, fromObject, toObject string, copyMeta CopyMeta, statusCode int) {
 	w, r := prepareTestRequest(tc, bktName, toObject, nil)
 	r.Header.Set(api.AmzCopySource, bktName+"/"+fromObject)
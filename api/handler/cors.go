@@ -8,6 +8,7 @@ import (
 	"github.com/TrueCloudLab/frostfs-s3-gw/api"
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/errors"
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/layer"
+	"github.com/TrueCloudLab/frostfs-s3-gw/audit"
 	"go.uber.org/zap"
 )
 
@@ -53,11 +54,20 @@ func (h *handler) PutBucketCorsHandler(w http.ResponseWriter, r *http.Request) {
 		CopiesNumber: h.cfg.CopiesNumber,
 	}
 
+	prevCors, _ := h.obj.GetBucketCORS(r.Context(), bktInfo)
+
 	if err = h.obj.PutBucketCORS(r.Context(), p); err != nil {
 		h.logAndSendError(w, "could not put cors configuration", reqInfo, err)
 		return
 	}
 
+	// the new configuration is parsed by the layer and then taken from the cache
+	cors, _ := h.obj.GetBucketCORS(r.Context(), bktInfo)
+	h.audit(r.Context(), &audit.Event{
+		Before: auditSummary(prevCors),
+		After:  auditSummary(cors),
+	})
+
 	api.WriteSuccessResponseHeadersOnly(w)
 }
 
@@ -70,10 +80,15 @@ func (h *handler) DeleteBucketCorsHandler(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	prevCors, _ := h.obj.GetBucketCORS(r.Context(), bktInfo)
+
 	if err = h.obj.DeleteBucketCORS(r.Context(), bktInfo); err != nil {
 		h.logAndSendError(w, "could not delete cors", reqInfo, err)
+		return
 	}
 
+	h.audit(r.Context(), &audit.Event{Before: auditSummary(prevCors)})
+
 	w.WriteHeader(http.StatusNoContent)
 }
 
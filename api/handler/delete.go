@@ -10,6 +10,7 @@ import (
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/errors"
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/layer"
+	"github.com/TrueCloudLab/frostfs-s3-gw/audit"
 	apistatus "github.com/TrueCloudLab/frostfs-sdk-go/client/status"
 	oid "github.com/TrueCloudLab/frostfs-sdk-go/object/id"
 	"github.com/TrueCloudLab/frostfs-sdk-go/session"
@@ -128,6 +129,8 @@ func (h *handler) DeleteObjectHandler(w http.ResponseWriter, r *http.Request) {
 		h.log.Error("couldn't send notification: %w", zap.Error(err))
 	}
 
+	h.audit(r.Context(), deleteAuditEvent(deletedObject))
+
 	if deletedObject.VersionID != "" {
 		w.Header().Set(api.AmzVersionID, deletedObject.VersionID)
 	}
@@ -232,7 +235,12 @@ func (h *handler) DeleteMultipleObjectsHandler(w http.ResponseWriter, r *http.Re
 				VersionID: obj.VersionID,
 			})
 			errs = append(errs, obj.Error)
-		} else if !requested.Quiet {
+			continue
+		}
+
+		h.audit(r.Context(), deleteAuditEvent(obj))
+
+		if !requested.Quiet {
 			deletedObj := DeletedObject{
 				ObjectIdentifier: ObjectIdentifier{
 					ObjectName: obj.Name,
@@ -280,6 +288,21 @@ func (h *handler) DeleteBucketHandler(w http.ResponseWriter, r *http.Request) {
 		SessionToken: sessionToken,
 	}); err != nil {
 		h.logAndSendError(w, "couldn't delete bucket", reqInfo, err)
+		return
 	}
+
+	h.audit(r.Context(), &audit.Event{Before: "container=" + bktInfo.CID.EncodeToString()})
+
 	w.WriteHeader(http.StatusNoContent)
 }
+
+func deleteAuditEvent(obj *layer.VersionedObject) *audit.Event {
+	event := &audit.Event{
+		Object:    obj.Name,
+		VersionID: obj.VersionID,
+	}
+	if obj.DeleteMarkVersion != "" {
+		event.After = "delete_marker=" + obj.DeleteMarkVersion
+	}
+	return event
+}
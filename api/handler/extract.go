@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/TrueCloudLab/frostfs-s3-gw/api"
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/errors"
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/layer"
+	"github.com/TrueCloudLab/frostfs-s3-gw/audit"
+	"go.uber.org/zap"
+)
+
+// snowballAutoExtract is the metadata key (without prefix) of the AmzSnowballAutoExtract header.
+const snowballAutoExtract = "snowball-auto-extract"
+
+func isAutoExtract(r *http.Request) bool {
+	autoExtract, _ := strconv.ParseBool(r.Header.Get(api.AmzSnowballAutoExtract))
+	return autoExtract
+}
+
+// extractArchive stores every file of the uploaded tar (or tar.gz) archive as a separate object
+// under the "directory" of the request object name, as AWS Snowball does.
+func (h *handler) extractArchive(w http.ResponseWriter, r *http.Request, params *layer.PutObjectParams) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	delete(params.Header, snowballAutoExtract)
+	// content type is detected for every extracted object
+	delete(params.Header, api.ContentType)
+
+	result, err := h.obj.ExtractArchive(r.Context(), &layer.ExtractArchiveParams{
+		BktInfo:      params.BktInfo,
+		Prefix:       reqInfo.ObjectName[:strings.LastIndex(reqInfo.ObjectName, "/")+1],
+		Reader:       params.Reader,
+		Header:       params.Header,
+		Lock:         params.Lock,
+		Encryption:   params.Encryption,
+		CopiesNumber: params.CopiesNumber,
+	})
+	if err != nil {
+		_, err2 := io.Copy(io.Discard, r.Body)
+		err3 := r.Body.Close()
+		h.logAndSendError(w, "could not extract archive", reqInfo, err, zap.Errors("body close errors", []error{err2, err3}))
+		return
+	}
+
+	response := &ExtractArchiveResponse{
+		Extracted: result.Extracted,
+		Size:      result.Size,
+		Errors:    make([]ExtractError, 0, len(result.Errors)),
+	}
+	for _, e := range result.Errors {
+		code := "BadRequest"
+		if s3err, ok := e.Err.(errors.Error); ok {
+			code = s3err.Code
+		}
+		response.Errors = append(response.Errors, ExtractError{
+			Key:     e.Name,
+			Code:    code,
+			Message: e.Err.Error(),
+		})
+	}
+
+	h.log.Info("archive is extracted",
+		zap.String("bucket", params.BktInfo.Name),
+		zap.String("object", reqInfo.ObjectName),
+		zap.Int("extracted", result.Extracted),
+		zap.Int("errors", len(result.Errors)))
+
+	s := &SendNotificationParams{
+		Event: EventObjectCreatedExtract,
+		NotificationInfo: &data.NotificationInfo{
+			Name: reqInfo.ObjectName,
+			Size: result.Size,
+		},
+		BktInfo: params.BktInfo,
+		ReqInfo: reqInfo,
+	}
+	if err = h.sendNotifications(r.Context(), s); err != nil {
+		h.log.Error("couldn't send notification: %w", zap.Error(err))
+	}
+
+	h.audit(r.Context(), &audit.Event{
+		Object: reqInfo.ObjectName,
+		After:  "extracted=" + strconv.Itoa(result.Extracted),
+	})
+
+	if params.Encryption.Enabled() {
+		addSSECHeaders(w.Header(), r.Header)
+	}
+
+	if err = api.EncodeToResponse(w, response); err != nil {
+		h.logAndSendError(w, "something went wrong", reqInfo, err)
+	}
+}
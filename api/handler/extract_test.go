@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"archive/tar"
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/TrueCloudLab/frostfs-s3-gw/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPutAutoExtractArchive(t *testing.T) {
+	tc := prepareHandlerContext(t)
+
+	bktName := "bucket-for-extract"
+	createTestBucket(tc, bktName)
+
+	buf := new(bytes.Buffer)
+	tw := tar.NewWriter(buf)
+	for _, entry := range []struct{ name, content string }{
+		{name: "a.txt", content: "content-a"},
+		{name: "sub/b", content: "content-b"},
+		{name: "../c", content: "content-c"},
+	} {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: entry.name, Size: int64(len(entry.content))}))
+		_, err := tw.Write([]byte(entry.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+
+	w, r := prepareTestPayloadRequest(tc, bktName, "dir/archive.tar", buf)
+	r.Header.Set(api.AmzSnowballAutoExtract, "true")
+	r.Header.Set(api.MetadataPrefix+"key", "val")
+	tc.Handler().PutObjectHandler(w, r)
+
+	response := &ExtractArchiveResponse{}
+	readResponse(t, w, http.StatusOK, response)
+	require.Equal(t, 2, response.Extracted)
+	require.EqualValues(t, 18, response.Size)
+	require.Len(t, response.Errors, 1)
+	require.Equal(t, "../c", response.Errors[0].Key)
+
+	headObject(t, tc, bktName, "dir/archive.tar", nil, http.StatusNotFound)
+	require.Equal(t, "tent-a", string(getObjectRange(t, tc, bktName, "dir/a.txt", 3, 8)))
+	checkObjectMetadata(t, tc, bktName, "dir/sub/b", "key", "val")
+
+	w, r = prepareTestPayloadRequest(tc, bktName, "broken.tar", bytes.NewReader([]byte("not an archive")))
+	r.Header.Set(api.AmzSnowballAutoExtract, "true")
+	tc.Handler().PutObjectHandler(w, r)
+	assertStatus(t, w, http.StatusBadRequest)
+}
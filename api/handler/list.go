@@ -2,9 +2,11 @@ package handler
 
 import (
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/TrueCloudLab/frostfs-s3-gw/api"
+	"github.com/TrueCloudLab/frostfs-s3-gw/creds/tokens"
 	"github.com/TrueCloudLab/frostfs-sdk-go/user"
 )
 
@@ -35,7 +37,13 @@ func (h *handler) ListBucketsHandler(w http.ResponseWriter, r *http.Request) {
 		},
 	}
 
+	// temporary credentials see only buckets of their scope
+	claims, _ := r.Context().Value(api.SessionClaims).(*tokens.SessionClaims)
+
 	for _, item := range list {
+		if claims != nil && !strings.HasPrefix(item.Name, claims.BucketPrefix) {
+			continue
+		}
 		res.Buckets.Buckets = append(res.Buckets.Buckets, Bucket{
 			Name:         item.Name,
 			CreationDate: item.Created.UTC().Format(time.RFC3339),
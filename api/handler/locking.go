@@ -12,6 +12,7 @@ import (
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
 	apiErrors "github.com/TrueCloudLab/frostfs-s3-gw/api/errors"
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/layer"
+	"github.com/TrueCloudLab/frostfs-s3-gw/audit"
 )
 
 const (
@@ -70,6 +71,11 @@ func (h *handler) PutBucketObjectLockConfigHandler(w http.ResponseWriter, r *htt
 		h.logAndSendError(w, "couldn't put bucket settings", reqInfo, err)
 		return
 	}
+
+	h.audit(r.Context(), &audit.Event{
+		Before: auditSummary(settings.LockConfiguration),
+		After:  auditSummary(lockingConf),
+	})
 }
 
 func (h *handler) GetBucketObjectLockConfigHandler(w http.ResponseWriter, r *http.Request) {
@@ -150,6 +156,11 @@ func (h *handler) PutObjectLegalHoldHandler(w http.ResponseWriter, r *http.Reque
 		h.logAndSendError(w, "couldn't head put legal hold", reqInfo, err)
 		return
 	}
+
+	h.audit(r.Context(), &audit.Event{
+		VersionID: p.ObjVersion.VersionID,
+		After:     "legal_hold=" + legalHold.Status,
+	})
 }
 
 func (h *handler) GetObjectLegalHoldHandler(w http.ResponseWriter, r *http.Request) {
@@ -229,6 +240,11 @@ func (h *handler) PutObjectRetentionHandler(w http.ResponseWriter, r *http.Reque
 		h.logAndSendError(w, "couldn't put legal hold", reqInfo, err)
 		return
 	}
+
+	h.audit(r.Context(), &audit.Event{
+		VersionID: p.ObjVersion.VersionID,
+		After:     auditSummary(retention),
+	})
 }
 
 func (h *handler) GetObjectRetentionHandler(w http.ResponseWriter, r *http.Request) {
@@ -12,6 +12,7 @@ import (
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/errors"
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/layer"
+	"github.com/TrueCloudLab/frostfs-s3-gw/audit"
 	"github.com/TrueCloudLab/frostfs-sdk-go/bearer"
 	"github.com/google/uuid"
 )
@@ -40,6 +41,7 @@ const (
 	EventObjectCreatedPut                             = "s3:ObjectCreated:Put"
 	EventObjectCreatedPost                            = "s3:ObjectCreated:Post"
 	EventObjectCreatedCopy                            = "s3:ObjectCreated:Copy"
+	EventObjectCreatedExtract                         = "s3:ObjectCreated:Extract"
 	EventReducedRedundancyLostObject                  = "s3:ReducedRedundancyLostObject"
 	EventObjectCreatedCompleteMultipartUpload         = "s3:ObjectCreated:CompleteMultipartUpload"
 	EventObjectRemoved                                = "s3:ObjectRemoved:*"
@@ -71,6 +73,7 @@ var validEvents = map[string]struct{}{
 	EventObjectCreatedPut:                             {},
 	EventObjectCreatedPost:                            {},
 	EventObjectCreatedCopy:                            {},
+	EventObjectCreatedExtract:                         {},
 	EventObjectCreatedCompleteMultipartUpload:         {},
 	EventObjectRemoved:                                {},
 	EventObjectRemovedDelete:                          {},
@@ -121,10 +124,17 @@ func (h *handler) PutBucketNotificationHandler(w http.ResponseWriter, r *http.Re
 		CopiesNumber:  h.cfg.CopiesNumber,
 	}
 
+	prevConf, _ := h.obj.GetBucketNotificationConfiguration(r.Context(), bktInfo)
+
 	if err = h.obj.PutBucketNotificationConfiguration(r.Context(), p); err != nil {
 		h.logAndSendError(w, "couldn't put bucket configuration", reqInfo, err)
 		return
 	}
+
+	h.audit(r.Context(), &audit.Event{
+		Before: auditSummary(prevConf),
+		After:  auditSummary(conf),
+	})
 }
 
 func (h *handler) GetBucketNotificationHandler(w http.ResponseWriter, r *http.Request) {
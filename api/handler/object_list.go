@@ -4,15 +4,18 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/TrueCloudLab/frostfs-s3-gw/api"
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/errors"
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/layer"
-	oid "github.com/TrueCloudLab/frostfs-sdk-go/object/id"
 )
 
+// tagFilterQuery is the ListObjectsV2 extension parameter which filters objects by tags.
+const tagFilterQuery = "tag"
+
 // ListObjectsV1Handler handles objects listing requests for API version 1.
 func (h *handler) ListObjectsV1Handler(w http.ResponseWriter, r *http.Request) {
 	reqInfo := api.GetReqInfo(r.Context())
@@ -139,6 +142,11 @@ func parseListObjectsArgsV2(reqInfo *api.ReqInfo) (*layer.ListObjectsParamsV2, e
 
 	res.StartAfter = queryValues.Get("start-after")
 	res.FetchOwner, _ = strconv.ParseBool(queryValues.Get("fetch-owner"))
+
+	if res.Tags, err = parseObjectFilters(queryValues[tagFilterQuery], nil); err != nil {
+		return nil, err
+	}
+
 	return &res, nil
 }
 
@@ -165,15 +173,36 @@ func parseListObjectArgs(reqInfo *api.ReqInfo) (*layer.ListObjectsParamsCommon,
 
 func parseContinuationToken(queryValues url.Values) (string, error) {
 	if val, ok := queryValues["continuation-token"]; ok {
-		var objID oid.ID
-		if err := objID.DecodeString(val[0]); err != nil {
-			return "", errors.GetAPIError(errors.ErrIncorrectContinuationToken)
+		if _, err := layer.DecodeContinuationToken(val[0]); err != nil {
+			return "", err
 		}
 		return val[0], nil
 	}
 	return "", nil
 }
 
+// parseObjectFilters parses 'key=value' (equality) and 'key' (existence) predicates.
+func parseObjectFilters(values []string, normalizeKey func(string) string) ([]layer.ObjectFilter, error) {
+	filters := make([]layer.ObjectFilter, 0, len(values))
+	for _, value := range values {
+		var filter layer.ObjectFilter
+		if index := strings.IndexByte(value, '='); index >= 0 {
+			filter.Key, filter.Value = value[:index], value[index+1:]
+		} else {
+			filter.Key, filter.Exists = value, true
+		}
+		if len(filter.Key) == 0 {
+			return nil, errors.GetAPIError(errors.ErrInvalidArgument)
+		}
+		if normalizeKey != nil {
+			filter.Key = normalizeKey(filter.Key)
+		}
+		filters = append(filters, filter)
+	}
+
+	return filters, nil
+}
+
 func fillPrefixes(src []string, encode string) []CommonPrefix {
 	var dst []CommonPrefix
 	for _, obj := range src {
@@ -249,11 +278,15 @@ func parseListObjectVersionsRequest(reqInfo *api.ReqInfo) (*layer.ListObjectVers
 	}
 
 	res.Prefix = queryValues.Get("prefix")
-	res.KeyMarker = queryValues.Get("marker")
+	res.KeyMarker = queryValues.Get("key-marker")
 	res.Delimiter = queryValues.Get("delimiter")
 	res.Encode = queryValues.Get("encoding-type")
 	res.VersionIDMarker = queryValues.Get("version-id-marker")
 
+	if res.VersionIDMarker != "" && res.KeyMarker == "" {
+		return nil, errors.GetAPIError(errors.ErrInvalidVersionIDMarker)
+	}
+
 	return &res, nil
 }
 
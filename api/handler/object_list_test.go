@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/layer"
 	"github.com/stretchr/testify/require"
 )
 
@@ -30,7 +31,7 @@ func TestParseContinuationToken(t *testing.T) {
 	})
 
 	t.Run("valid token", func(t *testing.T) {
-		tokenStr := "75BTT5Z9o79XuKdUeGqvQbqDnxu6qWcR5EhxW8BXFf8t"
+		tokenStr := layer.EncodeContinuationToken("dir/object")
 		var queryValues = map[string][]string{
 			"continuation-token": {tokenStr},
 		}
@@ -56,6 +57,49 @@ func TestListObjectNullVersions(t *testing.T) {
 	require.Equal(t, data.UnversionedObjectVersionID, result.Version[1].VersionID)
 }
 
+func TestListObjectsV2ByTags(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName := "bucket-for-listing-by-tags"
+	createTestBucket(hc, bktName)
+
+	putObjectContent(hc, bktName, "a", "content")
+	putObjectContent(hc, bktName, "dir/b", "content")
+	putObjectContent(hc, bktName, "c", "content")
+	putObjectTagging(t, hc, bktName, "a", map[string]string{"project": "x", "stage": "prod"})
+	putObjectTagging(t, hc, bktName, "dir/b", map[string]string{"project": "x"})
+	putObjectTagging(t, hc, bktName, "c", map[string]string{"project": "y"})
+
+	listByTags := func(delimiter string, tags ...string) []string {
+		query := prepareCommonListObjectsQuery("", delimiter, -1)
+		query["tag"] = tags
+		w, r := prepareTestFullRequest(hc, bktName, "", query, nil)
+		hc.Handler().ListObjectsV2Handler(w, r)
+		res := &ListObjectsV2Response{}
+		parseTestResponse(t, w, res)
+
+		var keys []string
+		for _, prefix := range res.CommonPrefixes {
+			keys = append(keys, prefix.Prefix)
+		}
+		for _, obj := range res.Contents {
+			keys = append(keys, obj.Key)
+		}
+		return keys
+	}
+
+	require.Equal(t, []string{"a", "dir/b"}, listByTags("", "project=x"))
+	require.Equal(t, []string{"dir/", "a"}, listByTags("/", "project=x"))
+	require.Equal(t, []string{"a"}, listByTags("", "project=x", "stage"))
+	require.Empty(t, listByTags("", "project=z"))
+
+	query := prepareCommonListObjectsQuery("", "", -1)
+	query.Set("tag", "=x")
+	w, r := prepareTestFullRequest(hc, bktName, "", query, nil)
+	hc.Handler().ListObjectsV2Handler(w, r)
+	assertStatus(t, w, http.StatusBadRequest)
+}
+
 func TestS3CompatibilityBucketListV2BothContinuationTokenStartAfter(t *testing.T) {
 	tc := prepareHandlerContext(t)
 
@@ -195,3 +239,64 @@ func listObjectsV1(t *testing.T, tc *handlerContext, bktName, prefix, delimiter,
 	parseTestResponse(t, w, res)
 	return res
 }
+
+func TestListObjectVersionsPagination(t *testing.T) {
+	hc := prepareHandlerContext(t)
+
+	bktName := "bucket-versions-pagination"
+	createTestBucket(hc, bktName)
+	putBucketVersioning(t, hc, bktName, true)
+
+	for _, objName := range []string{"a", "b", "dir/c", "dir/d"} {
+		putObjectContent(hc, bktName, objName, "content")
+		putObjectContent(hc, bktName, objName, "content2")
+	}
+
+	type entry struct{ key, version string }
+	var all []entry
+	for _, ver := range listVersions(t, hc, bktName).Version {
+		all = append(all, entry{ver.Key, ver.VersionID})
+	}
+	require.Len(t, all, 8)
+
+	for _, maxKeys := range []int{1, 3, 5} {
+		var (
+			listed                     []entry
+			keyMarker, versionIDMarker string
+		)
+		for {
+			query := url.Values{"max-keys": {strconv.Itoa(maxKeys)}}
+			if keyMarker != "" {
+				query.Set("key-marker", keyMarker)
+			}
+			if versionIDMarker != "" {
+				query.Set("version-id-marker", versionIDMarker)
+			}
+
+			w, r := prepareTestFullRequest(hc, bktName, "", query, nil)
+			hc.Handler().ListBucketObjectVersionsHandler(w, r)
+			assertStatus(t, w, http.StatusOK)
+			res := &ListObjectsVersionsResponse{}
+			parseTestResponse(t, w, res)
+
+			require.Equal(t, keyMarker, res.KeyMarker)
+			require.LessOrEqual(t, len(res.Version), maxKeys)
+			for _, ver := range res.Version {
+				listed = append(listed, entry{ver.Key, ver.VersionID})
+			}
+
+			if !res.IsTruncated {
+				break
+			}
+			keyMarker, versionIDMarker = res.NextKeyMarker, res.NextVersionIDMarker
+		}
+		require.Equal(t, all, listed)
+	}
+
+	t.Run("version id marker without key marker", func(t *testing.T) {
+		query := url.Values{"version-id-marker": {all[0].version}}
+		w, r := prepareTestFullRequest(hc, bktName, "", query, nil)
+		hc.Handler().ListBucketObjectVersionsHandler(w, r)
+		assertStatus(t, w, http.StatusBadRequest)
+	})
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/errors"
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/layer"
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/layer/encryption"
+	"github.com/TrueCloudLab/frostfs-s3-gw/audit"
 	"github.com/TrueCloudLab/frostfs-s3-gw/creds/accessbox"
 	"github.com/TrueCloudLab/frostfs-sdk-go/eacl"
 	"github.com/TrueCloudLab/frostfs-sdk-go/session"
@@ -246,6 +247,15 @@ func (h *handler) PutObjectHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if isAutoExtract(r) {
+		if containsACL || tagSet != nil {
+			h.logAndSendError(w, "acl and tagging of extracted objects", reqInfo, errors.GetAPIError(errors.ErrNotImplemented))
+			return
+		}
+		h.extractArchive(w, r, params)
+		return
+	}
+
 	extendedObjInfo, err := h.obj.PutObject(r.Context(), params)
 	if err != nil {
 		_, err2 := io.Copy(io.Discard, r.Body)
@@ -308,6 +318,12 @@ func (h *handler) PutObjectHandler(w http.ResponseWriter, r *http.Request) {
 		addSSECHeaders(w.Header(), r.Header)
 	}
 
+	h.audit(r.Context(), &audit.Event{
+		Object:    objInfo.Name,
+		VersionID: objInfo.VersionID(),
+		After:     "etag=" + objInfo.HashSum,
+	})
+
 	w.Header().Set(api.ETag, objInfo.HashSum)
 	api.WriteSuccessResponseHeadersOnly(w)
 }
@@ -494,6 +510,13 @@ func (h *handler) PostObject(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	h.audit(r.Context(), &audit.Event{
+		Bucket:    objInfo.Bucket,
+		Object:    objInfo.Name,
+		VersionID: objInfo.VersionID(),
+		After:     "etag=" + objInfo.HashSum,
+	})
+
 	if settings, err := h.obj.GetBucketSettings(r.Context(), bktInfo); err != nil {
 		h.log.Warn("couldn't get bucket versioning", zap.String("bucket name", reqInfo.BucketName), zap.Error(err))
 	} else if settings.VersioningEnabled() {
@@ -748,6 +771,8 @@ func (h *handler) CreateBucketHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	h.audit(r.Context(), &audit.Event{After: "container=" + bktInfo.CID.EncodeToString()})
+
 	api.WriteSuccessResponseHeadersOnly(w)
 }
 
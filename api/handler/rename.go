@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/TrueCloudLab/frostfs-s3-gw/api"
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/errors"
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/layer"
+	"github.com/TrueCloudLab/frostfs-s3-gw/audit"
+	"go.uber.org/zap"
+)
+
+// RenameObjectHandler moves the object (or the prefix if names end with '/') specified
+// in X-Frostfs-Rename-Source header to the request object name inside the same bucket.
+func (h *handler) RenameObjectHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	src, err := url.PathUnescape(r.Header.Get(api.FrostFSRenameSource))
+	if err != nil {
+		h.logAndSendError(w, "invalid rename source", reqInfo, errors.GetAPIError(errors.ErrInvalidRequest))
+		return
+	}
+	src = strings.TrimPrefix(src, "/")
+	if len(src) == 0 {
+		h.logAndSendError(w, "empty rename source", reqInfo, errors.GetAPIError(errors.ErrInvalidRequest))
+		return
+	}
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	settings, err := h.obj.GetBucketSettings(r.Context(), bktInfo)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket settings", reqInfo, err)
+		return
+	}
+
+	additional := []zap.Field{zap.String("src_object_name", src)}
+	renamed, err := h.obj.RenameObject(r.Context(), &layer.RenameObjectParams{
+		BktInfo:   bktInfo,
+		Settings:  settings,
+		SrcObject: src,
+		DstObject: reqInfo.ObjectName,
+	})
+	if err != nil {
+		h.logAndSendError(w, "couldn't rename object", reqInfo, err, additional...)
+		return
+	}
+
+	response := &RenameObjectResponse{Objects: make([]RenamedObject, 0, len(renamed))}
+	for _, obj := range renamed {
+		response.Objects = append(response.Objects, RenamedObject{
+			Source:    obj.Source,
+			Key:       obj.ObjectInfo.Name,
+			ETag:      obj.ObjectInfo.HashSum,
+			VersionID: obj.ObjectInfo.VersionID(),
+		})
+	}
+
+	if err = api.EncodeToResponse(w, response); err != nil {
+		h.logAndSendError(w, "something went wrong", reqInfo, err, additional...)
+		return
+	}
+
+	h.log.Info("object is renamed",
+		zap.String("bucket", bktInfo.Name),
+		zap.String("object", reqInfo.ObjectName),
+		zap.String("source", src),
+		zap.Int("count", len(renamed)))
+
+	for _, obj := range renamed {
+		h.sendRenameNotifications(r, bktInfo, obj)
+	}
+
+	h.audit(r.Context(), &audit.Event{
+		Bucket: bktInfo.Name,
+		Object: reqInfo.ObjectName,
+		Before: "source=" + bktInfo.Name + "/" + src,
+	})
+}
+
+// sendRenameNotifications reports renaming as removal of the source object and copying to the new name.
+func (h *handler) sendRenameNotifications(r *http.Request, bktInfo *data.BucketInfo, obj *layer.RenamedObject) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	removed := &SendNotificationParams{
+		Event: EventObjectRemovedDelete,
+		NotificationInfo: &data.NotificationInfo{
+			Name:    obj.Source,
+			Version: obj.ObjectInfo.VersionID(),
+		},
+		BktInfo: bktInfo,
+		ReqInfo: reqInfo,
+	}
+	if err := h.sendNotifications(r.Context(), removed); err != nil {
+		h.log.Error("couldn't send notification: %w", zap.Error(err))
+	}
+
+	created := &SendNotificationParams{
+		Event:            EventObjectCreatedCopy,
+		NotificationInfo: data.NotificationInfoFromObject(obj.ObjectInfo),
+		BktInfo:          bktInfo,
+		ReqInfo:          reqInfo,
+	}
+	if err := h.sendNotifications(r.Context(), created); err != nil {
+		h.log.Error("couldn't send notification: %w", zap.Error(err))
+	}
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/TrueCloudLab/frostfs-s3-gw/api"
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenameObject(t *testing.T) {
+	tc := prepareHandlerContext(t)
+
+	bktName := "bucket-for-rename"
+	createTestBucket(tc, bktName)
+	putObjectContent(tc, bktName, "obj", "content")
+	putObjectContent(tc, bktName, "dir/a", "a")
+	putObjectContent(tc, bktName, "dir/sub/b", "b")
+
+	response := renameObject(t, tc, bktName, "obj", "renamed", http.StatusOK)
+	require.Len(t, response.Objects, 1)
+	require.Equal(t, "obj", response.Objects[0].Source)
+	require.Equal(t, "renamed", response.Objects[0].Key)
+
+	headObject(t, tc, bktName, "obj", nil, http.StatusNotFound)
+	headObject(t, tc, bktName, "renamed", nil, http.StatusOK)
+	require.Equal(t, "tent", string(getObjectRange(t, tc, bktName, "renamed", 3, 6)))
+
+	response = renameObject(t, tc, bktName, "dir/", "moved/", http.StatusOK)
+	require.Len(t, response.Objects, 2)
+	require.Equal(t, "moved/a", response.Objects[0].Key)
+	require.Equal(t, "moved/sub/b", response.Objects[1].Key)
+
+	list := listObjectsV2(t, tc, bktName, "", "", "", "", -1)
+	require.Len(t, list.Contents, 3)
+	require.Equal(t, "moved/a", list.Contents[0].Key)
+	require.Equal(t, "moved/sub/b", list.Contents[1].Key)
+	require.Equal(t, "renamed", list.Contents[2].Key)
+
+	renameObject(t, tc, bktName, "dir/", "other/", http.StatusNotFound)
+	renameObject(t, tc, bktName, "moved/", "moved/sub/", http.StatusBadRequest)
+	renameObject(t, tc, bktName, "renamed", "moved/", http.StatusBadRequest)
+
+	putObjectContent(tc, bktName, "other/c", "c")
+	renameObject(t, tc, bktName, "moved/", "other/", http.StatusConflict)
+}
+
+func TestRenameLockedObject(t *testing.T) {
+	tc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-for-rename-lock", "object-for-rename-lock"
+	createTestBucketWithLock(tc, bktName, &data.ObjectLockConfiguration{ObjectLockEnabled: enabledValue})
+	putObjectContent(tc, bktName, objName, "content")
+
+	putObjectLegalHold(tc, bktName, objName, legalHoldOn)
+	renameObject(t, tc, bktName, objName, "renamed", http.StatusForbidden)
+
+	putObjectLegalHold(tc, bktName, objName, legalHoldOff)
+	renameObject(t, tc, bktName, objName, "renamed", http.StatusOK)
+}
+
+func renameObject(t *testing.T, tc *handlerContext, bktName, fromObject, toObject string, status int) *RenameObjectResponse {
+	w, r := prepareTestRequest(tc, bktName, toObject, nil)
+	r.Header.Set(api.FrostFSRenameSource, fromObject)
+	tc.Handler().RenameObjectHandler(w, r)
+
+	response := &RenameObjectResponse{}
+	if status == http.StatusOK {
+		readResponse(t, w, status, response)
+	} else {
+		assertStatus(t, w, status)
+	}
+	return response
+}
@@ -150,6 +150,35 @@ type CopyObjectResponse struct {
 	ETag         string   // md5sum of the copied object.
 }
 
+// RenameObjectResponse container returns objects moved by the rename request.
+type RenameObjectResponse struct {
+	XMLName xml.Name        `xml:"http://s3.amazonaws.com/doc/2006-03-01/ RenameObjectResult" json:"-"`
+	Objects []RenamedObject `xml:"Object"`
+}
+
+// RenamedObject contains the previous and the new name of the renamed object.
+type RenamedObject struct {
+	Source    string
+	Key       string
+	ETag      string
+	VersionID string `xml:"VersionId,omitempty"`
+}
+
+// ExtractArchiveResponse contains the result of the upload of the auto-extracted archive.
+type ExtractArchiveResponse struct {
+	XMLName   xml.Name       `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ExtractArchiveResult" json:"-"`
+	Extracted int            `xml:"Extracted"`
+	Size      int64          `xml:"Size"`
+	Errors    []ExtractError `xml:"Error,omitempty"`
+}
+
+// ExtractError describes the archive entry which isn't extracted.
+type ExtractError struct {
+	Key     string
+	Code    string
+	Message string
+}
+
 // ListObjectsVersionsResponse is a response of ListBucketObjectVersionsHandler.
 type ListObjectsVersionsResponse struct {
 	XMLName             xml.Name                `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListVersionsResult" json:"-"`
@@ -191,6 +220,66 @@ type Tag struct {
 	Value string
 }
 
+// AssumeRoleResponse contains result of STS AssumeRole.
+type AssumeRoleResponse struct {
+	XMLName          xml.Name            `xml:"https://sts.amazonaws.com/doc/2011-06-15/ AssumeRoleResponse" json:"-"`
+	Result           AssumeRoleResult    `xml:"AssumeRoleResult"`
+	ResponseMetadata STSResponseMetadata `xml:"ResponseMetadata"`
+}
+
+// AssumeRoleResult contains temporary credentials and assumed role user.
+type AssumeRoleResult struct {
+	AssumedRoleUser AssumedRoleUser `xml:"AssumedRoleUser"`
+	Credentials     STSCredentials  `xml:"Credentials"`
+}
+
+// AssumedRoleUser contains identifiers of temporary credentials.
+type AssumedRoleUser struct {
+	Arn           string `xml:"Arn"`
+	AssumedRoleID string `xml:"AssumedRoleId"`
+}
+
+// AssumeRoleWithWebIdentityResponse contains result of STS AssumeRoleWithWebIdentity.
+type AssumeRoleWithWebIdentityResponse struct {
+	XMLName          xml.Name                        `xml:"https://sts.amazonaws.com/doc/2011-06-15/ AssumeRoleWithWebIdentityResponse" json:"-"`
+	Result           AssumeRoleWithWebIdentityResult `xml:"AssumeRoleWithWebIdentityResult"`
+	ResponseMetadata STSResponseMetadata             `xml:"ResponseMetadata"`
+}
+
+// AssumeRoleWithWebIdentityResult contains temporary credentials and web identity info.
+type AssumeRoleWithWebIdentityResult struct {
+	SubjectFromWebIdentityToken string          `xml:"SubjectFromWebIdentityToken"`
+	Audience                    string          `xml:"Audience"`
+	Provider                    string          `xml:"Provider"`
+	AssumedRoleUser             AssumedRoleUser `xml:"AssumedRoleUser"`
+	Credentials                 STSCredentials  `xml:"Credentials"`
+}
+
+// GetSessionTokenResponse contains result of STS GetSessionToken.
+type GetSessionTokenResponse struct {
+	XMLName          xml.Name              `xml:"https://sts.amazonaws.com/doc/2011-06-15/ GetSessionTokenResponse" json:"-"`
+	Result           GetSessionTokenResult `xml:"GetSessionTokenResult"`
+	ResponseMetadata STSResponseMetadata   `xml:"ResponseMetadata"`
+}
+
+// GetSessionTokenResult contains temporary credentials.
+type GetSessionTokenResult struct {
+	Credentials STSCredentials `xml:"Credentials"`
+}
+
+// STSCredentials contains temporary credentials issued by STS.
+type STSCredentials struct {
+	AccessKeyID     string `xml:"AccessKeyId"`
+	SecretAccessKey string `xml:"SecretAccessKey"`
+	SessionToken    string `xml:"SessionToken"`
+	Expiration      string `xml:"Expiration"`
+}
+
+// STSResponseMetadata contains request id of STS request.
+type STSResponseMetadata struct {
+	RequestID string `xml:"RequestId"`
+}
+
 // MarshalXML -- StringMap marshals into XML.
 func (s StringMap) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
 	tokens := []xml.Token{start}
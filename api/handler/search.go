@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/TrueCloudLab/frostfs-s3-gw/api"
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/errors"
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/layer"
+)
+
+const searchMetadataQuery = "metadata"
+
+// SearchObjectsHandler lists latest versions of objects which match all user metadata and tag filters:
+// 'metadata' and 'tag' query parameters in the form of 'key=value' (equality) or 'key' (existence).
+// Other parameters (including the tag filter) and the response are the same as ListObjectsV2 ones.
+func (h *handler) SearchObjectsHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+	listParams, err := parseListObjectsArgsV2(reqInfo)
+	if err != nil {
+		h.logAndSendError(w, "failed to parse arguments", reqInfo, err)
+		return
+	}
+	if len(listParams.Delimiter) != 0 {
+		h.logAndSendError(w, "delimiter isn't supported by search", reqInfo, errors.GetAPIError(errors.ErrNotImplemented))
+		return
+	}
+
+	queryValues := reqInfo.URL.Query()
+	p := &layer.SearchObjectsParams{
+		Prefix:            listParams.Prefix,
+		MaxKeys:           listParams.MaxKeys,
+		StartAfter:        listParams.StartAfter,
+		ContinuationToken: listParams.ContinuationToken,
+		Tags:              listParams.Tags,
+	}
+
+	// user metadata keys are stored in lower case
+	if p.Metadata, err = parseObjectFilters(queryValues[searchMetadataQuery], strings.ToLower); err != nil {
+		h.logAndSendError(w, "invalid metadata filter", reqInfo, err)
+		return
+	}
+	if len(p.Metadata) == 0 && len(p.Tags) == 0 {
+		h.logAndSendError(w, "missing search filters", reqInfo, errors.GetAPIError(errors.ErrInvalidArgument))
+		return
+	}
+
+	if listParams.BktInfo, err = h.getBucketAndCheckOwner(r, reqInfo.BucketName); err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+	p.BktInfo = listParams.BktInfo
+
+	list, err := h.obj.SearchObjects(r.Context(), p)
+	if err != nil {
+		h.logAndSendError(w, "could not search objects", reqInfo, err)
+		return
+	}
+
+	if err = api.EncodeToResponse(w, encodeV2(listParams, list)); err != nil {
+		h.logAndSendError(w, "something went wrong", reqInfo, err)
+	}
+}
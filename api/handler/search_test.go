@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/TrueCloudLab/frostfs-s3-gw/api"
+	apiErrors "github.com/TrueCloudLab/frostfs-s3-gw/api/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchObjects(t *testing.T) {
+	tc := prepareHandlerContext(t)
+
+	bktName := "bucket-for-search"
+	createTestBucket(tc, bktName)
+
+	for name, project := range map[string]string{"a": "X", "b": "X", "c": "Y"} {
+		w, r := prepareTestPayloadRequest(tc, bktName, name, bytes.NewReader([]byte(name)))
+		r.Header.Set(api.MetadataPrefix+"Project", project)
+		tc.Handler().PutObjectHandler(w, r)
+		assertStatus(t, w, http.StatusOK)
+	}
+	putObjectTagging(t, tc, bktName, "b", map[string]string{"Stage": "prod"})
+
+	res := searchObjects(t, tc, bktName, url.Values{"metadata": []string{"Project=X"}}, http.StatusOK)
+	require.Len(t, res.Contents, 2)
+	require.Equal(t, "a", res.Contents[0].Key)
+	require.Equal(t, "b", res.Contents[1].Key)
+
+	res = searchObjects(t, tc, bktName, url.Values{"metadata": []string{"project"}, "tag": []string{"Stage=prod"}}, http.StatusOK)
+	require.Len(t, res.Contents, 1)
+	require.Equal(t, "b", res.Contents[0].Key)
+
+	// tag keys are case-sensitive
+	res = searchObjects(t, tc, bktName, url.Values{"tag": []string{"stage"}}, http.StatusOK)
+	require.Empty(t, res.Contents)
+
+	res = searchObjects(t, tc, bktName, url.Values{"metadata": []string{"project=X"}, "max-keys": []string{"1"}}, http.StatusOK)
+	require.True(t, res.IsTruncated)
+	require.Len(t, res.Contents, 1)
+
+	searchObjects(t, tc, bktName, url.Values{}, http.StatusBadRequest)
+	searchObjects(t, tc, bktName, url.Values{"tag": []string{"=prod"}}, http.StatusBadRequest)
+
+	query := url.Values{"metadata": []string{"project=X"}, "delimiter": []string{"/"}}
+	w, r := prepareTestFullRequest(tc, bktName, "", query, nil)
+	tc.Handler().SearchObjectsHandler(w, r)
+	assertS3Error(t, w, apiErrors.GetAPIError(apiErrors.ErrNotImplemented))
+}
+
+func searchObjects(t *testing.T, tc *handlerContext, bktName string, query url.Values, status int) *ListObjectsV2Response {
+	query.Set("search", "")
+	w, r := prepareTestFullRequest(tc, bktName, "", query, nil)
+	tc.Handler().SearchObjectsHandler(w, r)
+	assertStatus(t, w, status)
+	if status != http.StatusOK {
+		return nil
+	}
+
+	res := &ListObjectsV2Response{}
+	parseTestResponse(t, w, res)
+	return res
+}
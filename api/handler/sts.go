@@ -0,0 +1,391 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TrueCloudLab/frostfs-s3-gw/api"
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/auth/oidc"
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/errors"
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/layer"
+	"github.com/TrueCloudLab/frostfs-s3-gw/authmate"
+	"github.com/TrueCloudLab/frostfs-s3-gw/creds/accessbox"
+	"github.com/TrueCloudLab/frostfs-s3-gw/creds/tokens"
+	"github.com/TrueCloudLab/frostfs-sdk-go/bearer"
+	cid "github.com/TrueCloudLab/frostfs-sdk-go/container/id"
+	"github.com/TrueCloudLab/frostfs-sdk-go/user"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"go.uber.org/zap"
+)
+
+type (
+	// STSConfig contains parameters of temporary credentials issued via STS API.
+	STSConfig struct {
+		Issuer          CredentialsIssuer
+		Roles           STSRoles
+		Container       cid.ID
+		Owner           user.ID
+		GatesPublicKeys []*keys.PublicKey
+		DefaultDuration time.Duration
+		MaxDuration     time.Duration
+		// WebIdentity is nil if AssumeRoleWithWebIdentity is disabled.
+		WebIdentity WebIdentityVerifier
+	}
+
+	// WebIdentityVerifier validates web identity tokens of trusted identity providers.
+	WebIdentityVerifier interface {
+		Verify(token string, now time.Time) (*oidc.Claims, error)
+	}
+
+	// CredentialsIssuer issues temporary credentials stored in FrostFS access boxes.
+	CredentialsIssuer interface {
+		IssueTemporarySecret(context.Context, *authmate.IssueTemporarySecretOptions) (*authmate.TemporarySecret, error)
+	}
+
+	// STSRoles provides roles which can be assumed via STS API.
+	STSRoles interface {
+		Role(name string) (*STSRole, bool)
+	}
+
+	// STSRole describes a role which can be assumed via STS API.
+	// Bearer and session tokens of the role are signed by Key.
+	STSRole struct {
+		Name              string
+		Key               *keys.PrivateKey
+		AllowedUsers      []user.ID
+		EACLRules         []byte
+		SessionTokenRules []byte
+		SkipSessionRules  bool
+		MaxDuration       time.Duration
+		// WebIdentity is nil if the role can't be assumed with web identity.
+		WebIdentity *STSWebIdentity
+	}
+
+	// STSWebIdentity describes conditions of assuming a role with web identity.
+	STSWebIdentity struct {
+		// Claims maps claim names to patterns (see path.Match) that claim values must match.
+		Claims map[string]string
+		// BucketPrefixClaim is a claim which value restricts buckets available with the credentials.
+		BucketPrefixClaim string
+	}
+)
+
+const (
+	stsActionAssumeRole      = "AssumeRole"
+	stsActionGetSessionToken = "GetSessionToken"
+	stsActionWebIdentity     = "AssumeRoleWithWebIdentity"
+
+	// DefaultSTSDuration is a default lifetime of temporary credentials if it's not set in the request.
+	DefaultSTSDuration = time.Hour
+	// DefaultSTSMaxDuration is a default max lifetime of temporary credentials.
+	DefaultSTSMaxDuration = 12 * time.Hour
+
+	stsMinDuration = 15 * time.Minute
+)
+
+// STSHandler handles requests to STS API. Action is taken from the request form.
+func (h *handler) STSHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	if h.cfg.STS == nil {
+		h.logAndSendError(w, "sts is disabled", reqInfo, errors.GetAPIError(errors.ErrNotImplemented))
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		h.logAndSendError(w, "could not parse sts form", reqInfo, errors.GetAPIError(errors.ErrInvalidArgument))
+		return
+	}
+
+	switch action := r.PostForm.Get(api.Action); action {
+	case stsActionAssumeRole:
+		h.assumeRole(w, r, reqInfo)
+	case stsActionGetSessionToken:
+		h.getSessionToken(w, r, reqInfo)
+	case stsActionWebIdentity:
+		h.assumeRoleWithWebIdentity(w, r, reqInfo)
+	default:
+		h.logAndSendError(w, "unsupported sts action", reqInfo, errors.GetAPIError(errors.ErrNotImplemented))
+	}
+}
+
+func (h *handler) assumeRole(w http.ResponseWriter, r *http.Request, reqInfo *api.ReqInfo) {
+	caller, err := getCallerID(r.Context())
+	if err != nil {
+		h.logAndSendError(w, "caller can't assume role", reqInfo, err)
+		return
+	}
+
+	roleArn := r.PostForm.Get("RoleArn")
+	sessionName := r.PostForm.Get("RoleSessionName")
+	if roleArn == "" || sessionName == "" {
+		h.logAndSendError(w, "missing role arn or session name", reqInfo, errors.GetAPIError(errors.ErrMissingFields))
+		return
+	}
+
+	role, ok := h.cfg.STS.Roles.Role(parseRoleName(roleArn))
+	if !ok || !role.allows(caller) {
+		h.logAndSendError(w, "role can't be assumed", reqInfo, errors.GetAPIError(errors.ErrAccessDenied))
+		return
+	}
+
+	duration, err := h.stsDuration(r.PostForm.Get("DurationSeconds"), role.MaxDuration)
+	if err != nil {
+		h.logAndSendError(w, "invalid duration", reqInfo, err)
+		return
+	}
+
+	secret, err := h.cfg.STS.Issuer.IssueTemporarySecret(r.Context(), &authmate.IssueTemporarySecretOptions{
+		Container:         h.cfg.STS.Container,
+		Owner:             h.cfg.STS.Owner,
+		FrostFSKey:        role.Key,
+		GatesPublicKeys:   h.cfg.STS.GatesPublicKeys,
+		EACLRules:         role.EACLRules,
+		SessionTokenRules: role.SessionTokenRules,
+		SkipSessionRules:  role.SkipSessionRules,
+		Lifetime:          duration,
+		Subject:           caller.EncodeToString(),
+	})
+	if err != nil {
+		h.logAndSendError(w, "could not issue temporary credentials", reqInfo, err)
+		return
+	}
+
+	response := &AssumeRoleResponse{
+		Result: AssumeRoleResult{
+			AssumedRoleUser: AssumedRoleUser{
+				Arn:           "arn:aws:sts::" + caller.EncodeToString() + ":assumed-role/" + role.Name + "/" + sessionName,
+				AssumedRoleID: secret.AccessKeyID + ":" + sessionName,
+			},
+			Credentials: formSTSCredentials(secret),
+		},
+		ResponseMetadata: STSResponseMetadata{RequestID: reqInfo.RequestID},
+	}
+
+	if err = api.EncodeToResponse(w, response); err != nil {
+		h.logAndSendError(w, "something went wrong", reqInfo, err)
+	}
+}
+
+func (h *handler) getSessionToken(w http.ResponseWriter, r *http.Request, reqInfo *api.ReqInfo) {
+	caller, err := getCallerID(r.Context())
+	if err != nil {
+		h.logAndSendError(w, "caller can't get session token", reqInfo, err)
+		return
+	}
+	box, err := layer.GetBoxData(r.Context())
+	if err != nil {
+		h.logAndSendError(w, "could not get access box", reqInfo, err)
+		return
+	}
+
+	duration, err := h.stsDuration(r.PostForm.Get("DurationSeconds"), 0)
+	if err != nil {
+		h.logAndSendError(w, "invalid duration", reqInfo, err)
+		return
+	}
+
+	// Tokens of the caller are reused as is, so temporary credentials have
+	// exactly the same permissions but can be used only via this gate.
+	secret, err := h.cfg.STS.Issuer.IssueTemporarySecret(r.Context(), &authmate.IssueTemporarySecretOptions{
+		Container: h.cfg.STS.Container,
+		Owner:     h.cfg.STS.Owner,
+		GatesData: []*accessbox.GateData{box.Gate},
+		Policies:  box.Policies,
+		Lifetime:  duration,
+		Subject:   caller.EncodeToString(),
+	})
+	if err != nil {
+		h.logAndSendError(w, "could not issue temporary credentials", reqInfo, err)
+		return
+	}
+
+	response := &GetSessionTokenResponse{
+		Result: GetSessionTokenResult{
+			Credentials: formSTSCredentials(secret),
+		},
+		ResponseMetadata: STSResponseMetadata{RequestID: reqInfo.RequestID},
+	}
+
+	if err = api.EncodeToResponse(w, response); err != nil {
+		h.logAndSendError(w, "something went wrong", reqInfo, err)
+	}
+}
+
+func (h *handler) assumeRoleWithWebIdentity(w http.ResponseWriter, r *http.Request, reqInfo *api.ReqInfo) {
+	if h.cfg.STS.WebIdentity == nil {
+		h.logAndSendError(w, "web identity is disabled", reqInfo, errors.GetAPIError(errors.ErrNotImplemented))
+		return
+	}
+
+	roleArn := r.PostForm.Get("RoleArn")
+	sessionName := r.PostForm.Get("RoleSessionName")
+	token := r.PostForm.Get("WebIdentityToken")
+	if roleArn == "" || sessionName == "" || token == "" {
+		h.logAndSendError(w, "missing role arn, session name or web identity token", reqInfo, errors.GetAPIError(errors.ErrMissingFields))
+		return
+	}
+
+	claims, err := h.cfg.STS.WebIdentity.Verify(token, time.Now())
+	if err != nil {
+		h.logAndSendError(w, "could not verify web identity token", reqInfo, errors.GetAPIError(errors.ErrInvalidIdentityToken), zap.Error(err))
+		return
+	}
+	reqInfo.Subject = claims.Subject
+
+	role, ok := h.cfg.STS.Roles.Role(parseRoleName(roleArn))
+	if !ok || role.WebIdentity == nil {
+		h.logAndSendError(w, "role can't be assumed with web identity", reqInfo, errors.GetAPIError(errors.ErrAccessDenied))
+		return
+	}
+
+	bucketPrefix, err := role.WebIdentity.check(claims)
+	if err != nil {
+		h.logAndSendError(w, "web identity claims are rejected", reqInfo, err)
+		return
+	}
+
+	duration, err := h.stsDuration(r.PostForm.Get("DurationSeconds"), role.MaxDuration)
+	if err != nil {
+		h.logAndSendError(w, "invalid duration", reqInfo, err)
+		return
+	}
+
+	secret, err := h.cfg.STS.Issuer.IssueTemporarySecret(r.Context(), &authmate.IssueTemporarySecretOptions{
+		Container:         h.cfg.STS.Container,
+		Owner:             h.cfg.STS.Owner,
+		FrostFSKey:        role.Key,
+		GatesPublicKeys:   h.cfg.STS.GatesPublicKeys,
+		EACLRules:         role.EACLRules,
+		SessionTokenRules: role.SessionTokenRules,
+		SkipSessionRules:  role.SkipSessionRules,
+		Lifetime:          duration,
+		Subject:           claims.Subject,
+		BucketPrefix:      bucketPrefix,
+	})
+	if err != nil {
+		h.logAndSendError(w, "could not issue temporary credentials", reqInfo, err)
+		return
+	}
+
+	var audience string
+	if len(claims.Audience) > 0 {
+		audience = claims.Audience[0]
+	}
+
+	response := &AssumeRoleWithWebIdentityResponse{
+		Result: AssumeRoleWithWebIdentityResult{
+			SubjectFromWebIdentityToken: claims.Subject,
+			Audience:                    audience,
+			Provider:                    claims.Issuer,
+			AssumedRoleUser: AssumedRoleUser{
+				Arn:           "arn:aws:sts::" + h.cfg.STS.Owner.EncodeToString() + ":assumed-role/" + role.Name + "/" + sessionName,
+				AssumedRoleID: secret.AccessKeyID + ":" + sessionName,
+			},
+			Credentials: formSTSCredentials(secret),
+		},
+		ResponseMetadata: STSResponseMetadata{RequestID: reqInfo.RequestID},
+	}
+
+	if err = api.EncodeToResponse(w, response); err != nil {
+		h.logAndSendError(w, "something went wrong", reqInfo, err)
+	}
+}
+
+// stsDuration parses DurationSeconds parameter and checks it against max allowed lifetime.
+// Zero roleMaxDuration means that only the gate limit is applied.
+func (h *handler) stsDuration(value string, roleMaxDuration time.Duration) (time.Duration, error) {
+	maxDuration := h.cfg.STS.MaxDuration
+	if roleMaxDuration > 0 && roleMaxDuration < maxDuration {
+		maxDuration = roleMaxDuration
+	}
+
+	if value == "" {
+		if h.cfg.STS.DefaultDuration > maxDuration {
+			return maxDuration, nil
+		}
+		return h.cfg.STS.DefaultDuration, nil
+	}
+
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, errors.GetAPIError(errors.ErrInvalidArgument)
+	}
+
+	duration := time.Duration(seconds) * time.Second
+	if duration < stsMinDuration || duration > maxDuration {
+		return 0, errors.GetAPIError(errors.ErrInvalidArgument)
+	}
+
+	return duration, nil
+}
+
+// getCallerID returns owner of the credentials the request is signed with.
+// Temporary credentials can't be used to issue new ones, because it allows to escape their scope.
+func getCallerID(ctx context.Context) (user.ID, error) {
+	box, err := layer.GetBoxData(ctx)
+	if err != nil || box.Gate.BearerToken == nil {
+		return user.ID{}, errors.GetAPIError(errors.ErrAccessDenied)
+	}
+
+	if _, ok := ctx.Value(api.SessionClaims).(*tokens.SessionClaims); ok {
+		return user.ID{}, errors.GetAPIError(errors.ErrAccessDenied)
+	}
+
+	return bearer.ResolveIssuer(*box.Gate.BearerToken), nil
+}
+
+// parseRoleName gets role name from role ARN (arn:aws:iam::<account>:role/<name>).
+// If value isn't an ARN, it's considered as a role name.
+func parseRoleName(roleArn string) string {
+	if index := strings.LastIndex(roleArn, ":role/"); index != -1 {
+		return roleArn[index+len(":role/"):]
+	}
+	return roleArn
+}
+
+func (r *STSRole) allows(id user.ID) bool {
+	if len(r.AllowedUsers) == 0 {
+		return true
+	}
+
+	for _, allowed := range r.AllowedUsers {
+		if allowed.Equals(id) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// check matches claims against role conditions and returns bucket prefix the credentials are restricted to.
+func (i *STSWebIdentity) check(claims *oidc.Claims) (string, error) {
+	for name, pattern := range i.Claims {
+		if !claims.Match(name, pattern) {
+			return "", errors.GetAPIError(errors.ErrIDPRejectedClaim)
+		}
+	}
+
+	if i.BucketPrefixClaim == "" {
+		return "", nil
+	}
+
+	prefix, ok := claims.Value(i.BucketPrefixClaim)
+	if !ok || prefix == "" {
+		return "", errors.GetAPIError(errors.ErrIDPRejectedClaim)
+	}
+
+	return prefix, nil
+}
+
+func formSTSCredentials(secret *authmate.TemporarySecret) STSCredentials {
+	return STSCredentials{
+		AccessKeyID:     secret.AccessKeyID,
+		SecretAccessKey: secret.SecretAccessKey,
+		SessionToken:    secret.SessionToken,
+		Expiration:      secret.Expiration.UTC().Format(time.RFC3339),
+	}
+}
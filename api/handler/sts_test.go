@@ -0,0 +1,278 @@
+package handler
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TrueCloudLab/frostfs-s3-gw/api"
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/auth/oidc"
+	"github.com/TrueCloudLab/frostfs-s3-gw/authmate"
+	"github.com/TrueCloudLab/frostfs-s3-gw/creds/tokens"
+	"github.com/TrueCloudLab/frostfs-sdk-go/user"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/stretchr/testify/require"
+)
+
+type credentialsIssuerMock struct {
+	options *authmate.IssueTemporarySecretOptions
+}
+
+func (c *credentialsIssuerMock) IssueTemporarySecret(_ context.Context, options *authmate.IssueTemporarySecretOptions) (*authmate.TemporarySecret, error) {
+	c.options = options
+	return &authmate.TemporarySecret{
+		AccessKeyID:     "cid0oid",
+		SecretAccessKey: "secret",
+		SessionToken:    "token",
+		Expiration:      time.Now().Add(options.Lifetime),
+	}, nil
+}
+
+type stsRolesMock map[string]*STSRole
+
+func (r stsRolesMock) Role(name string) (*STSRole, bool) {
+	role, ok := r[name]
+	return role, ok
+}
+
+func TestAssumeRole(t *testing.T) {
+	hc := prepareHandlerContext(t)
+	issuer := setSTSConfig(t, hc)
+
+	key, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	var stranger user.ID
+	user.IDFromKey(&stranger, key.PrivateKey.PublicKey)
+
+	hc.h.cfg.STS.Roles = stsRolesMock{
+		"reader": {Name: "reader", Key: key, AllowedUsers: []user.ID{hc.owner}},
+		"writer": {Name: "writer", Key: key, AllowedUsers: []user.ID{stranger}},
+		"short":  {Name: "short", Key: key, MaxDuration: time.Hour},
+	}
+
+	t.Run("allowed", func(t *testing.T) {
+		w := stsRequest(hc, url.Values{
+			api.Action:        []string{stsActionAssumeRole},
+			"RoleArn":         []string{"arn:aws:iam::account:role/reader"},
+			"RoleSessionName": []string{"session"},
+			"DurationSeconds": []string{"7200"},
+		})
+
+		response := &AssumeRoleResponse{}
+		readResponse(t, w, http.StatusOK, response)
+		require.Equal(t, "cid0oid", response.Result.Credentials.AccessKeyID)
+		require.Equal(t, "token", response.Result.Credentials.SessionToken)
+		require.Equal(t, "cid0oid:session", response.Result.AssumedRoleUser.AssumedRoleID)
+		require.Equal(t, 2*time.Hour, issuer.options.Lifetime)
+		require.Equal(t, key, issuer.options.FrostFSKey)
+	})
+
+	t.Run("denied", func(t *testing.T) {
+		w := stsRequest(hc, url.Values{
+			api.Action:        []string{stsActionAssumeRole},
+			"RoleArn":         []string{"writer"},
+			"RoleSessionName": []string{"session"},
+		})
+		assertStatus(t, w, http.StatusForbidden)
+
+		w = stsRequest(hc, url.Values{
+			api.Action:        []string{stsActionAssumeRole},
+			"RoleArn":         []string{"unknown"},
+			"RoleSessionName": []string{"session"},
+		})
+		assertStatus(t, w, http.StatusForbidden)
+	})
+
+	t.Run("invalid duration", func(t *testing.T) {
+		for _, duration := range []time.Duration{time.Minute, 2 * time.Hour} {
+			w := stsRequest(hc, url.Values{
+				api.Action:        []string{stsActionAssumeRole},
+				"RoleArn":         []string{"short"},
+				"RoleSessionName": []string{"session"},
+				"DurationSeconds": []string{strconv.Itoa(int(duration.Seconds()))},
+			})
+			assertStatus(t, w, http.StatusBadRequest)
+		}
+	})
+}
+
+func TestGetSessionToken(t *testing.T) {
+	hc := prepareHandlerContext(t)
+	issuer := setSTSConfig(t, hc)
+
+	w := stsRequest(hc, url.Values{api.Action: []string{stsActionGetSessionToken}})
+
+	response := &GetSessionTokenResponse{}
+	readResponse(t, w, http.StatusOK, response)
+	require.Equal(t, "cid0oid", response.Result.Credentials.AccessKeyID)
+	require.Equal(t, DefaultSTSDuration, issuer.options.Lifetime)
+	require.Len(t, issuer.options.GatesData, 1)
+}
+
+func TestGetSessionTokenWithTemporaryCredentials(t *testing.T) {
+	hc := prepareHandlerContext(t)
+	setSTSConfig(t, hc)
+
+	hc.context = context.WithValue(hc.context, api.SessionClaims, &tokens.SessionClaims{Subject: "subject"})
+
+	w := stsRequest(hc, url.Values{api.Action: []string{stsActionGetSessionToken}})
+	assertStatus(t, w, http.StatusForbidden)
+}
+
+func TestListBucketsWithTemporaryCredentials(t *testing.T) {
+	hc := prepareHandlerContext(t)
+	createTestBucket(hc, "team-bucket")
+	createTestBucket(hc, "other-bucket")
+
+	hc.context = context.WithValue(hc.context, api.SessionClaims, &tokens.SessionClaims{BucketPrefix: "team-"})
+
+	w, r := prepareTestFullRequest(hc, "", "", nil, nil)
+	hc.Handler().ListBucketsHandler(w, r)
+
+	response := &ListBucketsResponse{}
+	readResponse(t, w, http.StatusOK, response)
+	require.Len(t, response.Buckets.Buckets, 1)
+	require.Equal(t, "team-bucket", response.Buckets.Buckets[0].Name)
+}
+
+func TestAssumeRoleWithWebIdentity(t *testing.T) {
+	hc := prepareHandlerContext(t)
+	issuer := setSTSConfig(t, hc)
+
+	hc.context = context.Background()
+
+	idpKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	jwks, err := json.Marshal(map[string]interface{}{"keys": []map[string]string{{
+		"kty": "EC",
+		"crv": "P-256",
+		"x":   base64.RawURLEncoding.EncodeToString(idpKey.X.Bytes()),
+		"y":   base64.RawURLEncoding.EncodeToString(idpKey.Y.Bytes()),
+	}}})
+	require.NoError(t, err)
+	jwksPath := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(jwksPath, jwks, 0600))
+
+	verifier, err := oidc.NewVerifier([]oidc.ProviderConfig{{Issuer: "https://idp", Audience: []string{"s3"}, JWKSPath: jwksPath}})
+	require.NoError(t, err)
+	hc.h.cfg.STS.WebIdentity = verifier
+
+	roleKey, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	hc.h.cfg.STS.Roles = stsRolesMock{
+		"ci": {Name: "ci", Key: roleKey, WebIdentity: &STSWebIdentity{
+			Claims:            map[string]string{"ref": "refs/heads/*"},
+			BucketPrefixClaim: "project",
+		}},
+		"static": {Name: "static", Key: roleKey},
+	}
+
+	claims := map[string]interface{}{
+		"iss":     "https://idp",
+		"sub":     "ci-job",
+		"aud":     "s3",
+		"exp":     time.Now().Add(time.Hour).Unix(),
+		"ref":     "refs/heads/master",
+		"project": "team-",
+	}
+
+	t.Run("allowed", func(t *testing.T) {
+		w := stsRequest(hc, webIdentityForm("ci", signTestJWT(t, idpKey, claims)))
+
+		response := &AssumeRoleWithWebIdentityResponse{}
+		readResponse(t, w, http.StatusOK, response)
+		require.Equal(t, "ci-job", response.Result.SubjectFromWebIdentityToken)
+		require.Equal(t, "ci-job", issuer.options.Subject)
+		require.Equal(t, "team-", issuer.options.BucketPrefix)
+	})
+
+	t.Run("rejected claim", func(t *testing.T) {
+		claims["ref"] = "refs/tags/v1"
+		defer func() { claims["ref"] = "refs/heads/master" }()
+
+		w := stsRequest(hc, webIdentityForm("ci", signTestJWT(t, idpKey, claims)))
+		assertStatus(t, w, http.StatusForbidden)
+	})
+
+	t.Run("role without web identity", func(t *testing.T) {
+		w := stsRequest(hc, webIdentityForm("static", signTestJWT(t, idpKey, claims)))
+		assertStatus(t, w, http.StatusForbidden)
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		anotherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		w := stsRequest(hc, webIdentityForm("ci", signTestJWT(t, anotherKey, claims)))
+		assertStatus(t, w, http.StatusBadRequest)
+	})
+}
+
+func webIdentityForm(role, token string) url.Values {
+	return url.Values{
+		api.Action:         []string{stsActionWebIdentity},
+		"RoleArn":          []string{"arn:aws:iam::account:role/" + role},
+		"RoleSessionName":  []string{"session"},
+		"WebIdentityToken": []string{token},
+	}
+}
+
+func signTestJWT(t *testing.T, key *ecdsa.PrivateKey, claims map[string]interface{}) string {
+	hdr, err := json.Marshal(map[string]string{"alg": "ES256", "typ": "JWT"})
+	require.NoError(t, err)
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+
+	input := base64.RawURLEncoding.EncodeToString(hdr) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(input))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func setSTSConfig(t *testing.T, hc *handlerContext) *credentialsIssuerMock {
+	key, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+
+	issuer := &credentialsIssuerMock{}
+	hc.h.cfg.STS = &STSConfig{
+		Issuer:          issuer,
+		Roles:           stsRolesMock{},
+		GatesPublicKeys: []*keys.PublicKey{key.PublicKey()},
+		DefaultDuration: DefaultSTSDuration,
+		MaxDuration:     DefaultSTSMaxDuration,
+	}
+
+	return issuer
+}
+
+func stsRequest(hc *handlerContext, form url.Values) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(form.Encode()))
+	r.Header.Set(api.ContentType, "application/x-www-form-urlencoded")
+
+	reqInfo := api.NewReqInfo(w, r, api.ObjectRequest{})
+	r = r.WithContext(api.SetReqInfo(hc.Context(), reqInfo))
+
+	hc.Handler().STSHandler(w, r)
+	return w
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/errors"
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/layer"
+	"github.com/TrueCloudLab/frostfs-s3-gw/audit"
 	"go.uber.org/zap"
 )
 
@@ -46,12 +47,21 @@ func (h *handler) PutObjectTaggingHandler(w http.ResponseWriter, r *http.Request
 		},
 		TagSet: tagSet,
 	}
+	// missing previous tags mustn't fail the request, the error is returned by PutObjectTagging then
+	_, prevTagSet, _ := h.obj.GetObjectTagging(r.Context(), &layer.GetObjectTaggingParams{ObjectVersion: tagPrm.ObjectVersion})
+
 	nodeVersion, err := h.obj.PutObjectTagging(r.Context(), tagPrm)
 	if err != nil {
 		h.logAndSendError(w, "could not put object tagging", reqInfo, err)
 		return
 	}
 
+	h.audit(r.Context(), &audit.Event{
+		VersionID: nodeVersion.OID.EncodeToString(),
+		Before:    auditSummary(prevTagSet),
+		After:     auditSummary(tagSet),
+	})
+
 	s := &SendNotificationParams{
 		Event: EventObjectTaggingPut,
 		NotificationInfo: &data.NotificationInfo{
@@ -121,6 +131,7 @@ func (h *handler) DeleteObjectTaggingHandler(w http.ResponseWriter, r *http.Requ
 		ObjectName: reqInfo.ObjectName,
 		VersionID:  reqInfo.URL.Query().Get(api.QueryVersionID),
 	}
+	_, prevTagSet, _ := h.obj.GetObjectTagging(r.Context(), &layer.GetObjectTaggingParams{ObjectVersion: p})
 
 	nodeVersion, err := h.obj.DeleteObjectTagging(r.Context(), p)
 	if err != nil {
@@ -128,6 +139,11 @@ func (h *handler) DeleteObjectTaggingHandler(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	h.audit(r.Context(), &audit.Event{
+		VersionID: nodeVersion.OID.EncodeToString(),
+		Before:    auditSummary(prevTagSet),
+	})
+
 	s := &SendNotificationParams{
 		Event: EventObjectTaggingDelete,
 		NotificationInfo: &data.NotificationInfo{
@@ -161,10 +177,17 @@ func (h *handler) PutBucketTaggingHandler(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	prevTagSet, _ := h.obj.GetBucketTagging(r.Context(), bktInfo)
+
 	if err = h.obj.PutBucketTagging(r.Context(), bktInfo, tagSet); err != nil {
 		h.logAndSendError(w, "could not put object tagging", reqInfo, err)
 		return
 	}
+
+	h.audit(r.Context(), &audit.Event{
+		Before: auditSummary(prevTagSet),
+		After:  auditSummary(tagSet),
+	})
 }
 
 func (h *handler) GetBucketTaggingHandler(w http.ResponseWriter, r *http.Request) {
@@ -197,10 +220,14 @@ func (h *handler) DeleteBucketTaggingHandler(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	prevTagSet, _ := h.obj.GetBucketTagging(r.Context(), bktInfo)
+
 	if err = h.obj.DeleteBucketTagging(r.Context(), bktInfo); err != nil {
 		h.logAndSendError(w, "could not delete bucket tagging", reqInfo, err)
 		return
 	}
+
+	h.audit(r.Context(), &audit.Event{Before: auditSummary(prevTagSet)})
 	w.WriteHeader(http.StatusNoContent)
 }
 
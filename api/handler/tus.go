@@ -0,0 +1,448 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TrueCloudLab/frostfs-s3-gw/api"
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/auth"
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/errors"
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/layer"
+	"github.com/TrueCloudLab/frostfs-s3-gw/audit"
+	"github.com/TrueCloudLab/frostfs-s3-gw/creds/accessbox"
+	"github.com/TrueCloudLab/frostfs-s3-gw/creds/tokens"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	tusQuery = "tus"
+
+	tusProtocolVersion = "1.0.0"
+	tusExtensions      = "creation,termination,checksum"
+	tusChecksums       = "sha1,md5,sha256"
+	tusPatchMediaType  = "application/offset+octet-stream"
+
+	// Upload-Metadata keys which set the name and the content type of the uploaded object.
+	tusMetaKey      = "key"
+	tusMetaFilename = "filename"
+	tusMetaType     = "content-type"
+	tusMetaFiletype = "filetype"
+)
+
+// TusOptionsHandler returns the configuration of the tus server. CORS preflight requests are
+// handled as usual.
+func (h *handler) TusOptionsHandler(w http.ResponseWriter, r *http.Request) {
+	if len(r.Header.Get(api.Origin)) != 0 {
+		h.Preflight(w, r)
+		return
+	}
+
+	w.Header().Set(api.TusResumable, tusProtocolVersion)
+	w.Header().Set(api.TusVersion, tusProtocolVersion)
+	w.Header().Set(api.TusExtension, tusExtensions)
+	w.Header().Set(api.TusChecksumAlgorithm, tusChecksums)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// TusCreateHandler creates the resumable upload (tus creation extension). The name of the object is
+// the path of the request followed by the 'key' (or 'filename') value of the Upload-Metadata header.
+func (h *handler) TusCreateHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	if !h.checkTusResumable(w, r, reqInfo) {
+		return
+	}
+
+	length, err := strconv.ParseInt(r.Header.Get(api.UploadLength), 10, 64)
+	if err != nil || length < 0 {
+		h.logAndSendError(w, "invalid upload length", reqInfo, errors.GetAPIError(errors.ErrInvalidArgument))
+		return
+	}
+
+	tusMeta, err := parseTusMetadata(r.Header.Get(api.UploadMetadata))
+	if err != nil {
+		h.logAndSendError(w, "invalid upload metadata", reqInfo, errors.GetAPIError(errors.ErrInvalidArgument), zap.Error(err))
+		return
+	}
+
+	name, ok := tusMeta[tusMetaKey]
+	if !ok {
+		name = tusMeta[tusMetaFilename]
+	}
+	key := reqInfo.ObjectName + name
+	if len(key) == 0 {
+		h.logAndSendError(w, "missing object name", reqInfo, errors.GetAPIError(errors.ErrInvalidArgument))
+		return
+	}
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	metadata := make(map[string]string, len(tusMeta))
+	for k, v := range tusMeta {
+		switch k {
+		case tusMetaKey:
+		case tusMetaType, tusMetaFiletype:
+			metadata[api.ContentType] = v
+		default:
+			metadata[k] = v
+		}
+	}
+
+	copiesNumber, err := getCopiesNumberOrDefault(metadata, h.cfg.CopiesNumber)
+	if err != nil {
+		h.logAndSendError(w, "invalid copies number", reqInfo, err)
+		return
+	}
+
+	uploadID := uuid.New().String()
+	additional := []zap.Field{zap.String("uploadID", uploadID), zap.String("Key", key)}
+
+	token, err := h.tusUploadToken(r.Context(), reqInfo.BucketName, key, uploadID)
+	if err != nil {
+		h.logAndSendError(w, "could not issue upload token", reqInfo, err, additional...)
+		return
+	}
+
+	p := &layer.CreateTusUploadParams{
+		Info: &layer.UploadInfoParams{
+			UploadID: uploadID,
+			Bkt:      bktInfo,
+			Key:      key,
+		},
+		Length:       length,
+		Metadata:     r.Header.Get(api.UploadMetadata),
+		Header:       metadata,
+		CopiesNumber: copiesNumber,
+	}
+
+	extendedObjInfo, err := h.obj.CreateTusUpload(r.Context(), p)
+	if err != nil {
+		h.logAndSendError(w, "could not create tus upload", reqInfo, err, additional...)
+		return
+	}
+	if extendedObjInfo != nil {
+		h.tusUploadCompleted(w, r, reqInfo, bktInfo, extendedObjInfo.ObjectInfo)
+	}
+
+	base := strings.TrimSuffix(r.URL.Path, reqInfo.ObjectName)
+	if !strings.HasSuffix(base, "/") {
+		base += "/"
+	}
+	location := base + s3URLEncode(key, encodePathSegment) + "?" + tusQuery + "=" + uploadID
+	if token != "" {
+		location += "&" + auth.TusUploadToken + "=" + token
+	}
+	w.Header().Set(api.Location, location)
+	w.WriteHeader(http.StatusCreated)
+}
+
+// tusUploadToken returns the token to access the upload by its location without signing requests.
+// The token is scoped to the upload, expires with temporary credentials and isn't issued for anonymous requests.
+func (h *handler) tusUploadToken(ctx context.Context, bucket, key, uploadID string) (string, error) {
+	box, ok := ctx.Value(api.BoxData).(*accessbox.Box)
+	accessKeyID, _ := ctx.Value(api.AccessKeyID).(string)
+	if !ok || box == nil || box.Gate == nil || accessKeyID == "" {
+		return "", nil
+	}
+
+	lifetime := h.cfg.TusTokenLifetime
+	if lifetime <= 0 {
+		lifetime = DefaultTusTokenLifetime
+	}
+
+	claims := tokens.UploadClaims{
+		AccessKeyID: accessKeyID,
+		Bucket:      bucket,
+		Key:         key,
+		UploadID:    uploadID,
+		Expiration:  time.Now().Add(lifetime).Unix(),
+	}
+	if session, ok := ctx.Value(api.SessionClaims).(*tokens.SessionClaims); ok {
+		claims.Session = session
+		if session.Expiration < claims.Expiration {
+			claims.Expiration = session.Expiration
+		}
+	}
+
+	return tokens.NewUploadToken(box.Gate.AccessKey, claims)
+}
+
+// TusHeadHandler returns the offset of the resumable upload.
+func (h *handler) TusHeadHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	if !h.checkTusResumable(w, r, reqInfo) {
+		return
+	}
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	uploadID := reqInfo.URL.Query().Get(tusQuery)
+	upload, err := h.obj.GetTusUpload(r.Context(), &layer.UploadInfoParams{
+		UploadID: uploadID,
+		Bkt:      bktInfo,
+		Key:      reqInfo.ObjectName,
+	})
+	if err != nil {
+		h.logAndSendError(w, "could not get tus upload", reqInfo, err,
+			zap.String("uploadID", uploadID), zap.String("Key", reqInfo.ObjectName))
+		return
+	}
+
+	w.Header().Set(api.UploadOffset, strconv.FormatInt(upload.Offset, 10))
+	w.Header().Set(api.UploadLength, strconv.FormatInt(upload.Length, 10))
+	if len(upload.Metadata) != 0 {
+		w.Header().Set(api.UploadMetadata, upload.Metadata)
+	}
+	w.Header().Set(api.CacheControl, "no-store")
+	w.WriteHeader(http.StatusOK)
+}
+
+// TusPatchHandler writes the request payload to the resumable upload at the Upload-Offset.
+// The object is completed when the whole payload is received.
+func (h *handler) TusPatchHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	if !h.checkTusResumable(w, r, reqInfo) {
+		return
+	}
+
+	if r.Header.Get(api.ContentType) != tusPatchMediaType {
+		h.logAndSendError(w, "invalid content type", reqInfo, errors.GetAPIError(errors.ErrUnsupportedMediaType))
+		return
+	}
+
+	offset, err := strconv.ParseInt(r.Header.Get(api.UploadOffset), 10, 64)
+	if err != nil || offset < 0 {
+		h.logAndSendError(w, "invalid upload offset", reqInfo, errors.GetAPIError(errors.ErrInvalidArgument))
+		return
+	}
+	if r.ContentLength < 0 {
+		h.logAndSendError(w, "chunked payload", reqInfo, errors.GetAPIError(errors.ErrMissingContentLength))
+		return
+	}
+
+	var (
+		reader   io.Reader = r.Body
+		checksum *tusChecksumReader
+	)
+	if value := r.Header.Get(api.UploadChecksum); len(value) != 0 {
+		if checksum, err = newTusChecksumReader(r.Body, r.ContentLength, value); err != nil {
+			h.logAndSendError(w, "invalid upload checksum", reqInfo, errors.GetAPIError(errors.ErrInvalidArgument), zap.Error(err))
+			return
+		}
+		reader = checksum
+	}
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	uploadID := reqInfo.URL.Query().Get(tusQuery)
+	additional := []zap.Field{zap.String("uploadID", uploadID), zap.String("Key", reqInfo.ObjectName)}
+
+	upload, extendedObjInfo, err := h.obj.PatchTusUpload(r.Context(), &layer.PatchTusUploadParams{
+		Info: &layer.UploadInfoParams{
+			UploadID: uploadID,
+			Bkt:      bktInfo,
+			Key:      reqInfo.ObjectName,
+		},
+		Offset: offset,
+		Size:   r.ContentLength,
+		Reader: reader,
+	})
+	if err != nil {
+		if checksum != nil && checksum.mismatch {
+			err = errors.GetAPIError(errors.ErrUploadChecksumMismatch)
+		}
+		_, err2 := io.Copy(io.Discard, r.Body)
+		err3 := r.Body.Close()
+		additional = append(additional, zap.Errors("body close errors", []error{err2, err3}))
+		h.logAndSendError(w, "could not patch tus upload", reqInfo, err, additional...)
+		return
+	}
+
+	if extendedObjInfo != nil {
+		h.tusUploadCompleted(w, r, reqInfo, bktInfo, extendedObjInfo.ObjectInfo)
+	}
+
+	w.Header().Set(api.UploadOffset, strconv.FormatInt(upload.Offset, 10))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// TusDeleteHandler terminates the resumable upload (tus termination extension).
+func (h *handler) TusDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	reqInfo := api.GetReqInfo(r.Context())
+
+	if !h.checkTusResumable(w, r, reqInfo) {
+		return
+	}
+
+	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
+	if err != nil {
+		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
+		return
+	}
+
+	p := &layer.UploadInfoParams{
+		UploadID: reqInfo.URL.Query().Get(tusQuery),
+		Bkt:      bktInfo,
+		Key:      reqInfo.ObjectName,
+	}
+	additional := []zap.Field{zap.String("uploadID", p.UploadID), zap.String("Key", p.Key)}
+
+	if _, err = h.obj.GetTusUpload(r.Context(), p); err != nil {
+		h.logAndSendError(w, "could not get tus upload", reqInfo, err, additional...)
+		return
+	}
+
+	if err = h.obj.AbortMultipartUpload(r.Context(), p); err != nil {
+		h.logAndSendError(w, "could not terminate tus upload", reqInfo, err, additional...)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// checkTusResumable sets the protocol version to the response and checks the version
+// requested by the client.
+func (h *handler) checkTusResumable(w http.ResponseWriter, r *http.Request, reqInfo *api.ReqInfo) bool {
+	w.Header().Set(api.TusResumable, tusProtocolVersion)
+
+	if r.Header.Get(api.TusResumable) != tusProtocolVersion {
+		w.Header().Set(api.TusVersion, tusProtocolVersion)
+		h.logAndSendError(w, "unsupported tus version", reqInfo, errors.GetAPIError(errors.ErrPreconditionFailed))
+		return false
+	}
+
+	return true
+}
+
+func (h *handler) tusUploadCompleted(w http.ResponseWriter, r *http.Request, reqInfo *api.ReqInfo, bktInfo *data.BucketInfo, objInfo *data.ObjectInfo) {
+	s := &SendNotificationParams{
+		Event:            EventObjectCreatedCompleteMultipartUpload,
+		NotificationInfo: data.NotificationInfoFromObject(objInfo),
+		BktInfo:          bktInfo,
+		ReqInfo:          reqInfo,
+	}
+	if err := h.sendNotifications(r.Context(), s); err != nil {
+		h.log.Error("couldn't send notification: %w", zap.Error(err))
+	}
+
+	h.audit(r.Context(), &audit.Event{
+		Bucket:    objInfo.Bucket,
+		Object:    objInfo.Name,
+		VersionID: objInfo.VersionID(),
+		After:     "etag=" + objInfo.HashSum,
+	})
+
+	w.Header().Set(api.ETag, objInfo.HashSum)
+}
+
+// parseTusMetadata parses the Upload-Metadata header: comma-separated pairs of the key
+// and the base64 encoded value. The value may be omitted.
+func parseTusMetadata(header string) (map[string]string, error) {
+	res := make(map[string]string)
+	if len(header) == 0 {
+		return res, nil
+	}
+
+	for _, pair := range strings.Split(header, ",") {
+		fields := strings.Fields(pair)
+		if len(fields) == 0 || len(fields) > 2 {
+			return nil, fmt.Errorf("invalid pair '%s'", pair)
+		}
+
+		key := strings.ToLower(fields[0])
+		if _, ok := res[key]; ok {
+			return nil, fmt.Errorf("duplicate key '%s'", key)
+		}
+
+		var value []byte
+		if len(fields) == 2 {
+			var err error
+			if value, err = base64.StdEncoding.DecodeString(fields[1]); err != nil {
+				return nil, fmt.Errorf("invalid value of '%s': %w", key, err)
+			}
+		}
+		res[key] = string(value)
+	}
+
+	return res, nil
+}
+
+// tusChecksumReader verifies the Upload-Checksum of the chunk. The mismatch is reported as the read error
+// when the whole chunk is read, so the chunk isn't stored.
+type tusChecksumReader struct {
+	r         io.Reader
+	h         hash.Hash
+	expected  []byte
+	remaining int64
+	checked   bool
+	mismatch  bool
+}
+
+func newTusChecksumReader(r io.Reader, size int64, header string) (*tusChecksumReader, error) {
+	fields := strings.Fields(header)
+	if len(fields) != 2 {
+		return nil, fmt.Errorf("invalid checksum '%s'", header)
+	}
+
+	var h hash.Hash
+	switch fields[0] {
+	case "sha1":
+		h = sha1.New()
+	case "md5":
+		h = md5.New()
+	case "sha256":
+		h = sha256.New()
+	default:
+		return nil, fmt.Errorf("unsupported checksum algorithm '%s'", fields[0])
+	}
+
+	expected, err := base64.StdEncoding.DecodeString(fields[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid checksum value: %w", err)
+	}
+
+	return &tusChecksumReader{r: r, h: h, expected: expected, remaining: size}, nil
+}
+
+func (c *tusChecksumReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.h.Write(p[:n])
+	c.remaining -= int64(n)
+
+	if !c.checked && (c.remaining <= 0 || err == io.EOF) {
+		c.checked = true
+		if !bytes.Equal(c.h.Sum(nil), c.expected) {
+			c.mismatch = true
+			return n, fmt.Errorf("upload checksum mismatch")
+		}
+	}
+
+	return n, err
+}
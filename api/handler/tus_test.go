@@ -0,0 +1,222 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/TrueCloudLab/frostfs-s3-gw/api"
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/auth"
+	apiErrors "github.com/TrueCloudLab/frostfs-s3-gw/api/errors"
+	"github.com/TrueCloudLab/frostfs-s3-gw/creds/accessbox"
+	"github.com/TrueCloudLab/frostfs-s3-gw/creds/tokens"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTusUpload(t *testing.T) {
+	tc := prepareHandlerContext(t)
+
+	bktName := "bucket-for-tus"
+	createTestBucket(tc, bktName)
+
+	w, r := prepareTusRequest(tc, http.MethodPost, bktName, "dir/", "", nil)
+	r.Header.Set(api.UploadLength, "6")
+	r.Header.Set(api.UploadMetadata, "filename "+base64.StdEncoding.EncodeToString([]byte("my file.txt"))+
+		",filetype "+base64.StdEncoding.EncodeToString([]byte("text/plain"))+",empty")
+	tc.Handler().TusCreateHandler(w, r)
+	assertStatus(t, w, http.StatusCreated)
+	require.Equal(t, tusProtocolVersion, w.Header().Get(api.TusResumable))
+
+	location, err := url.Parse(w.Header().Get(api.Location))
+	require.NoError(t, err)
+	require.Equal(t, "/"+bktName+"/dir/my file.txt", location.Path)
+	uploadID := location.Query().Get(tusQuery)
+	require.NotEmpty(t, uploadID)
+	objName := "dir/my file.txt"
+
+	w = patchTusUpload(t, tc, bktName, objName, uploadID, 0, "abc", "", http.StatusNoContent)
+	require.Equal(t, "3", w.Header().Get(api.UploadOffset))
+
+	w = patchTusUpload(t, tc, bktName, objName, uploadID, 0, "def", "", http.StatusConflict)
+	assertS3Error(t, w, apiErrors.GetAPIError(apiErrors.ErrUploadOffsetMismatch))
+
+	w = patchTusUpload(t, tc, bktName, objName, uploadID, 3, "def", "sha1 "+base64.StdEncoding.EncodeToString([]byte("invalid")), 460)
+	assertS3Error(t, w, apiErrors.GetAPIError(apiErrors.ErrUploadChecksumMismatch))
+
+	w, r = prepareTusRequest(tc, http.MethodHead, bktName, objName, uploadID, nil)
+	tc.Handler().TusHeadHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Equal(t, "3", w.Header().Get(api.UploadOffset))
+	require.Equal(t, "6", w.Header().Get(api.UploadLength))
+	require.Equal(t, "no-store", w.Header().Get(api.CacheControl))
+
+	checksum := sha1.Sum([]byte("def"))
+	w = patchTusUpload(t, tc, bktName, objName, uploadID, 3, "def", "sha1 "+base64.StdEncoding.EncodeToString(checksum[:]), http.StatusNoContent)
+	require.Equal(t, "6", w.Header().Get(api.UploadOffset))
+	require.NotEmpty(t, w.Header().Get(api.ETag))
+
+	require.Equal(t, "abcdef", string(getObjectRange(t, tc, bktName, objName, 0, 5)))
+	checkObjectMetadata(t, tc, bktName, objName, "filename", "my file.txt")
+	w, r = prepareTestRequest(tc, bktName, objName, nil)
+	tc.Handler().HeadObjectHandler(w, r)
+	require.Equal(t, "text/plain", w.Header().Get(api.ContentType))
+
+	w, r = prepareTusRequest(tc, http.MethodHead, bktName, objName, uploadID, nil)
+	tc.Handler().TusHeadHandler(w, r)
+	assertStatus(t, w, http.StatusNotFound)
+}
+
+func TestTusUploadTermination(t *testing.T) {
+	tc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-for-tus", "object"
+	createTestBucket(tc, bktName)
+
+	w, r := prepareTusRequest(tc, http.MethodPost, bktName, objName, "", nil)
+	r.Header.Set(api.UploadLength, "10")
+	tc.Handler().TusCreateHandler(w, r)
+	assertStatus(t, w, http.StatusCreated)
+
+	location, err := url.Parse(w.Header().Get(api.Location))
+	require.NoError(t, err)
+	uploadID := location.Query().Get(tusQuery)
+
+	w, r = prepareTusRequest(tc, http.MethodDelete, bktName, objName, uploadID, nil)
+	tc.Handler().TusDeleteHandler(w, r)
+	assertStatus(t, w, http.StatusNoContent)
+
+	w = patchTusUpload(t, tc, bktName, objName, uploadID, 0, "abc", "", http.StatusNotFound)
+	assertS3Error(t, w, apiErrors.GetAPIError(apiErrors.ErrNoSuchUpload))
+}
+
+func TestTusProtocolChecks(t *testing.T) {
+	tc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-for-tus", "object"
+	createTestBucket(tc, bktName)
+
+	w, r := prepareTusRequest(tc, http.MethodOptions, bktName, "", "", nil)
+	tc.Handler().TusOptionsHandler(w, r)
+	assertStatus(t, w, http.StatusNoContent)
+	require.Equal(t, tusProtocolVersion, w.Header().Get(api.TusVersion))
+	require.Equal(t, tusExtensions, w.Header().Get(api.TusExtension))
+
+	w, r = prepareTusRequest(tc, http.MethodPost, bktName, objName, "", nil)
+	r.Header.Set(api.TusResumable, "0.2.2")
+	r.Header.Set(api.UploadLength, "10")
+	tc.Handler().TusCreateHandler(w, r)
+	assertStatus(t, w, http.StatusPreconditionFailed)
+	require.Equal(t, tusProtocolVersion, w.Header().Get(api.TusVersion))
+
+	w, r = prepareTusRequest(tc, http.MethodPost, bktName, "", "", nil)
+	r.Header.Set(api.UploadLength, "10")
+	tc.Handler().TusCreateHandler(w, r)
+	assertStatus(t, w, http.StatusBadRequest)
+
+	w, r = prepareTusRequest(tc, http.MethodPatch, bktName, objName, "upload", bytes.NewReader([]byte("abc")))
+	r.Header.Set(api.UploadOffset, "0")
+	tc.Handler().TusPatchHandler(w, r)
+	assertS3Error(t, w, apiErrors.GetAPIError(apiErrors.ErrUnsupportedMediaType))
+}
+
+func TestTusUploadToken(t *testing.T) {
+	tc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-for-tus", "dir/object"
+	createTestBucket(tc, bktName)
+
+	box := tc.Context().Value(api.BoxData).(*accessbox.Box)
+	box.Gate.AccessKey = "box-secret"
+
+	createUpload := func(session *tokens.SessionClaims) *tokens.UploadClaims {
+		w, r := prepareTusRequest(tc, http.MethodPost, bktName, objName, "", nil)
+		r.Header.Set(api.UploadLength, "10")
+		ctx := context.WithValue(r.Context(), api.AccessKeyID, "access-key-id")
+		if session != nil {
+			ctx = context.WithValue(ctx, api.SessionClaims, session)
+		}
+		tc.Handler().TusCreateHandler(w, r.WithContext(ctx))
+		assertStatus(t, w, http.StatusCreated)
+
+		location, err := url.Parse(w.Header().Get(api.Location))
+		require.NoError(t, err)
+		uploadID := location.Query().Get(tusQuery)
+		token := location.Query().Get(auth.TusUploadToken)
+
+		claims, err := tokens.ParseUploadToken(token, time.Now())
+		require.NoError(t, err)
+		require.NoError(t, tokens.CheckUploadToken("box-secret", token))
+		require.Equal(t, uploadID, claims.UploadID)
+		return claims
+	}
+
+	claims := createUpload(nil)
+	require.Equal(t, "access-key-id", claims.AccessKeyID)
+	require.Equal(t, bktName, claims.Bucket)
+	require.Equal(t, objName, claims.Key)
+	require.Nil(t, claims.Session)
+	require.InDelta(t, time.Now().Add(DefaultTusTokenLifetime).Unix(), claims.Expiration, 5)
+
+	session := &tokens.SessionClaims{Expiration: time.Now().Add(time.Hour).Unix(), Subject: "subject"}
+	claims = createUpload(session)
+	require.Equal(t, session, claims.Session)
+	require.Equal(t, session.Expiration, claims.Expiration)
+
+	// token isn't issued without the access key ID, e.g. for anonymous requests
+	w, r := prepareTusRequest(tc, http.MethodPost, bktName, objName, "", nil)
+	r.Header.Set(api.UploadLength, "10")
+	tc.Handler().TusCreateHandler(w, r)
+	assertStatus(t, w, http.StatusCreated)
+	location, err := url.Parse(w.Header().Get(api.Location))
+	require.NoError(t, err)
+	require.Empty(t, location.Query().Get(auth.TusUploadToken))
+}
+
+func TestParseTusMetadata(t *testing.T) {
+	meta, err := parseTusMetadata("key dGVzdA==, Empty")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"key": "test", "empty": ""}, meta)
+
+	for _, header := range []string{"key !", "key dGVzdA== extra", "key,key", ",,"} {
+		_, err = parseTusMetadata(header)
+		require.Error(t, err, header)
+	}
+}
+
+func prepareTusRequest(hc *handlerContext, method, bktName, objName, uploadID string, body *bytes.Reader) (*httptest.ResponseRecorder, *http.Request) {
+	target := "/" + bktName + "/" + url.PathEscape(objName)
+	if len(uploadID) != 0 {
+		target += "?" + tusQuery + "=" + uploadID
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(method, target, nil)
+	if body != nil {
+		r = httptest.NewRequest(method, target, body)
+	}
+	r.Header.Set(api.TusResumable, tusProtocolVersion)
+
+	reqInfo := api.NewReqInfo(w, r, api.ObjectRequest{Bucket: bktName, Object: objName})
+	r = r.WithContext(api.SetReqInfo(hc.Context(), reqInfo))
+
+	return w, r
+}
+
+func patchTusUpload(t *testing.T, tc *handlerContext, bktName, objName, uploadID string, offset int64, content, checksum string, status int) *httptest.ResponseRecorder {
+	w, r := prepareTusRequest(tc, http.MethodPatch, bktName, objName, uploadID, bytes.NewReader([]byte(content)))
+	r.Header.Set(api.ContentType, tusPatchMediaType)
+	r.Header.Set(api.UploadOffset, strconv.FormatInt(offset, 10))
+	if len(checksum) != 0 {
+		r.Header.Set(api.UploadChecksum, checksum)
+	}
+	tc.Handler().TusPatchHandler(w, r)
+	assertStatus(t, w, status)
+	return w
+}
@@ -25,6 +25,9 @@ func (h *handler) logAndSendError(w http.ResponseWriter, logText string, reqInfo
 		zap.String("object", reqInfo.ObjectName),
 		zap.String("description", logText),
 		zap.Error(err)}
+	if reqInfo.Subject != "" {
+		fields = append(fields, zap.String("subject", reqInfo.Subject))
+	}
 	fields = append(fields, additional...)
 	h.log.Error("call method", fields...)
 }
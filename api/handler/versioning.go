@@ -8,6 +8,7 @@ import (
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/errors"
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/layer"
+	"github.com/TrueCloudLab/frostfs-s3-gw/audit"
 )
 
 func (h *handler) PutBucketVersioningHandler(w http.ResponseWriter, r *http.Request) {
@@ -52,7 +53,13 @@ func (h *handler) PutBucketVersioningHandler(w http.ResponseWriter, r *http.Requ
 
 	if err = h.obj.PutBucketSettings(r.Context(), p); err != nil {
 		h.logAndSendError(w, "couldn't put update versioning settings", reqInfo, err)
+		return
 	}
+
+	h.audit(r.Context(), &audit.Event{
+		Before: "versioning=" + settings.Versioning,
+		After:  "versioning=" + newSettings.Versioning,
+	})
 }
 
 // GetBucketVersioningHandler implements bucket versioning getter handler.
@@ -65,6 +65,21 @@ const (
 	ContainerID   = "X-Container-Id"
 	ContainerName = "X-Container-Name"
 
+	FrostFSRenameSource = "X-Frostfs-Rename-Source"
+
+	AmzSnowballAutoExtract = "X-Amz-Meta-Snowball-Auto-Extract"
+
+	AmzWriteOffsetBytes = "X-Amz-Write-Offset-Bytes"
+
+	TusResumable         = "Tus-Resumable"
+	TusVersion           = "Tus-Version"
+	TusExtension         = "Tus-Extension"
+	TusChecksumAlgorithm = "Tus-Checksum-Algorithm"
+	UploadOffset         = "Upload-Offset"
+	UploadLength         = "Upload-Length"
+	UploadMetadata       = "Upload-Metadata"
+	UploadChecksum       = "Upload-Checksum"
+
 	AccessControlAllowOrigin      = "Access-Control-Allow-Origin"
 	AccessControlAllowMethods     = "Access-Control-Allow-Methods"
 	AccessControlExposeHeaders    = "Access-Control-Expose-Headers"
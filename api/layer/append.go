@@ -0,0 +1,292 @@
+package layer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"sync"
+
+	"github.com/TrueCloudLab/frostfs-s3-gw/api"
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
+	apiErrors "github.com/TrueCloudLab/frostfs-s3-gw/api/errors"
+	"github.com/TrueCloudLab/frostfs-s3-gw/internal/tracing"
+	cid "github.com/TrueCloudLab/frostfs-sdk-go/container/id"
+	oid "github.com/TrueCloudLab/frostfs-sdk-go/object/id"
+	"go.uber.org/zap"
+)
+
+// AppendObjectParams stores object append request parameters.
+type AppendObjectParams struct {
+	BktInfo *data.BucketInfo
+	Object  string
+	// Offset must be equal to the current size of the object.
+	Offset uint64
+	Size   int64
+	Reader io.Reader
+	// Header is the metadata of the object, it's used only if the object is created by the append.
+	Header       map[string]string
+	CopiesNumber uint32
+}
+
+const (
+	// AppendedSegments is the header of the object info which lists segments
+	// appended to the payload of the version object (see data.SegmentsToString).
+	AppendedSegments = api.FrostFSSystemMetadataPrefix + "Appended-Segments"
+	// AppendedToAttributeName is the attribute of the segment object which stores
+	// the version ID of the object the segment is appended to.
+	AppendedToAttributeName = api.FrostFSSystemMetadataPrefix + "Appended-To"
+
+	// DefaultAppendMaxSegments is the default number of appended segments which triggers compaction.
+	DefaultAppendMaxSegments = 64
+
+	keyLockStripes = 64
+)
+
+// keyLocks serializes appends to the same object and writes to the same resumable upload
+// inside the gateway, so the write offset is checked against the actual size.
+type keyLocks [keyLockStripes]sync.Mutex
+
+func (l *keyLocks) lock(cnrID cid.ID, name string) func() {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(cnrID.EncodeToString()))
+	_, _ = h.Write([]byte(name))
+
+	mu := &l[h.Sum32()%keyLockStripes]
+	mu.Lock()
+	return mu.Unlock
+}
+
+// AppendObject appends the payload to the latest version of the object. The payload is stored as
+// a separate segment object, the version (and its ID) is kept. The object is created if it doesn't exist.
+// Segments are merged into a single one when their number exceeds the configured limit.
+func (n *layer) AppendObject(ctx context.Context, p *AppendObjectParams) (_ *data.ExtendedObjectInfo, err error) {
+	ctx, span := tracing.StartSpan(ctx, "layer.AppendObject")
+	defer func() { tracing.End(span, err) }()
+
+	unlock := n.keyLocks.lock(p.BktInfo.CID, p.Object)
+	defer unlock()
+
+	version, err := n.treeService.GetLatestVersion(ctx, p.BktInfo, p.Object)
+	if err != nil && !errors.Is(err, ErrNodeNotFound) {
+		return nil, fmt.Errorf("couldn't get latest version: %w", err)
+	}
+
+	if version == nil || version.IsDeleteMarker() {
+		if p.Offset != 0 {
+			return nil, apiErrors.GetAPIError(apiErrors.ErrInvalidWriteOffset)
+		}
+		return n.PutObject(ctx, &PutObjectParams{
+			BktInfo:      p.BktInfo,
+			Object:       p.Object,
+			Size:         p.Size,
+			Reader:       p.Reader,
+			Header:       p.Header,
+			CopiesNumber: p.CopiesNumber,
+		})
+	}
+
+	if p.Offset != uint64(version.Size) {
+		return nil, apiErrors.GetAPIError(apiErrors.ErrInvalidWriteOffset)
+	}
+
+	meta, err := n.objectHead(ctx, p.BktInfo, version.OID)
+	if err != nil {
+		return nil, err
+	}
+	objInfo := versionObjectInfo(objectInfoFromMeta(p.BktInfo, meta), version)
+	if FormEncryptionInfo(objInfo.Headers).Enabled {
+		return nil, apiErrors.GetAPIError(apiErrors.ErrNotImplemented)
+	}
+
+	if err = n.checkLocks(ctx, p.BktInfo, []*data.NodeVersion{version}); err != nil {
+		return nil, err
+	}
+
+	segmentID, hash, err := n.objectPutAndHash(ctx, n.segmentPrm(ctx, p.BktInfo, version, p.Size, p.Reader, p.CopiesNumber), p.BktInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := *version
+	updated.Segments = append(version.Segments[:len(version.Segments):len(version.Segments)],
+		&data.PartInfo{Number: len(version.Segments) + 1, OID: segmentID, Size: p.Size})
+	updated.Size += p.Size
+	updated.ETag = appendedETag(version.ETag, hash)
+
+	// The tree service has no conditional updates, so appends through other gateways are detected
+	// by reading the version right before and after the update. Appends to the same object
+	// are expected to be routed to the same gateway, see docs/aws_s3_compat.md.
+	if err = n.checkVersionUnchanged(ctx, p.BktInfo, version); err != nil {
+		n.deleteSegments(ctx, p.BktInfo, updated.Segments[len(version.Segments):])
+		return nil, err
+	}
+	if err = n.treeService.UpdateVersion(ctx, p.BktInfo, &updated); err != nil {
+		n.deleteSegments(ctx, p.BktInfo, updated.Segments[len(version.Segments):])
+		return nil, fmt.Errorf("couldn't update version in tree service: %w", err)
+	}
+	if err = n.checkSegmentAppended(ctx, p.BktInfo, &updated, segmentID); err != nil {
+		n.deleteSegments(ctx, p.BktInfo, updated.Segments[len(version.Segments):])
+		return nil, err
+	}
+	n.updateUsage(p.BktInfo, data.BucketUsage{Bytes: p.Size})
+
+	reqInfo := api.GetReqInfo(ctx)
+	n.log.Debug("append object",
+		zap.String("reqId", reqInfo.RequestID),
+		zap.String("bucket", p.BktInfo.Name), zap.Stringer("cid", p.BktInfo.CID),
+		zap.String("object", p.Object), zap.Stringer("segment", segmentID))
+
+	if len(updated.Segments) > n.appendMaxSegments {
+		if err = n.compactSegments(ctx, p.BktInfo, &updated, p.CopiesNumber); err != nil {
+			n.log.Warn("couldn't compact appended segments", zap.String("bucket", p.BktInfo.Name),
+				zap.String("object", p.Object), zap.Error(err))
+		}
+	}
+
+	n.cache.CleanListCacheEntriesContainingObject(p.Object, p.BktInfo.CID)
+	n.cache.DeleteObjectName(p.BktInfo.CID, p.BktInfo.Name, p.Object)
+	n.cache.DeleteObject(newAddress(p.BktInfo.CID, version.OID))
+	n.invalidate(InvalidateObject, p.BktInfo, p.Object, "")
+	n.invalidate(InvalidateObject, p.BktInfo, p.Object, version.OID.EncodeToString())
+
+	return &data.ExtendedObjectInfo{
+		ObjectInfo:  versionObjectInfo(objInfo, &updated),
+		NodeVersion: &updated,
+	}, nil
+}
+
+// compactSegments merges appended segments of the version into a single segment object,
+// so reading of the object doesn't need too many storage requests. The payload of the version object is kept.
+func (n *layer) compactSegments(ctx context.Context, bktInfo *data.BucketInfo, version *data.NodeVersion, copiesNumber uint32) error {
+	segments := version.Segments
+	size := partsSize(segments)
+
+	r := &multiObjectReader{
+		ctx:   ctx,
+		layer: n,
+		parts: segments,
+	}
+	r.prm.bktInfo = bktInfo
+
+	segmentID, _, err := n.objectPutAndHash(ctx, n.segmentPrm(ctx, bktInfo, version, size, r, copiesNumber), bktInfo)
+	if err != nil {
+		return fmt.Errorf("put compacted segment: %w", err)
+	}
+
+	if err = n.checkVersionUnchanged(ctx, bktInfo, version); err != nil {
+		n.deleteSegments(ctx, bktInfo, []*data.PartInfo{{OID: segmentID}})
+		return fmt.Errorf("check version: %w", err)
+	}
+
+	version.Segments = []*data.PartInfo{{Number: 1, OID: segmentID, Size: size}}
+	if err = n.treeService.UpdateVersion(ctx, bktInfo, version); err != nil {
+		n.deleteSegments(ctx, bktInfo, version.Segments)
+		version.Segments = segments
+		return fmt.Errorf("update version: %w", err)
+	}
+
+	n.deleteSegments(ctx, bktInfo, segments)
+	return nil
+}
+
+// checkVersionUnchanged returns ErrInvalidWriteOffset if the latest version of the object
+// isn't the expected one, e.g. the object is appended or overwritten through another gateway.
+func (n *layer) checkVersionUnchanged(ctx context.Context, bktInfo *data.BucketInfo, expected *data.NodeVersion) error {
+	latest, err := n.treeService.GetLatestVersion(ctx, bktInfo, expected.FilePath)
+	if errors.Is(err, ErrNodeNotFound) {
+		return apiErrors.GetAPIError(apiErrors.ErrInvalidWriteOffset)
+	}
+	if err != nil {
+		return fmt.Errorf("couldn't get latest version: %w", err)
+	}
+
+	if latest.ID != expected.ID || latest.ETag != expected.ETag || latest.Size != expected.Size {
+		return apiErrors.GetAPIError(apiErrors.ErrInvalidWriteOffset)
+	}
+	return nil
+}
+
+// checkSegmentAppended returns ErrInvalidWriteOffset if the latest version of the object doesn't contain
+// the appended segment, i.e. the update is overwritten by the concurrent one through another gateway.
+func (n *layer) checkSegmentAppended(ctx context.Context, bktInfo *data.BucketInfo, updated *data.NodeVersion, segmentID oid.ID) error {
+	latest, err := n.treeService.GetLatestVersion(ctx, bktInfo, updated.FilePath)
+	if err != nil && !errors.Is(err, ErrNodeNotFound) {
+		return fmt.Errorf("couldn't get latest version: %w", err)
+	}
+
+	if latest != nil && latest.ID == updated.ID {
+		for _, segment := range latest.Segments {
+			if segment.OID.Equals(segmentID) {
+				return nil
+			}
+		}
+	}
+
+	n.log.Warn("append is overwritten by concurrent update", zap.String("bucket", bktInfo.Name),
+		zap.String("object", updated.FilePath), zap.Stringer("segment", segmentID))
+	return apiErrors.GetAPIError(apiErrors.ErrInvalidWriteOffset)
+}
+
+func (n *layer) segmentPrm(ctx context.Context, bktInfo *data.BucketInfo, version *data.NodeVersion, size int64, r io.Reader, copiesNumber uint32) PrmObjectCreate {
+	return PrmObjectCreate{
+		Container:    bktInfo.CID,
+		Creator:      n.Owner(ctx),
+		Attributes:   [][2]string{{AppendedToAttributeName, version.OID.EncodeToString()}},
+		PayloadSize:  uint64(size),
+		Payload:      r,
+		CreationTime: TimeNow(ctx),
+		CopiesNumber: copiesNumber,
+	}
+}
+
+// deleteSegments deletes segment objects, errors are logged only.
+func (n *layer) deleteSegments(ctx context.Context, bktInfo *data.BucketInfo, segments []*data.PartInfo) {
+	for _, segment := range segments {
+		if err := n.objectDelete(ctx, bktInfo, segment.OID); err != nil {
+			n.log.Warn("couldn't delete appended segment", zap.String("bucket", bktInfo.Name),
+				zap.Stringer("oid", segment.OID), zap.Error(err))
+		}
+	}
+}
+
+// appendedETag forms the ETag of the object after the append from the previous ETag
+// and the hash of the appended segment.
+func appendedETag(prev string, segmentHash []byte) string {
+	h := sha256.New()
+	h.Write([]byte(prev))
+	h.Write(segmentHash)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// initAppendedPayloadReader reads the payload of the version object followed by payloads of appended segments.
+// The size of the object info is the total size of the payload.
+func (n *layer) initAppendedPayloadReader(ctx context.Context, p getParams, objInfo *data.ObjectInfo) (io.Reader, error) {
+	segments, err := data.ParseSegments(objInfo.Headers[AppendedSegments])
+	if err != nil {
+		return nil, fmt.Errorf("parse appended segments: %w", err)
+	}
+
+	parts := make([]*data.PartInfo, 0, len(segments)+1)
+	parts = append(parts, &data.PartInfo{OID: p.oid, Size: objInfo.Size - partsSize(segments)})
+	parts = append(parts, segments...)
+
+	r := &multiObjectReader{
+		ctx:   ctx,
+		layer: n,
+		prm:   getParams{bktInfo: p.bktInfo},
+		parts: parts,
+		off:   p.off,
+		ln:    p.ln,
+	}
+
+	// the first part is opened in advance to report access errors before the payload is written
+	if err = r.openNextPart(); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	return r, nil
+}
@@ -0,0 +1,176 @@
+package layer
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
+	apiErrors "github.com/TrueCloudLab/frostfs-s3-gw/api/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func (tc *testContext) appendObject(offset uint64, content string) (*data.ExtendedObjectInfo, error) {
+	return tc.layer.AppendObject(tc.ctx, &AppendObjectParams{
+		BktInfo: tc.bktInfo,
+		Object:  tc.obj,
+		Offset:  offset,
+		Size:    int64(len(content)),
+		Reader:  bytes.NewReader([]byte(content)),
+		Header:  make(map[string]string),
+	})
+}
+
+func (tc *testContext) getObjectRange(objInfo *data.ObjectInfo, start, end uint64) string {
+	content := bytes.NewBuffer(nil)
+	err := tc.layer.GetObject(tc.ctx, &GetObjectParams{
+		ObjectInfo: objInfo,
+		Writer:     content,
+		BucketInfo: tc.bktInfo,
+		Range:      &RangeParams{Start: start, End: end},
+	})
+	require.NoError(tc.t, err)
+	return content.String()
+}
+
+func TestAppendObject(t *testing.T) {
+	tc := prepareContext(t)
+
+	_, err := tc.appendObject(1, "ab")
+	require.True(t, apiErrors.IsS3Error(err, apiErrors.ErrInvalidWriteOffset), err)
+
+	created, err := tc.appendObject(0, "ab")
+	require.NoError(t, err)
+
+	var etags []string
+	for _, content := range []string{"cd", "ef"} {
+		extObjInfo, err := tc.appendObject(uint64(created.ObjectInfo.Size+int64(len(etags))*2), content)
+		require.NoError(t, err)
+		etags = append(etags, extObjInfo.ObjectInfo.HashSum)
+	}
+	require.NotEqual(t, etags[0], etags[1])
+
+	_, err = tc.appendObject(3, "gh")
+	require.True(t, apiErrors.IsS3Error(err, apiErrors.ErrInvalidWriteOffset), err)
+
+	objInfo, content := tc.getObject(tc.obj, "", false)
+	require.Equal(t, "abcdef", string(content))
+	require.EqualValues(t, 6, objInfo.Size)
+	require.Equal(t, etags[1], objInfo.HashSum)
+	require.Equal(t, created.ObjectInfo.VersionID(), objInfo.VersionID())
+
+	require.Equal(t, "bcde", tc.getObjectRange(objInfo, 1, 4))
+	require.Equal(t, "d", tc.getObjectRange(objInfo, 3, 3))
+	require.Equal(t, "ef", tc.getObjectRange(objInfo, 4, 5))
+
+	list := tc.listObjectsV2()
+	require.Len(t, list, 1)
+	require.EqualValues(t, 6, list[0].Size)
+
+	usage, err := tc.layer.GetBucketUsage(tc.ctx, tc.bktInfo)
+	require.NoError(t, err)
+	require.Equal(t, data.BucketUsage{Bytes: 6, Objects: 1, Versions: 1}, *usage)
+}
+
+func TestAppendObjectCompaction(t *testing.T) {
+	tc := prepareContext(t)
+	tc.layer.(*layer).appendMaxSegments = 2
+
+	created, err := tc.appendObject(0, "a")
+	require.NoError(t, err)
+
+	var extObjInfo *data.ExtendedObjectInfo
+	for i, content := range []string{"b", "c", "d"} {
+		extObjInfo, err = tc.appendObject(uint64(i+1), content)
+		require.NoError(t, err)
+	}
+	require.Len(t, extObjInfo.NodeVersion.Segments, 1)
+	require.EqualValues(t, 3, extObjInfo.NodeVersion.Segments[0].Size)
+
+	objInfo, content := tc.getObject(tc.obj, "", false)
+	require.Equal(t, "abcd", string(content))
+	require.Equal(t, created.ObjectInfo.VersionID(), objInfo.VersionID())
+
+	versions := tc.listVersions()
+	require.Len(t, versions.Version, 1)
+
+	// merged segments are deleted, the payload of the version and the compacted segment remain
+	require.Len(t, tc.testFrostFS.Objects(), 2)
+
+	settings, err := tc.layer.GetBucketSettings(tc.ctx, tc.bktInfo)
+	require.NoError(t, err)
+
+	// the compacted segment is deleted with the object
+	tc.deleteObject(tc.obj, "", settings)
+	require.Len(t, tc.testFrostFS.Objects(), 0)
+}
+
+// hookedTreeService calls the hook before the specified call of GetLatestVersion,
+// it imitates requests through another gateway in the middle of the operation.
+type hookedTreeService struct {
+	TreeService
+	calls    int
+	hookCall int
+	hook     func()
+}
+
+func (t *hookedTreeService) GetLatestVersion(ctx context.Context, bktInfo *data.BucketInfo, objectName string) (*data.NodeVersion, error) {
+	t.calls++
+	if t.calls == t.hookCall {
+		t.hook()
+	}
+	return t.TreeService.GetLatestVersion(ctx, bktInfo, objectName)
+}
+
+func TestAppendObjectThroughAnotherGateway(t *testing.T) {
+	tc := prepareContext(t)
+	n := tc.layer.(*layer)
+
+	_, err := tc.appendObject(0, "ab")
+	require.NoError(t, err)
+
+	other := NewLayer(zap.NewExample(), tc.testFrostFS, &Config{
+		Caches:      DefaultCachesConfigs(zap.NewExample()),
+		AnonKey:     n.anonKey,
+		TreeService: n.treeService,
+	})
+	// the version is appended through another gateway after it's read by the first one
+	n.treeService = &hookedTreeService{TreeService: n.treeService, hookCall: 2, hook: func() {
+		_, err := other.AppendObject(tc.ctx, &AppendObjectParams{
+			BktInfo: tc.bktInfo,
+			Object:  tc.obj,
+			Offset:  2,
+			Size:    2,
+			Reader:  bytes.NewReader([]byte("cd")),
+		})
+		require.NoError(t, err)
+	}}
+
+	_, err = tc.appendObject(2, "ef")
+	require.True(t, apiErrors.IsS3Error(err, apiErrors.ErrInvalidWriteOffset), err)
+
+	version, err := n.treeService.GetLatestVersion(tc.ctx, tc.bktInfo, tc.obj)
+	require.NoError(t, err)
+	require.EqualValues(t, 4, version.Size)
+
+	// the segment of the rejected append is deleted
+	require.Len(t, tc.testFrostFS.Objects(), 2)
+}
+
+func TestSegmentsString(t *testing.T) {
+	tc := prepareContext(t)
+
+	_, err := tc.appendObject(0, "a")
+	require.NoError(t, err)
+	extObjInfo, err := tc.appendObject(1, "bc")
+	require.NoError(t, err)
+
+	segments := extObjInfo.NodeVersion.Segments
+	parsed, err := data.ParseSegments(data.SegmentsToString(segments))
+	require.NoError(t, err)
+	require.Equal(t, segments, parsed)
+
+	_, err = data.ParseSegments("invalid")
+	require.Error(t, err)
+}
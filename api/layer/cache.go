@@ -1,6 +1,8 @@
 package layer
 
 import (
+	"fmt"
+
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/cache"
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
 	cid "github.com/TrueCloudLab/frostfs-sdk-go/container/id"
@@ -30,6 +32,19 @@ type CachesConfig struct {
 	AccessControl *cache.Config
 }
 
+// Names of caches which can be flushed.
+const (
+	CacheObjects       = "objects"
+	CacheList          = "list"
+	CacheNames         = "names"
+	CacheBuckets       = "buckets"
+	CacheSystem        = "system"
+	CacheAccessControl = "accesscontrol"
+)
+
+// CachesNames contains names of all caches of the layer.
+var CachesNames = []string{CacheObjects, CacheList, CacheNames, CacheBuckets, CacheSystem, CacheAccessControl}
+
 // DefaultCachesConfigs returns filled configs.
 func DefaultCachesConfigs(logger *zap.Logger) *CachesConfig {
 	return &CachesConfig{
@@ -55,6 +70,28 @@ func NewCache(cfg *CachesConfig) *Cache {
 	}
 }
 
+// Purge removes all entries from the cache with the provided name.
+func (c *Cache) Purge(name string) error {
+	switch name {
+	case CacheObjects:
+		c.objCache.Purge()
+	case CacheList:
+		c.listsCache.Purge()
+	case CacheNames:
+		c.namesCache.Purge()
+	case CacheBuckets:
+		c.bucketCache.Purge()
+	case CacheSystem:
+		c.systemCache.Purge()
+	case CacheAccessControl:
+		c.accessCache.Purge()
+	default:
+		return fmt.Errorf("unknown cache '%s'", name)
+	}
+
+	return nil
+}
+
 func (c *Cache) GetBucket(name string) *data.BucketInfo {
 	return c.bucketCache.Get(name)
 }
@@ -142,6 +179,24 @@ func (c *Cache) PutList(owner user.ID, key cache.ObjectsListKey, list []*data.No
 	}
 }
 
+func (c *Cache) GetDirectoryChildren(owner user.ID, key cache.ObjectsListKey) *data.TreeDirectoryChildren {
+	if !c.accessCache.Get(owner, key.String()) {
+		return nil
+	}
+
+	return c.listsCache.GetDirectoryChildren(key)
+}
+
+func (c *Cache) PutDirectoryChildren(owner user.ID, key cache.ObjectsListKey, children *data.TreeDirectoryChildren) {
+	if err := c.listsCache.PutDirectoryChildren(key, children); err != nil {
+		c.logger.Warn("couldn't cache directory children", zap.Error(err))
+	}
+
+	if err := c.accessCache.Put(owner, key.String()); err != nil {
+		c.logger.Warn("couldn't cache access control operation", zap.Error(err))
+	}
+}
+
 func (c *Cache) GetTagging(owner user.ID, key string) map[string]string {
 	if !c.accessCache.Get(owner, key) {
 		return nil
@@ -182,6 +237,10 @@ func (c *Cache) PutLockInfo(owner user.ID, key string, lockInfo *data.LockInfo)
 	}
 }
 
+func (c *Cache) DeleteLockInfo(key string) {
+	c.systemCache.Delete(key)
+}
+
 func (c *Cache) GetSettings(owner user.ID, bktInfo *data.BucketInfo) *data.BucketSettings {
 	key := bktInfo.Name + bktInfo.SettingsObjectName()
 
@@ -203,6 +262,10 @@ func (c *Cache) PutSettings(owner user.ID, bktInfo *data.BucketInfo, settings *d
 	}
 }
 
+func (c *Cache) DeleteSettings(bktInfo *data.BucketInfo) {
+	c.systemCache.Delete(bktInfo.Name + bktInfo.SettingsObjectName())
+}
+
 func (c *Cache) GetCORS(owner user.ID, bkt *data.BucketInfo) *data.CORSConfiguration {
 	key := bkt.Name + bkt.CORSObjectName()
 
@@ -249,3 +312,7 @@ func (c *Cache) PutNotificationConfiguration(owner user.ID, bktInfo *data.Bucket
 		c.logger.Warn("couldn't cache access control operation", zap.Error(err))
 	}
 }
+
+func (c *Cache) DeleteNotificationConfiguration(bktInfo *data.BucketInfo) {
+	c.systemCache.Delete(bktInfo.Name + bktInfo.NotificationConfigurationObjectName())
+}
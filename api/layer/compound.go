@@ -6,10 +6,13 @@ import (
 
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/errors"
+	"github.com/TrueCloudLab/frostfs-s3-gw/internal/tracing"
 )
 
-func (n *layer) GetObjectTaggingAndLock(ctx context.Context, objVersion *ObjectVersion, nodeVersion *data.NodeVersion) (map[string]string, *data.LockInfo, error) {
-	var err error
+func (n *layer) GetObjectTaggingAndLock(ctx context.Context, objVersion *ObjectVersion, nodeVersion *data.NodeVersion) (_ map[string]string, _ *data.LockInfo, err error) {
+	ctx, span := tracing.StartSpan(ctx, "layer.GetObjectTaggingAndLock")
+	defer func() { tracing.End(span, err) }()
+
 	owner := n.Owner(ctx)
 
 	tags := n.cache.GetTagging(owner, objectTaggingCacheKey(objVersion))
@@ -0,0 +1,270 @@
+package layer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/TrueCloudLab/frostfs-s3-gw/api"
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
+	"go.uber.org/zap"
+)
+
+// payloadCanBeShared checks if the copy can reference the payload object of the source version
+// instead of writing the new one: the payload must be stored in the same container and be
+// exactly the same (including encryption and placement).
+func payloadCanBeShared(p *CopyObjectParams) bool {
+	if p.RewritePayload || p.Range != nil || !p.ScrBktInfo.CID.Equals(p.DstBktInfo.CID) {
+		return false
+	}
+
+	// appended segments belong to the version, so the combined payload is copied
+	if len(p.SrcObject.Headers[AppendedSegments]) != 0 {
+		return false
+	}
+
+	if FormEncryptionInfo(p.SrcObject.Headers).Enabled != p.Encryption.Enabled() {
+		return false
+	}
+
+	return p.Header[AttributeFrostfsCopiesNumber] == p.SrcObject.Headers[AttributeFrostfsCopiesNumber]
+}
+
+// copyObjectByReference adds the unversioned version which references the payload object of the source version.
+// Headers of the copy are stored in the tree service. Returns false if the version can't be added
+// this way, so the payload must be copied: versions are identified by object ids, so in versioned
+// buckets the copy would share the version id (and locks) with the source. The only exception is
+// the latest version copied onto itself to replace metadata, it's updated in place.
+func (n *layer) copyObjectByReference(ctx context.Context, p *CopyObjectParams) (*data.ExtendedObjectInfo, bool, error) {
+	if p.Lock != nil && (p.Lock.Retention != nil || p.Lock.LegalHold != nil) {
+		return nil, false, nil
+	}
+
+	settings, err := n.GetBucketSettings(ctx, p.DstBktInfo)
+	if err != nil {
+		return nil, false, fmt.Errorf("couldn't get versioning settings object: %w", err)
+	}
+	if settings.VersioningEnabled() && !p.ReplaceMetadata {
+		return nil, false, nil
+	}
+
+	versions, err := n.treeService.GetVersions(ctx, p.DstBktInfo, p.DstObject)
+	if err != nil && !errors.Is(err, ErrNodeNotFound) {
+		return nil, false, fmt.Errorf("couldn't get versions: %w", err)
+	}
+
+	var replacesReference bool
+	var updated *data.NodeVersion
+	for _, version := range versions {
+		if !version.OID.Equals(p.SrcObject.ID) {
+			continue
+		}
+		switch {
+		case settings.VersioningEnabled() && !version.IsUnversioned && isLatestVersion(version, versions):
+			// the version doesn't reference another payload, so it must not become the unversioned one
+			updated = version
+		case settings.VersioningEnabled() || !version.IsUnversioned:
+			// versions of the object made before versioning was suspended have the same ids
+			return nil, false, nil
+		default:
+			replacesReference = true
+		}
+	}
+	if settings.VersioningEnabled() && updated == nil {
+		return nil, false, nil
+	}
+
+	size := p.SrcObject.Size
+	if p.Encryption.Enabled() {
+		if size, err = strconv.ParseInt(p.SrcObject.Headers[AttributeDecryptedSize], 10, 64); err != nil {
+			return nil, false, fmt.Errorf("invalid decrypted size: %w", err)
+		}
+	}
+
+	headers := make(map[string]string, len(p.Header))
+	for key, val := range p.Header {
+		if !strings.HasPrefix(key, api.FrostFSSystemMetadataPrefix) {
+			headers[key] = val
+		}
+	}
+	if len(headers[api.ContentType]) == 0 {
+		if headers[api.ContentType] = MimeByFilePath(p.DstObject); len(headers[api.ContentType]) == 0 {
+			headers[api.ContentType] = p.SrcObject.ContentType
+		}
+	}
+
+	newVersion := &data.NodeVersion{
+		BaseNodeVersion: data.BaseNodeVersion{
+			OID:      p.SrcObject.ID,
+			FilePath: p.DstObject,
+			Size:     size,
+			ETag:     p.SrcObject.HashSum,
+		},
+		Copy: &data.CopyInfo{
+			Created: TimeNow(ctx),
+			Owner:   n.Owner(ctx),
+			Headers: headers,
+		},
+		IsUnversioned: updated == nil,
+	}
+
+	if updated != nil {
+		// metadata of the object copied onto itself is updated in place
+		newVersion.ID, newVersion.ParenID = updated.ID, updated.ParenID
+		if err = n.treeService.UpdateVersion(ctx, p.DstBktInfo, newVersion); err != nil {
+			return nil, false, fmt.Errorf("couldn't update version in tree service: %w", err)
+		}
+		n.updateUsage(p.DstBktInfo, versionUsage(newVersion).Sub(versionUsage(updated)))
+		n.cache.DeleteObject(newAddress(p.DstBktInfo.CID, newVersion.OID))
+		n.invalidate(InvalidateObject, p.DstBktInfo, p.DstObject, newVersion.OID.EncodeToString())
+	} else {
+		// the replaced unversioned version already references the payload
+		if !replacesReference {
+			if err = n.treeService.AddObjectReference(ctx, p.DstBktInfo, p.SrcObject.ID); err != nil {
+				return nil, false, fmt.Errorf("couldn't add object reference: %w", err)
+			}
+		}
+		if err = n.addVersion(ctx, p.DstBktInfo, newVersion); err != nil {
+			return nil, false, fmt.Errorf("couldn't add new verion to tree service: %w", err)
+		}
+	}
+	n.cache.CleanListCacheEntriesContainingObject(p.DstObject, p.DstBktInfo.CID)
+
+	reqInfo := api.GetReqInfo(ctx)
+	n.log.Debug("copy object by reference",
+		zap.String("reqId", reqInfo.RequestID),
+		zap.String("bucket", p.DstBktInfo.Name), zap.Stringer("cid", p.DstBktInfo.CID),
+		zap.String("object", p.DstObject), zap.Stringer("oid", newVersion.OID))
+
+	// versions which reference payload of another version aren't cached
+	// since object cache is keyed by object address
+	n.cache.DeleteObjectName(p.DstBktInfo.CID, p.DstBktInfo.Name, p.DstObject)
+	n.invalidate(InvalidateObject, p.DstBktInfo, p.DstObject, "")
+
+	objInfo := *p.SrcObject
+	objInfo.Bucket = p.DstBktInfo.Name
+
+	return &data.ExtendedObjectInfo{
+		ObjectInfo:  versionObjectInfo(&objInfo, newVersion),
+		NodeVersion: newVersion,
+	}, true, nil
+}
+
+func isLatestVersion(version *data.NodeVersion, versions []*data.NodeVersion) bool {
+	for _, other := range versions {
+		if other.Timestamp > version.Timestamp {
+			return false
+		}
+	}
+	return true
+}
+
+// versionObjectInfo sets attributes of the object info which are defined by the version node
+// rather than by the object header: the name (the object could be renamed), attributes of the copy
+// which references the payload object of another version and the payload appended to the object.
+func versionObjectInfo(objInfo *data.ObjectInfo, node *data.NodeVersion) *data.ObjectInfo {
+	objInfo.Name = node.FilePath
+
+	if node.Copy != nil {
+		headers := make(map[string]string, len(node.Copy.Headers))
+		// system attributes describe the payload (e.g. encryption), so they are kept
+		for key, val := range objInfo.Headers {
+			if strings.HasPrefix(key, api.FrostFSSystemMetadataPrefix) {
+				headers[key] = val
+			}
+		}
+		for key, val := range node.Copy.Headers {
+			headers[key] = val
+		}
+
+		objInfo.ContentType = headers[api.ContentType]
+		delete(headers, api.ContentType)
+		objInfo.Headers = headers
+		objInfo.Created = node.Copy.Created
+		objInfo.Owner = node.Copy.Owner
+	}
+
+	if len(node.Segments) != 0 {
+		objInfo.Headers[AppendedSegments] = data.SegmentsToString(node.Segments)
+		objInfo.Size = node.Size
+		objInfo.HashSum = node.ETag
+	}
+
+	return objInfo
+}
+
+// objectInfoCacheable checks if the object info of the version can be cached by the address
+// of the payload object: attributes of copies and appended objects are defined by the version node.
+func objectInfoCacheable(node *data.NodeVersion) bool {
+	return node.Copy == nil && len(node.Segments) == 0
+}
+
+// addVersion adds the version to the tree service and updates the bucket usage by the added version
+// and the replaced one. If the new version replaces the unversioned version which references
+// the payload of another version, the reference is removed.
+func (n *layer) addVersion(ctx context.Context, bktInfo *data.BucketInfo, version *data.NodeVersion) (err error) {
+	var replaced *data.NodeVersion
+	if version.IsUnversioned {
+		if replaced, err = n.treeService.GetUnversioned(ctx, bktInfo, version.FilePath); err != nil && !errors.Is(err, ErrNodeNotFound) {
+			return fmt.Errorf("couldn't get unversioned version: %w", err)
+		}
+	}
+	latest := n.latestVersion(ctx, bktInfo, version.FilePath)
+
+	if version.ID, err = n.treeService.AddVersion(ctx, bktInfo, version); err != nil {
+		return err
+	}
+	n.updateUsage(bktInfo, addedVersionUsage(version, latest, replaced))
+
+	if replaced != nil && !replaced.OID.Equals(version.OID) {
+		n.removeReplacedReference(ctx, bktInfo, replaced)
+	}
+	return nil
+}
+
+// removeReplacedReference removes the reference of the replaced version to the payload of another version,
+// so the payload is deleted with the last version referencing it. Errors are logged only.
+func (n *layer) removeReplacedReference(ctx context.Context, bktInfo *data.BucketInfo, replaced *data.NodeVersion) {
+	if replaced.Copy == nil {
+		return
+	}
+
+	references, err := n.treeService.GetObjectReferences(ctx, bktInfo, replaced.OID)
+	if err == nil && len(references) != 0 {
+		err = n.treeService.RemoveObjectReference(ctx, bktInfo, references[0])
+	}
+	if err != nil {
+		n.log.Warn("couldn't remove reference of replaced version", zap.String("bucket", bktInfo.Name),
+			zap.String("object", replaced.FilePath), zap.Stringer("oid", replaced.OID), zap.Error(err))
+	}
+}
+
+// deleteVersionObject deletes the payload object of the removed version
+// if it isn't referenced by other versions. Appended segments belong to the version, so they are always deleted.
+func (n *layer) deleteVersionObject(ctx context.Context, bktInfo *data.BucketInfo, version *data.NodeVersion) error {
+	references, err := n.treeService.GetObjectReferences(ctx, bktInfo, version.OID)
+	if err != nil {
+		return fmt.Errorf("couldn't get object references: %w", err)
+	}
+
+	if len(references) == 0 {
+		if err = n.objectDelete(ctx, bktInfo, version.OID); err != nil {
+			return err
+		}
+	} else {
+		// locks are checked by FrostFS only on deletion of the object
+		if err = n.checkLocks(ctx, bktInfo, []*data.NodeVersion{version}); err != nil {
+			return err
+		}
+
+		n.cache.DeleteObject(newAddress(bktInfo.CID, version.OID))
+		if err = n.treeService.RemoveObjectReference(ctx, bktInfo, references[0]); err != nil {
+			return err
+		}
+	}
+
+	n.deleteSegments(ctx, bktInfo, version.Segments)
+	return nil
+}
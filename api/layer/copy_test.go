@@ -0,0 +1,131 @@
+package layer
+
+import (
+	"testing"
+
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
+	"github.com/stretchr/testify/require"
+)
+
+func (tc *testContext) copyObject(srcObjInfo *data.ObjectInfo, dstObject string, header map[string]string) *data.ObjectInfo {
+	extObjInfo, err := tc.layer.CopyObject(tc.ctx, &CopyObjectParams{
+		SrcObject:  srcObjInfo,
+		ScrBktInfo: tc.bktInfo,
+		DstBktInfo: tc.bktInfo,
+		DstObject:  dstObject,
+		SrcSize:    srcObjInfo.Size,
+		Header:     header,
+	})
+	require.NoError(tc.t, err)
+
+	return extObjInfo.ObjectInfo
+}
+
+func TestCopyObjectByReference(t *testing.T) {
+	tc := prepareContext(t)
+	settings := &data.BucketSettings{Versioning: data.VersioningUnversioned}
+
+	tc.obj = "src"
+	src := tc.putObject([]byte("content"))
+	require.Len(t, tc.testFrostFS.Objects(), 1)
+
+	dst := tc.copyObject(src, "dst", map[string]string{"key": "val", "Content-Type": "text/plain"})
+	require.Equal(t, src.ID, dst.ID)
+	require.Len(t, tc.testFrostFS.Objects(), 1)
+
+	objInfo, content := tc.getObject("dst", "", false)
+	require.Equal(t, "dst", objInfo.Name)
+	require.Equal(t, "content", string(content))
+	require.Equal(t, "text/plain", objInfo.ContentType)
+	require.Equal(t, map[string]string{"key": "val"}, objInfo.Headers)
+
+	objInfo, _ = tc.getObject("src", "", false)
+	require.Equal(t, "src", objInfo.Name)
+	require.Empty(t, objInfo.Headers["key"])
+
+	// the payload is deleted with the last version referencing it
+	tc.deleteObject("src", "", settings)
+	require.NotNil(t, tc.getObjectByID(src.ID))
+	tc.getObject("dst", "", false)
+
+	tc.deleteObject("dst", "", settings)
+	require.Nil(t, tc.getObjectByID(src.ID))
+}
+
+func TestCopyObjectByReferenceVersions(t *testing.T) {
+	tc := prepareContext(t)
+	settings := &data.BucketSettings{Versioning: data.VersioningUnversioned}
+
+	tc.obj = "a"
+	src := tc.putObject([]byte("content"))
+	dst := tc.copyObject(src, "b", map[string]string{})
+	require.Equal(t, src.ID, dst.ID)
+
+	// versions referencing the same payload are resolved separately
+	versions := tc.listVersions()
+	require.Len(t, versions.Version, 2)
+	require.Equal(t, "a", versions.Version[0].ObjectInfo.Name)
+	require.Equal(t, "b", versions.Version[1].ObjectInfo.Name)
+
+	// the overwritten copy doesn't reference the payload anymore
+	tc.obj = "b"
+	tc.putObject([]byte("new content"))
+	tc.deleteObject("a", "", settings)
+	require.Nil(t, tc.getObjectByID(src.ID))
+}
+
+func TestCopyObjectByReferenceVersioned(t *testing.T) {
+	tc := prepareContext(t)
+	err := tc.layer.PutBucketSettings(tc.ctx, &PutSettingsParams{
+		BktInfo:  tc.bktInfo,
+		Settings: &data.BucketSettings{Versioning: data.VersioningEnabled},
+	})
+	require.NoError(t, err)
+
+	tc.obj = "a"
+	src := tc.putObject([]byte("content"))
+
+	// versions are identified by object ids, so the payload is copied in versioned buckets
+	dst := tc.copyObject(src, "b", map[string]string{})
+	require.NotEqual(t, src.ID, dst.ID)
+	require.Len(t, tc.testFrostFS.Objects(), 2)
+
+	versions := tc.listVersions()
+	require.Len(t, versions.Version, 2)
+	require.Equal(t, "a", versions.Version[0].ObjectInfo.Name)
+	require.Equal(t, "b", versions.Version[1].ObjectInfo.Name)
+
+	_, content := tc.getObject("b", dst.VersionID(), false)
+	require.Equal(t, "content", string(content))
+}
+
+func TestCopyObjectMetadataInPlace(t *testing.T) {
+	tc := prepareContext(t)
+	settings := &data.BucketSettings{Versioning: data.VersioningEnabled}
+	err := tc.layer.PutBucketSettings(tc.ctx, &PutSettingsParams{BktInfo: tc.bktInfo, Settings: settings})
+	require.NoError(t, err)
+
+	src := tc.putObject([]byte("content"))
+
+	extObjInfo, err := tc.layer.CopyObject(tc.ctx, &CopyObjectParams{
+		SrcObject:       src,
+		ScrBktInfo:      tc.bktInfo,
+		DstBktInfo:      tc.bktInfo,
+		DstObject:       tc.obj,
+		SrcSize:         src.Size,
+		Header:          map[string]string{"key": "val"},
+		ReplaceMetadata: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, src.ID, extObjInfo.ObjectInfo.ID)
+	require.Len(t, tc.testFrostFS.Objects(), 1)
+	require.Len(t, tc.listVersions().Version, 1)
+
+	objInfo, content := tc.getObject(tc.obj, "", false)
+	require.Equal(t, "val", objInfo.Headers["key"])
+	require.Equal(t, "content", string(content))
+
+	// no reference is added, so the payload is deleted with the version
+	tc.deleteObject(tc.obj, src.VersionID(), settings)
+	require.Nil(t, tc.getObjectByID(src.ID))
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/errors"
+	"github.com/TrueCloudLab/frostfs-s3-gw/internal/tracing"
 	"go.uber.org/zap"
 )
 
@@ -17,7 +18,10 @@ const wildcard = "*"
 
 var supportedMethods = map[string]struct{}{"GET": {}, "HEAD": {}, "POST": {}, "PUT": {}, "DELETE": {}}
 
-func (n *layer) PutBucketCORS(ctx context.Context, p *PutCORSParams) error {
+func (n *layer) PutBucketCORS(ctx context.Context, p *PutCORSParams) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "layer.PutBucketCORS")
+	defer func() { tracing.End(span, err) }()
+
 	var (
 		buf  bytes.Buffer
 		tee  = io.TeeReader(p.Reader, &buf)
@@ -66,11 +70,15 @@ func (n *layer) PutBucketCORS(ctx context.Context, p *PutCORSParams) error {
 	}
 
 	n.cache.PutCORS(n.Owner(ctx), p.BktInfo, cors)
+	n.invalidate(InvalidateCORS, p.BktInfo, "", "")
 
 	return nil
 }
 
-func (n *layer) GetBucketCORS(ctx context.Context, bktInfo *data.BucketInfo) (*data.CORSConfiguration, error) {
+func (n *layer) GetBucketCORS(ctx context.Context, bktInfo *data.BucketInfo) (_ *data.CORSConfiguration, err error) {
+	ctx, span := tracing.StartSpan(ctx, "layer.GetBucketCORS")
+	defer func() { tracing.End(span, err) }()
+
 	cors, err := n.getCORS(ctx, bktInfo)
 	if err != nil {
 		if errorsStd.Is(err, ErrNodeNotFound) {
@@ -82,7 +90,10 @@ func (n *layer) GetBucketCORS(ctx context.Context, bktInfo *data.BucketInfo) (*d
 	return cors, nil
 }
 
-func (n *layer) DeleteBucketCORS(ctx context.Context, bktInfo *data.BucketInfo) error {
+func (n *layer) DeleteBucketCORS(ctx context.Context, bktInfo *data.BucketInfo) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "layer.DeleteBucketCORS")
+	defer func() { tracing.End(span, err) }()
+
 	objID, err := n.treeService.DeleteBucketCORS(ctx, bktInfo)
 	objIDNotFound := errorsStd.Is(err, ErrNoNodeToRemove)
 	if err != nil && !objIDNotFound {
@@ -95,6 +106,7 @@ func (n *layer) DeleteBucketCORS(ctx context.Context, bktInfo *data.BucketInfo)
 	}
 
 	n.cache.DeleteCORS(bktInfo)
+	n.invalidate(InvalidateCORS, bktInfo, "", "")
 
 	return nil
 }
@@ -0,0 +1,242 @@
+package layer
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TrueCloudLab/frostfs-s3-gw/api"
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
+	apiErrors "github.com/TrueCloudLab/frostfs-s3-gw/api/errors"
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/layer/encryption"
+	"github.com/TrueCloudLab/frostfs-s3-gw/internal/tracing"
+	"go.uber.org/zap"
+)
+
+type (
+	// ExtractArchiveParams stores info for the extraction of tar (or tar.gz) archive into separate objects.
+	ExtractArchiveParams struct {
+		BktInfo *data.BucketInfo
+		// Prefix is prepended to the names of archive entries.
+		Prefix string
+		Reader io.Reader
+		// Header is the metadata of every extracted object.
+		Header       map[string]string
+		Lock         *data.ObjectLock
+		Encryption   encryption.Params
+		CopiesNumber uint32
+	}
+
+	// ExtractArchiveResult stores the result of the archive extraction.
+	ExtractArchiveResult struct {
+		Extracted int
+		Size      int64
+		Errors    []ExtractError
+	}
+
+	// ExtractError describes the archive entry which isn't extracted.
+	ExtractError struct {
+		// Name is the object name or the entry name if the object name can't be formed.
+		Name string
+		Err  error
+	}
+)
+
+const (
+	// extractBatchSize is the number of extracted objects which versions are added to the tree service at once.
+	extractBatchSize = 100
+
+	// MetadataMtime is the metadata of the extracted object which stores modification time
+	// of the archive entry (in seconds since epoch).
+	MetadataMtime = "mtime"
+)
+
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// ExtractArchive stores every regular file of the archive as a separate object. Versions of objects are
+// added to the tree service in batches. Errors of entries don't interrupt the extraction, they are
+// returned in the result. The error is returned if the archive can't be read.
+func (n *layer) ExtractArchive(ctx context.Context, p *ExtractArchiveParams) (_ *ExtractArchiveResult, err error) {
+	ctx, span := tracing.StartSpan(ctx, "layer.ExtractArchive")
+	defer func() { tracing.End(span, err) }()
+
+	bktSettings, err := n.GetBucketSettings(ctx, p.BktInfo)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get versioning settings object: %w", err)
+	}
+
+	r := bufio.NewReader(p.Reader)
+	if magic, err := r.Peek(len(gzipMagic)); err == nil && bytes.Equal(magic, gzipMagic) {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, n.invalidArchive(ctx, err)
+		}
+		defer gz.Close()
+		p.Reader = gz
+	} else {
+		p.Reader = r
+	}
+
+	result := &ExtractArchiveResult{}
+	batch := make([]*data.ExtendedObjectInfo, 0, extractBatchSize)
+	tr := tar.NewReader(p.Reader)
+
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			n.addExtractedBatch(ctx, p, batch, result)
+			return result, n.invalidArchive(ctx, err)
+		}
+
+		if header.Typeflag == tar.TypeDir {
+			continue
+		}
+
+		name, err := extractedObjectName(p.Prefix, header)
+		if err != nil {
+			result.Errors = append(result.Errors, ExtractError{Name: header.Name, Err: err})
+			continue
+		}
+
+		metadata := make(map[string]string, len(p.Header)+1)
+		for key, val := range p.Header {
+			metadata[key] = val
+		}
+		if !header.ModTime.IsZero() {
+			metadata[MetadataMtime] = formatMtime(header.ModTime)
+		}
+
+		extObjInfo, err := n.putObjectPayload(ctx, &PutObjectParams{
+			BktInfo:      p.BktInfo,
+			Object:       name,
+			Size:         header.Size,
+			Reader:       tr,
+			Header:       metadata,
+			Encryption:   p.Encryption,
+			CopiesNumber: p.CopiesNumber,
+		}, bktSettings)
+		if err != nil {
+			result.Errors = append(result.Errors, ExtractError{Name: name, Err: err})
+			continue
+		}
+
+		if batch = append(batch, extObjInfo); len(batch) == extractBatchSize {
+			n.addExtractedBatch(ctx, p, batch, result)
+			batch = batch[:0]
+		}
+	}
+
+	n.addExtractedBatch(ctx, p, batch, result)
+
+	return result, nil
+}
+
+// formatMtime formats the time as seconds since epoch with the fraction if it's present (PAX archives).
+func formatMtime(mtime time.Time) string {
+	res := strconv.FormatInt(mtime.Unix(), 10)
+	if nsec := mtime.Nanosecond(); nsec != 0 {
+		res += "." + strings.TrimRight(fmt.Sprintf("%09d", nsec), "0")
+	}
+	return res
+}
+
+func (n *layer) invalidArchive(ctx context.Context, err error) error {
+	reqInfo := api.GetReqInfo(ctx)
+	n.log.Debug("couldn't read archive", zap.String("reqId", reqInfo.RequestID), zap.Error(err))
+	return apiErrors.GetAPIError(apiErrors.ErrInvalidArgument)
+}
+
+// extractedObjectName forms the name of the object from the archive entry name.
+func extractedObjectName(prefix string, header *tar.Header) (string, error) {
+	if header.Typeflag != tar.TypeReg && header.Typeflag != tar.TypeRegA {
+		return "", fmt.Errorf("unsupported entry type '%c'", header.Typeflag)
+	}
+
+	name := strings.TrimPrefix(header.Name, "./")
+	if len(name) == 0 || path.IsAbs(name) || strings.HasSuffix(name, "/") {
+		return "", fmt.Errorf("invalid entry name")
+	}
+	for _, elem := range strings.Split(name, "/") {
+		if elem == ".." {
+			return "", fmt.Errorf("invalid entry name")
+		}
+	}
+
+	return prefix + name, nil
+}
+
+// addExtractedBatch adds versions of the stored objects to the tree service. Payloads of the objects
+// which versions can't be added are removed.
+func (n *layer) addExtractedBatch(ctx context.Context, p *ExtractArchiveParams, batch []*data.ExtendedObjectInfo, result *ExtractArchiveResult) {
+	if len(batch) == 0 {
+		return
+	}
+
+	versions := make([]*data.NodeVersion, len(batch))
+	for i, extObjInfo := range batch {
+		versions[i] = extObjInfo.NodeVersion
+	}
+
+	replaced, err := n.treeService.AddVersions(ctx, p.BktInfo, versions)
+	if err != nil {
+		n.log.Error("couldn't add extracted versions to tree service", zap.String("bucket", p.BktInfo.Name),
+			zap.Stringer("cid", p.BktInfo.CID), zap.Error(err))
+	}
+	for _, version := range replaced {
+		n.removeReplacedReference(ctx, p.BktInfo, version)
+	}
+
+	var added []*data.NodeVersion
+	for _, extObjInfo := range batch {
+		objInfo, version := extObjInfo.ObjectInfo, extObjInfo.NodeVersion
+		if version.ID == 0 {
+			result.Errors = append(result.Errors, ExtractError{Name: objInfo.Name, Err: err})
+			if err := n.objectDelete(ctx, p.BktInfo, objInfo.ID); err != nil {
+				n.log.Warn("couldn't delete extracted object", zap.String("bucket", p.BktInfo.Name),
+					zap.String("object", objInfo.Name), zap.Stringer("oid", objInfo.ID), zap.Error(err))
+			}
+			continue
+		}
+		added = append(added, version)
+
+		if p.Lock != nil && (p.Lock.Retention != nil || p.Lock.LegalHold != nil) {
+			putLockInfoPrms := &PutLockInfoParams{
+				ObjVersion: &ObjectVersion{
+					BktInfo:    p.BktInfo,
+					ObjectName: objInfo.Name,
+					VersionID:  objInfo.VersionID(),
+				},
+				NewLock:      p.Lock,
+				CopiesNumber: p.CopiesNumber,
+				NodeVersion:  version,
+			}
+			if err := n.PutLockInfo(ctx, putLockInfoPrms); err != nil {
+				result.Errors = append(result.Errors, ExtractError{Name: objInfo.Name, Err: err})
+			}
+		}
+
+		result.Extracted++
+		result.Size += version.Size
+
+		n.cache.CleanListCacheEntriesContainingObject(objInfo.Name, p.BktInfo.CID)
+		n.cache.PutObjectWithName(objInfo.Owner, extObjInfo)
+		n.invalidate(InvalidateObject, p.BktInfo, objInfo.Name, "")
+	}
+
+	// usage of objects which already have versioned versions is approximate,
+	// the drift is fixed by reconciliation
+	usage := data.UsageOfVersions(added)
+	n.updateUsage(p.BktInfo, usage.Sub(data.UsageOfVersions(replaced)))
+}
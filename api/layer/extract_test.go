@@ -0,0 +1,94 @@
+package layer
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
+	"github.com/stretchr/testify/require"
+)
+
+type testEntry struct {
+	header  tar.Header
+	content string
+}
+
+func makeTar(t *testing.T, compress bool, entries ...testEntry) io.Reader {
+	buf := new(bytes.Buffer)
+	var w io.Writer = buf
+
+	var gz *gzip.Writer
+	if compress {
+		gz = gzip.NewWriter(buf)
+		w = gz
+	}
+
+	tw := tar.NewWriter(w)
+	for _, entry := range entries {
+		header := entry.header
+		header.Size = int64(len(entry.content))
+		require.NoError(t, tw.WriteHeader(&header))
+		_, err := tw.Write([]byte(entry.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	if gz != nil {
+		require.NoError(t, gz.Close())
+	}
+
+	return buf
+}
+
+func TestExtractArchive(t *testing.T) {
+	tc := prepareContext(t)
+	mtime := time.Unix(1600000000, 0)
+
+	for _, compress := range []bool{false, true} {
+		reader := makeTar(t, compress,
+			testEntry{header: tar.Header{Typeflag: tar.TypeDir, Name: "dir/"}},
+			testEntry{header: tar.Header{Typeflag: tar.TypeReg, Name: "dir/a", ModTime: mtime}, content: "content-a"},
+			testEntry{header: tar.Header{Typeflag: tar.TypeReg, Name: "./b"}, content: "content-b"},
+			testEntry{header: tar.Header{Typeflag: tar.TypeSymlink, Name: "link", Linkname: "b"}},
+			testEntry{header: tar.Header{Typeflag: tar.TypeReg, Name: "../escape"}, content: "escape"},
+		)
+
+		result, err := tc.layer.ExtractArchive(tc.ctx, &ExtractArchiveParams{
+			BktInfo: tc.bktInfo,
+			Prefix:  "prefix/",
+			Reader:  reader,
+			Header:  map[string]string{"key": "val"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, 2, result.Extracted)
+		require.EqualValues(t, 18, result.Size)
+		require.Len(t, result.Errors, 2)
+		require.Equal(t, "link", result.Errors[0].Name)
+		require.Equal(t, "../escape", result.Errors[1].Name)
+
+		objInfo, content := tc.getObject("prefix/dir/a", "", false)
+		require.Equal(t, "content-a", string(content))
+		require.Equal(t, "val", objInfo.Headers["key"])
+		require.Equal(t, "1600000000", objInfo.Headers[MetadataMtime])
+
+		_, content = tc.getObject("prefix/b", "", false)
+		require.Equal(t, "content-b", string(content))
+	}
+
+	// unversioned objects are replaced by the second extraction
+	require.Len(t, tc.listVersions().Version, 2)
+	usage, err := tc.layer.GetBucketUsage(tc.ctx, tc.bktInfo)
+	require.NoError(t, err)
+	require.Equal(t, data.BucketUsage{Bytes: 18, Objects: 2, Versions: 2}, *usage)
+
+	require.Equal(t, "1600000000.5", formatMtime(time.Unix(1600000000, 500000000)))
+
+	_, err = tc.layer.ExtractArchive(tc.ctx, &ExtractArchiveParams{
+		BktInfo: tc.bktInfo,
+		Reader:  bytes.NewReader([]byte("not an archive")),
+	})
+	require.Error(t, err)
+}
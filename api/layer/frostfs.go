@@ -128,6 +128,18 @@ type PrmObjectDelete struct {
 	Object oid.ID
 }
 
+// PrmObjectSearch groups parameters of FrostFS.SearchObjects operation.
+type PrmObjectSearch struct {
+	// Authentication parameters.
+	PrmAuth
+
+	// Container to search objects in.
+	Container cid.ID
+
+	// Filters of object attributes, all of them must match.
+	Filters []ObjectFilter
+}
+
 // ErrAccessDenied is returned from FrostFS in case of access violation.
 var ErrAccessDenied = errors.New("access denied")
 
@@ -210,6 +222,14 @@ type FrostFS interface {
 	// It returns any error encountered which prevented the removal request from being sent.
 	DeleteObject(context.Context, PrmObjectDelete) error
 
+	// SearchObjects returns identifiers of root regular objects of the FrostFS container
+	// which attributes match the filters (all objects if there are no filters).
+	//
+	// It returns ErrAccessDenied on search access violation.
+	//
+	// It returns any error encountered which prevented the objects from being listed.
+	SearchObjects(context.Context, PrmObjectSearch) ([]oid.ID, error)
+
 	// TimeToEpoch computes current epoch and the epoch that corresponds to the provided now and future time.
 	// Note:
 	// * future time must be after the now
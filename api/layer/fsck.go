@@ -0,0 +1,382 @@
+package layer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
+	"github.com/TrueCloudLab/frostfs-s3-gw/internal/tracing"
+	"github.com/TrueCloudLab/frostfs-sdk-go/client"
+	oid "github.com/TrueCloudLab/frostfs-sdk-go/object/id"
+	"go.uber.org/zap"
+)
+
+type (
+	// FsckParams stores params of the bucket consistency check.
+	FsckParams struct {
+		BktInfo *data.BucketInfo
+		// GracePeriod protects recently created objects from being considered orphans:
+		// they can be referenced by the tree when in-progress requests are finished.
+		GracePeriod time.Duration
+		// RestoreNodes enables re-creation of version nodes for orphans
+		// whose names aren't present in the version tree.
+		RestoreNodes bool
+		// DeleteOrphans enables removal of orphans (which weren't restored).
+		DeleteOrphans bool
+	}
+
+	// FsckReport describes inconsistencies between the tree service and FrostFS objects of the bucket.
+	FsckReport struct {
+		// Objects is the number of regular objects in the bucket container.
+		Objects int
+		// Versions is the number of version nodes in the tree (including delete markers).
+		Versions int
+		// Orphans are objects not referenced by any tree node.
+		Orphans []FsckObject
+		// Dangling are tree nodes referencing objects which don't exist.
+		Dangling []FsckReference
+		// DuplicateLatest are names of objects which have several nodes claiming to be the latest version.
+		DuplicateLatest []string
+		// Restored are orphans version nodes were re-created for.
+		Restored []FsckObject
+		// Deleted are orphans which were removed.
+		Deleted []FsckObject
+	}
+
+	// FsckObject is an object of the bucket container.
+	FsckObject struct {
+		ID      oid.ID
+		Name    string
+		Created time.Time
+	}
+
+	// FsckReference is a reference to an object from the tree node.
+	FsckReference struct {
+		Kind   string
+		Name   string
+		NodeID uint64
+		OID    oid.ID
+	}
+)
+
+// Kinds of tree nodes referencing objects.
+const (
+	FsckVersion      = "version"
+	FsckLegalHold    = "legal-hold"
+	FsckRetention    = "retention"
+	FsckPart         = "part"
+	FsckSegment      = "segment"
+	FsckCORS         = "cors"
+	FsckNotification = "notification"
+)
+
+// Fsck compares nodes of the tree service with objects of the bucket container.
+// The tree is read before the container is searched, so objects created during the check
+// can be only reported as orphans and the grace period protects them.
+func (n *layer) Fsck(ctx context.Context, p *FsckParams) (_ *FsckReport, err error) {
+	ctx, span := tracing.StartSpan(ctx, "layer.Fsck")
+	defer func() { tracing.End(span, err) }()
+
+	versions, err := n.treeService.GetAllVersionsByPrefix(ctx, p.BktInfo, "")
+	if err != nil && !errors.Is(err, ErrNodeNotFound) {
+		return nil, fmt.Errorf("get all versions: %w", err)
+	}
+
+	refs, err := n.fsckReferences(ctx, p.BktInfo, versions)
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := n.frostFS.SearchObjects(ctx, n.fsckSearchPrm(ctx, p.BktInfo))
+	if err != nil {
+		return nil, fmt.Errorf("search objects: %w", err)
+	}
+
+	report := &FsckReport{
+		Objects:         len(ids),
+		Versions:        len(versions),
+		DuplicateLatest: duplicateLatest(versions),
+	}
+
+	existing := make(map[oid.ID]struct{}, len(ids))
+	for _, id := range ids {
+		existing[id] = struct{}{}
+	}
+
+	referenced := make(map[oid.ID]struct{}, len(refs))
+	for _, ref := range refs {
+		referenced[ref.OID] = struct{}{}
+		if _, ok := existing[ref.OID]; ok {
+			continue
+		}
+
+		// lock objects aren't regular ones and the object could be created after the search
+		exists, err := n.fsckObjectExists(ctx, p.BktInfo, ref.OID)
+		if err != nil {
+			return nil, fmt.Errorf("head object '%s' referenced by %s node %d: %w", ref.OID.EncodeToString(), ref.Kind, ref.NodeID, err)
+		}
+		if !exists {
+			report.Dangling = append(report.Dangling, ref)
+		}
+	}
+
+	deadline := TimeNow(ctx).Add(-p.GracePeriod)
+	for _, id := range ids {
+		if _, ok := referenced[id]; ok {
+			continue
+		}
+
+		meta, err := n.objectHead(ctx, p.BktInfo, id)
+		if err != nil {
+			if client.IsErrObjectNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("head orphan object '%s': %w", id.EncodeToString(), err)
+		}
+
+		objInfo := objectInfoFromMeta(p.BktInfo, meta)
+		if objInfo.Created.After(deadline) {
+			continue
+		}
+
+		report.Orphans = append(report.Orphans, FsckObject{ID: id, Name: objInfo.Name, Created: objInfo.Created})
+	}
+
+	sort.Slice(report.Orphans, func(i, j int) bool {
+		if report.Orphans[i].Name == report.Orphans[j].Name {
+			return report.Orphans[i].Created.Before(report.Orphans[j].Created)
+		}
+		return report.Orphans[i].Name < report.Orphans[j].Name
+	})
+
+	if err = n.fsckRepair(ctx, p, versions, report); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+func (n *layer) fsckSearchPrm(ctx context.Context, bktInfo *data.BucketInfo) PrmObjectSearch {
+	prm := PrmObjectSearch{Container: bktInfo.CID}
+	n.prepareAuthParameters(ctx, &prm.PrmAuth, bktInfo.Owner)
+	return prm
+}
+
+// fsckReferences collects objects referenced by version (including appended segments), lock, part and system nodes of the bucket.
+func (n *layer) fsckReferences(ctx context.Context, bktInfo *data.BucketInfo, versions []*data.NodeVersion) ([]FsckReference, error) {
+	refs := make([]FsckReference, 0, len(versions))
+
+	for _, version := range versions {
+		if !version.IsDeleteMarker() {
+			refs = append(refs, FsckReference{Kind: FsckVersion, Name: version.FilePath, NodeID: version.ID, OID: version.OID})
+		}
+		for _, segment := range version.Segments {
+			refs = append(refs, FsckReference{Kind: FsckSegment, Name: version.FilePath, NodeID: version.ID, OID: segment.OID})
+		}
+
+		lockInfo, err := n.treeService.GetLock(ctx, bktInfo, version.ID)
+		if err != nil && !errors.Is(err, ErrNodeNotFound) {
+			return nil, fmt.Errorf("get lock of '%s': %w", version.FilePath, err)
+		}
+		if lockInfo == nil {
+			continue
+		}
+		if lockInfo.IsLegalHoldSet() {
+			refs = append(refs, FsckReference{Kind: FsckLegalHold, Name: version.FilePath, NodeID: lockInfo.ID(), OID: lockInfo.LegalHold()})
+		}
+		if lockInfo.IsRetentionSet() {
+			refs = append(refs, FsckReference{Kind: FsckRetention, Name: version.FilePath, NodeID: lockInfo.ID(), OID: lockInfo.Retention()})
+		}
+	}
+
+	uploads, err := n.treeService.GetMultipartUploadsByPrefix(ctx, bktInfo, "")
+	if err != nil && !errors.Is(err, ErrNodeNotFound) {
+		return nil, fmt.Errorf("get multipart uploads: %w", err)
+	}
+	for _, upload := range uploads {
+		parts, err := n.treeService.GetParts(ctx, bktInfo, upload.ID)
+		if err != nil && !errors.Is(err, ErrNodeNotFound) {
+			return nil, fmt.Errorf("get parts of upload '%s': %w", upload.UploadID, err)
+		}
+		for _, part := range parts {
+			refs = append(refs, FsckReference{Kind: FsckPart, Name: upload.Key, NodeID: upload.ID, OID: part.OID})
+		}
+	}
+
+	corsID, err := n.treeService.GetBucketCORS(ctx, bktInfo)
+	if err != nil && !errors.Is(err, ErrNodeNotFound) {
+		return nil, fmt.Errorf("get bucket cors: %w", err)
+	} else if err == nil {
+		refs = append(refs, FsckReference{Kind: FsckCORS, Name: bktInfo.CORSObjectName(), OID: corsID})
+	}
+
+	notificationID, err := n.treeService.GetNotificationConfigurationNode(ctx, bktInfo)
+	if err != nil && !errors.Is(err, ErrNodeNotFound) {
+		return nil, fmt.Errorf("get notification configuration: %w", err)
+	} else if err == nil {
+		refs = append(refs, FsckReference{Kind: FsckNotification, Name: bktInfo.NotificationConfigurationObjectName(), OID: notificationID})
+	}
+
+	return refs, nil
+}
+
+func (n *layer) fsckObjectExists(ctx context.Context, bktInfo *data.BucketInfo, id oid.ID) (bool, error) {
+	if _, err := n.objectHead(ctx, bktInfo, id); err != nil {
+		if client.IsErrObjectNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// duplicateLatest returns names of objects which have several unversioned nodes
+// or several nodes with the greatest timestamp.
+func duplicateLatest(versions []*data.NodeVersion) []string {
+	type latest struct {
+		timestamp   uint64
+		count       int
+		unversioned int
+	}
+
+	byName := make(map[string]*latest)
+	for _, version := range versions {
+		l, ok := byName[version.FilePath]
+		if !ok {
+			l = &latest{}
+			byName[version.FilePath] = l
+		}
+
+		if version.IsUnversioned {
+			l.unversioned++
+		}
+
+		switch {
+		case l.count == 0 || version.Timestamp > l.timestamp:
+			l.timestamp, l.count = version.Timestamp, 1
+		case version.Timestamp == l.timestamp:
+			l.count++
+		}
+	}
+
+	var result []string
+	for name, l := range byName {
+		if l.count > 1 || l.unversioned > 1 {
+			result = append(result, name)
+		}
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+// fsckRepair restores version nodes and removes orphans according to the params.
+// Nodes are re-created only for names which have no versions in the tree at all,
+// otherwise the restored node would become the latest version regardless of the object age.
+// Parts of multipart uploads and system objects aren't restored.
+func (n *layer) fsckRepair(ctx context.Context, p *FsckParams, versions []*data.NodeVersion, report *FsckReport) error {
+	if !p.RestoreNodes && !p.DeleteOrphans {
+		return nil
+	}
+
+	restored := make(map[oid.ID]struct{})
+
+	if p.RestoreNodes {
+		settings, err := n.GetBucketSettings(ctx, p.BktInfo)
+		if err != nil {
+			return fmt.Errorf("get bucket settings: %w", err)
+		}
+
+		inTree := make(map[string]struct{}, len(versions))
+		for _, version := range versions {
+			inTree[version.FilePath] = struct{}{}
+		}
+
+		// orphans are sorted by name and creation time, so the latest version is restored last
+		for i, orphan := range report.Orphans {
+			if _, ok := inTree[orphan.Name]; ok {
+				continue
+			}
+			if !settings.VersioningEnabled() && i+1 < len(report.Orphans) && report.Orphans[i+1].Name == orphan.Name {
+				continue
+			}
+
+			ok, err := n.restoreVersionNode(ctx, p.BktInfo, orphan, !settings.VersioningEnabled())
+			if err != nil {
+				return fmt.Errorf("restore version node of '%s': %w", orphan.ID.EncodeToString(), err)
+			}
+			if ok {
+				restored[orphan.ID] = struct{}{}
+				report.Restored = append(report.Restored, orphan)
+			}
+		}
+	}
+
+	if p.DeleteOrphans {
+		for _, orphan := range report.Orphans {
+			if _, ok := restored[orphan.ID]; ok {
+				continue
+			}
+
+			if err := n.objectDelete(ctx, p.BktInfo, orphan.ID); err != nil {
+				n.log.Warn("couldn't delete orphan object", zap.String("bucket", p.BktInfo.Name),
+					zap.Stringer("cid", p.BktInfo.CID), zap.Stringer("oid", orphan.ID), zap.Error(err))
+				continue
+			}
+			report.Deleted = append(report.Deleted, orphan)
+		}
+	}
+
+	if len(report.Restored) != 0 || len(report.Deleted) != 0 {
+		if _, err := n.ReconcileBucketUsage(ctx, p.BktInfo); err != nil {
+			return fmt.Errorf("reconcile bucket usage: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// restoreVersionNode adds the version node for the orphan if it is an object version.
+func (n *layer) restoreVersionNode(ctx context.Context, bktInfo *data.BucketInfo, orphan FsckObject, unversioned bool) (bool, error) {
+	if orphan.Name == "" || orphan.Name == bktInfo.CORSObjectName() || orphan.Name == bktInfo.NotificationConfigurationObjectName() {
+		return false, nil
+	}
+
+	meta, err := n.objectHead(ctx, bktInfo, orphan.ID)
+	if err != nil {
+		return false, err
+	}
+
+	objInfo := objectInfoFromMeta(bktInfo, meta)
+	if _, ok := objInfo.Headers[UploadIDAttributeName]; ok {
+		return false, nil
+	}
+
+	size := objInfo.Size
+	if decSize, ok := objInfo.Headers[AttributeDecryptedSize]; ok {
+		if size, err = strconv.ParseInt(decSize, 10, 64); err != nil {
+			return false, fmt.Errorf("invalid decrypted size header: %w", err)
+		}
+	}
+
+	newVersion := &data.NodeVersion{
+		BaseNodeVersion: data.BaseNodeVersion{
+			OID:      orphan.ID,
+			FilePath: orphan.Name,
+			Size:     size,
+			ETag:     objInfo.HashSum,
+		},
+		IsUnversioned: unversioned,
+	}
+
+	if err = n.addVersion(ctx, bktInfo, newVersion); err != nil {
+		return false, err
+	}
+	n.cache.CleanListCacheEntriesContainingObject(orphan.Name, bktInfo.CID)
+
+	return true, nil
+}
@@ -0,0 +1,98 @@
+package layer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFsck(t *testing.T) {
+	tc := prepareContext(t)
+	treeService := tc.layer.(*layer).treeService
+	err := tc.layer.PutBucketSettings(tc.ctx, &PutSettingsParams{
+		BktInfo:  tc.bktInfo,
+		Settings: &data.BucketSettings{Versioning: data.VersioningEnabled},
+	})
+	require.NoError(t, err)
+
+	putObject := func(name string) *data.NodeVersion {
+		extObjInfo, err := tc.layer.PutObject(tc.ctx, &PutObjectParams{
+			BktInfo: tc.bktInfo,
+			Object:  name,
+			Reader:  bytes.NewReader([]byte(name)),
+			Header:  make(map[string]string),
+		})
+		require.NoError(t, err)
+		return extObjInfo.NodeVersion
+	}
+
+	fsck := func(p *FsckParams) *FsckReport {
+		p.BktInfo = tc.bktInfo
+		report, err := tc.layer.Fsck(tc.ctx, p)
+		require.NoError(t, err)
+		return report
+	}
+
+	dangling := putObject("dangling")
+	orphan := putObject("orphan")
+	putObject("consistent")
+	removed := putObject("removed")
+	putObject("removed")
+
+	uploadInfo := &UploadInfoParams{UploadID: "upload", Bkt: tc.bktInfo, Key: "multipart"}
+	require.NoError(t, tc.layer.CreateMultipartUpload(tc.ctx, &CreateMultipartParams{Info: uploadInfo}))
+	_, err = tc.layer.UploadPart(tc.ctx, &UploadPartParams{
+		Info:       uploadInfo,
+		PartNumber: 1,
+		Size:       3,
+		Reader:     bytes.NewReader([]byte("abc")),
+	})
+	require.NoError(t, err)
+
+	report := fsck(&FsckParams{})
+	require.Empty(t, report.Orphans)
+	require.Empty(t, report.Dangling)
+	require.Equal(t, 6, report.Objects)
+	require.Equal(t, 5, report.Versions)
+
+	require.NoError(t, tc.testFrostFS.DeleteObject(tc.ctx, PrmObjectDelete{Container: tc.bktInfo.CID, Object: dangling.OID}))
+	require.NoError(t, treeService.RemoveVersion(tc.ctx, tc.bktInfo, orphan.ID))
+	require.NoError(t, treeService.RemoveVersion(tc.ctx, tc.bktInfo, removed.ID))
+
+	report = fsck(&FsckParams{})
+	require.Equal(t, []FsckReference{{Kind: FsckVersion, Name: "dangling", NodeID: dangling.ID, OID: dangling.OID}}, report.Dangling)
+	require.Len(t, report.Orphans, 2)
+	require.Equal(t, "orphan", report.Orphans[0].Name)
+	require.Equal(t, "removed", report.Orphans[1].Name)
+	require.Empty(t, report.Restored)
+	require.Empty(t, report.Deleted)
+
+	// the name of the second orphan is in the tree, so it can be only deleted
+	report = fsck(&FsckParams{RestoreNodes: true, DeleteOrphans: true})
+	require.Equal(t, []FsckObject{report.Orphans[0]}, report.Restored)
+	require.Equal(t, []FsckObject{report.Orphans[1]}, report.Deleted)
+	require.Nil(t, tc.getObjectByID(removed.OID))
+
+	objInfo, _ := tc.getObject("orphan", "", false)
+	require.Equal(t, orphan.OID, objInfo.ID)
+
+	report = fsck(&FsckParams{})
+	require.Empty(t, report.Orphans)
+	require.Len(t, report.Dangling, 1)
+}
+
+func TestFsckDuplicateLatest(t *testing.T) {
+	versions := []*data.NodeVersion{
+		{BaseNodeVersion: data.BaseNodeVersion{FilePath: "tie", Timestamp: 1}},
+		{BaseNodeVersion: data.BaseNodeVersion{FilePath: "tie", Timestamp: 2}},
+		{BaseNodeVersion: data.BaseNodeVersion{FilePath: "tie", Timestamp: 2}},
+		{BaseNodeVersion: data.BaseNodeVersion{FilePath: "unversioned", Timestamp: 1}, IsUnversioned: true},
+		{BaseNodeVersion: data.BaseNodeVersion{FilePath: "unversioned", Timestamp: 2}, IsUnversioned: true},
+		{BaseNodeVersion: data.BaseNodeVersion{FilePath: "ok", Timestamp: 2}},
+		{BaseNodeVersion: data.BaseNodeVersion{FilePath: "ok", Timestamp: 3}, IsUnversioned: true},
+	}
+
+	require.Equal(t, []string{"tie", "unversioned"}, duplicateLatest(versions))
+}
@@ -0,0 +1,150 @@
+package layer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
+	oid "github.com/TrueCloudLab/frostfs-sdk-go/object/id"
+	"github.com/google/uuid"
+	"github.com/nats-io/nats.go"
+	"go.uber.org/zap"
+)
+
+type (
+	// CacheInvalidationBus delivers cache invalidation messages to all gateways.
+	CacheInvalidationBus interface {
+		Publish(subject string, data []byte) error
+		SubscribeBroadcast(ctx context.Context, subject string, handler MsgHandler) error
+	}
+
+	// CacheInvalidation describes cache entries which are stale after state mutation on another gateway.
+	CacheInvalidation struct {
+		// Source is an identifier of the gateway which sent the message.
+		Source    string `json:"source"`
+		Kind      string `json:"kind"`
+		CID       string `json:"cid"`
+		Bucket    string `json:"bucket"`
+		Object    string `json:"object,omitempty"`
+		VersionID string `json:"versionId,omitempty"`
+	}
+
+	cacheInvalidator struct {
+		bus     CacheInvalidationBus
+		subject string
+		source  string
+	}
+)
+
+// Kinds of cache invalidation messages.
+const (
+	InvalidateObject       = "object"
+	InvalidateBucket       = "bucket"
+	InvalidateSettings     = "settings"
+	InvalidateCORS         = "cors"
+	InvalidateNotification = "notification"
+	InvalidateTagging      = "tagging"
+	InvalidateLock         = "lock"
+)
+
+// InitializeCacheInvalidation subscribes to cache invalidation messages of other gateways
+// and starts to publish invalidation messages when the layer mutates state.
+func (n *layer) InitializeCacheInvalidation(ctx context.Context, bus CacheInvalidationBus, subject string) error {
+	if n.invalidator != nil {
+		return fmt.Errorf("cache invalidation already initialized")
+	}
+
+	invalidator := &cacheInvalidator{
+		bus:     bus,
+		subject: subject,
+		source:  uuid.NewString(),
+	}
+
+	if err := bus.SubscribeBroadcast(ctx, subject, MsgHandlerFunc(func(_ context.Context, msg *nats.Msg) error {
+		return n.handleCacheInvalidation(invalidator.source, msg.Data)
+	})); err != nil {
+		return fmt.Errorf("subscribe to cache invalidation: %w", err)
+	}
+
+	n.invalidator = invalidator
+	return nil
+}
+
+// invalidate publishes cache invalidation message for other gateways.
+func (n *layer) invalidate(kind string, bktInfo *data.BucketInfo, object, versionID string) {
+	if n.invalidator == nil {
+		return
+	}
+
+	msg, err := json.Marshal(CacheInvalidation{
+		Source:    n.invalidator.source,
+		Kind:      kind,
+		CID:       bktInfo.CID.EncodeToString(),
+		Bucket:    bktInfo.Name,
+		Object:    object,
+		VersionID: versionID,
+	})
+	if err != nil {
+		n.log.Error("couldn't marshal cache invalidation", zap.Error(err))
+		return
+	}
+
+	if err = n.invalidator.bus.Publish(n.invalidator.subject, msg); err != nil {
+		n.log.Warn("couldn't publish cache invalidation", zap.String("kind", kind),
+			zap.String("bucket", bktInfo.Name), zap.String("object", object), zap.Error(err))
+	}
+}
+
+func (n *layer) handleCacheInvalidation(source string, msg []byte) error {
+	var inv CacheInvalidation
+	if err := json.Unmarshal(msg, &inv); err != nil {
+		return fmt.Errorf("unmarshal cache invalidation: %w", err)
+	}
+
+	if inv.Source == source {
+		return nil
+	}
+
+	bktInfo := &data.BucketInfo{Name: inv.Bucket}
+	if err := bktInfo.CID.DecodeString(inv.CID); err != nil {
+		return fmt.Errorf("invalid container id '%s': %w", inv.CID, err)
+	}
+
+	objVersion := &ObjectVersion{BktInfo: bktInfo, ObjectName: inv.Object, VersionID: inv.VersionID}
+
+	switch inv.Kind {
+	case InvalidateObject:
+		n.cache.DeleteObjectName(bktInfo.CID, bktInfo.Name, inv.Object)
+		n.cache.CleanListCacheEntriesContainingObject(inv.Object, bktInfo.CID)
+		var objID oid.ID
+		if objID.DecodeString(inv.VersionID) == nil {
+			n.cache.DeleteObject(newAddress(bktInfo.CID, objID))
+		}
+	case InvalidateBucket:
+		n.cache.DeleteBucket(bktInfo.Name)
+	case InvalidateSettings:
+		n.cache.DeleteSettings(bktInfo)
+	case InvalidateCORS:
+		n.cache.DeleteCORS(bktInfo)
+	case InvalidateNotification:
+		n.cache.DeleteNotificationConfiguration(bktInfo)
+	case InvalidateTagging:
+		if inv.Object == "" {
+			n.cache.DeleteTagging(bucketTaggingCacheKey(bktInfo.CID))
+			break
+		}
+		n.cache.DeleteTagging(objectTaggingCacheKey(objVersion))
+		// entry of the latest version can be cached without version id
+		n.cache.DeleteTagging(objectTaggingCacheKey(&ObjectVersion{BktInfo: bktInfo, ObjectName: inv.Object}))
+	case InvalidateLock:
+		n.cache.DeleteLockInfo(lockObjectKey(objVersion))
+		n.cache.DeleteLockInfo(lockObjectKey(&ObjectVersion{BktInfo: bktInfo, ObjectName: inv.Object}))
+	default:
+		return fmt.Errorf("unknown cache invalidation kind '%s'", inv.Kind)
+	}
+
+	n.log.Debug("cache entries are invalidated", zap.String("kind", inv.Kind),
+		zap.String("bucket", inv.Bucket), zap.String("object", inv.Object))
+	return nil
+}
@@ -0,0 +1,105 @@
+package layer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type testInvalidationBus struct {
+	handlers []MsgHandler
+}
+
+func (b *testInvalidationBus) Publish(subject string, data []byte) error {
+	for _, h := range b.handlers {
+		if err := h.HandleMessage(context.Background(), &nats.Msg{Subject: subject, Data: data}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *testInvalidationBus) SubscribeBroadcast(_ context.Context, _ string, handler MsgHandler) error {
+	b.handlers = append(b.handlers, handler)
+	return nil
+}
+
+func TestCacheInvalidation(t *testing.T) {
+	tc := prepareContext(t)
+
+	// another gateway works with the same storage and tree service
+	replica := NewLayer(zap.NewExample(), tc.testFrostFS, &Config{
+		Caches:      DefaultCachesConfigs(zap.NewExample()),
+		AnonKey:     tc.layer.(*layer).anonKey,
+		TreeService: tc.layer.(*layer).treeService,
+	})
+
+	bus := &testInvalidationBus{}
+	require.NoError(t, tc.layer.InitializeCacheInvalidation(tc.ctx, bus, "invalidation"))
+	require.NoError(t, replica.InitializeCacheInvalidation(tc.ctx, bus, "invalidation"))
+	require.Error(t, replica.InitializeCacheInvalidation(tc.ctx, bus, "invalidation"))
+
+	settings, err := replica.GetBucketSettings(tc.ctx, tc.bktInfo)
+	require.NoError(t, err)
+	require.False(t, settings.VersioningEnabled())
+
+	err = tc.layer.PutBucketSettings(tc.ctx, &PutSettingsParams{
+		BktInfo:  tc.bktInfo,
+		Settings: &data.BucketSettings{Versioning: data.VersioningEnabled},
+	})
+	require.NoError(t, err)
+
+	settings, err = replica.GetBucketSettings(tc.ctx, tc.bktInfo)
+	require.NoError(t, err)
+	require.True(t, settings.VersioningEnabled())
+
+	headObject := func() *data.ObjectInfo {
+		objInfo, err := replica.GetObjectInfo(tc.ctx, &HeadObjectParams{BktInfo: tc.bktInfo, Object: tc.obj})
+		require.NoError(t, err)
+		return objInfo
+	}
+
+	tc.putObject([]byte("content1"))
+	require.Equal(t, int64(8), headObject().Size)
+
+	objInfo := tc.putObject([]byte("content22"))
+	require.Equal(t, objInfo.ID, headObject().ID)
+
+	versions, err := replica.ListObjectVersions(tc.ctx, &ListObjectVersionsParams{BktInfo: tc.bktInfo, MaxKeys: 1000})
+	require.NoError(t, err)
+	require.Len(t, versions.Version, 2)
+
+	tc.deleteObject(tc.obj, "", settings)
+
+	_, err = replica.GetObjectInfo(tc.ctx, &HeadObjectParams{BktInfo: tc.bktInfo, Object: tc.obj})
+	require.Error(t, err)
+
+	versions, err = replica.ListObjectVersions(tc.ctx, &ListObjectVersionsParams{BktInfo: tc.bktInfo, MaxKeys: 1000})
+	require.NoError(t, err)
+	require.Len(t, versions.DeleteMarker, 1)
+
+	listObjects := func() []string {
+		res, err := replica.ListObjectsV2(tc.ctx, &ListObjectsParamsV2{
+			ListObjectsParamsCommon: ListObjectsParamsCommon{BktInfo: tc.bktInfo, MaxKeys: 1000},
+		})
+		require.NoError(t, err)
+		names := make([]string, len(res.Objects))
+		for i, obj := range res.Objects {
+			names[i] = obj.Name
+		}
+		return names
+	}
+
+	// cached lists of the directory are dropped on the other gateway
+	tc.obj = "dir/obj1"
+	tc.putObject([]byte("content"))
+	require.Equal(t, []string{"dir/obj1"}, listObjects())
+
+	tc.obj = "dir/obj2"
+	tc.putObject([]byte("content"))
+	require.Equal(t, []string{"dir/obj1", "dir/obj2"}, listObjects())
+}
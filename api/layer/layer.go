@@ -12,10 +12,12 @@ import (
 	"time"
 
 	"github.com/TrueCloudLab/frostfs-s3-gw/api"
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/cache"
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/errors"
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/layer/encryption"
 	"github.com/TrueCloudLab/frostfs-s3-gw/creds/accessbox"
+	"github.com/TrueCloudLab/frostfs-s3-gw/internal/tracing"
 	"github.com/TrueCloudLab/frostfs-sdk-go/bearer"
 	cid "github.com/TrueCloudLab/frostfs-sdk-go/container/id"
 	"github.com/TrueCloudLab/frostfs-sdk-go/eacl"
@@ -52,6 +54,18 @@ type (
 		ncontroller EventListener
 		cache       *Cache
 		treeService TreeService
+		usage       *usageRegistry
+		usageOwners []user.ID
+		metrics     OperationMetrics
+		// invalidator is nil if cache invalidation between gateways is disabled.
+		invalidator *cacheInvalidator
+		// payloadCache is nil if local payload cache is disabled.
+		payloadCache *cache.PayloadCache
+		keyLocks     keyLocks
+		// appendMaxSegments is the number of appended segments which triggers compaction.
+		appendMaxSegments int
+		// maxCachedChildren is the greatest number of directory children kept in the list cache.
+		maxCachedChildren int
 	}
 
 	Config struct {
@@ -60,6 +74,16 @@ type (
 		AnonKey      AnonymousKey
 		Resolver     BucketResolver
 		TreeService  TreeService
+		// Metrics is optional, storage operations aren't observed if it's nil.
+		Metrics OperationMetrics
+		// PayloadCache is optional, payloads of small objects are cached on a local disk if it's set.
+		PayloadCache *cache.PayloadCache
+		// AppendMaxSegments is the number of appended segments which triggers compaction,
+		// DefaultAppendMaxSegments is used if it's not positive.
+		AppendMaxSegments int
+		// UsageOwners are owners whose containers are enumerated by usage loading and reconciliation
+		// in addition to owners of buckets served by the gateway.
+		UsageOwners []user.ID
 	}
 
 	// AnonymousKey contains data for anonymous requests.
@@ -140,6 +164,12 @@ type (
 		Lock        *data.ObjectLock
 		Encryption  encryption.Params
 		CopiesNuber uint32
+		// RewritePayload disables referencing of the source payload object by the copy
+		// (e.g. if object ACL is set), so the payload is always copied.
+		RewritePayload bool
+		// ReplaceMetadata is set if the object is copied onto itself to change its metadata.
+		// If the new version can't reference the source payload, metadata of the latest version is updated in place.
+		ReplaceMetadata bool
 	}
 	// CreateBucketParams stores bucket create request parameters.
 	CreateBucketParams struct {
@@ -186,7 +216,9 @@ type (
 	// Client provides S3 API client interface.
 	Client interface {
 		Initialize(ctx context.Context, c EventListener) error
+		InitializeCacheInvalidation(ctx context.Context, bus CacheInvalidationBus, subject string) error
 		EphemeralKey() *keys.PublicKey
+		FlushCache(name string) error
 
 		GetBucketSettings(ctx context.Context, bktInfo *data.BucketInfo) (*data.BucketSettings, error)
 		PutBucketSettings(ctx context.Context, p *PutSettingsParams) error
@@ -218,11 +250,15 @@ type (
 		DeleteObjectTagging(ctx context.Context, p *ObjectVersion) (*data.NodeVersion, error)
 
 		PutObject(ctx context.Context, p *PutObjectParams) (*data.ExtendedObjectInfo, error)
+		AppendObject(ctx context.Context, p *AppendObjectParams) (*data.ExtendedObjectInfo, error)
+		ExtractArchive(ctx context.Context, p *ExtractArchiveParams) (*ExtractArchiveResult, error)
 
 		CopyObject(ctx context.Context, p *CopyObjectParams) (*data.ExtendedObjectInfo, error)
+		RenameObject(ctx context.Context, p *RenameObjectParams) ([]*RenamedObject, error)
 
 		ListObjectsV1(ctx context.Context, p *ListObjectsParamsV1) (*ListObjectsInfoV1, error)
 		ListObjectsV2(ctx context.Context, p *ListObjectsParamsV2) (*ListObjectsInfoV2, error)
+		SearchObjects(ctx context.Context, p *SearchObjectsParams) (*ListObjectsInfoV2, error)
 		ListObjectVersions(ctx context.Context, p *ListObjectVersionsParams) (*ListObjectVersionsInfo, error)
 
 		DeleteObjects(ctx context.Context, p *DeleteObjectParams) []*VersionedObject
@@ -235,9 +271,22 @@ type (
 		AbortMultipartUpload(ctx context.Context, p *UploadInfoParams) error
 		ListParts(ctx context.Context, p *ListPartsParams) (*ListPartsInfo, error)
 
+		CreateTusUpload(ctx context.Context, p *CreateTusUploadParams) (*data.ExtendedObjectInfo, error)
+		GetTusUpload(ctx context.Context, p *UploadInfoParams) (*TusUpload, error)
+		PatchTusUpload(ctx context.Context, p *PatchTusUploadParams) (*TusUpload, *data.ExtendedObjectInfo, error)
+
 		PutBucketNotificationConfiguration(ctx context.Context, p *PutBucketNotificationConfigurationParams) error
 		GetBucketNotificationConfiguration(ctx context.Context, bktInfo *data.BucketInfo) (*data.NotificationConfiguration, error)
 
+		GetBucketUsage(ctx context.Context, bktInfo *data.BucketInfo) (*data.BucketUsage, error)
+		ReconcileBucketUsage(ctx context.Context, bktInfo *data.BucketInfo) (*data.BucketUsage, error)
+		ReconcileUsage(ctx context.Context)
+		LoadUsage(ctx context.Context)
+		FlushUsage(ctx context.Context)
+		BucketsUsage() []*data.BucketUsageInfo
+
+		Fsck(ctx context.Context, p *FsckParams) (*FsckReport, error)
+
 		// Compound methods for optimizations
 
 		// GetObjectTaggingAndLock unifies GetObjectTagging and GetLock methods in single tree service invocation.
@@ -269,13 +318,30 @@ func (f MsgHandlerFunc) HandleMessage(ctx context.Context, msg *nats.Msg) error
 // NewLayer creates an instance of a layer. It checks credentials
 // and establishes gRPC connection with the node.
 func NewLayer(log *zap.Logger, frostFS FrostFS, config *Config) Client {
+	var metrics OperationMetrics = noOperationMetrics{}
+	if config.Metrics != nil {
+		metrics = config.Metrics
+	}
+
+	appendMaxSegments := DefaultAppendMaxSegments
+	if config.AppendMaxSegments > 0 {
+		appendMaxSegments = config.AppendMaxSegments
+	}
+
 	return &layer{
-		frostFS:     frostFS,
-		log:         log,
-		anonKey:     config.AnonKey,
-		resolver:    config.Resolver,
-		cache:       NewCache(config.Caches),
-		treeService: config.TreeService,
+		frostFS:      frostFS,
+		log:          log,
+		anonKey:      config.AnonKey,
+		resolver:     config.Resolver,
+		cache:        NewCache(config.Caches),
+		treeService:  config.TreeService,
+		usage:        newUsageRegistry(),
+		usageOwners:  config.UsageOwners,
+		metrics:      metrics,
+		payloadCache: config.PayloadCache,
+
+		appendMaxSegments: appendMaxSegments,
+		maxCachedChildren: maxCachedDirectoryChildren,
 	}
 }
 
@@ -283,6 +349,11 @@ func (n *layer) EphemeralKey() *keys.PublicKey {
 	return n.anonKey.Key.PublicKey()
 }
 
+// FlushCache removes all entries from the cache with the provided name (see CachesNames).
+func (n *layer) FlushCache(name string) error {
+	return n.cache.Purge(name)
+}
+
 func (n *layer) Initialize(ctx context.Context, c EventListener) error {
 	if n.IsNotificationEnabled() {
 		return fmt.Errorf("already initialized")
@@ -339,8 +410,11 @@ func (n *layer) prepareAuthParameters(ctx context.Context, prm *PrmAuth, bktOwne
 }
 
 // GetBucketInfo returns bucket info by name.
-func (n *layer) GetBucketInfo(ctx context.Context, name string) (*data.BucketInfo, error) {
-	name, err := url.QueryUnescape(name)
+func (n *layer) GetBucketInfo(ctx context.Context, name string) (_ *data.BucketInfo, err error) {
+	ctx, span := tracing.StartSpan(ctx, "layer.GetBucketInfo")
+	defer func() { tracing.End(span, err) }()
+
+	name, err = url.QueryUnescape(name)
 	if err != nil {
 		return nil, fmt.Errorf("unescape bucket name: %w", err)
 	}
@@ -359,7 +433,10 @@ func (n *layer) GetBucketInfo(ctx context.Context, name string) (*data.BucketInf
 }
 
 // GetBucketACL returns bucket acl info by name.
-func (n *layer) GetBucketACL(ctx context.Context, bktInfo *data.BucketInfo) (*BucketACL, error) {
+func (n *layer) GetBucketACL(ctx context.Context, bktInfo *data.BucketInfo) (_ *BucketACL, err error) {
+	ctx, span := tracing.StartSpan(ctx, "layer.GetBucketACL")
+	defer func() { tracing.End(span, err) }()
+
 	eACL, err := n.GetContainerEACL(ctx, bktInfo.CID)
 	if err != nil {
 		return nil, fmt.Errorf("get container eacl: %w", err)
@@ -372,18 +449,27 @@ func (n *layer) GetBucketACL(ctx context.Context, bktInfo *data.BucketInfo) (*Bu
 }
 
 // PutBucketACL puts bucket acl by name.
-func (n *layer) PutBucketACL(ctx context.Context, param *PutBucketACLParams) error {
+func (n *layer) PutBucketACL(ctx context.Context, param *PutBucketACLParams) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "layer.PutBucketACL")
+	defer func() { tracing.End(span, err) }()
+
 	return n.setContainerEACLTable(ctx, param.BktInfo.CID, param.EACL, param.SessionToken)
 }
 
 // ListBuckets returns all user containers. The name of the bucket is a container
 // id. Timestamp is omitted since it is not saved in frostfs container.
-func (n *layer) ListBuckets(ctx context.Context) ([]*data.BucketInfo, error) {
+func (n *layer) ListBuckets(ctx context.Context) (_ []*data.BucketInfo, err error) {
+	ctx, span := tracing.StartSpan(ctx, "layer.ListBuckets")
+	defer func() { tracing.End(span, err) }()
+
 	return n.containerList(ctx)
 }
 
 // GetObject from storage.
-func (n *layer) GetObject(ctx context.Context, p *GetObjectParams) error {
+func (n *layer) GetObject(ctx context.Context, p *GetObjectParams) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "layer.GetObject")
+	defer func() { tracing.End(span, err) }()
+
 	var params getParams
 
 	params.oid = p.ObjectInfo.ID
@@ -408,10 +494,18 @@ func (n *layer) GetObject(ctx context.Context, p *GetObjectParams) error {
 		}
 	}
 
-	payload, err := n.initObjectPayloadReader(ctx, params)
+	payloadStart := time.Now()
+	var payloadReader io.Reader
+	if len(p.ObjectInfo.Headers[AppendedSegments]) != 0 {
+		payloadReader, err = n.initAppendedPayloadReader(ctx, params, p.ObjectInfo)
+	} else {
+		payloadReader, err = n.initCachedPayloadReader(ctx, params, p.ObjectInfo)
+	}
 	if err != nil {
 		return fmt.Errorf("init object payload reader: %w", err)
 	}
+	payload := &timedReader{r: payloadReader, spent: time.Since(payloadStart)}
+	defer func() { n.metrics.ObserveOperation(operationPayloadRead, payload.spent) }()
 
 	bufSize := uint64(32 * 1024) // configure?
 	if params.ln != 0 && params.ln < bufSize {
@@ -421,7 +515,7 @@ func (n *layer) GetObject(ctx context.Context, p *GetObjectParams) error {
 	// alloc buffer for copying
 	buf := make([]byte, bufSize) // sync-pool it?
 
-	r := payload
+	var r io.Reader = payload
 	if decReader != nil {
 		if err = decReader.SetReader(payload); err != nil {
 			return fmt.Errorf("set reader to decrypter: %w", err)
@@ -471,7 +565,10 @@ func getDecrypter(p *GetObjectParams) (*encryption.Decrypter, error) {
 }
 
 // GetObjectInfo returns meta information about the object.
-func (n *layer) GetObjectInfo(ctx context.Context, p *HeadObjectParams) (*data.ObjectInfo, error) {
+func (n *layer) GetObjectInfo(ctx context.Context, p *HeadObjectParams) (_ *data.ObjectInfo, err error) {
+	ctx, span := tracing.StartSpan(ctx, "layer.GetObjectInfo")
+	defer func() { tracing.End(span, err) }()
+
 	extendedObjectInfo, err := n.GetExtendedObjectInfo(ctx, p)
 	if err != nil {
 		return nil, err
@@ -481,9 +578,11 @@ func (n *layer) GetObjectInfo(ctx context.Context, p *HeadObjectParams) (*data.O
 }
 
 // GetExtendedObjectInfo returns meta information and corresponding info from the tree service about the object.
-func (n *layer) GetExtendedObjectInfo(ctx context.Context, p *HeadObjectParams) (*data.ExtendedObjectInfo, error) {
+func (n *layer) GetExtendedObjectInfo(ctx context.Context, p *HeadObjectParams) (_ *data.ExtendedObjectInfo, err error) {
+	ctx, span := tracing.StartSpan(ctx, "layer.GetExtendedObjectInfo")
+	defer func() { tracing.End(span, err) }()
+
 	var objInfo *data.ExtendedObjectInfo
-	var err error
 
 	if len(p.VersionID) == 0 {
 		objInfo, err = n.headLastVersionIfNotDeleted(ctx, p.BktInfo, p.Object)
@@ -506,7 +605,28 @@ func (n *layer) GetExtendedObjectInfo(ctx context.Context, p *HeadObjectParams)
 }
 
 // CopyObject from one bucket into another bucket.
-func (n *layer) CopyObject(ctx context.Context, p *CopyObjectParams) (*data.ExtendedObjectInfo, error) {
+func (n *layer) CopyObject(ctx context.Context, p *CopyObjectParams) (_ *data.ExtendedObjectInfo, err error) {
+	ctx, span := tracing.StartSpan(ctx, "layer.CopyObject")
+	defer func() { tracing.End(span, err) }()
+
+	if payloadCanBeShared(p) {
+		extObjInfo, ok, err := n.copyObjectByReference(ctx, p)
+		if err != nil || ok {
+			return extObjInfo, err
+		}
+	}
+
+	header := p.Header
+	if _, ok := header[AppendedSegments]; ok {
+		// segments belong to the source version, the header comes with the source metadata
+		header = make(map[string]string, len(p.Header))
+		for key, val := range p.Header {
+			if key != AppendedSegments {
+				header[key] = val
+			}
+		}
+	}
+
 	pr, pw := io.Pipe()
 
 	go func() {
@@ -528,7 +648,7 @@ func (n *layer) CopyObject(ctx context.Context, p *CopyObjectParams) (*data.Exte
 		Object:       p.DstObject,
 		Size:         p.SrcSize,
 		Reader:       pr,
-		Header:       p.Header,
+		Header:       header,
 		Encryption:   p.Encryption,
 		CopiesNumber: p.CopiesNuber,
 	})
@@ -556,12 +676,23 @@ func (n *layer) deleteObject(ctx context.Context, bkt *data.BucketInfo, settings
 			return obj
 		}
 
-		obj.Error = n.treeService.RemoveVersion(ctx, bkt, nodeVersion.ID)
+		latest := n.latestVersion(ctx, bkt, obj.Name)
+		if obj.Error = n.treeService.RemoveVersion(ctx, bkt, nodeVersion.ID); obj.Error == nil {
+			usage := data.BucketUsage{}.Sub(versionUsage(nodeVersion))
+			if latest != nil && latest.ID == nodeVersion.ID {
+				// the previous version becomes the latest one
+				usage = usage.Sub(latestVersionUsage(latest))
+				usage.Add(latestVersionUsage(n.latestVersion(ctx, bkt, obj.Name)))
+			}
+			n.updateUsage(bkt, usage)
+		}
 		n.cache.CleanListCacheEntriesContainingObject(obj.Name, bkt.CID)
+		n.invalidate(InvalidateObject, bkt, obj.Name, nodeVersion.OID.EncodeToString())
 		return obj
 	}
 
-	var newVersion *data.NodeVersion
+	// the unversioned version is replaced by the delete marker if versioning is suspended
+	var newVersion, replaced *data.NodeVersion
 
 	if settings.VersioningSuspended() {
 		obj.VersionID = data.UnversionedObjectVersionID
@@ -570,6 +701,7 @@ func (n *layer) deleteObject(ctx context.Context, bkt *data.BucketInfo, settings
 		if nodeVersion, obj.Error = n.getNodeVersionToDelete(ctx, bkt, obj); obj.Error != nil {
 			return dismissNotFoundError(obj)
 		}
+		replaced = nodeVersion
 
 		if obj.DeleteMarkVersion, obj.Error = n.removeOldVersion(ctx, bkt, nodeVersion, obj); obj.Error != nil {
 			return obj
@@ -596,11 +728,15 @@ func (n *layer) deleteObject(ctx context.Context, bkt *data.BucketInfo, settings
 		IsUnversioned: settings.VersioningSuspended(),
 	}
 
+	latest := n.latestVersion(ctx, bkt, obj.Name)
 	if _, obj.Error = n.treeService.AddVersion(ctx, bkt, newVersion); obj.Error != nil {
 		return obj
 	}
+	n.updateUsage(bkt, addedVersionUsage(newVersion, latest, replaced))
 
 	n.cache.DeleteObjectName(bkt.CID, bkt.Name, obj.Name)
+	n.cache.CleanListCacheEntriesContainingObject(obj.Name, bkt.CID)
+	n.invalidate(InvalidateObject, bkt, obj.Name, "")
 
 	return obj
 }
@@ -630,11 +766,14 @@ func (n *layer) removeOldVersion(ctx context.Context, bkt *data.BucketInfo, node
 		return obj.VersionID, nil
 	}
 
-	return "", n.objectDelete(ctx, bkt, nodeVersion.OID)
+	return "", n.deleteVersionObject(ctx, bkt, nodeVersion)
 }
 
 // DeleteObjects from the storage.
 func (n *layer) DeleteObjects(ctx context.Context, p *DeleteObjectParams) []*VersionedObject {
+	ctx, span := tracing.StartSpan(ctx, "layer.DeleteObjects")
+	defer span.End()
+
 	for i, obj := range p.Objects {
 		p.Objects[i] = n.deleteObject(ctx, p.BktInfo, p.Settings, obj)
 	}
@@ -642,7 +781,10 @@ func (n *layer) DeleteObjects(ctx context.Context, p *DeleteObjectParams) []*Ver
 	return p.Objects
 }
 
-func (n *layer) CreateBucket(ctx context.Context, p *CreateBucketParams) (*data.BucketInfo, error) {
+func (n *layer) CreateBucket(ctx context.Context, p *CreateBucketParams) (_ *data.BucketInfo, err error) {
+	ctx, span := tracing.StartSpan(ctx, "layer.CreateBucket")
+	defer func() { tracing.End(span, err) }()
+
 	bktInfo, err := n.GetBucketInfo(ctx, p.Name)
 	if err != nil {
 		if errors.IsS3Error(err, errors.ErrNoSuchBucket) {
@@ -672,7 +814,10 @@ func (n *layer) ResolveBucket(ctx context.Context, name string) (cid.ID, error)
 	return cnrID, nil
 }
 
-func (n *layer) DeleteBucket(ctx context.Context, p *DeleteBucketParams) error {
+func (n *layer) DeleteBucket(ctx context.Context, p *DeleteBucketParams) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "layer.DeleteBucket")
+	defer func() { tracing.End(span, err) }()
+
 	nodeVersions, err := n.bucketNodeVersions(ctx, p.BktInfo, "")
 	if err != nil {
 		return err
@@ -682,5 +827,7 @@ func (n *layer) DeleteBucket(ctx context.Context, p *DeleteBucketParams) error {
 	}
 
 	n.cache.DeleteBucket(p.BktInfo.Name)
+	n.invalidate(InvalidateBucket, p.BktInfo, "", "")
+	n.usage.remove(p.BktInfo.CID)
 	return n.frostFS.DeleteContainer(ctx, p.BktInfo.CID, p.SessionToken)
 }
@@ -0,0 +1,309 @@
+package layer
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/cache"
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
+	apiErrors "github.com/TrueCloudLab/frostfs-s3-gw/api/errors"
+)
+
+// treeWalker iterates over versions of objects in lexical order of their names
+// (versions of the same object are ordered from the newest one).
+// The version tree is fetched directory by directory, directories which can't contain
+// listed objects (out of prefix, before marker or inside already listed common prefix)
+// aren't fetched at all. So the cost of the listing page is bounded by the page size
+// and the width of the visited directories rather than by the bucket size. Children of
+// the visited directories are cached, so the next pages of a wide directory don't fetch it again.
+type treeWalker struct {
+	layer      *layer
+	bktInfo    *data.BucketInfo
+	prefix     string
+	delimiter  string
+	marker     string
+	latestOnly bool
+	// after is the greatest name visited so far, the marker initially (exclusive).
+	after string
+	// skipPrefix is the last common prefix, objects with this prefix are already listed.
+	skipPrefix string
+	// versionIDMarker is a version of the marker object listing of all versions is resumed after.
+	versionIDMarker string
+	markerPassed    bool
+	lastName        string
+	limit           int
+	// filter selects latest versions to be listed, all versions are listed if it isn't set.
+	filter func(context.Context, *data.NodeVersion) (bool, error)
+	result []*data.ExtendedObjectInfo
+}
+
+// listLatestVersions returns up to max-keys+1 latest versions of objects (nodes of directories are
+// represented by the first object inside) that must be listed according to the params.
+func (n *layer) listLatestVersions(ctx context.Context, p allObjectParams) ([]*data.NodeVersion, error) {
+	w := &treeWalker{
+		layer:      n,
+		bktInfo:    p.Bucket,
+		prefix:     p.Prefix,
+		delimiter:  p.Delimiter,
+		marker:     p.Marker,
+		latestOnly: true,
+		after:      p.Marker,
+		limit:      p.MaxKeys + 1, // we use maxKeys+1 to be able to know nextMarker/nextContinuationToken
+		filter:     p.Filter,
+	}
+
+	if p.ContinuationToken != "" {
+		name, err := DecodeContinuationToken(p.ContinuationToken)
+		if err != nil {
+			return nil, err
+		}
+		if name > w.marker {
+			w.marker, w.after = name, name
+		}
+	}
+
+	if err := w.run(ctx); err != nil {
+		return nil, err
+	}
+
+	nodes := make([]*data.NodeVersion, len(w.result))
+	for i, version := range w.result {
+		nodes[i] = version.NodeVersion
+	}
+
+	return nodes, nil
+}
+
+// listAllVersions returns up to max-keys+1 versions and common prefixes that must be listed
+// according to the params. Object infos of versions aren't resolved.
+func (n *layer) listAllVersions(ctx context.Context, p *ListObjectVersionsParams) ([]*data.ExtendedObjectInfo, error) {
+	w := &treeWalker{
+		layer:           n,
+		bktInfo:         p.BktInfo,
+		prefix:          p.Prefix,
+		delimiter:       p.Delimiter,
+		marker:          p.KeyMarker,
+		after:           p.KeyMarker,
+		versionIDMarker: p.VersionIDMarker,
+		limit:           p.MaxKeys + 1, // we use maxKeys+1 to be able to know that the result is truncated
+	}
+
+	if err := w.run(ctx); err != nil {
+		return nil, err
+	}
+
+	return w.result, nil
+}
+
+// EncodeContinuationToken forms the continuation token of the listing resumed after the object name.
+// The token doesn't depend on object IDs: they aren't unique for copied objects, and stored objects
+// keep the name they were uploaded with after renaming.
+func EncodeContinuationToken(name string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(name))
+}
+
+// DecodeContinuationToken returns the name of the object the listing is resumed after.
+func DecodeContinuationToken(token string) (string, error) {
+	name, err := base64.RawURLEncoding.Strict().DecodeString(token)
+	if err != nil || len(name) == 0 {
+		return "", apiErrors.GetAPIError(apiErrors.ErrIncorrectContinuationToken)
+	}
+	return string(name), nil
+}
+
+// maxCachedDirectoryChildren is the default number of directory children which can be kept in the list cache.
+const maxCachedDirectoryChildren = 1000
+
+// getDirectoryChildren returns children of the directory from cache or from the tree service.
+// The tree service returns children in no particular order and can't be asked for a part of them,
+// so all children are fetched to be sorted: the cost of a listing page isn't bounded by max-keys,
+// it grows with the width of the walked directories. Children of wide directories aren't cached
+// to keep the memory used by the list cache bounded, they are fetched for every page.
+func (n *layer) getDirectoryChildren(ctx context.Context, bktInfo *data.BucketInfo, dir *data.TreeDirectory, latestOnly bool) (*data.TreeDirectoryChildren, error) {
+	owner := n.Owner(ctx)
+	cacheKey := cache.CreateDirectoryChildrenCacheKey(bktInfo.CID, dir.Path, latestOnly)
+	if children := n.cache.GetDirectoryChildren(owner, cacheKey); children != nil {
+		return children, nil
+	}
+
+	versions, dirs, err := n.treeService.GetDirectoryChildren(ctx, bktInfo, dir, latestOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	children := &data.TreeDirectoryChildren{Versions: versions, Dirs: dirs}
+	if len(versions)+len(dirs) <= n.maxCachedChildren {
+		n.cache.PutDirectoryChildren(owner, cacheKey, children)
+	}
+
+	return children, nil
+}
+
+func (w *treeWalker) run(ctx context.Context) error {
+	var dirPath string
+	if index := strings.LastIndex(w.prefix, "/"); index >= 0 {
+		dirPath = w.prefix[:index]
+	}
+
+	dir, err := w.layer.treeService.GetDirectory(ctx, w.bktInfo, dirPath)
+	if err != nil {
+		if errors.Is(err, ErrNodeNotFound) {
+			return nil
+		}
+		return fmt.Errorf("get directory '%s': %w", dirPath, err)
+	}
+
+	return w.walk(ctx, dir)
+}
+
+func (w *treeWalker) walk(ctx context.Context, dir *data.TreeDirectory) error {
+	dirChildren, err := w.layer.getDirectoryChildren(ctx, w.bktInfo, dir, w.latestOnly)
+	if err != nil {
+		return fmt.Errorf("get children of directory '%s': %w", dir.Path, err)
+	}
+	objects, dirs := dirChildren.Versions, dirChildren.Dirs
+
+	type child struct {
+		key  string
+		node *data.NodeVersion
+		dir  *data.TreeDirectory
+	}
+
+	children := make([]child, 0, len(objects)+len(dirs))
+	for _, node := range objects {
+		children = append(children, child{key: node.FilePath, node: node})
+	}
+	for _, subDir := range dirs {
+		children = append(children, child{key: subDir.Path, dir: subDir})
+	}
+
+	// names of objects inside the directory start with its path, so such order
+	// of the children corresponds to the lexical order of object names
+	sort.Slice(children, func(i, j int) bool {
+		if children[i].key == children[j].key && children[i].node != nil && children[j].node != nil {
+			return children[i].node.Timestamp > children[j].node.Timestamp
+		}
+		return children[i].key < children[j].key
+	})
+
+	for _, ch := range children {
+		if len(w.result) == w.limit {
+			return nil
+		}
+
+		if ch.node != nil {
+			if w.latestOnly {
+				if err = w.visitLatest(ctx, ch.node); err != nil {
+					return err
+				}
+			} else {
+				w.visitVersion(ch.node)
+			}
+			continue
+		}
+
+		if w.skipDirectory(ch.dir.Path) {
+			continue
+		}
+
+		if err = w.walk(ctx, ch.dir); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (w *treeWalker) visitLatest(ctx context.Context, node *data.NodeVersion) error {
+	name := node.FilePath
+	if !strings.HasPrefix(name, w.prefix) || name <= w.after {
+		return nil
+	}
+	w.after = name
+
+	if node.IsDeleteMarker() || w.skipPrefix != "" && strings.HasPrefix(name, w.skipPrefix) {
+		return nil
+	}
+
+	// the common prefix is listed only if some object inside is selected by the filter
+	if w.filter != nil {
+		selected, err := w.filter(ctx, node)
+		if err != nil {
+			return fmt.Errorf("filter '%s': %w", name, err)
+		}
+		if !selected {
+			return nil
+		}
+	}
+
+	if dirName := tryDirectoryName(node, w.prefix, w.delimiter); len(dirName) != 0 {
+		w.skipPrefix = dirName
+		if dirName <= w.marker {
+			return nil
+		}
+	}
+
+	w.result = append(w.result, &data.ExtendedObjectInfo{NodeVersion: node, IsLatest: true})
+	return nil
+}
+
+func (w *treeWalker) visitVersion(node *data.NodeVersion) {
+	name := node.FilePath
+	if !strings.HasPrefix(name, w.prefix) || name < w.marker {
+		return
+	}
+
+	isLatest := name != w.lastName
+	w.lastName = name
+
+	if name == w.marker {
+		// versions of the marker object are listed only after the version id marker
+		if !w.markerPassed {
+			w.markerPassed = w.versionIDMarker != "" && nodeVersionID(node) == w.versionIDMarker
+			return
+		}
+	}
+
+	if w.skipPrefix != "" && strings.HasPrefix(name, w.skipPrefix) {
+		return
+	}
+
+	if dirName := tryDirectoryName(node, w.prefix, w.delimiter); len(dirName) != 0 {
+		w.skipPrefix = dirName
+		if dirName > w.marker {
+			w.result = append(w.result, &data.ExtendedObjectInfo{
+				ObjectInfo:  &data.ObjectInfo{CID: w.bktInfo.CID, Bucket: w.bktInfo.Name, Name: dirName, IsDir: true},
+				NodeVersion: node,
+			})
+		}
+		return
+	}
+
+	w.result = append(w.result, &data.ExtendedObjectInfo{NodeVersion: node, IsLatest: isLatest})
+}
+
+// skipDirectory checks if the directory can't contain objects to be listed.
+func (w *treeWalker) skipDirectory(path string) bool {
+	if !strings.HasPrefix(path, w.prefix) && !strings.HasPrefix(w.prefix, path) {
+		return true
+	}
+
+	if w.skipPrefix != "" && strings.HasPrefix(path, w.skipPrefix) {
+		return true
+	}
+
+	// all names inside the directory are less than the bound
+	// if the directory path is less and isn't a prefix of the bound
+	return path <= w.after && !strings.HasPrefix(w.after, path)
+}
+
+func nodeVersionID(node *data.NodeVersion) string {
+	if node.IsUnversioned {
+		return data.UnversionedObjectVersionID
+	}
+	return node.OID.EncodeToString()
+}
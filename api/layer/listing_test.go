@@ -0,0 +1,262 @@
+package layer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
+	"github.com/stretchr/testify/require"
+)
+
+type countingTreeService struct {
+	TreeService
+	fetched []string
+}
+
+func (c *countingTreeService) GetDirectoryChildren(ctx context.Context, bktInfo *data.BucketInfo, dir *data.TreeDirectory, latestOnly bool) ([]*data.NodeVersion, []*data.TreeDirectory, error) {
+	c.fetched = append(c.fetched, dir.Path)
+	return c.TreeService.GetDirectoryChildren(ctx, bktInfo, dir, latestOnly)
+}
+
+// listAll lists objects page by page following continuation tokens.
+func (tc *testContext) listAll(prefix, delimiter string, maxKeys int) []string {
+	var (
+		result []string
+		token  string
+	)
+
+	for {
+		res, err := tc.layer.ListObjectsV2(tc.ctx, &ListObjectsParamsV2{
+			ListObjectsParamsCommon: ListObjectsParamsCommon{
+				BktInfo:   tc.bktInfo,
+				Prefix:    prefix,
+				Delimiter: delimiter,
+				MaxKeys:   maxKeys,
+			},
+			ContinuationToken: token,
+		})
+		require.NoError(tc.t, err)
+		require.LessOrEqual(tc.t, len(res.Prefixes)+len(res.Objects), maxKeys)
+
+		result = append(result, res.Prefixes...)
+		for _, obj := range res.Objects {
+			result = append(result, obj.Name)
+		}
+
+		if !res.IsTruncated {
+			return result
+		}
+		token = res.NextContinuationToken
+	}
+}
+
+func TestListObjectsPagination(t *testing.T) {
+	tc := prepareContext(t)
+
+	names := []string{"a", "b/1", "b/2", "b/c/3", "b/c/4", "b0", "d/e/f/5", "g"}
+	for _, name := range names {
+		_, err := tc.layer.PutObject(tc.ctx, &PutObjectParams{
+			BktInfo: tc.bktInfo,
+			Object:  name,
+			Reader:  bytes.NewReader([]byte(name)),
+			Header:  make(map[string]string),
+		})
+		require.NoError(t, err)
+	}
+	tc.deleteObject("g", "", &data.BucketSettings{Versioning: data.VersioningEnabled})
+
+	for _, maxKeys := range []int{1, 2, 3, 1000} {
+		require.Equal(t, names[:7], tc.listAll("", "", maxKeys))
+		require.ElementsMatch(t, []string{"a", "b/", "b0", "d/"}, tc.listAll("", "/", maxKeys))
+		require.ElementsMatch(t, []string{"b/1", "b/2", "b/c/"}, tc.listAll("b/", "/", maxKeys))
+		require.Equal(t, []string{"b/c/3", "b/c/4"}, tc.listAll("b/c", "", maxKeys))
+	}
+
+	counter := &countingTreeService{TreeService: tc.layer.(*layer).treeService}
+	tc.layer.(*layer).treeService = counter
+	tc.layer.(*layer).cache.listsCache.Purge()
+
+	res, err := tc.layer.ListObjectsV1(tc.ctx, &ListObjectsParamsV1{
+		ListObjectsParamsCommon: ListObjectsParamsCommon{
+			BktInfo:   tc.bktInfo,
+			Delimiter: "/",
+			MaxKeys:   1,
+		},
+		Marker: "a",
+	})
+	require.NoError(t, err)
+	require.True(t, res.IsTruncated)
+	require.Equal(t, []string{"b/"}, res.Prefixes)
+	require.Equal(t, "b/", res.NextMarker)
+	// only the first object of the common prefix is fetched, the rest of the bucket isn't
+	require.Equal(t, []string{"", "b/"}, counter.fetched)
+}
+
+func TestListObjectsPaginationAfterCopy(t *testing.T) {
+	tc := prepareContext(t)
+
+	tc.obj = "a"
+	src := tc.putObject([]byte("a"))
+	tc.obj = "b"
+	tc.putObject([]byte("b"))
+
+	// the copy shares the object id with the source
+	dst := tc.copyObject(src, "c", make(map[string]string))
+	require.Equal(t, src.ID, dst.ID)
+
+	for _, maxKeys := range []int{1, 2, 1000} {
+		require.Equal(t, []string{"a", "b", "c"}, tc.listAll("", "", maxKeys))
+	}
+}
+
+func TestListObjectsPaginationAfterRename(t *testing.T) {
+	tc := prepareContext(t)
+
+	for _, name := range []string{"a", "b", "c", "d/1", "d/2"} {
+		tc.obj = name
+		tc.putObject([]byte(name))
+	}
+
+	// stored objects keep the names they were uploaded with
+	for src, dst := range map[string]string{"a": "z", "d/": "e/"} {
+		_, err := tc.layer.RenameObject(tc.ctx, &RenameObjectParams{
+			BktInfo:   tc.bktInfo,
+			Settings:  &data.BucketSettings{Versioning: data.VersioningUnversioned},
+			SrcObject: src,
+			DstObject: dst,
+		})
+		require.NoError(t, err)
+	}
+
+	for _, maxKeys := range []int{1, 2, 1000} {
+		require.Equal(t, []string{"b", "c", "e/1", "e/2", "z"}, tc.listAll("", "", maxKeys))
+		require.ElementsMatch(t, []string{"b", "c", "e/", "z"}, tc.listAll("", "/", maxKeys))
+	}
+}
+
+func TestListObjectsNameFromTree(t *testing.T) {
+	tc := prepareContext(t)
+
+	objInfo := tc.putObject([]byte("content"))
+
+	// the cached info of the object stored under another name doesn't change the listed key
+	stale := *objInfo
+	stale.Name = "stale"
+	n := tc.layer.(*layer)
+	n.cache.PutObject(n.Owner(tc.ctx), &data.ExtendedObjectInfo{ObjectInfo: &stale})
+
+	require.Equal(t, []string{tc.obj}, tc.listAll("", "", 1000))
+}
+
+func TestListObjectsWideDirectory(t *testing.T) {
+	tc := prepareContext(t)
+
+	var names []string
+	for i := 0; i < 20; i++ {
+		names = append(names, fmt.Sprintf("dir/%02d", i))
+		tc.putObjectWithHeader(names[i], make(map[string]string))
+	}
+
+	counter := &countingTreeService{TreeService: tc.layer.(*layer).treeService}
+	tc.layer.(*layer).treeService = counter
+
+	var (
+		listed []string
+		token  string
+	)
+	for {
+		res, err := tc.layer.ListObjectsV2(tc.ctx, &ListObjectsParamsV2{
+			ListObjectsParamsCommon: ListObjectsParamsCommon{BktInfo: tc.bktInfo, Prefix: "dir/", MaxKeys: 3},
+			ContinuationToken:       token,
+		})
+		require.NoError(t, err)
+		for _, obj := range res.Objects {
+			listed = append(listed, obj.Name)
+		}
+		if !res.IsTruncated {
+			break
+		}
+		token = res.NextContinuationToken
+	}
+	require.Equal(t, names, listed)
+	// the directory is fetched once for all pages
+	require.Equal(t, []string{"dir/"}, counter.fetched)
+
+	// new object invalidates children of the directory
+	tc.putObjectWithHeader("dir/20", make(map[string]string))
+	res, err := tc.layer.ListObjectsV2(tc.ctx, &ListObjectsParamsV2{
+		ListObjectsParamsCommon: ListObjectsParamsCommon{BktInfo: tc.bktInfo, Prefix: "dir/", MaxKeys: 1000},
+	})
+	require.NoError(t, err)
+	require.Len(t, res.Objects, 21)
+	require.Equal(t, []string{"dir/", "dir/"}, counter.fetched)
+
+	// children of the directory wider than the limit aren't cached
+	tc.layer.(*layer).maxCachedChildren = 20
+	tc.putObjectWithHeader("dir/21", make(map[string]string))
+	counter.fetched = nil
+	for i := 0; i < 2; i++ {
+		res, err = tc.layer.ListObjectsV2(tc.ctx, &ListObjectsParamsV2{
+			ListObjectsParamsCommon: ListObjectsParamsCommon{BktInfo: tc.bktInfo, Prefix: "dir/", MaxKeys: 1000},
+		})
+		require.NoError(t, err)
+		require.Len(t, res.Objects, 22)
+	}
+	require.Equal(t, []string{"dir/", "dir/"}, counter.fetched)
+}
+
+func TestListObjectsByTags(t *testing.T) {
+	tc := prepareContext(t)
+
+	for _, name := range []string{"a", "b/1", "b/2", "c/1", "d"} {
+		objInfo := tc.putObjectWithHeader(name, make(map[string]string))
+		tags := map[string]string{"project": "x"}
+		switch name {
+		case "b/1", "c/1":
+			tags = map[string]string{"project": "y"}
+		case "b/2":
+			tags["stage"] = "prod"
+		}
+		_, err := tc.layer.PutObjectTagging(tc.ctx, &PutObjectTaggingParams{
+			ObjectVersion: &ObjectVersion{BktInfo: tc.bktInfo, ObjectName: name, VersionID: objInfo.VersionID()},
+			TagSet:        tags,
+		})
+		require.NoError(t, err)
+	}
+
+	list := func(delimiter, token string, maxKeys int, tags ...ObjectFilter) *ListObjectsInfoV2 {
+		res, err := tc.layer.ListObjectsV2(tc.ctx, &ListObjectsParamsV2{
+			ListObjectsParamsCommon: ListObjectsParamsCommon{
+				BktInfo:   tc.bktInfo,
+				Delimiter: delimiter,
+				MaxKeys:   maxKeys,
+			},
+			ContinuationToken: token,
+			Tags:              tags,
+		})
+		require.NoError(t, err)
+		return res
+	}
+	names := func(res *ListObjectsInfoV2) []string {
+		result := append([]string{}, res.Prefixes...)
+		for _, obj := range res.Objects {
+			result = append(result, obj.Name)
+		}
+		return result
+	}
+
+	project := ObjectFilter{Key: "project", Value: "x"}
+	require.Equal(t, []string{"a", "b/2", "d"}, names(list("", "", 1000, project)))
+	require.Equal(t, []string{"b/2"}, names(list("", "", 1000, project, ObjectFilter{Key: "stage", Exists: true})))
+	require.Empty(t, names(list("", "", 1000, ObjectFilter{Key: "stage", Value: "dev"})))
+
+	// common prefixes are listed only if they contain matching objects
+	require.Equal(t, []string{"b/", "a", "d"}, names(list("/", "", 1000, project)))
+
+	res := list("", "", 2, project)
+	require.True(t, res.IsTruncated)
+	require.Equal(t, []string{"a", "b/2"}, names(res))
+	require.Equal(t, []string{"d"}, names(list("", res.NextContinuationToken, 2, project)))
+}
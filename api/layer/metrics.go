@@ -0,0 +1,42 @@
+package layer
+
+import (
+	"io"
+	"time"
+)
+
+// OperationMetrics observes duration of storage operations made by the layer.
+type OperationMetrics interface {
+	ObserveOperation(operation string, duration time.Duration)
+}
+
+// Storage operations observed by OperationMetrics.
+const (
+	operationTreeLookup   = "tree_lookup"
+	operationObjectHead   = "object_head"
+	operationObjectGet    = "object_get"
+	operationPayloadRead  = "payload_read"
+	operationPayloadWrite = "payload_write"
+)
+
+type noOperationMetrics struct{}
+
+func (noOperationMetrics) ObserveOperation(string, time.Duration) {}
+
+// observeOperation reports duration of the operation started at start.
+func (n *layer) observeOperation(operation string, start time.Time) {
+	n.metrics.ObserveOperation(operation, time.Since(start))
+}
+
+// timedReader counts time spent in reading from the underlying reader.
+type timedReader struct {
+	r     io.Reader
+	spent time.Duration
+}
+
+func (t *timedReader) Read(p []byte) (int, error) {
+	start := time.Now()
+	n, err := t.r.Read(p)
+	t.spent += time.Since(start)
+	return n, err
+}
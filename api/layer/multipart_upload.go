@@ -15,6 +15,7 @@ import (
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/errors"
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/layer/encryption"
+	"github.com/TrueCloudLab/frostfs-s3-gw/internal/tracing"
 	oid "github.com/TrueCloudLab/frostfs-sdk-go/object/id"
 	"github.com/TrueCloudLab/frostfs-sdk-go/user"
 	"github.com/minio/sio"
@@ -133,7 +134,20 @@ type (
 	}
 )
 
-func (n *layer) CreateMultipartUpload(ctx context.Context, p *CreateMultipartParams) error {
+func (n *layer) CreateMultipartUpload(ctx context.Context, p *CreateMultipartParams) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "layer.CreateMultipartUpload")
+	defer func() { tracing.End(span, err) }()
+
+	info, err := n.newMultipartInfo(ctx, p)
+	if err != nil {
+		return err
+	}
+
+	return n.treeService.CreateMultipartUpload(ctx, p.Info.Bkt, info)
+}
+
+// newMultipartInfo forms the multipart upload node info.
+func (n *layer) newMultipartInfo(ctx context.Context, p *CreateMultipartParams) (*data.MultipartInfo, error) {
 	metaSize := len(p.Header)
 	if p.Data != nil {
 		metaSize += len(p.Data.ACLHeaders)
@@ -165,14 +179,17 @@ func (n *layer) CreateMultipartUpload(ctx context.Context, p *CreateMultipartPar
 
 	if p.Info.Encryption.Enabled() {
 		if err := addEncryptionHeaders(info.Meta, p.Info.Encryption); err != nil {
-			return fmt.Errorf("add encryption header: %w", err)
+			return nil, fmt.Errorf("add encryption header: %w", err)
 		}
 	}
 
-	return n.treeService.CreateMultipartUpload(ctx, p.Info.Bkt, info)
+	return info, nil
 }
 
-func (n *layer) UploadPart(ctx context.Context, p *UploadPartParams) (string, error) {
+func (n *layer) UploadPart(ctx context.Context, p *UploadPartParams) (_ string, err error) {
+	ctx, span := tracing.StartSpan(ctx, "layer.UploadPart")
+	defer func() { tracing.End(span, err) }()
+
 	multipartInfo, err := n.treeService.GetMultipartUpload(ctx, p.Info.Bkt, p.Info.Key, p.Info.UploadID)
 	if err != nil {
 		if stderrors.Is(err, ErrNodeNotFound) {
@@ -246,19 +263,22 @@ func (n *layer) uploadPart(ctx context.Context, multipartInfo *data.MultipartInf
 		Created:  prm.CreationTime,
 	}
 
-	oldPartID, err := n.treeService.AddPart(ctx, bktInfo, multipartInfo.ID, partInfo)
-	oldPartIDNotFound := stderrors.Is(err, ErrNoNodeToRemove)
-	if err != nil && !oldPartIDNotFound {
+	oldPart, err := n.treeService.AddPart(ctx, bktInfo, multipartInfo.ID, partInfo)
+	oldPartNotFound := stderrors.Is(err, ErrNoNodeToRemove)
+	if err != nil && !oldPartNotFound {
 		return nil, err
 	}
-	if !oldPartIDNotFound {
-		if err = n.objectDelete(ctx, bktInfo, oldPartID); err != nil {
+	usageDelta := data.BucketUsage{MultipartBytes: decSize}
+	if !oldPartNotFound {
+		usageDelta.MultipartBytes -= oldPart.Size
+		if err = n.objectDelete(ctx, bktInfo, oldPart.OID); err != nil {
 			n.log.Error("couldn't delete old part object", zap.Error(err),
 				zap.String("cnrID", bktInfo.CID.EncodeToString()),
 				zap.String("bucket name", bktInfo.Name),
-				zap.String("objID", oldPartID.EncodeToString()))
+				zap.String("objID", oldPart.OID.EncodeToString()))
 		}
 	}
+	n.updateUsage(bktInfo, usageDelta)
 
 	objInfo := &data.ObjectInfo{
 		ID:  id,
@@ -274,7 +294,10 @@ func (n *layer) uploadPart(ctx context.Context, multipartInfo *data.MultipartInf
 	return objInfo, nil
 }
 
-func (n *layer) UploadPartCopy(ctx context.Context, p *UploadCopyParams) (*data.ObjectInfo, error) {
+func (n *layer) UploadPartCopy(ctx context.Context, p *UploadCopyParams) (_ *data.ObjectInfo, err error) {
+	ctx, span := tracing.StartSpan(ctx, "layer.UploadPartCopy")
+	defer func() { tracing.End(span, err) }()
+
 	multipartInfo, err := n.treeService.GetMultipartUpload(ctx, p.Info.Bkt, p.Info.Key, p.Info.UploadID)
 	if err != nil {
 		if stderrors.Is(err, ErrNodeNotFound) {
@@ -330,6 +353,10 @@ type multiObjectReader struct {
 	curReader io.Reader
 
 	parts []*data.PartInfo
+
+	// off and ln define the range of the combined payload of parts,
+	// zero ln means the payload up to the end.
+	off, ln uint64
 }
 
 func (x *multiObjectReader) Read(p []byte) (n int, err error) {
@@ -340,25 +367,68 @@ func (x *multiObjectReader) Read(p []byte) (n int, err error) {
 		}
 	}
 
+	if err = x.openNextPart(); err != nil {
+		return n, err
+	}
+
+	next, err := x.Read(p[n:])
+
+	return n + next, err
+}
+
+// openNextPart initializes the payload reader of the next part in the range.
+// Returns io.EOF if there are no more parts to read.
+func (x *multiObjectReader) openNextPart() error {
+	for len(x.parts) != 0 && x.off >= uint64(x.parts[0].Size) {
+		x.off -= uint64(x.parts[0].Size)
+		x.parts = x.parts[1:]
+	}
+
 	if len(x.parts) == 0 {
-		return n, io.EOF
+		x.curReader = nil
+		return io.EOF
 	}
 
-	x.prm.oid = x.parts[0].OID
+	part := x.parts[0]
+	x.parts = x.parts[1:]
+
+	x.prm.oid = part.OID
+	x.prm.off, x.prm.ln = 0, 0
+	if x.off != 0 || x.ln != 0 {
+		ln := uint64(part.Size) - x.off
+		if x.ln != 0 {
+			if x.ln <= ln {
+				ln = x.ln
+				x.parts = nil
+			} else {
+				x.ln -= ln
+			}
+		}
+		if ln != uint64(part.Size) {
+			x.prm.off, x.prm.ln = x.off, ln
+		}
+		x.off = 0
+	}
 
+	var err error
 	x.curReader, err = x.layer.initObjectPayloadReader(x.ctx, x.prm)
 	if err != nil {
-		return n, fmt.Errorf("init payload reader for the next part: %w", err)
+		return fmt.Errorf("init payload reader for the next part: %w", err)
 	}
 
-	x.parts = x.parts[1:]
+	return nil
+}
 
-	next, err := x.Read(p[n:])
+func (n *layer) CompleteMultipartUpload(ctx context.Context, p *CompleteMultipartParams) (_ *UploadData, _ *data.ExtendedObjectInfo, err error) {
+	ctx, span := tracing.StartSpan(ctx, "layer.CompleteMultipartUpload")
+	defer func() { tracing.End(span, err) }()
 
-	return n + next, err
+	return n.completeMultipartUpload(ctx, p, uploadMinSize)
 }
 
-func (n *layer) CompleteMultipartUpload(ctx context.Context, p *CompleteMultipartParams) (*UploadData, *data.ExtendedObjectInfo, error) {
+// completeMultipartUpload combines parts into the object. All parts except the last one
+// must be at least minPartSize bytes.
+func (n *layer) completeMultipartUpload(ctx context.Context, p *CompleteMultipartParams, minPartSize int64) (*UploadData, *data.ExtendedObjectInfo, error) {
 	for i := 1; i < len(p.Parts); i++ {
 		if p.Parts[i].PartNumber <= p.Parts[i-1].PartNumber {
 			return nil, nil, errors.GetAPIError(errors.ErrInvalidPartOrder)
@@ -386,7 +456,7 @@ func (n *layer) CompleteMultipartUpload(ctx context.Context, p *CompleteMultipar
 			return nil, nil, errors.GetAPIError(errors.ErrInvalidPart)
 		}
 		// for the last part we have no minimum size limit
-		if i != len(p.Parts)-1 && partInfo.Size < uploadMinSize {
+		if i != len(p.Parts)-1 && partInfo.Size < minPartSize {
 			return nil, nil, errors.GetAPIError(errors.ErrEntityTooSmall)
 		}
 		parts = append(parts, partInfo)
@@ -473,10 +543,18 @@ func (n *layer) CompleteMultipartUpload(ctx context.Context, p *CompleteMultipar
 		n.cache.DeleteObject(addr)
 	}
 
-	return uploadData, extObjInfo, n.treeService.DeleteMultipartUpload(ctx, p.Info.Bkt, multipartInfo.ID)
+	if err = n.treeService.DeleteMultipartUpload(ctx, p.Info.Bkt, multipartInfo.ID); err != nil {
+		return nil, nil, err
+	}
+	n.updateUsage(p.Info.Bkt, data.BucketUsage{MultipartBytes: -partsMapSize(partsInfo)})
+
+	return uploadData, extObjInfo, nil
 }
 
-func (n *layer) ListMultipartUploads(ctx context.Context, p *ListMultipartUploadsParams) (*ListMultipartUploadsInfo, error) {
+func (n *layer) ListMultipartUploads(ctx context.Context, p *ListMultipartUploadsParams) (_ *ListMultipartUploadsInfo, err error) {
+	ctx, span := tracing.StartSpan(ctx, "layer.ListMultipartUploads")
+	defer func() { tracing.End(span, err) }()
+
 	var result ListMultipartUploadsInfo
 	if p.MaxUploads == 0 {
 		return &result, nil
@@ -536,7 +614,10 @@ func (n *layer) ListMultipartUploads(ctx context.Context, p *ListMultipartUpload
 	return &result, nil
 }
 
-func (n *layer) AbortMultipartUpload(ctx context.Context, p *UploadInfoParams) error {
+func (n *layer) AbortMultipartUpload(ctx context.Context, p *UploadInfoParams) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "layer.AbortMultipartUpload")
+	defer func() { tracing.End(span, err) }()
+
 	multipartInfo, parts, err := n.getUploadParts(ctx, p)
 	if err != nil {
 		return err
@@ -549,10 +630,18 @@ func (n *layer) AbortMultipartUpload(ctx context.Context, p *UploadInfoParams) e
 		}
 	}
 
-	return n.treeService.DeleteMultipartUpload(ctx, p.Bkt, multipartInfo.ID)
+	if err = n.treeService.DeleteMultipartUpload(ctx, p.Bkt, multipartInfo.ID); err != nil {
+		return err
+	}
+	n.updateUsage(p.Bkt, data.BucketUsage{MultipartBytes: -partsMapSize(parts)})
+
+	return nil
 }
 
-func (n *layer) ListParts(ctx context.Context, p *ListPartsParams) (*ListPartsInfo, error) {
+func (n *layer) ListParts(ctx context.Context, p *ListPartsParams) (_ *ListPartsInfo, err error) {
+	ctx, span := tracing.StartSpan(ctx, "layer.ListParts")
+	defer func() { tracing.End(span, err) }()
+
 	var res ListPartsInfo
 	multipartInfo, partsInfo, err := n.getUploadParts(ctx, p.Info)
 	if err != nil {
@@ -15,6 +15,7 @@ import (
 	"github.com/TrueCloudLab/frostfs-s3-gw/creds/accessbox"
 	"github.com/TrueCloudLab/frostfs-sdk-go/bearer"
 	"github.com/TrueCloudLab/frostfs-sdk-go/checksum"
+	apistatus "github.com/TrueCloudLab/frostfs-sdk-go/client/status"
 	"github.com/TrueCloudLab/frostfs-sdk-go/container"
 	cid "github.com/TrueCloudLab/frostfs-sdk-go/container/id"
 	"github.com/TrueCloudLab/frostfs-sdk-go/eacl"
@@ -161,7 +162,7 @@ func (t *TestFrostFS) ReadObject(ctx context.Context, prm PrmObjectRead) (*Objec
 		}, nil
 	}
 
-	return nil, fmt.Errorf("object not found %s", addr)
+	return nil, fmt.Errorf("%w: %s", apistatus.ObjectNotFound{}, addr)
 }
 
 func (t *TestFrostFS) CreateObject(ctx context.Context, prm PrmObjectCreate) (oid.ID, error) {
@@ -240,6 +241,30 @@ func (t *TestFrostFS) DeleteObject(ctx context.Context, prm PrmObjectDelete) err
 	return nil
 }
 
+func (t *TestFrostFS) SearchObjects(_ context.Context, prm PrmObjectSearch) ([]oid.ID, error) {
+	result := make([]oid.ID, 0, len(t.objects))
+
+	for _, val := range t.objects {
+		objCnrID, _ := val.ContainerID()
+		if !prm.Container.Equals(objCnrID) || val.Type() != object.TypeRegular {
+			continue
+		}
+
+		attributes := make(map[string]string, len(val.Attributes()))
+		for _, attr := range val.Attributes() {
+			attributes[attr.Key()] = attr.Value()
+		}
+		if !matchObjectFilters(prm.Filters, attributes) {
+			continue
+		}
+
+		objID, _ := val.ID()
+		result = append(result, objID)
+	}
+
+	return result, nil
+}
+
 func (t *TestFrostFS) TimeToEpoch(_ context.Context, now, futureTime time.Time) (uint64, uint64, error) {
 	return t.currentEpoch, t.currentEpoch + uint64(futureTime.Sub(now).Seconds()), nil
 }
@@ -9,6 +9,7 @@ import (
 
 	"github.com/TrueCloudLab/frostfs-s3-gw/api"
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
+	"github.com/TrueCloudLab/frostfs-s3-gw/internal/tracing"
 	"go.uber.org/zap"
 )
 
@@ -19,7 +20,10 @@ type PutBucketNotificationConfigurationParams struct {
 	CopiesNumber  uint32
 }
 
-func (n *layer) PutBucketNotificationConfiguration(ctx context.Context, p *PutBucketNotificationConfigurationParams) error {
+func (n *layer) PutBucketNotificationConfiguration(ctx context.Context, p *PutBucketNotificationConfigurationParams) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "layer.PutBucketNotificationConfiguration")
+	defer func() { tracing.End(span, err) }()
+
 	confXML, err := xml.Marshal(p.Configuration)
 	if err != nil {
 		return fmt.Errorf("marshal notify configuration: %w", err)
@@ -55,11 +59,15 @@ func (n *layer) PutBucketNotificationConfiguration(ctx context.Context, p *PutBu
 	}
 
 	n.cache.PutNotificationConfiguration(n.Owner(ctx), p.BktInfo, p.Configuration)
+	n.invalidate(InvalidateNotification, p.BktInfo, "", "")
 
 	return nil
 }
 
-func (n *layer) GetBucketNotificationConfiguration(ctx context.Context, bktInfo *data.BucketInfo) (*data.NotificationConfiguration, error) {
+func (n *layer) GetBucketNotificationConfiguration(ctx context.Context, bktInfo *data.BucketInfo) (_ *data.NotificationConfiguration, err error) {
+	ctx, span := tracing.StartSpan(ctx, "layer.GetBucketNotificationConfiguration")
+	defer func() { tracing.End(span, err) }()
+
 	owner := n.Owner(ctx)
 	if conf := n.cache.GetNotificationConfiguration(owner, bktInfo); conf != nil {
 		return conf, nil
@@ -13,11 +13,13 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/TrueCloudLab/frostfs-s3-gw/api"
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/cache"
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
 	apiErrors "github.com/TrueCloudLab/frostfs-s3-gw/api/errors"
+	"github.com/TrueCloudLab/frostfs-s3-gw/internal/tracing"
 	"github.com/TrueCloudLab/frostfs-sdk-go/client"
 	cid "github.com/TrueCloudLab/frostfs-sdk-go/container/id"
 	"github.com/TrueCloudLab/frostfs-sdk-go/object"
@@ -57,6 +59,8 @@ type (
 		ContinuationToken string
 		StartAfter        string
 		FetchOwner        bool
+		// Tags are filters of object tags, all of them must match.
+		Tags []ObjectFilter
 	}
 
 	allObjectParams struct {
@@ -66,13 +70,11 @@ type (
 		MaxKeys           int
 		Marker            string
 		ContinuationToken string
+		// Filter selects listed versions (see treeWalker).
+		Filter func(context.Context, *data.NodeVersion) (bool, error)
 	}
 )
 
-const (
-	continuationToken = "<continuation-token>"
-)
-
 func newAddress(cnr cid.ID, obj oid.ID) oid.Address {
 	var addr oid.Address
 	addr.SetContainer(cnr)
@@ -82,6 +84,8 @@ func newAddress(cnr cid.ID, obj oid.ID) oid.Address {
 
 // objectHead returns all object's headers.
 func (n *layer) objectHead(ctx context.Context, bktInfo *data.BucketInfo, idObj oid.ID) (*object.Object, error) {
+	defer n.observeOperation(operationObjectHead, time.Now())
+
 	prm := PrmObjectRead{
 		Container:  bktInfo.CID,
 		Object:     idObj,
@@ -120,6 +124,8 @@ func (n *layer) initObjectPayloadReader(ctx context.Context, p getParams) (io.Re
 
 // objectGet returns an object with payload in the object.
 func (n *layer) objectGet(ctx context.Context, bktInfo *data.BucketInfo, objID oid.ID) (*object.Object, error) {
+	defer n.observeOperation(operationObjectGet, time.Now())
+
 	prm := PrmObjectRead{
 		Container:   bktInfo.CID,
 		Object:      objID,
@@ -183,14 +189,53 @@ func ParseCompletedPartHeader(hdr string) (*Part, error) {
 }
 
 // PutObject stores object into FrostFS, took payload from io.Reader.
-func (n *layer) PutObject(ctx context.Context, p *PutObjectParams) (*data.ExtendedObjectInfo, error) {
-	owner := n.Owner(ctx)
+func (n *layer) PutObject(ctx context.Context, p *PutObjectParams) (_ *data.ExtendedObjectInfo, err error) {
+	ctx, span := tracing.StartSpan(ctx, "layer.PutObject")
+	defer func() { tracing.End(span, err) }()
 
 	bktSettings, err := n.GetBucketSettings(ctx, p.BktInfo)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't get versioning settings object: %w", err)
 	}
 
+	extendedObjInfo, err := n.putObjectPayload(ctx, p, bktSettings)
+	if err != nil {
+		return nil, err
+	}
+	newVersion, id := extendedObjInfo.NodeVersion, extendedObjInfo.ObjectInfo.ID
+
+	if err = n.addVersion(ctx, p.BktInfo, newVersion); err != nil {
+		return nil, fmt.Errorf("couldn't add new verion to tree service: %w", err)
+	}
+
+	if p.Lock != nil && (p.Lock.Retention != nil || p.Lock.LegalHold != nil) {
+		putLockInfoPrms := &PutLockInfoParams{
+			ObjVersion: &ObjectVersion{
+				BktInfo:    p.BktInfo,
+				ObjectName: p.Object,
+				VersionID:  id.EncodeToString(),
+			},
+			NewLock:      p.Lock,
+			CopiesNumber: p.CopiesNumber,
+			NodeVersion:  newVersion, // provide new version to make one less tree service call in PutLockInfo
+		}
+
+		if err = n.PutLockInfo(ctx, putLockInfoPrms); err != nil {
+			return nil, err
+		}
+	}
+
+	n.cache.CleanListCacheEntriesContainingObject(p.Object, p.BktInfo.CID)
+	n.cache.PutObjectWithName(extendedObjInfo.ObjectInfo.Owner, extendedObjInfo)
+	n.invalidate(InvalidateObject, p.BktInfo, p.Object, "")
+
+	return extendedObjInfo, nil
+}
+
+// putObjectPayload stores the payload object. The version isn't added to the tree service.
+func (n *layer) putObjectPayload(ctx context.Context, p *PutObjectParams, bktSettings *data.BucketSettings) (*data.ExtendedObjectInfo, error) {
+	owner := n.Owner(ctx)
+
 	newVersion := &data.NodeVersion{
 		BaseNodeVersion: data.BaseNodeVersion{
 			FilePath: p.Object,
@@ -199,6 +244,7 @@ func (n *layer) PutObject(ctx context.Context, p *PutObjectParams) (*data.Extend
 		IsUnversioned: !bktSettings.VersioningEnabled(),
 	}
 
+	var err error
 	r := p.Reader
 	if p.Encryption.Enabled() {
 		p.Header[AttributeDecryptedSize] = strconv.FormatInt(p.Size, 10)
@@ -256,28 +302,6 @@ func (n *layer) PutObject(ctx context.Context, p *PutObjectParams) (*data.Extend
 
 	newVersion.OID = id
 	newVersion.ETag = hex.EncodeToString(hash)
-	if newVersion.ID, err = n.treeService.AddVersion(ctx, p.BktInfo, newVersion); err != nil {
-		return nil, fmt.Errorf("couldn't add new verion to tree service: %w", err)
-	}
-
-	if p.Lock != nil && (p.Lock.Retention != nil || p.Lock.LegalHold != nil) {
-		putLockInfoPrms := &PutLockInfoParams{
-			ObjVersion: &ObjectVersion{
-				BktInfo:    p.BktInfo,
-				ObjectName: p.Object,
-				VersionID:  id.EncodeToString(),
-			},
-			NewLock:      p.Lock,
-			CopiesNumber: p.CopiesNumber,
-			NodeVersion:  newVersion, // provide new version to make one less tree service call in PutLockInfo
-		}
-
-		if err = n.PutLockInfo(ctx, putLockInfoPrms); err != nil {
-			return nil, err
-		}
-	}
-
-	n.cache.CleanListCacheEntriesContainingObject(p.Object, p.BktInfo.CID)
 
 	objInfo := &data.ObjectInfo{
 		ID:  id,
@@ -293,14 +317,10 @@ func (n *layer) PutObject(ctx context.Context, p *PutObjectParams) (*data.Extend
 		HashSum:     newVersion.ETag,
 	}
 
-	extendedObjInfo := &data.ExtendedObjectInfo{
+	return &data.ExtendedObjectInfo{
 		ObjectInfo:  objInfo,
 		NodeVersion: newVersion,
-	}
-
-	n.cache.PutObjectWithName(owner, extendedObjInfo)
-
-	return extendedObjInfo, nil
+	}, nil
 }
 
 func (n *layer) headLastVersionIfNotDeleted(ctx context.Context, bkt *data.BucketInfo, objectName string) (*data.ExtendedObjectInfo, error) {
@@ -325,14 +345,14 @@ func (n *layer) headLastVersionIfNotDeleted(ctx context.Context, bkt *data.Bucke
 	if err != nil {
 		return nil, err
 	}
-	objInfo := objectInfoFromMeta(bkt, meta)
-
 	extObjInfo := &data.ExtendedObjectInfo{
-		ObjectInfo:  objInfo,
+		ObjectInfo:  versionObjectInfo(objectInfoFromMeta(bkt, meta), node),
 		NodeVersion: node,
 	}
 
-	n.cache.PutObjectWithName(owner, extObjInfo)
+	if objectInfoCacheable(node) {
+		n.cache.PutObjectWithName(owner, extObjInfo)
+	}
 
 	return extObjInfo, nil
 }
@@ -366,8 +386,10 @@ func (n *layer) headVersion(ctx context.Context, bkt *data.BucketInfo, p *HeadOb
 	}
 
 	owner := n.Owner(ctx)
-	if extObjInfo := n.cache.GetObject(owner, newAddress(bkt.CID, foundVersion.OID)); extObjInfo != nil {
-		return extObjInfo, nil
+	if objectInfoCacheable(foundVersion) {
+		if extObjInfo := n.cache.GetObject(owner, newAddress(bkt.CID, foundVersion.OID)); extObjInfo != nil {
+			return extObjInfo, nil
+		}
 	}
 
 	meta, err := n.objectHead(ctx, bkt, foundVersion.OID)
@@ -377,14 +399,14 @@ func (n *layer) headVersion(ctx context.Context, bkt *data.BucketInfo, p *HeadOb
 		}
 		return nil, err
 	}
-	objInfo := objectInfoFromMeta(bkt, meta)
-
 	extObjInfo := &data.ExtendedObjectInfo{
-		ObjectInfo:  objInfo,
+		ObjectInfo:  versionObjectInfo(objectInfoFromMeta(bkt, meta), foundVersion),
 		NodeVersion: foundVersion,
 	}
 
-	n.cache.PutObject(owner, extObjInfo)
+	if objectInfoCacheable(foundVersion) {
+		n.cache.PutObject(owner, extObjInfo)
+	}
 
 	return extObjInfo, nil
 }
@@ -399,6 +421,9 @@ func (n *layer) objectDelete(ctx context.Context, bktInfo *data.BucketInfo, idOb
 	n.prepareAuthParameters(ctx, &prm.PrmAuth, bktInfo.Owner)
 
 	n.cache.DeleteObject(newAddress(bktInfo.CID, idObj))
+	if n.payloadCache != nil {
+		n.payloadCache.Delete(newAddress(bktInfo.CID, idObj))
+	}
 
 	return n.frostFS.DeleteObject(ctx, prm)
 }
@@ -406,6 +431,8 @@ func (n *layer) objectDelete(ctx context.Context, bktInfo *data.BucketInfo, idOb
 // objectPutAndHash prepare auth parameters and invoke frostfs.CreateObject.
 // Returns object ID and payload sha256 hash.
 func (n *layer) objectPutAndHash(ctx context.Context, prm PrmObjectCreate, bktInfo *data.BucketInfo) (oid.ID, []byte, error) {
+	defer n.observeOperation(operationPayloadWrite, time.Now())
+
 	n.prepareAuthParameters(ctx, &prm.PrmAuth, bktInfo.Owner)
 	hash := sha256.New()
 	prm.Payload = wrapReader(prm.Payload, 64*1024, func(buf []byte) {
@@ -419,7 +446,10 @@ func (n *layer) objectPutAndHash(ctx context.Context, prm PrmObjectCreate, bktIn
 }
 
 // ListObjectsV1 returns objects in a bucket for requests of Version 1.
-func (n *layer) ListObjectsV1(ctx context.Context, p *ListObjectsParamsV1) (*ListObjectsInfoV1, error) {
+func (n *layer) ListObjectsV1(ctx context.Context, p *ListObjectsParamsV1) (_ *ListObjectsInfoV1, err error) {
+	ctx, span := tracing.StartSpan(ctx, "layer.ListObjectsV1")
+	defer func() { tracing.End(span, err) }()
+
 	var result ListObjectsInfoV1
 
 	prm := allObjectParams{
@@ -446,7 +476,10 @@ func (n *layer) ListObjectsV1(ctx context.Context, p *ListObjectsParamsV1) (*Lis
 }
 
 // ListObjectsV2 returns objects in a bucket for requests of Version 2.
-func (n *layer) ListObjectsV2(ctx context.Context, p *ListObjectsParamsV2) (*ListObjectsInfoV2, error) {
+func (n *layer) ListObjectsV2(ctx context.Context, p *ListObjectsParamsV2) (_ *ListObjectsInfoV2, err error) {
+	ctx, span := tracing.StartSpan(ctx, "layer.ListObjectsV2")
+	defer func() { tracing.End(span, err) }()
+
 	var result ListObjectsInfoV2
 
 	prm := allObjectParams{
@@ -458,6 +491,13 @@ func (n *layer) ListObjectsV2(ctx context.Context, p *ListObjectsParamsV2) (*Lis
 		ContinuationToken: p.ContinuationToken,
 	}
 
+	// tags are stored in the tree service, so versions are filtered during the tree walk
+	if len(p.Tags) != 0 {
+		prm.Filter = func(ctx context.Context, node *data.NodeVersion) (bool, error) {
+			return n.matchTagFilters(ctx, p.BktInfo, node, p.Tags)
+		}
+	}
+
 	objects, next, err := n.getLatestObjectsVersions(ctx, prm)
 	if err != nil {
 		return nil, err
@@ -465,7 +505,7 @@ func (n *layer) ListObjectsV2(ctx context.Context, p *ListObjectsParamsV2) (*Lis
 
 	if next != nil {
 		result.IsTruncated = true
-		result.NextContinuationToken = next.ID.EncodeToString()
+		result.NextContinuationToken = EncodeContinuationToken(objects[len(objects)-1].Name)
 	}
 
 	result.Prefixes, result.Objects = triageObjects(objects)
@@ -486,34 +526,23 @@ func (n *layer) getLatestObjectsVersions(ctx context.Context, p allObjectParams)
 		return nil, nil, nil
 	}
 
-	owner := n.Owner(ctx)
-	cacheKey := cache.CreateObjectsListCacheKey(p.Bucket.CID, p.Prefix, true)
-	nodeVersions := n.cache.GetList(owner, cacheKey)
-
-	if nodeVersions == nil {
-		nodeVersions, err = n.treeService.GetLatestVersionsByPrefix(ctx, p.Bucket, p.Prefix)
-		if err != nil {
-			return nil, nil, err
-		}
-		n.cache.PutList(owner, cacheKey, nodeVersions)
+	nodeVersions, err := n.listLatestVersions(ctx, p)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	if len(nodeVersions) == 0 {
 		return nil, nil, nil
 	}
 
-	sort.Slice(nodeVersions, func(i, j int) bool {
-		return nodeVersions[i].FilePath < nodeVersions[j].FilePath
-	})
-
 	poolCtx, cancel := context.WithCancel(ctx)
 	defer cancel()
-	objOutCh, err := n.initWorkerPool(poolCtx, 2, p, nodesGenerator(poolCtx, p, nodeVersions))
+	objOutCh, err := n.initWorkerPool(poolCtx, 2, p, nodesGenerator(poolCtx, nodeVersions))
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to init worker pool: %w", err)
 	}
 
-	objects = make([]*data.ObjectInfo, 0, p.MaxKeys)
+	objects = make([]*data.ObjectInfo, 0, len(nodeVersions))
 
 	for obj := range objOutCh {
 		objects = append(objects, obj)
@@ -531,26 +560,16 @@ func (n *layer) getLatestObjectsVersions(ctx context.Context, p allObjectParams)
 	return
 }
 
-func nodesGenerator(ctx context.Context, p allObjectParams, nodeVersions []*data.NodeVersion) <-chan *data.NodeVersion {
+func nodesGenerator(ctx context.Context, nodeVersions []*data.NodeVersion) <-chan *data.NodeVersion {
 	nodeCh := make(chan *data.NodeVersion)
-	existed := make(map[string]struct{}, len(nodeVersions)) // to squash the same directories
 
 	go func() {
-		var generated int
 	LOOP:
 		for _, node := range nodeVersions {
-			if shouldSkip(node, p, existed) {
-				continue
-			}
-
 			select {
 			case <-ctx.Done():
 				break LOOP
 			case nodeCh <- node:
-				generated++
-				if generated == p.MaxKeys+1 { // we use maxKeys+1 to be able to know nextMarker/nextContinuationToken
-					break LOOP
-				}
 			}
 		}
 		close(nodeCh)
@@ -641,81 +660,11 @@ func (n *layer) bucketNodeVersions(ctx context.Context, bkt *data.BucketInfo, pr
 	return nodeVersions, nil
 }
 
-func (n *layer) getAllObjectsVersions(ctx context.Context, bkt *data.BucketInfo, prefix, delimiter string) (map[string][]*data.ExtendedObjectInfo, error) {
-	nodeVersions, err := n.bucketNodeVersions(ctx, bkt, prefix)
-	if err != nil {
-		return nil, err
-	}
-
-	versions := make(map[string][]*data.ExtendedObjectInfo, len(nodeVersions))
-
-	for _, nodeVersion := range nodeVersions {
-		oi := &data.ObjectInfo{}
-
-		if nodeVersion.IsDeleteMarker() { // delete marker does not match any object in FrostFS
-			oi.ID = nodeVersion.OID
-			oi.Name = nodeVersion.FilePath
-			oi.Owner = nodeVersion.DeleteMarker.Owner
-			oi.Created = nodeVersion.DeleteMarker.Created
-			oi.IsDeleteMarker = true
-		} else {
-			if oi = n.objectInfoFromObjectsCacheOrFrostFS(ctx, bkt, nodeVersion, prefix, delimiter); oi == nil {
-				continue
-			}
-		}
-
-		eoi := &data.ExtendedObjectInfo{
-			ObjectInfo:  oi,
-			NodeVersion: nodeVersion,
-		}
-
-		objVersions, ok := versions[oi.Name]
-		if !ok {
-			objVersions = []*data.ExtendedObjectInfo{eoi}
-		} else if !oi.IsDir {
-			objVersions = append(objVersions, eoi)
-		}
-		versions[oi.Name] = objVersions
-	}
-
-	return versions, nil
-}
-
 func IsSystemHeader(key string) bool {
 	_, ok := api.SystemMetadata[key]
 	return ok || strings.HasPrefix(key, api.FrostFSSystemMetadataPrefix)
 }
 
-func shouldSkip(node *data.NodeVersion, p allObjectParams, existed map[string]struct{}) bool {
-	if node.IsDeleteMarker() {
-		return true
-	}
-
-	filePath := node.FilePath
-	if dirName := tryDirectoryName(node, p.Prefix, p.Delimiter); len(dirName) != 0 {
-		filePath = dirName
-	}
-	if _, ok := existed[filePath]; ok {
-		return true
-	}
-
-	if filePath <= p.Marker {
-		return true
-	}
-
-	if p.ContinuationToken != "" {
-		if _, ok := existed[continuationToken]; !ok {
-			if p.ContinuationToken != node.OID.EncodeToString() {
-				return true
-			}
-			existed[continuationToken] = struct{}{}
-		}
-	}
-
-	existed[filePath] = struct{}{}
-	return false
-}
-
 func triageObjects(allObjects []*data.ObjectInfo) (prefixes []string, objects []*data.ObjectInfo) {
 	for _, ov := range allObjects {
 		if ov.IsDir {
@@ -746,8 +695,16 @@ func (n *layer) objectInfoFromObjectsCacheOrFrostFS(ctx context.Context, bktInfo
 	}
 
 	owner := n.Owner(ctx)
-	if extInfo := n.cache.GetObject(owner, newAddress(bktInfo.CID, node.OID)); extInfo != nil {
-		return extInfo.ObjectInfo
+	if objectInfoCacheable(node) {
+		if extInfo := n.cache.GetObject(owner, newAddress(bktInfo.CID, node.OID)); extInfo != nil {
+			if extInfo.ObjectInfo.Name == node.FilePath {
+				return extInfo.ObjectInfo
+			}
+			// the id doesn't identify the key, the object could be cached before renaming
+			objInfo := *extInfo.ObjectInfo
+			objInfo.Name = node.FilePath
+			return &objInfo
+		}
 	}
 
 	meta, err := n.objectHead(ctx, bktInfo, node.OID)
@@ -756,8 +713,10 @@ func (n *layer) objectInfoFromObjectsCacheOrFrostFS(ctx context.Context, bktInfo
 		return nil
 	}
 
-	oi = objectInfoFromMeta(bktInfo, meta)
-	n.cache.PutObject(owner, &data.ExtendedObjectInfo{ObjectInfo: oi, NodeVersion: node})
+	oi = versionObjectInfo(objectInfoFromMeta(bktInfo, meta), node)
+	if objectInfoCacheable(node) {
+		n.cache.PutObject(owner, &data.ExtendedObjectInfo{ObjectInfo: oi, NodeVersion: node})
+	}
 
 	return oi
 }
@@ -769,7 +728,7 @@ func tryDirectory(bktInfo *data.BucketInfo, node *data.NodeVersion, prefix, deli
 	}
 
 	return &data.ObjectInfo{
-		ID:             node.OID, // to use it as continuation token
+		ID:             node.OID,
 		CID:            bktInfo.CID,
 		IsDir:          true,
 		IsDeleteMarker: node.IsDeleteMarker(),
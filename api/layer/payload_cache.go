@@ -0,0 +1,50 @@
+package layer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
+	"go.uber.org/zap"
+)
+
+// initCachedPayloadReader reads small object payload from the local payload cache.
+// On cache miss the full payload is read from FrostFS and cached, so range reads
+// of the same object are served from the cache too.
+func (n *layer) initCachedPayloadReader(ctx context.Context, p getParams, objInfo *data.ObjectInfo) (io.Reader, error) {
+	if n.payloadCache == nil || objInfo.Size < 0 || uint64(objInfo.Size) > n.payloadCache.MaxObjectSize() {
+		return n.initObjectPayloadReader(ctx, p)
+	}
+
+	addr := newAddress(p.bktInfo.CID, p.oid)
+	payload, ok := n.payloadCache.Get(addr, objInfo.HashSum)
+	if !ok {
+		full := p
+		full.off, full.ln = 0, 0
+
+		r, err := n.initObjectPayloadReader(ctx, full)
+		if err != nil {
+			return nil, err
+		}
+
+		if payload, err = io.ReadAll(r); err != nil {
+			return nil, fmt.Errorf("read payload: %w", err)
+		}
+
+		if err = n.payloadCache.Put(addr, objInfo.HashSum, payload); err != nil {
+			n.log.Warn("couldn't cache object payload", zap.Stringer("address", addr), zap.Error(err))
+		}
+	}
+
+	if p.ln == 0 {
+		return bytes.NewReader(payload), nil
+	}
+
+	if p.off+p.ln > uint64(len(payload)) {
+		return nil, fmt.Errorf("payload range %d:%d is out of payload size %d", p.off, p.ln, len(payload))
+	}
+
+	return bytes.NewReader(payload[p.off : p.off+p.ln]), nil
+}
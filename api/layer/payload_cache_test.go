@@ -0,0 +1,47 @@
+package layer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/cache"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestPayloadCache(t *testing.T) {
+	tc := prepareContext(t)
+
+	payloadCache, err := cache.NewPayloadCache(&cache.PayloadConfig{
+		Dir:           t.TempDir(),
+		MaxSize:       1024,
+		MaxObjectSize: 16,
+		Logger:        zap.NewExample(),
+	})
+	require.NoError(t, err)
+	tc.layer.(*layer).payloadCache = payloadCache
+
+	small := tc.putObject([]byte("small content"))
+	_, payload := tc.getObject(tc.obj, small.VersionID(), false)
+	require.Equal(t, []byte("small content"), payload)
+	require.Equal(t, uint64(len("small content")), payloadCache.Size())
+
+	// payload is served from the cache without FrostFS
+	delete(tc.testFrostFS.objects, small.Address().EncodeToString())
+
+	content := bytes.NewBuffer(nil)
+	err = tc.layer.GetObject(tc.ctx, &GetObjectParams{
+		ObjectInfo: small,
+		BucketInfo: tc.bktInfo,
+		Writer:     content,
+		Range:      &RangeParams{Start: 6, End: 9},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []byte("cont"), content.Bytes())
+
+	// big objects aren't cached
+	big := tc.putObject([]byte("content which is too big to be cached"))
+	_, payload = tc.getObject(tc.obj, big.VersionID(), false)
+	require.Equal(t, []byte("content which is too big to be cached"), payload)
+	require.Equal(t, uint64(len("small content")), payloadCache.Size())
+}
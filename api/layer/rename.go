@@ -0,0 +1,249 @@
+package layer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
+	apiErrors "github.com/TrueCloudLab/frostfs-s3-gw/api/errors"
+	"github.com/TrueCloudLab/frostfs-s3-gw/internal/tracing"
+)
+
+type (
+	// RenameObjectParams stores rename request params.
+	RenameObjectParams struct {
+		BktInfo  *data.BucketInfo
+		Settings *data.BucketSettings
+		// SrcObject and DstObject are names of objects or prefixes (with the trailing slash).
+		SrcObject string
+		DstObject string
+	}
+
+	// RenamedObject describes the object moved by RenameObject.
+	RenamedObject struct {
+		// Source is the name of the object before renaming.
+		Source string
+		// ObjectInfo is the latest version of the object with the new name.
+		ObjectInfo *data.ObjectInfo
+	}
+)
+
+// RenameObject moves the object or the whole prefix to the new name by relinking nodes of the version tree,
+// object payloads aren't copied. All versions of the object are moved together with their tags and locks.
+// Prefix is moved atomically, the destination prefix must not contain objects.
+func (n *layer) RenameObject(ctx context.Context, p *RenameObjectParams) (_ []*RenamedObject, err error) {
+	ctx, span := tracing.StartSpan(ctx, "layer.RenameObject")
+	defer func() { tracing.End(span, err) }()
+
+	srcIsPrefix, dstIsPrefix := strings.HasSuffix(p.SrcObject, "/"), strings.HasSuffix(p.DstObject, "/")
+	if p.SrcObject == p.DstObject || srcIsPrefix != dstIsPrefix {
+		return nil, apiErrors.GetAPIError(apiErrors.ErrInvalidRenameDest)
+	}
+
+	if srcIsPrefix {
+		if strings.HasPrefix(p.DstObject, p.SrcObject) || p.SrcObject == "/" || p.DstObject == "/" {
+			return nil, apiErrors.GetAPIError(apiErrors.ErrInvalidRenameDest)
+		}
+		return n.renamePrefix(ctx, p)
+	}
+
+	return n.renameObject(ctx, p)
+}
+
+func (n *layer) renameObject(ctx context.Context, p *RenameObjectParams) ([]*RenamedObject, error) {
+	versions, err := n.treeService.GetVersions(ctx, p.BktInfo, p.SrcObject)
+	if err != nil && !errors.Is(err, ErrNodeNotFound) {
+		return nil, fmt.Errorf("get versions of '%s': %w", p.SrcObject, err)
+	}
+	if len(versions) == 0 {
+		return nil, apiErrors.GetAPIError(apiErrors.ErrNoSuchKey)
+	}
+
+	// versions are moved from the oldest one, so the latest version stays the latest
+	sort.Slice(versions, func(i, j int) bool { return versions[i].Timestamp < versions[j].Timestamp })
+	latest := versions[len(versions)-1]
+	if latest.IsDeleteMarker() {
+		return nil, apiErrors.GetAPIError(apiErrors.ErrNoSuchKey)
+	}
+
+	dstVersions, err := n.treeService.GetVersions(ctx, p.BktInfo, p.DstObject)
+	if err != nil && !errors.Is(err, ErrNodeNotFound) {
+		return nil, fmt.Errorf("get versions of '%s': %w", p.DstObject, err)
+	}
+
+	// there can be only one unversioned (null) version of the object,
+	// also it's overwritten like by PUT if versioning isn't enabled
+	var srcUnversioned bool
+	for _, version := range versions {
+		srcUnversioned = srcUnversioned || version.IsUnversioned
+	}
+	var replaced []*data.NodeVersion
+	for _, version := range dstVersions {
+		if version.IsUnversioned && (srcUnversioned || !p.Settings.VersioningEnabled()) {
+			replaced = append(replaced, version)
+		}
+	}
+
+	if err = n.checkLocks(ctx, p.BktInfo, append(replaced, versions...)); err != nil {
+		return nil, err
+	}
+
+	if err = n.treeService.MoveVersions(ctx, p.BktInfo, versions, p.DstObject); err != nil {
+		return nil, fmt.Errorf("move versions of '%s': %w", p.SrcObject, err)
+	}
+	// the source object is gone, the moved latest version becomes the latest one of the destination
+	n.updateUsage(p.BktInfo, data.BucketUsage{Objects: -data.UsageOfVersions(dstVersions).Objects})
+	n.invalidateRenamed(p.BktInfo, p.SrcObject, versions)
+	n.invalidateRenamed(p.BktInfo, p.DstObject, replaced)
+
+	for _, version := range replaced {
+		if !version.IsDeleteMarker() {
+			if err = n.deleteVersionObject(ctx, p.BktInfo, version); err != nil {
+				return nil, fmt.Errorf("delete replaced object '%s': %w", version.OID.EncodeToString(), err)
+			}
+		}
+		if err = n.treeService.RemoveVersion(ctx, p.BktInfo, version.ID); err != nil {
+			return nil, fmt.Errorf("remove replaced version node: %w", err)
+		}
+		n.updateUsage(p.BktInfo, data.BucketUsage{}.Sub(versionUsage(version)))
+	}
+
+	latest.FilePath = p.DstObject
+	return []*RenamedObject{{Source: p.SrcObject, ObjectInfo: getPartialObjectInfo(p.BktInfo, latest)}}, nil
+}
+
+func (n *layer) renamePrefix(ctx context.Context, p *RenameObjectParams) ([]*RenamedObject, error) {
+	srcPath, dstPath := strings.TrimSuffix(p.SrcObject, "/"), strings.TrimSuffix(p.DstObject, "/")
+
+	dir, err := n.treeService.GetDirectory(ctx, p.BktInfo, srcPath)
+	if err != nil {
+		if errors.Is(err, ErrNodeNotFound) {
+			return nil, apiErrors.GetAPIError(apiErrors.ErrNoSuchKey)
+		}
+		return nil, fmt.Errorf("get directory '%s': %w", srcPath, err)
+	}
+
+	dstDir, err := n.treeService.GetDirectory(ctx, p.BktInfo, dstPath)
+	if err != nil && !errors.Is(err, ErrNodeNotFound) {
+		return nil, fmt.Errorf("get directory '%s': %w", dstPath, err)
+	} else if err == nil {
+		// directory without versions is left after removal of all objects with the prefix
+		dstVersions, err := n.treeService.GetAllVersionsByPrefix(ctx, p.BktInfo, p.DstObject)
+		if err != nil && !errors.Is(err, ErrNodeNotFound) {
+			return nil, fmt.Errorf("get versions by prefix '%s': %w", p.DstObject, err)
+		}
+		if len(dstVersions) != 0 {
+			return nil, apiErrors.GetAPIError(apiErrors.ErrRenameDestinationExists)
+		}
+		if err = n.treeService.RemoveVersion(ctx, p.BktInfo, dstDir.ID); err != nil {
+			return nil, fmt.Errorf("remove empty directory '%s': %w", dstPath, err)
+		}
+	}
+
+	versions, err := n.treeService.GetAllVersionsByPrefix(ctx, p.BktInfo, p.SrcObject)
+	if err != nil && !errors.Is(err, ErrNodeNotFound) {
+		return nil, fmt.Errorf("get versions by prefix '%s': %w", p.SrcObject, err)
+	}
+
+	if err = n.checkLocks(ctx, p.BktInfo, versions); err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string][]*data.NodeVersion)
+	for _, version := range versions {
+		byName[version.FilePath] = append(byName[version.FilePath], version)
+	}
+
+	if err = n.treeService.MoveDirectory(ctx, p.BktInfo, dir, dstPath); err != nil {
+		return nil, fmt.Errorf("move directory '%s': %w", srcPath, err)
+	}
+
+	result := make([]*RenamedObject, 0, len(byName))
+	for name, objVersions := range byName {
+		newName := p.DstObject + strings.TrimPrefix(name, p.SrcObject)
+		n.invalidateRenamed(p.BktInfo, name, objVersions)
+		n.invalidateRenamed(p.BktInfo, newName, nil)
+
+		latest := objVersions[0]
+		for _, version := range objVersions {
+			if version.Timestamp > latest.Timestamp {
+				latest = version
+			}
+		}
+		if latest.IsDeleteMarker() {
+			continue
+		}
+
+		latest.FilePath = newName
+		result = append(result, &RenamedObject{Source: name, ObjectInfo: getPartialObjectInfo(p.BktInfo, latest)})
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Source < result[j].Source })
+
+	return result, nil
+}
+
+// checkLocks returns AccessDenied error if any of the versions is under legal hold or retention.
+func (n *layer) checkLocks(ctx context.Context, bktInfo *data.BucketInfo, versions []*data.NodeVersion) error {
+	if !bktInfo.ObjectLockEnabled {
+		return nil
+	}
+
+	now := TimeNow(ctx)
+	for _, version := range versions {
+		if version.IsDeleteMarker() {
+			continue
+		}
+
+		lockInfo, err := n.treeService.GetLock(ctx, bktInfo, version.ID)
+		if err != nil && !errors.Is(err, ErrNodeNotFound) {
+			return fmt.Errorf("get lock of '%s': %w", version.FilePath, err)
+		}
+		if lockInfo == nil {
+			continue
+		}
+
+		if lockInfo.IsLegalHoldSet() {
+			return apiErrors.GetAPIError(apiErrors.ErrAccessDenied)
+		}
+		if lockInfo.IsRetentionSet() {
+			until, err := time.Parse(time.RFC3339, lockInfo.UntilDate())
+			if err != nil || until.After(now) {
+				return apiErrors.GetAPIError(apiErrors.ErrAccessDenied)
+			}
+		}
+	}
+
+	return nil
+}
+
+// invalidateRenamed drops cache entries of the object name and its versions whose names are changed.
+func (n *layer) invalidateRenamed(bktInfo *data.BucketInfo, name string, versions []*data.NodeVersion) {
+	n.cache.CleanListCacheEntriesContainingObject(name, bktInfo.CID)
+	n.cache.DeleteObjectName(bktInfo.CID, bktInfo.Name, name)
+	n.invalidate(InvalidateObject, bktInfo, name, "")
+
+	latest := &ObjectVersion{BktInfo: bktInfo, ObjectName: name}
+	n.cache.DeleteTagging(objectTaggingCacheKey(latest))
+	n.cache.DeleteLockInfo(lockObjectKey(latest))
+
+	for _, version := range versions {
+		n.cache.DeleteObject(newAddress(bktInfo.CID, version.OID))
+		n.invalidate(InvalidateObject, bktInfo, name, version.OID.EncodeToString())
+
+		for _, versionID := range []string{version.OID.EncodeToString(), nodeVersionID(version)} {
+			objVersion := &ObjectVersion{BktInfo: bktInfo, ObjectName: name, VersionID: versionID}
+			n.cache.DeleteTagging(objectTaggingCacheKey(objVersion))
+			n.cache.DeleteLockInfo(lockObjectKey(objVersion))
+		}
+	}
+
+	if len(versions) != 0 {
+		n.invalidate(InvalidateTagging, bktInfo, name, "")
+		n.invalidate(InvalidateLock, bktInfo, name, "")
+	}
+}
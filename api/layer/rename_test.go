@@ -0,0 +1,123 @@
+package layer
+
+import (
+	"testing"
+
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
+	apiErrors "github.com/TrueCloudLab/frostfs-s3-gw/api/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenameObjectVersions(t *testing.T) {
+	tc := prepareContext(t)
+	settings := &data.BucketSettings{Versioning: data.VersioningEnabled}
+	err := tc.layer.PutBucketSettings(tc.ctx, &PutSettingsParams{BktInfo: tc.bktInfo, Settings: settings})
+	require.NoError(t, err)
+
+	rename := func(src, dst string) error {
+		_, err := tc.layer.RenameObject(tc.ctx, &RenameObjectParams{
+			BktInfo:   tc.bktInfo,
+			Settings:  settings,
+			SrcObject: src,
+			DstObject: dst,
+		})
+		return err
+	}
+
+	tc.obj = "src"
+	first := tc.putObject([]byte("first"))
+	second := tc.putObject([]byte("second"))
+	tc.obj = "dst"
+	existing := tc.putObject([]byte("existing"))
+
+	require.NoError(t, rename("src", "dst"))
+	tc.getObject("src", "", true)
+
+	// moved versions become the latest ones, the previous version of the destination is kept
+	objInfo, content := tc.getObject("dst", "", false)
+	require.Equal(t, second.ID, objInfo.ID)
+	require.Equal(t, "dst", objInfo.Name)
+	require.Equal(t, "second", string(content))
+
+	for _, version := range []*data.ObjectInfo{first, second, existing} {
+		objInfo, _ = tc.getObject("dst", version.VersionID(), false)
+		require.Equal(t, "dst", objInfo.Name)
+	}
+	require.Len(t, tc.listVersions().Version, 3)
+
+	require.ErrorIs(t, rename("src", "other"), apiErrors.GetAPIError(apiErrors.ErrNoSuchKey))
+	require.ErrorIs(t, rename("dst", "dst"), apiErrors.GetAPIError(apiErrors.ErrInvalidRenameDest))
+}
+
+func TestRenameObjectUnversioned(t *testing.T) {
+	tc := prepareContext(t)
+	settings := &data.BucketSettings{Versioning: data.VersioningUnversioned}
+
+	tc.obj = "src"
+	src := tc.putObject([]byte("src"))
+	tc.obj = "dst"
+	dst := tc.putObject([]byte("dst"))
+
+	// the destination object is overwritten as it is done by PUT
+	_, err := tc.layer.RenameObject(tc.ctx, &RenameObjectParams{
+		BktInfo:   tc.bktInfo,
+		Settings:  settings,
+		SrcObject: "src",
+		DstObject: "dst",
+	})
+	require.NoError(t, err)
+
+	objInfo, _ := tc.getObject("dst", "", false)
+	require.Equal(t, src.ID, objInfo.ID)
+	require.Nil(t, tc.getObjectByID(dst.ID))
+	require.Len(t, tc.listVersions().Version, 1)
+}
+
+func TestRenameObjectPagination(t *testing.T) {
+	tc := prepareContext(t)
+	settings := &data.BucketSettings{Versioning: data.VersioningEnabled}
+	err := tc.layer.PutBucketSettings(tc.ctx, &PutSettingsParams{BktInfo: tc.bktInfo, Settings: settings})
+	require.NoError(t, err)
+
+	rename := func(src, dst string) {
+		_, err := tc.layer.RenameObject(tc.ctx, &RenameObjectParams{
+			BktInfo:   tc.bktInfo,
+			Settings:  settings,
+			SrcObject: src,
+			DstObject: dst,
+		})
+		require.NoError(t, err)
+	}
+
+	for _, name := range []string{"a", "b", "c", "dir/1", "dir/2"} {
+		tc.obj = name
+		tc.putObject([]byte(name))
+	}
+
+	listParams := &ListObjectsParamsV2{
+		ListObjectsParamsCommon: ListObjectsParamsCommon{BktInfo: tc.bktInfo, MaxKeys: 2},
+	}
+	res, err := tc.layer.ListObjectsV2(tc.ctx, listParams)
+	require.NoError(t, err)
+	require.True(t, res.IsTruncated)
+
+	// the listing is resumed after the last listed key even if the object was renamed meanwhile
+	rename("b", "y")
+	rename("dir/", "e/")
+
+	var names []string
+	for res.IsTruncated {
+		listParams.ContinuationToken = res.NextContinuationToken
+		res, err = tc.layer.ListObjectsV2(tc.ctx, listParams)
+		require.NoError(t, err)
+		for _, obj := range res.Objects {
+			names = append(names, obj.Name)
+		}
+	}
+	require.Equal(t, []string{"c", "e/1", "e/2", "y"}, names)
+
+	rename("a", "z")
+	for _, maxKeys := range []int{1, 2, 1000} {
+		require.Equal(t, []string{"c", "e/1", "e/2", "y", "z"}, tc.listAll("", "", maxKeys))
+	}
+}
@@ -0,0 +1,129 @@
+package layer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
+	"github.com/TrueCloudLab/frostfs-s3-gw/internal/tracing"
+	oid "github.com/TrueCloudLab/frostfs-sdk-go/object/id"
+)
+
+type (
+	// ObjectFilter is the predicate of the object metadata or tag: the value must be equal to Value
+	// or only the key must exist if Exists is set.
+	ObjectFilter struct {
+		Key    string
+		Value  string
+		Exists bool
+	}
+
+	// SearchObjectsParams stores parameters of the search of objects by user metadata and tags.
+	SearchObjectsParams struct {
+		BktInfo           *data.BucketInfo
+		Prefix            string
+		MaxKeys           int
+		StartAfter        string
+		ContinuationToken string
+		// Metadata are filters of user metadata, all of them must match.
+		Metadata []ObjectFilter
+		// Tags are filters of object tags, all of them must match.
+		Tags []ObjectFilter
+	}
+)
+
+// SearchObjects returns latest versions of objects which match metadata and tag filters.
+// Objects with the metadata are searched in FrostFS by attributes, the result is joined with
+// latest versions from the tree service, so deleted and overwritten objects aren't returned.
+func (n *layer) SearchObjects(ctx context.Context, p *SearchObjectsParams) (_ *ListObjectsInfoV2, err error) {
+	ctx, span := tracing.StartSpan(ctx, "layer.SearchObjects")
+	defer func() { tracing.End(span, err) }()
+
+	filter, err := n.searchFilter(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	objects, next, err := n.getLatestObjectsVersions(ctx, allObjectParams{
+		Bucket:            p.BktInfo,
+		Prefix:            p.Prefix,
+		MaxKeys:           p.MaxKeys,
+		Marker:            p.StartAfter,
+		ContinuationToken: p.ContinuationToken,
+		Filter:            filter,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result ListObjectsInfoV2
+	if next != nil {
+		result.IsTruncated = true
+		result.NextContinuationToken = EncodeContinuationToken(objects[len(objects)-1].Name)
+	}
+	result.Objects = objects
+
+	return &result, nil
+}
+
+// searchFilter returns the filter of latest versions for the tree walk.
+func (n *layer) searchFilter(ctx context.Context, p *SearchObjectsParams) (func(context.Context, *data.NodeVersion) (bool, error), error) {
+	var found map[oid.ID]struct{}
+	if len(p.Metadata) != 0 {
+		prm := PrmObjectSearch{Container: p.BktInfo.CID, Filters: p.Metadata}
+		n.prepareAuthParameters(ctx, &prm.PrmAuth, p.BktInfo.Owner)
+
+		ids, err := n.frostFS.SearchObjects(ctx, prm)
+		if err != nil {
+			return nil, fmt.Errorf("search objects: %w", err)
+		}
+
+		found = make(map[oid.ID]struct{}, len(ids))
+		for _, id := range ids {
+			found[id] = struct{}{}
+		}
+	}
+
+	return func(ctx context.Context, node *data.NodeVersion) (bool, error) {
+		if len(p.Metadata) != 0 {
+			if node.Copy != nil {
+				// metadata of copies is stored in the tree service, attributes belong to the source object
+				if !matchObjectFilters(p.Metadata, node.Copy.Headers) {
+					return false, nil
+				}
+			} else if _, ok := found[node.OID]; !ok {
+				return false, nil
+			}
+		}
+
+		return n.matchTagFilters(ctx, p.BktInfo, node, p.Tags)
+	}, nil
+}
+
+// matchTagFilters checks tags of the version which are stored in the tree service.
+func (n *layer) matchTagFilters(ctx context.Context, bktInfo *data.BucketInfo, node *data.NodeVersion, filters []ObjectFilter) (bool, error) {
+	if len(filters) == 0 {
+		return true, nil
+	}
+
+	_, tags, err := n.GetObjectTagging(ctx, &GetObjectTaggingParams{
+		ObjectVersion: &ObjectVersion{BktInfo: bktInfo, ObjectName: node.FilePath},
+		NodeVersion:   node,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return matchObjectFilters(filters, tags), nil
+}
+
+func matchObjectFilters(filters []ObjectFilter, values map[string]string) bool {
+	for _, filter := range filters {
+		value, ok := values[filter.Key]
+		if !ok || !filter.Exists && value != filter.Value {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,112 @@
+package layer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
+	"github.com/stretchr/testify/require"
+)
+
+func (tc *testContext) putObjectWithHeader(name string, header map[string]string) *data.ObjectInfo {
+	extObjInfo, err := tc.layer.PutObject(tc.ctx, &PutObjectParams{
+		BktInfo: tc.bktInfo,
+		Object:  name,
+		Size:    int64(len(name)),
+		Reader:  bytes.NewReader([]byte(name)),
+		Header:  header,
+	})
+	require.NoError(tc.t, err)
+
+	return extObjInfo.ObjectInfo
+}
+
+func (tc *testContext) searchObjects(p *SearchObjectsParams) []string {
+	p.BktInfo = tc.bktInfo
+	if p.MaxKeys == 0 {
+		p.MaxKeys = 1000
+	}
+
+	res, err := tc.layer.SearchObjects(tc.ctx, p)
+	require.NoError(tc.t, err)
+
+	names := make([]string, 0, len(res.Objects))
+	for _, objInfo := range res.Objects {
+		names = append(names, objInfo.Name)
+	}
+	return names
+}
+
+func TestSearchObjects(t *testing.T) {
+	tc := prepareContext(t)
+
+	tc.putObjectWithHeader("a", map[string]string{"project": "x", "owner": "alice"})
+	tc.putObjectWithHeader("dir/b", map[string]string{"project": "x"})
+	tc.putObjectWithHeader("c", map[string]string{"project": "y"})
+	src := tc.putObjectWithHeader("d", map[string]string{"project": "x"})
+
+	// the overwritten version isn't found
+	tc.putObjectWithHeader("d", map[string]string{"project": "y"})
+
+	// metadata of the copy is stored in the tree service
+	tc.copyObject(src, "e", map[string]string{"project": "x"})
+	tc.copyObject(src, "f", map[string]string{"project": "z"})
+
+	settings, err := tc.layer.GetBucketSettings(tc.ctx, tc.bktInfo)
+	require.NoError(t, err)
+	tc.putObjectWithHeader("g", map[string]string{"project": "x"})
+	tc.deleteObject("g", "", settings)
+
+	xFilter := []ObjectFilter{{Key: "project", Value: "x"}}
+	require.Equal(t, []string{"a", "dir/b", "e"}, tc.searchObjects(&SearchObjectsParams{Metadata: xFilter}))
+	require.Equal(t, []string{"dir/b"}, tc.searchObjects(&SearchObjectsParams{Metadata: xFilter, Prefix: "dir/"}))
+	require.Equal(t, []string{"a"}, tc.searchObjects(&SearchObjectsParams{
+		Metadata: []ObjectFilter{{Key: "project", Value: "x"}, {Key: "owner", Exists: true}},
+	}))
+	require.Equal(t, []string{"a", "c", "d", "dir/b", "e", "f"}, tc.searchObjects(&SearchObjectsParams{
+		Metadata: []ObjectFilter{{Key: "project", Exists: true}},
+	}))
+
+	res, err := tc.layer.SearchObjects(tc.ctx, &SearchObjectsParams{BktInfo: tc.bktInfo, Metadata: xFilter, MaxKeys: 1})
+	require.NoError(t, err)
+	require.True(t, res.IsTruncated)
+	require.Len(t, res.Objects, 1)
+	require.Equal(t, []string{"dir/b", "e"}, tc.searchObjects(&SearchObjectsParams{
+		Metadata:          xFilter,
+		ContinuationToken: res.NextContinuationToken,
+	}))
+	require.Equal(t, []string{"dir/b", "e"}, tc.searchObjects(&SearchObjectsParams{Metadata: xFilter, StartAfter: "a"}))
+}
+
+func TestSearchObjectsByTags(t *testing.T) {
+	tc := prepareContext(t)
+
+	for name, tags := range map[string]map[string]string{
+		"a": {"project": "x", "team": "core"},
+		"b": {"project": "x"},
+		"c": {"project": "y"},
+		"d": nil,
+	} {
+		objInfo := tc.putObjectWithHeader(name, map[string]string{"kind": "log"})
+		if tags == nil {
+			continue
+		}
+		_, err := tc.layer.PutObjectTagging(tc.ctx, &PutObjectTaggingParams{
+			ObjectVersion: &ObjectVersion{BktInfo: tc.bktInfo, ObjectName: name, VersionID: objInfo.VersionID()},
+			TagSet:        tags,
+		})
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, []string{"a", "b"}, tc.searchObjects(&SearchObjectsParams{
+		Tags: []ObjectFilter{{Key: "project", Value: "x"}},
+	}))
+	require.Equal(t, []string{"a"}, tc.searchObjects(&SearchObjectsParams{
+		Metadata: []ObjectFilter{{Key: "kind", Value: "log"}},
+		Tags:     []ObjectFilter{{Key: "project", Value: "x"}, {Key: "team", Exists: true}},
+	}))
+	require.Empty(t, tc.searchObjects(&SearchObjectsParams{
+		Metadata: []ObjectFilter{{Key: "kind", Value: "other"}},
+		Tags:     []ObjectFilter{{Key: "project", Exists: true}},
+	}))
+}
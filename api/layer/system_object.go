@@ -11,6 +11,7 @@ import (
 
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/errors"
+	"github.com/TrueCloudLab/frostfs-s3-gw/internal/tracing"
 	oid "github.com/TrueCloudLab/frostfs-sdk-go/object/id"
 )
 
@@ -27,6 +28,9 @@ type PutLockInfoParams struct {
 }
 
 func (n *layer) PutLockInfo(ctx context.Context, p *PutLockInfoParams) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "layer.PutLockInfo")
+	defer func() { tracing.End(span, err) }()
+
 	newLock := p.NewLock
 	versionNode := p.NodeVersion
 	// sometimes node version can be provided from executing context
@@ -96,6 +100,7 @@ func (n *layer) PutLockInfo(ctx context.Context, p *PutLockInfoParams) (err erro
 	}
 
 	n.cache.PutLockInfo(n.Owner(ctx), lockObjectKey(p.ObjVersion), lockInfo)
+	n.invalidate(InvalidateLock, p.ObjVersion.BktInfo, p.ObjVersion.ObjectName, p.ObjVersion.VersionID)
 
 	return nil
 }
@@ -130,7 +135,10 @@ func (n *layer) putLockObject(ctx context.Context, bktInfo *data.BucketInfo, obj
 	return id, err
 }
 
-func (n *layer) GetLockInfo(ctx context.Context, objVersion *ObjectVersion) (*data.LockInfo, error) {
+func (n *layer) GetLockInfo(ctx context.Context, objVersion *ObjectVersion) (_ *data.LockInfo, err error) {
+	ctx, span := tracing.StartSpan(ctx, "layer.GetLockInfo")
+	defer func() { tracing.End(span, err) }()
+
 	owner := n.Owner(ctx)
 	if lockInfo := n.cache.GetLockInfo(owner, lockObjectKey(objVersion)); lockInfo != nil {
 		return lockInfo, nil
@@ -191,7 +199,10 @@ func lockObjectKey(objVersion *ObjectVersion) string {
 	return ".lock." + objVersion.BktInfo.CID.EncodeToString() + "." + objVersion.ObjectName + "." + objVersion.VersionID
 }
 
-func (n *layer) GetBucketSettings(ctx context.Context, bktInfo *data.BucketInfo) (*data.BucketSettings, error) {
+func (n *layer) GetBucketSettings(ctx context.Context, bktInfo *data.BucketInfo) (_ *data.BucketSettings, err error) {
+	ctx, span := tracing.StartSpan(ctx, "layer.GetBucketSettings")
+	defer func() { tracing.End(span, err) }()
+
 	owner := n.Owner(ctx)
 	if settings := n.cache.GetSettings(owner, bktInfo); settings != nil {
 		return settings, nil
@@ -210,12 +221,16 @@ func (n *layer) GetBucketSettings(ctx context.Context, bktInfo *data.BucketInfo)
 	return settings, nil
 }
 
-func (n *layer) PutBucketSettings(ctx context.Context, p *PutSettingsParams) error {
+func (n *layer) PutBucketSettings(ctx context.Context, p *PutSettingsParams) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "layer.PutBucketSettings")
+	defer func() { tracing.End(span, err) }()
+
 	if err := n.treeService.PutSettingsNode(ctx, p.BktInfo, p.Settings); err != nil {
 		return fmt.Errorf("failed to get settings node: %w", err)
 	}
 
 	n.cache.PutSettings(n.Owner(ctx), p.BktInfo, p.Settings)
+	n.invalidate(InvalidateSettings, p.BktInfo, "", "")
 
 	return nil
 }
@@ -3,10 +3,12 @@ package layer
 import (
 	"context"
 	errorsStd "errors"
+	"time"
 
 	"github.com/TrueCloudLab/frostfs-s3-gw/api"
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/errors"
+	"github.com/TrueCloudLab/frostfs-s3-gw/internal/tracing"
 	cid "github.com/TrueCloudLab/frostfs-sdk-go/container/id"
 	oid "github.com/TrueCloudLab/frostfs-sdk-go/object/id"
 	"github.com/TrueCloudLab/frostfs-sdk-go/user"
@@ -28,8 +30,10 @@ type PutObjectTaggingParams struct {
 	NodeVersion *data.NodeVersion // optional
 }
 
-func (n *layer) GetObjectTagging(ctx context.Context, p *GetObjectTaggingParams) (string, map[string]string, error) {
-	var err error
+func (n *layer) GetObjectTagging(ctx context.Context, p *GetObjectTaggingParams) (_ string, _ map[string]string, err error) {
+	ctx, span := tracing.StartSpan(ctx, "layer.GetObjectTagging")
+	defer func() { tracing.End(span, err) }()
+
 	owner := n.Owner(ctx)
 
 	if len(p.ObjectVersion.VersionID) != 0 && p.ObjectVersion.VersionID != data.UnversionedObjectVersionID {
@@ -65,6 +69,9 @@ func (n *layer) GetObjectTagging(ctx context.Context, p *GetObjectTaggingParams)
 }
 
 func (n *layer) PutObjectTagging(ctx context.Context, p *PutObjectTaggingParams) (nodeVersion *data.NodeVersion, err error) {
+	ctx, span := tracing.StartSpan(ctx, "layer.PutObjectTagging")
+	defer func() { tracing.End(span, err) }()
+
 	nodeVersion = p.NodeVersion
 	if nodeVersion == nil {
 		nodeVersion, err = n.getNodeVersionFromCacheOrFrostfs(ctx, p.ObjectVersion)
@@ -83,11 +90,15 @@ func (n *layer) PutObjectTagging(ctx context.Context, p *PutObjectTaggingParams)
 	}
 
 	n.cache.PutTagging(n.Owner(ctx), objectTaggingCacheKey(p.ObjectVersion), p.TagSet)
+	n.invalidate(InvalidateTagging, p.ObjectVersion.BktInfo, p.ObjectVersion.ObjectName, p.ObjectVersion.VersionID)
 
 	return nodeVersion, nil
 }
 
-func (n *layer) DeleteObjectTagging(ctx context.Context, p *ObjectVersion) (*data.NodeVersion, error) {
+func (n *layer) DeleteObjectTagging(ctx context.Context, p *ObjectVersion) (_ *data.NodeVersion, err error) {
+	ctx, span := tracing.StartSpan(ctx, "layer.DeleteObjectTagging")
+	defer func() { tracing.End(span, err) }()
+
 	version, err := n.getNodeVersion(ctx, p)
 	if err != nil {
 		return nil, err
@@ -104,11 +115,15 @@ func (n *layer) DeleteObjectTagging(ctx context.Context, p *ObjectVersion) (*dat
 	p.VersionID = version.OID.EncodeToString()
 
 	n.cache.DeleteTagging(objectTaggingCacheKey(p))
+	n.invalidate(InvalidateTagging, p.BktInfo, p.ObjectName, p.VersionID)
 
 	return version, nil
 }
 
-func (n *layer) GetBucketTagging(ctx context.Context, bktInfo *data.BucketInfo) (map[string]string, error) {
+func (n *layer) GetBucketTagging(ctx context.Context, bktInfo *data.BucketInfo) (_ map[string]string, err error) {
+	ctx, span := tracing.StartSpan(ctx, "layer.GetBucketTagging")
+	defer func() { tracing.End(span, err) }()
+
 	owner := n.Owner(ctx)
 
 	if tags := n.cache.GetTagging(owner, bucketTaggingCacheKey(bktInfo.CID)); tags != nil {
@@ -125,18 +140,26 @@ func (n *layer) GetBucketTagging(ctx context.Context, bktInfo *data.BucketInfo)
 	return tags, nil
 }
 
-func (n *layer) PutBucketTagging(ctx context.Context, bktInfo *data.BucketInfo, tagSet map[string]string) error {
+func (n *layer) PutBucketTagging(ctx context.Context, bktInfo *data.BucketInfo, tagSet map[string]string) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "layer.PutBucketTagging")
+	defer func() { tracing.End(span, err) }()
+
 	if err := n.treeService.PutBucketTagging(ctx, bktInfo, tagSet); err != nil {
 		return err
 	}
 
 	n.cache.PutTagging(n.Owner(ctx), bucketTaggingCacheKey(bktInfo.CID), tagSet)
+	n.invalidate(InvalidateTagging, bktInfo, "", "")
 
 	return nil
 }
 
-func (n *layer) DeleteBucketTagging(ctx context.Context, bktInfo *data.BucketInfo) error {
+func (n *layer) DeleteBucketTagging(ctx context.Context, bktInfo *data.BucketInfo) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "layer.DeleteBucketTagging")
+	defer func() { tracing.End(span, err) }()
+
 	n.cache.DeleteTagging(bucketTaggingCacheKey(bktInfo.CID))
+	n.invalidate(InvalidateTagging, bktInfo, "", "")
 
 	return n.treeService.DeleteBucketTagging(ctx, bktInfo)
 }
@@ -150,6 +173,8 @@ func bucketTaggingCacheKey(cnrID cid.ID) string {
 }
 
 func (n *layer) getNodeVersion(ctx context.Context, objVersion *ObjectVersion) (*data.NodeVersion, error) {
+	defer n.observeOperation(operationTreeLookup, time.Now())
+
 	var err error
 	var version *data.NodeVersion
 
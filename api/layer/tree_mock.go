@@ -12,12 +12,16 @@ import (
 
 type TreeServiceMock struct {
 	settings   map[string]*data.BucketSettings
+	usage      map[string]*data.BucketUsage
 	versions   map[string]map[string][]*data.NodeVersion
 	system     map[string]map[string]*data.BaseNodeVersion
 	locks      map[string]map[uint64]*data.LockInfo
 	tags       map[string]map[uint64]map[string]string
 	multiparts map[string]map[string][]*data.MultipartInfo
 	parts      map[string]map[int]*data.PartInfo
+	references map[string]map[uint64]oid.ID
+	// lastVersionID makes ids of version nodes unique like in the real tree.
+	lastVersionID uint64
 }
 
 func (t *TreeServiceMock) GetObjectTaggingAndLock(ctx context.Context, bktInfo *data.BucketInfo, objVersion *data.NodeVersion) (map[string]string, *data.LockInfo, error) {
@@ -26,6 +30,35 @@ func (t *TreeServiceMock) GetObjectTaggingAndLock(ctx context.Context, bktInfo *
 	return nil, lock, err
 }
 
+func (t *TreeServiceMock) AddObjectReference(_ context.Context, bktInfo *data.BucketInfo, objID oid.ID) error {
+	cnrReferences, ok := t.references[bktInfo.CID.EncodeToString()]
+	if !ok {
+		cnrReferences = make(map[uint64]oid.ID)
+		t.references[bktInfo.CID.EncodeToString()] = cnrReferences
+	}
+
+	t.lastVersionID++
+	cnrReferences[t.lastVersionID] = objID
+
+	return nil
+}
+
+func (t *TreeServiceMock) GetObjectReferences(_ context.Context, bktInfo *data.BucketInfo, objID oid.ID) ([]uint64, error) {
+	var result []uint64
+	for nodeID, id := range t.references[bktInfo.CID.EncodeToString()] {
+		if id.Equals(objID) {
+			result = append(result, nodeID)
+		}
+	}
+
+	return result, nil
+}
+
+func (t *TreeServiceMock) RemoveObjectReference(_ context.Context, bktInfo *data.BucketInfo, nodeID uint64) error {
+	delete(t.references[bktInfo.CID.EncodeToString()], nodeID)
+	return nil
+}
+
 func (t *TreeServiceMock) GetObjectTagging(_ context.Context, bktInfo *data.BucketInfo, nodeVersion *data.NodeVersion) (map[string]string, error) {
 	cnrTagsMap, ok := t.tags[bktInfo.CID.EncodeToString()]
 	if !ok {
@@ -77,12 +110,14 @@ func (t *TreeServiceMock) DeleteBucketTagging(ctx context.Context, bktInfo *data
 func NewTreeService() *TreeServiceMock {
 	return &TreeServiceMock{
 		settings:   make(map[string]*data.BucketSettings),
+		usage:      make(map[string]*data.BucketUsage),
 		versions:   make(map[string]map[string][]*data.NodeVersion),
 		system:     make(map[string]map[string]*data.BaseNodeVersion),
 		locks:      make(map[string]map[uint64]*data.LockInfo),
 		tags:       make(map[string]map[uint64]map[string]string),
 		multiparts: make(map[string]map[string][]*data.MultipartInfo),
 		parts:      make(map[string]map[int]*data.PartInfo),
+		references: make(map[string]map[uint64]oid.ID),
 	}
 }
 
@@ -100,16 +135,32 @@ func (t *TreeServiceMock) GetSettingsNode(_ context.Context, bktInfo *data.Bucke
 	return settings, nil
 }
 
-func (t *TreeServiceMock) GetNotificationConfigurationNode(ctx context.Context, bktInfo *data.BucketInfo) (oid.ID, error) {
-	panic("implement me")
+func (t *TreeServiceMock) PutBucketUsage(_ context.Context, bktInfo *data.BucketInfo, usage *data.BucketUsage) error {
+	usageCopy := *usage
+	t.usage[bktInfo.CID.EncodeToString()] = &usageCopy
+	return nil
+}
+
+func (t *TreeServiceMock) GetBucketUsage(_ context.Context, bktInfo *data.BucketInfo) (*data.BucketUsage, error) {
+	usage, ok := t.usage[bktInfo.CID.EncodeToString()]
+	if !ok {
+		return nil, ErrNodeNotFound
+	}
+
+	usageCopy := *usage
+	return &usageCopy, nil
+}
+
+func (t *TreeServiceMock) GetNotificationConfigurationNode(context.Context, *data.BucketInfo) (oid.ID, error) {
+	return oid.ID{}, ErrNodeNotFound
 }
 
 func (t *TreeServiceMock) PutNotificationConfigurationNode(ctx context.Context, bktInfo *data.BucketInfo, objID oid.ID) (oid.ID, error) {
 	panic("implement me")
 }
 
-func (t *TreeServiceMock) GetBucketCORS(ctx context.Context, bktInfo *data.BucketInfo) (oid.ID, error) {
-	panic("implement me")
+func (t *TreeServiceMock) GetBucketCORS(context.Context, *data.BucketInfo) (oid.ID, error) {
+	return oid.ID{}, ErrNodeNotFound
 }
 
 func (t *TreeServiceMock) PutBucketCORS(ctx context.Context, bktInfo *data.BucketInfo, objID oid.ID) (oid.ID, error) {
@@ -145,9 +196,7 @@ func (t *TreeServiceMock) GetLatestVersion(_ context.Context, bktInfo *data.Buck
 		return nil, ErrNodeNotFound
 	}
 
-	sort.Slice(versions, func(i, j int) bool {
-		return versions[i].ID < versions[j].ID
-	})
+	sortVersions(versions)
 
 	if len(versions) != 0 {
 		return versions[len(versions)-1], nil
@@ -156,29 +205,52 @@ func (t *TreeServiceMock) GetLatestVersion(_ context.Context, bktInfo *data.Buck
 	return nil, ErrNodeNotFound
 }
 
-func (t *TreeServiceMock) GetLatestVersionsByPrefix(_ context.Context, bktInfo *data.BucketInfo, prefix string) ([]*data.NodeVersion, error) {
-	cnrVersionsMap, ok := t.versions[bktInfo.CID.EncodeToString()]
-	if !ok {
-		return nil, ErrNodeNotFound
+func (t *TreeServiceMock) GetDirectory(_ context.Context, bktInfo *data.BucketInfo, path string) (*data.TreeDirectory, error) {
+	if path == "" {
+		return &data.TreeDirectory{}, nil
 	}
 
-	var result []*data.NodeVersion
+	dirPath := path + "/"
+	for key := range t.versions[bktInfo.CID.EncodeToString()] {
+		if strings.HasPrefix(key, dirPath) {
+			return &data.TreeDirectory{Path: dirPath}, nil
+		}
+	}
 
-	for key, versions := range cnrVersionsMap {
-		if !strings.HasPrefix(key, prefix) {
+	return nil, ErrNodeNotFound
+}
+
+func (t *TreeServiceMock) GetDirectoryChildren(_ context.Context, bktInfo *data.BucketInfo, dir *data.TreeDirectory, latestOnly bool) ([]*data.NodeVersion, []*data.TreeDirectory, error) {
+	var (
+		objects []*data.NodeVersion
+		dirs    []*data.TreeDirectory
+		existed = make(map[string]struct{})
+	)
+
+	for key, versions := range t.versions[bktInfo.CID.EncodeToString()] {
+		if !strings.HasPrefix(key, dir.Path) || len(versions) == 0 {
 			continue
 		}
 
-		sort.Slice(versions, func(i, j int) bool {
-			return versions[i].ID < versions[j].ID
-		})
+		if name, _, isDir := strings.Cut(key[len(dir.Path):], "/"); isDir {
+			dirPath := dir.Path + name + "/"
+			if _, ok := existed[dirPath]; !ok {
+				existed[dirPath] = struct{}{}
+				dirs = append(dirs, &data.TreeDirectory{Path: dirPath})
+			}
+			continue
+		}
 
-		if len(versions) != 0 {
-			result = append(result, versions[len(versions)-1])
+		if !latestOnly {
+			objects = append(objects, versions...)
+			continue
 		}
+
+		sortVersions(versions)
+		objects = append(objects, versions[len(versions)-1])
 	}
 
-	return result, nil
+	return objects, dirs, nil
 }
 
 func (t *TreeServiceMock) GetUnversioned(_ context.Context, bktInfo *data.BucketInfo, objectName string) (*data.NodeVersion, error) {
@@ -202,6 +274,9 @@ func (t *TreeServiceMock) GetUnversioned(_ context.Context, bktInfo *data.Bucket
 }
 
 func (t *TreeServiceMock) AddVersion(_ context.Context, bktInfo *data.BucketInfo, newVersion *data.NodeVersion) (uint64, error) {
+	t.lastVersionID++
+	newVersion.ID = t.lastVersionID
+
 	cnrVersionsMap, ok := t.versions[bktInfo.CID.EncodeToString()]
 	if !ok {
 		t.versions[bktInfo.CID.EncodeToString()] = map[string][]*data.NodeVersion{
@@ -216,12 +291,9 @@ func (t *TreeServiceMock) AddVersion(_ context.Context, bktInfo *data.BucketInfo
 		return newVersion.ID, nil
 	}
 
-	sort.Slice(versions, func(i, j int) bool {
-		return versions[i].ID < versions[j].ID
-	})
+	sortVersions(versions)
 
 	if len(versions) != 0 {
-		newVersion.ID = versions[len(versions)-1].ID + 1
 		newVersion.Timestamp = versions[len(versions)-1].Timestamp + 1
 	}
 
@@ -241,6 +313,43 @@ func (t *TreeServiceMock) AddVersion(_ context.Context, bktInfo *data.BucketInfo
 	return newVersion.ID, nil
 }
 
+func (t *TreeServiceMock) AddVersions(ctx context.Context, bktInfo *data.BucketInfo, versions []*data.NodeVersion) ([]*data.NodeVersion, error) {
+	var replaced []*data.NodeVersion
+	for _, version := range versions {
+		if version.IsUnversioned {
+			if node, err := t.GetUnversioned(ctx, bktInfo, version.FilePath); err == nil {
+				replaced = append(replaced, node)
+			}
+		}
+		if _, err := t.AddVersion(ctx, bktInfo, version); err != nil {
+			return replaced, err
+		}
+	}
+
+	return replaced, nil
+}
+
+func (t *TreeServiceMock) UpdateVersion(_ context.Context, bktInfo *data.BucketInfo, version *data.NodeVersion) error {
+	versions := t.versions[bktInfo.CID.EncodeToString()][version.FilePath]
+	for i, node := range versions {
+		if node.ID != version.ID {
+			continue
+		}
+
+		// updated node becomes the latest one
+		updated := *version
+		for _, other := range versions {
+			if other.Timestamp >= updated.Timestamp {
+				updated.Timestamp = other.Timestamp + 1
+			}
+		}
+		versions[i] = &updated
+		return nil
+	}
+
+	return ErrNodeNotFound
+}
+
 func (t *TreeServiceMock) RemoveVersion(_ context.Context, bktInfo *data.BucketInfo, nodeID uint64) error {
 	cnrVersionsMap, ok := t.versions[bktInfo.CID.EncodeToString()]
 	if !ok {
@@ -259,6 +368,72 @@ func (t *TreeServiceMock) RemoveVersion(_ context.Context, bktInfo *data.BucketI
 	return ErrNodeNotFound
 }
 
+func (t *TreeServiceMock) MoveVersions(_ context.Context, bktInfo *data.BucketInfo, versions []*data.NodeVersion, newName string) error {
+	cnrVersionsMap, ok := t.versions[bktInfo.CID.EncodeToString()]
+	if !ok {
+		return ErrNodeNotFound
+	}
+
+	for _, version := range versions {
+		srcVersions := cnrVersionsMap[version.FilePath]
+		index := -1
+		for i, node := range srcVersions {
+			if node.ID == version.ID {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			return ErrNodeNotFound
+		}
+
+		// new slice is allocated since the caller can hold the result of GetVersions
+		node := srcVersions[index]
+		cnrVersionsMap[node.FilePath] = append(append([]*data.NodeVersion{}, srcVersions[:index]...), srcVersions[index+1:]...)
+		if len(cnrVersionsMap[node.FilePath]) == 0 {
+			delete(cnrVersionsMap, node.FilePath)
+		}
+
+		// moved node becomes the latest one
+		dstVersions := cnrVersionsMap[newName]
+		node.Timestamp = 0
+		for _, dstNode := range dstVersions {
+			if dstNode.Timestamp >= node.Timestamp {
+				node.Timestamp = dstNode.Timestamp + 1
+			}
+		}
+		node.FilePath = newName
+		cnrVersionsMap[newName] = append(dstVersions, node)
+	}
+
+	return nil
+}
+
+func (t *TreeServiceMock) MoveDirectory(_ context.Context, bktInfo *data.BucketInfo, dir *data.TreeDirectory, newPath string) error {
+	cnrVersionsMap, ok := t.versions[bktInfo.CID.EncodeToString()]
+	if !ok {
+		return ErrNodeNotFound
+	}
+
+	moved := make(map[string][]*data.NodeVersion)
+	for name, versions := range cnrVersionsMap {
+		if strings.HasPrefix(name, dir.Path) {
+			moved[name] = versions
+			delete(cnrVersionsMap, name)
+		}
+	}
+
+	for name, versions := range moved {
+		newName := newPath + "/" + name[len(dir.Path):]
+		for _, node := range versions {
+			node.FilePath = newName
+		}
+		cnrVersionsMap[newName] = versions
+	}
+
+	return nil
+}
+
 func (t *TreeServiceMock) GetAllVersionsByPrefix(_ context.Context, bktInfo *data.BucketInfo, prefix string) ([]*data.NodeVersion, error) {
 	cnrVersionsMap, ok := t.versions[bktInfo.CID.EncodeToString()]
 	if !ok {
@@ -293,8 +468,15 @@ func (t *TreeServiceMock) CreateMultipartUpload(_ context.Context, bktInfo *data
 	return nil
 }
 
-func (t *TreeServiceMock) GetMultipartUploadsByPrefix(ctx context.Context, bktInfo *data.BucketInfo, prefix string) ([]*data.MultipartInfo, error) {
-	panic("implement me")
+func (t *TreeServiceMock) GetMultipartUploadsByPrefix(_ context.Context, bktInfo *data.BucketInfo, prefix string) ([]*data.MultipartInfo, error) {
+	var result []*data.MultipartInfo
+	for key, multiparts := range t.multiparts[bktInfo.CID.EncodeToString()] {
+		if strings.HasPrefix(key, prefix) {
+			result = append(result, multiparts...)
+		}
+	}
+
+	return result, nil
 }
 
 func (t *TreeServiceMock) GetMultipartUpload(_ context.Context, bktInfo *data.BucketInfo, objectName, uploadID string) (*data.MultipartInfo, error) {
@@ -313,14 +495,14 @@ func (t *TreeServiceMock) GetMultipartUpload(_ context.Context, bktInfo *data.Bu
 	return nil, ErrNodeNotFound
 }
 
-func (t *TreeServiceMock) AddPart(ctx context.Context, bktInfo *data.BucketInfo, multipartNodeID uint64, info *data.PartInfo) (oldObjIDToDelete oid.ID, err error) {
+func (t *TreeServiceMock) AddPart(ctx context.Context, bktInfo *data.BucketInfo, multipartNodeID uint64, info *data.PartInfo) (oldPartToDelete *data.PartInfo, err error) {
 	multipartInfo, err := t.GetMultipartUpload(ctx, bktInfo, info.Key, info.UploadID)
 	if err != nil {
-		return oid.ID{}, err
+		return nil, err
 	}
 
 	if multipartInfo.ID != multipartNodeID {
-		return oid.ID{}, fmt.Errorf("invalid multipart info id")
+		return nil, fmt.Errorf("invalid multipart info id")
 	}
 
 	partsMap, ok := t.parts[info.UploadID]
@@ -328,10 +510,14 @@ func (t *TreeServiceMock) AddPart(ctx context.Context, bktInfo *data.BucketInfo,
 		partsMap = make(map[int]*data.PartInfo)
 	}
 
+	oldPartToDelete, ok = partsMap[info.Number]
 	partsMap[info.Number] = info
 
 	t.parts[info.UploadID] = partsMap
-	return oid.ID{}, nil
+	if !ok {
+		return nil, ErrNoNodeToRemove
+	}
+	return oldPartToDelete, nil
 }
 
 func (t *TreeServiceMock) GetParts(_ context.Context, bktInfo *data.BucketInfo, multipartNodeID uint64) ([]*data.PartInfo, error) {
@@ -408,3 +594,13 @@ func (t *TreeServiceMock) GetLock(ctx context.Context, bktInfo *data.BucketInfo,
 
 	return cnrLockMap[nodeID], nil
 }
+
+// sortVersions orders versions from the oldest to the latest one like the tree service does.
+func sortVersions(versions []*data.NodeVersion) {
+	sort.Slice(versions, func(i, j int) bool {
+		if versions[i].Timestamp == versions[j].Timestamp {
+			return versions[i].ID < versions[j].ID
+		}
+		return versions[i].Timestamp < versions[j].Timestamp
+	})
+}
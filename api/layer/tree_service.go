@@ -18,6 +18,14 @@ type TreeService interface {
 	// If tree node is not found returns ErrNodeNotFound error.
 	GetSettingsNode(ctx context.Context, bktInfo *data.BucketInfo) (*data.BucketSettings, error)
 
+	// PutBucketUsage update or create new usage node in tree service.
+	PutBucketUsage(ctx context.Context, bktInfo *data.BucketInfo, usage *data.BucketUsage) error
+
+	// GetBucketUsage retrieves the usage node from the tree service and form data.BucketUsage.
+	//
+	// If tree node is not found returns ErrNodeNotFound error.
+	GetBucketUsage(ctx context.Context, bktInfo *data.BucketInfo) (*data.BucketUsage, error)
+
 	// GetNotificationConfigurationNode gets an object id that corresponds to object with bucket CORS.
 	//
 	// If tree node is not found returns ErrNodeNotFound error.
@@ -44,6 +52,16 @@ type TreeService interface {
 	// If object id to remove is not found returns ErrNoNodeToRemove error.
 	DeleteBucketCORS(ctx context.Context, bktInfo *data.BucketInfo) (oid.ID, error)
 
+	// AddObjectReference registers one more version which references the payload object,
+	// so the object isn't deleted with the first of such versions.
+	AddObjectReference(ctx context.Context, bktInfo *data.BucketInfo, objID oid.ID) error
+
+	// GetObjectReferences returns ids of nodes registered by AddObjectReference for the object.
+	GetObjectReferences(ctx context.Context, bktInfo *data.BucketInfo, objID oid.ID) ([]uint64, error)
+
+	// RemoveObjectReference removes the node registered by AddObjectReference.
+	RemoveObjectReference(ctx context.Context, bktInfo *data.BucketInfo, nodeID uint64) error
+
 	GetObjectTagging(ctx context.Context, bktInfo *data.BucketInfo, objVersion *data.NodeVersion) (map[string]string, error)
 	PutObjectTagging(ctx context.Context, bktInfo *data.BucketInfo, objVersion *data.NodeVersion, tagSet map[string]string) error
 	DeleteObjectTagging(ctx context.Context, bktInfo *data.BucketInfo, objVersion *data.NodeVersion) error
@@ -54,12 +72,38 @@ type TreeService interface {
 
 	GetVersions(ctx context.Context, bktInfo *data.BucketInfo, objectName string) ([]*data.NodeVersion, error)
 	GetLatestVersion(ctx context.Context, bktInfo *data.BucketInfo, objectName string) (*data.NodeVersion, error)
-	GetLatestVersionsByPrefix(ctx context.Context, bktInfo *data.BucketInfo, prefix string) ([]*data.NodeVersion, error)
+
+	// GetDirectory returns the directory of the version tree with the provided path (without trailing separator).
+	// Empty path corresponds to the root directory.
+	//
+	// If directory is not found returns ErrNodeNotFound error.
+	GetDirectory(ctx context.Context, bktInfo *data.BucketInfo, path string) (*data.TreeDirectory, error)
+
+	// GetDirectoryChildren returns versions (including delete markers) of objects and subdirectories
+	// which are direct children of the directory. If latestOnly is set only the latest version
+	// of every object is returned. Results are not sorted.
+	GetDirectoryChildren(ctx context.Context, bktInfo *data.BucketInfo, dir *data.TreeDirectory, latestOnly bool) ([]*data.NodeVersion, []*data.TreeDirectory, error)
+
 	GetAllVersionsByPrefix(ctx context.Context, bktInfo *data.BucketInfo, prefix string) ([]*data.NodeVersion, error)
 	GetUnversioned(ctx context.Context, bktInfo *data.BucketInfo, objectName string) (*data.NodeVersion, error)
 	AddVersion(ctx context.Context, bktInfo *data.BucketInfo, newVersion *data.NodeVersion) (uint64, error)
+	// AddVersions adds versions of many objects at once, IDs of the added nodes are set to the versions.
+	// Parent directories are looked up once per directory, unversioned versions to replace are looked up by names.
+	// Returns unversioned versions replaced by the new ones. On error versions which aren't added have zero ID.
+	AddVersions(ctx context.Context, bktInfo *data.BucketInfo, versions []*data.NodeVersion) ([]*data.NodeVersion, error)
+	// UpdateVersion rewrites attributes of the existing version node (identified by ID) with the provided ones.
+	UpdateVersion(ctx context.Context, bktInfo *data.BucketInfo, version *data.NodeVersion) error
 	RemoveVersion(ctx context.Context, bktInfo *data.BucketInfo, nodeID uint64) error
 
+	// MoveVersions relinks version nodes of the object (with their tagging and lock nodes) to the object
+	// with the new name. Nodes are moved in the provided order, so the last one becomes the latest version.
+	// Missing parent directories of the new name are created.
+	MoveVersions(ctx context.Context, bktInfo *data.BucketInfo, versions []*data.NodeVersion, newName string) error
+
+	// MoveDirectory relinks the directory node with all its descendants to the new path (without trailing separator)
+	// by a single tree operation. Missing parent directories of the new path are created.
+	MoveDirectory(ctx context.Context, bktInfo *data.BucketInfo, dir *data.TreeDirectory, newPath string) error
+
 	PutLock(ctx context.Context, bktInfo *data.BucketInfo, nodeID uint64, lock *data.LockInfo) error
 	GetLock(ctx context.Context, bktInfo *data.BucketInfo, nodeID uint64) (*data.LockInfo, error)
 
@@ -69,10 +113,10 @@ type TreeService interface {
 	GetMultipartUpload(ctx context.Context, bktInfo *data.BucketInfo, objectName, uploadID string) (*data.MultipartInfo, error)
 
 	// AddPart puts a node to a system tree as a child of appropriate multipart upload
-	// and returns objectID of a previous part which must be deleted in FrostFS.
+	// and returns info of a replaced part which object must be deleted in FrostFS.
 	//
-	// If object id to remove is not found returns ErrNoNodeToRemove error.
-	AddPart(ctx context.Context, bktInfo *data.BucketInfo, multipartNodeID uint64, info *data.PartInfo) (oldObjIDToDelete oid.ID, err error)
+	// If the replaced part is not found returns ErrNoNodeToRemove error.
+	AddPart(ctx context.Context, bktInfo *data.BucketInfo, multipartNodeID uint64, info *data.PartInfo) (oldPartToDelete *data.PartInfo, err error)
 	GetParts(ctx context.Context, bktInfo *data.BucketInfo, multipartNodeID uint64) ([]*data.PartInfo, error)
 
 	// Compound methods for optimizations
@@ -0,0 +1,200 @@
+package layer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/TrueCloudLab/frostfs-s3-gw/api"
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/errors"
+	"github.com/TrueCloudLab/frostfs-s3-gw/internal/tracing"
+	"go.uber.org/zap"
+)
+
+type (
+	// CreateTusUploadParams stores parameters of the resumable upload (tus protocol) creation.
+	CreateTusUploadParams struct {
+		Info *UploadInfoParams
+		// Length is the total size of the uploaded object.
+		Length int64
+		// Metadata is the Upload-Metadata header of the request, it's returned with the upload state.
+		Metadata string
+		// Header is the metadata of the uploaded object.
+		Header       map[string]string
+		CopiesNumber uint32
+	}
+
+	// PatchTusUploadParams stores parameters of the chunk written to the resumable upload.
+	PatchTusUploadParams struct {
+		Info *UploadInfoParams
+		// Offset must be equal to the current offset of the upload.
+		Offset int64
+		Size   int64
+		Reader io.Reader
+	}
+
+	// TusUpload is the state of the resumable upload.
+	TusUpload struct {
+		Length   int64
+		Offset   int64
+		Metadata string
+		Created  time.Time
+	}
+)
+
+// Keys of the multipart upload meta which store the state of the resumable upload.
+// They have no prefix, so they aren't moved to the uploaded object.
+const (
+	tusLengthKey   = "tus-length"
+	tusMetadataKey = "tus-metadata"
+)
+
+// CreateTusUpload starts the resumable upload backed by the multipart upload. Chunks of the upload
+// are stored as parts, the object is completed when the whole payload is received.
+// The object of the empty upload is completed at once and returned.
+func (n *layer) CreateTusUpload(ctx context.Context, p *CreateTusUploadParams) (_ *data.ExtendedObjectInfo, err error) {
+	ctx, span := tracing.StartSpan(ctx, "layer.CreateTusUpload")
+	defer func() { tracing.End(span, err) }()
+
+	info, err := n.newMultipartInfo(ctx, &CreateMultipartParams{
+		Info:         p.Info,
+		Header:       p.Header,
+		CopiesNumber: p.CopiesNumber,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	info.Meta[tusLengthKey] = strconv.FormatInt(p.Length, 10)
+	if len(p.Metadata) != 0 {
+		info.Meta[tusMetadataKey] = p.Metadata
+	}
+
+	if err = n.treeService.CreateMultipartUpload(ctx, p.Info.Bkt, info); err != nil {
+		return nil, err
+	}
+
+	if p.Length == 0 {
+		return n.completeTusUpload(ctx, p.Info)
+	}
+
+	return nil, nil
+}
+
+// GetTusUpload returns the state of the resumable upload.
+func (n *layer) GetTusUpload(ctx context.Context, p *UploadInfoParams) (_ *TusUpload, err error) {
+	ctx, span := tracing.StartSpan(ctx, "layer.GetTusUpload")
+	defer func() { tracing.End(span, err) }()
+
+	multipartInfo, parts, err := n.getUploadParts(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	return tusUploadFromMultipart(multipartInfo, parts)
+}
+
+// PatchTusUpload writes the chunk to the resumable upload as the next part. When the whole payload
+// is received, the object is completed and returned.
+func (n *layer) PatchTusUpload(ctx context.Context, p *PatchTusUploadParams) (_ *TusUpload, _ *data.ExtendedObjectInfo, err error) {
+	ctx, span := tracing.StartSpan(ctx, "layer.PatchTusUpload")
+	defer func() { tracing.End(span, err) }()
+
+	unlock := n.keyLocks.lock(p.Info.Bkt.CID, p.Info.UploadID)
+	defer unlock()
+
+	multipartInfo, parts, err := n.getUploadParts(ctx, p.Info)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	upload, err := tusUploadFromMultipart(multipartInfo, parts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if p.Offset != upload.Offset {
+		return nil, nil, errors.GetAPIError(errors.ErrUploadOffsetMismatch)
+	}
+	if p.Size > uploadMaxSize || upload.Offset+p.Size > upload.Length || len(parts) >= UploadMaxPartNumber {
+		return nil, nil, errors.GetAPIError(errors.ErrEntityTooLarge)
+	}
+
+	if p.Size != 0 {
+		if _, err = n.uploadPart(ctx, multipartInfo, &UploadPartParams{
+			Info:       p.Info,
+			PartNumber: len(parts) + 1,
+			Size:       p.Size,
+			Reader:     p.Reader,
+		}); err != nil {
+			return nil, nil, err
+		}
+		upload.Offset += p.Size
+	}
+
+	if upload.Offset < upload.Length {
+		return upload, nil, nil
+	}
+
+	extObjInfo, err := n.completeTusUpload(ctx, p.Info)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return upload, extObjInfo, nil
+}
+
+// completeTusUpload combines all parts of the resumable upload into the object.
+// Parts are written by chunks of the client, so there is no limit of the part size.
+func (n *layer) completeTusUpload(ctx context.Context, p *UploadInfoParams) (*data.ExtendedObjectInfo, error) {
+	_, parts, err := n.getUploadParts(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	completed := make([]*CompletedPart, 0, len(parts))
+	for _, part := range parts {
+		completed = append(completed, &CompletedPart{ETag: part.ETag, PartNumber: part.Number})
+	}
+	sort.Slice(completed, func(i, j int) bool {
+		return completed[i].PartNumber < completed[j].PartNumber
+	})
+
+	_, extObjInfo, err := n.completeMultipartUpload(ctx, &CompleteMultipartParams{Info: p, Parts: completed}, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	reqInfo := api.GetReqInfo(ctx)
+	n.log.Debug("tus upload is completed",
+		zap.String("reqId", reqInfo.RequestID),
+		zap.String("bucket", p.Bkt.Name), zap.Stringer("cid", p.Bkt.CID),
+		zap.String("object", p.Key), zap.String("upload id", p.UploadID),
+		zap.Int("parts", len(completed)))
+
+	return extObjInfo, nil
+}
+
+func tusUploadFromMultipart(multipartInfo *data.MultipartInfo, parts map[int]*data.PartInfo) (*TusUpload, error) {
+	lengthStr, ok := multipartInfo.Meta[tusLengthKey]
+	if !ok {
+		// regular multipart uploads can't be continued by the tus protocol
+		return nil, errors.GetAPIError(errors.ErrNoSuchUpload)
+	}
+
+	length, err := strconv.ParseInt(lengthStr, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid upload length '%s': %w", lengthStr, err)
+	}
+
+	return &TusUpload{
+		Length:   length,
+		Offset:   partsMapSize(parts),
+		Metadata: multipartInfo.Meta[tusMetadataKey],
+		Created:  multipartInfo.Created,
+	}, nil
+}
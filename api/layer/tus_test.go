@@ -0,0 +1,100 @@
+package layer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/TrueCloudLab/frostfs-s3-gw/api"
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
+	apiErrors "github.com/TrueCloudLab/frostfs-s3-gw/api/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func (tc *testContext) patchTusUpload(info *UploadInfoParams, offset int64, content string) (*TusUpload, *data.ExtendedObjectInfo, error) {
+	return tc.layer.PatchTusUpload(tc.ctx, &PatchTusUploadParams{
+		Info:   info,
+		Offset: offset,
+		Size:   int64(len(content)),
+		Reader: bytes.NewReader([]byte(content)),
+	})
+}
+
+func TestTusUpload(t *testing.T) {
+	tc := prepareContext(t)
+
+	info := &UploadInfoParams{UploadID: "upload", Bkt: tc.bktInfo, Key: tc.obj}
+	extObjInfo, err := tc.layer.CreateTusUpload(tc.ctx, &CreateTusUploadParams{
+		Info:     info,
+		Length:   6,
+		Metadata: "filename Zm9v",
+		Header:   map[string]string{api.ContentType: "text/plain", "filename": "foo"},
+	})
+	require.NoError(t, err)
+	require.Nil(t, extObjInfo)
+
+	upload, err := tc.layer.GetTusUpload(tc.ctx, info)
+	require.NoError(t, err)
+	require.EqualValues(t, 6, upload.Length)
+	require.Zero(t, upload.Offset)
+	require.Equal(t, "filename Zm9v", upload.Metadata)
+
+	upload, extObjInfo, err = tc.patchTusUpload(info, 0, "ab")
+	require.NoError(t, err)
+	require.Nil(t, extObjInfo)
+	require.EqualValues(t, 2, upload.Offset)
+
+	_, _, err = tc.patchTusUpload(info, 0, "cd")
+	require.True(t, apiErrors.IsS3Error(err, apiErrors.ErrUploadOffsetMismatch), err)
+	_, _, err = tc.patchTusUpload(info, 2, "cdefg")
+	require.True(t, apiErrors.IsS3Error(err, apiErrors.ErrEntityTooLarge), err)
+
+	// chunks are not limited by the minimal part size
+	upload, extObjInfo, err = tc.patchTusUpload(info, 2, "c")
+	require.NoError(t, err)
+	require.Nil(t, extObjInfo)
+
+	upload, extObjInfo, err = tc.patchTusUpload(info, upload.Offset, "def")
+	require.NoError(t, err)
+	require.NotNil(t, extObjInfo)
+	require.EqualValues(t, 6, upload.Offset)
+
+	objInfo, content := tc.getObject(tc.obj, "", false)
+	require.Equal(t, "abcdef", string(content))
+	require.Equal(t, "text/plain", objInfo.ContentType)
+	require.Equal(t, "foo", objInfo.Headers["filename"])
+	require.NotContains(t, objInfo.Headers, tusLengthKey)
+
+	_, err = tc.layer.GetTusUpload(tc.ctx, info)
+	require.True(t, apiErrors.IsS3Error(err, apiErrors.ErrNoSuchUpload), err)
+}
+
+func TestTusUploadEmpty(t *testing.T) {
+	tc := prepareContext(t)
+
+	extObjInfo, err := tc.layer.CreateTusUpload(tc.ctx, &CreateTusUploadParams{
+		Info:   &UploadInfoParams{UploadID: "upload", Bkt: tc.bktInfo, Key: tc.obj},
+		Header: make(map[string]string),
+	})
+	require.NoError(t, err)
+	require.NotNil(t, extObjInfo)
+	require.Zero(t, extObjInfo.ObjectInfo.Size)
+
+	_, content := tc.getObject(tc.obj, "", false)
+	require.Empty(t, content)
+}
+
+func TestTusUploadOfMultipart(t *testing.T) {
+	tc := prepareContext(t)
+
+	info := &UploadInfoParams{UploadID: "upload", Bkt: tc.bktInfo, Key: tc.obj}
+	err := tc.layer.CreateMultipartUpload(tc.ctx, &CreateMultipartParams{
+		Info:   info,
+		Header: make(map[string]string),
+		Data:   &UploadData{},
+	})
+	require.NoError(t, err)
+
+	// regular multipart uploads aren't available by the tus protocol
+	_, err = tc.layer.GetTusUpload(tc.ctx, info)
+	require.True(t, apiErrors.IsS3Error(err, apiErrors.ErrNoSuchUpload), err)
+}
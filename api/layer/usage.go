@@ -0,0 +1,362 @@
+package layer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
+	"github.com/TrueCloudLab/frostfs-s3-gw/internal/tracing"
+	cid "github.com/TrueCloudLab/frostfs-sdk-go/container/id"
+	"github.com/TrueCloudLab/frostfs-sdk-go/user"
+	"go.uber.org/zap"
+)
+
+type (
+	// usageRegistry keeps the last known usage of buckets and changes of usage
+	// which aren't stored in the tree service yet.
+	usageRegistry struct {
+		mu      sync.Mutex
+		buckets map[cid.ID]*bucketUsageEntry
+
+		// treeMu serializes updates of usage nodes in the tree service.
+		// It's never taken on the object request path.
+		treeMu sync.Mutex
+	}
+
+	bucketUsageEntry struct {
+		info *data.BucketInfo
+		// stored is the last usage read from or written to the tree service.
+		stored data.BucketUsage
+		// pending is the change of usage which isn't flushed to the tree service yet.
+		pending data.BucketUsage
+	}
+)
+
+func newUsageRegistry() *usageRegistry {
+	return &usageRegistry{buckets: make(map[cid.ID]*bucketUsageEntry)}
+}
+
+// entry must be called with locked mutex.
+func (r *usageRegistry) entry(bktInfo *data.BucketInfo) *bucketUsageEntry {
+	e, ok := r.buckets[bktInfo.CID]
+	if !ok {
+		e = &bucketUsageEntry{info: bktInfo}
+		r.buckets[bktInfo.CID] = e
+	}
+	return e
+}
+
+func (r *usageRegistry) add(bktInfo *data.BucketInfo, delta data.BucketUsage) {
+	r.mu.Lock()
+	r.entry(bktInfo).pending.Add(delta)
+	r.mu.Unlock()
+}
+
+func (r *usageRegistry) takePending(bktInfo *data.BucketInfo) data.BucketUsage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e := r.entry(bktInfo)
+	pending := e.pending
+	e.pending = data.BucketUsage{}
+	return pending
+}
+
+func (r *usageRegistry) setStored(bktInfo *data.BucketInfo, usage data.BucketUsage) {
+	r.mu.Lock()
+	r.entry(bktInfo).stored = usage
+	r.mu.Unlock()
+}
+
+func (r *usageRegistry) remove(cnrID cid.ID) {
+	r.mu.Lock()
+	delete(r.buckets, cnrID)
+	r.mu.Unlock()
+}
+
+// list returns buckets of the registry, only buckets with not flushed changes if pendingOnly is set.
+func (r *usageRegistry) list(pendingOnly bool) []*data.BucketInfo {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	res := make([]*data.BucketInfo, 0, len(r.buckets))
+	for _, e := range r.buckets {
+		if !pendingOnly || !e.pending.IsZero() {
+			res = append(res, e.info)
+		}
+	}
+	return res
+}
+
+// BucketsUsage returns the last known usage of buckets including changes which aren't flushed yet.
+// Buckets are known after they are served by the gateway or listed by LoadUsage and ReconcileUsage.
+func (n *layer) BucketsUsage() []*data.BucketUsageInfo {
+	n.usage.mu.Lock()
+	defer n.usage.mu.Unlock()
+
+	res := make([]*data.BucketUsageInfo, 0, len(n.usage.buckets))
+	for _, e := range n.usage.buckets {
+		usage := e.stored
+		usage.Add(e.pending)
+		res = append(res, &data.BucketUsageInfo{Bucket: e.info, Usage: usage})
+	}
+	return res
+}
+
+// GetBucketUsage flushes not stored changes of the bucket usage and returns usage stored in the tree service.
+// Usage is computed from the tree if it has never been stored.
+func (n *layer) GetBucketUsage(ctx context.Context, bktInfo *data.BucketInfo) (_ *data.BucketUsage, err error) {
+	ctx, span := tracing.StartSpan(ctx, "layer.GetBucketUsage")
+	defer func() { tracing.End(span, err) }()
+
+	n.usage.treeMu.Lock()
+	defer n.usage.treeMu.Unlock()
+
+	return n.flushBucketUsage(ctx, bktInfo)
+}
+
+// ReconcileBucketUsage recomputes usage of the bucket from all versions and multipart uploads in the tree service
+// and replaces the stored one. It fixes drift caused by concurrent or failed updates.
+func (n *layer) ReconcileBucketUsage(ctx context.Context, bktInfo *data.BucketInfo) (_ *data.BucketUsage, err error) {
+	ctx, span := tracing.StartSpan(ctx, "layer.ReconcileBucketUsage")
+	defer func() { tracing.End(span, err) }()
+
+	n.usage.treeMu.Lock()
+	defer n.usage.treeMu.Unlock()
+
+	return n.reconcileUsage(ctx, bktInfo)
+}
+
+// reconcileUsage must be called with locked tree mutex of the registry.
+func (n *layer) reconcileUsage(ctx context.Context, bktInfo *data.BucketInfo) (_ *data.BucketUsage, err error) {
+	// Changes made before the computation are in the tree already.
+	pending := n.usage.takePending(bktInfo)
+	defer func() {
+		if err != nil {
+			n.usage.add(bktInfo, pending)
+		}
+	}()
+
+	versions, err := n.treeService.GetAllVersionsByPrefix(ctx, bktInfo, "")
+	if err != nil && !errors.Is(err, ErrNodeNotFound) {
+		return nil, fmt.Errorf("get all versions: %w", err)
+	}
+	usage := data.UsageOfVersions(versions)
+
+	uploads, err := n.treeService.GetMultipartUploadsByPrefix(ctx, bktInfo, "")
+	if err != nil && !errors.Is(err, ErrNodeNotFound) {
+		return nil, fmt.Errorf("get multipart uploads: %w", err)
+	}
+	for _, upload := range uploads {
+		parts, err := n.treeService.GetParts(ctx, bktInfo, upload.ID)
+		if err != nil && !errors.Is(err, ErrNodeNotFound) {
+			return nil, fmt.Errorf("get parts of upload '%s': %w", upload.UploadID, err)
+		}
+		usage.MultipartBytes += partsSize(parts)
+	}
+
+	if stored, err := n.treeService.GetBucketUsage(ctx, bktInfo); err == nil && *stored != usage {
+		n.log.Info("bucket usage is reconciled", zap.String("bucket", bktInfo.Name),
+			zap.Stringer("cid", bktInfo.CID), zap.Any("stored", stored), zap.Any("actual", usage))
+	}
+
+	if err = n.treeService.PutBucketUsage(ctx, bktInfo, &usage); err != nil {
+		return nil, fmt.Errorf("put bucket usage: %w", err)
+	}
+	n.usage.setStored(bktInfo, usage)
+
+	return &usage, nil
+}
+
+// flushBucketUsage adds not stored changes to the usage in the tree service.
+// It must be called with locked tree mutex of the registry.
+func (n *layer) flushBucketUsage(ctx context.Context, bktInfo *data.BucketInfo) (_ *data.BucketUsage, err error) {
+	pending := n.usage.takePending(bktInfo)
+	defer func() {
+		if err != nil {
+			n.usage.add(bktInfo, pending)
+		}
+	}()
+
+	usage, err := n.treeService.GetBucketUsage(ctx, bktInfo)
+	if errors.Is(err, ErrNodeNotFound) {
+		// usage has never been stored, it's computed from the tree which has the changes already
+		n.usage.add(bktInfo, pending)
+		pending = data.BucketUsage{}
+		return n.reconcileUsage(ctx, bktInfo)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get bucket usage: %w", err)
+	}
+
+	if !pending.IsZero() {
+		usage.Add(pending)
+		if err = n.treeService.PutBucketUsage(ctx, bktInfo, usage); err != nil {
+			return nil, fmt.Errorf("put bucket usage: %w", err)
+		}
+	}
+	n.usage.setStored(bktInfo, *usage)
+
+	return usage, nil
+}
+
+// FlushUsage stores changes of usage of all buckets which aren't flushed yet.
+// Failed changes are kept to be flushed next time.
+func (n *layer) FlushUsage(ctx context.Context) {
+	n.usage.treeMu.Lock()
+	defer n.usage.treeMu.Unlock()
+
+	for _, bktInfo := range n.usage.list(true) {
+		if ctx.Err() != nil {
+			return
+		}
+		if _, err := n.flushBucketUsage(ctx, bktInfo); err != nil {
+			n.log.Warn("couldn't flush bucket usage", zap.String("bucket", bktInfo.Name),
+				zap.Stringer("cid", bktInfo.CID), zap.Error(err))
+		}
+	}
+}
+
+// LoadUsage reads the stored usage of all buckets, see usageBuckets.
+// Usage of buckets which has never been stored is computed from the tree.
+func (n *layer) LoadUsage(ctx context.Context) {
+	for _, bktInfo := range n.usageBuckets(ctx) {
+		if ctx.Err() != nil {
+			return
+		}
+		if _, err := n.GetBucketUsage(ctx, bktInfo); err != nil {
+			n.log.Warn("couldn't load bucket usage", zap.String("bucket", bktInfo.Name),
+				zap.Stringer("cid", bktInfo.CID), zap.Error(err))
+		}
+	}
+}
+
+// ReconcileUsage reconciles usage of all buckets, see usageBuckets.
+func (n *layer) ReconcileUsage(ctx context.Context) {
+	for _, bktInfo := range n.usageBuckets(ctx) {
+		if ctx.Err() != nil {
+			return
+		}
+		if _, err := n.ReconcileBucketUsage(ctx, bktInfo); err != nil {
+			n.log.Warn("couldn't reconcile bucket usage", zap.String("bucket", bktInfo.Name),
+				zap.Stringer("cid", bktInfo.CID), zap.Error(err))
+		}
+	}
+}
+
+// usageBuckets returns buckets known to the gateway and all containers of the configured usage owners
+// and owners of the known buckets. Known buckets which are no longer listed for their owner are forgotten.
+func (n *layer) usageBuckets(ctx context.Context) []*data.BucketInfo {
+	buckets := make(map[cid.ID]*data.BucketInfo)
+	owners := make(map[string]user.ID, len(n.usageOwners))
+	for _, owner := range n.usageOwners {
+		owners[owner.EncodeToString()] = owner
+	}
+	for _, bktInfo := range n.usage.list(false) {
+		buckets[bktInfo.CID] = bktInfo
+		owners[bktInfo.Owner.EncodeToString()] = bktInfo.Owner
+	}
+
+	for _, owner := range owners {
+		cnrIDs, err := n.frostFS.UserContainers(ctx, owner)
+		if err != nil {
+			n.log.Warn("couldn't list containers of usage owner", zap.Stringer("owner", owner), zap.Error(err))
+			continue
+		}
+
+		listed := make(map[cid.ID]struct{}, len(cnrIDs))
+		for _, cnrID := range cnrIDs {
+			listed[cnrID] = struct{}{}
+			if _, ok := buckets[cnrID]; ok {
+				continue
+			}
+			if bktInfo, err := n.containerInfo(ctx, cnrID); err == nil {
+				buckets[cnrID] = bktInfo
+			}
+		}
+
+		for cnrID, bktInfo := range buckets {
+			if _, ok := listed[cnrID]; !ok && bktInfo.Owner.Equals(owner) {
+				n.usage.remove(cnrID)
+				delete(buckets, cnrID)
+			}
+		}
+	}
+
+	res := make([]*data.BucketInfo, 0, len(buckets))
+	for _, bktInfo := range buckets {
+		res = append(res, bktInfo)
+	}
+	return res
+}
+
+// updateUsage adds delta to the usage of the bucket. The change is stored in the tree service
+// by FlushUsage or GetBucketUsage, it doesn't block the request. Changes which are lost
+// (e.g. on the gateway crash) are fixed by reconciliation.
+func (n *layer) updateUsage(bktInfo *data.BucketInfo, delta data.BucketUsage) {
+	if !delta.IsZero() {
+		n.usage.add(bktInfo, delta)
+	}
+}
+
+// versionUsage returns usage of the single version, the object isn't counted.
+func versionUsage(version *data.NodeVersion) data.BucketUsage {
+	if version.IsDeleteMarker() {
+		return data.BucketUsage{DeleteMarkers: 1}
+	}
+	return data.BucketUsage{Versions: 1, Bytes: version.Size}
+}
+
+// latestVersionUsage returns usage of the object by its latest version: the object is counted
+// if the version exists and isn't a delete marker.
+func latestVersionUsage(latest *data.NodeVersion) data.BucketUsage {
+	if latest == nil || latest.IsDeleteMarker() {
+		return data.BucketUsage{}
+	}
+	return data.BucketUsage{Objects: 1}
+}
+
+// addedVersionUsage returns the change of the bucket usage made by the version which becomes the latest one
+// of the object instead of the previous latest version. The replaced version is the unversioned one
+// removed from the tree, it's nil if the added version doesn't replace anything.
+func addedVersionUsage(added, latest, replaced *data.NodeVersion) data.BucketUsage {
+	delta := versionUsage(added)
+	delta.Add(latestVersionUsage(added))
+	delta = delta.Sub(latestVersionUsage(latest))
+	if replaced != nil {
+		delta = delta.Sub(versionUsage(replaced))
+	}
+	return delta
+}
+
+// latestVersion returns the latest version of the object to account the usage change. Nil is returned
+// if the object doesn't exist or the version can't be fetched, the drift is fixed by reconciliation then.
+func (n *layer) latestVersion(ctx context.Context, bktInfo *data.BucketInfo, objectName string) *data.NodeVersion {
+	latest, err := n.treeService.GetLatestVersion(ctx, bktInfo, objectName)
+	if err != nil {
+		if !errors.Is(err, ErrNodeNotFound) {
+			n.log.Warn("couldn't get latest version", zap.String("bucket", bktInfo.Name),
+				zap.String("object", objectName), zap.Error(err))
+		}
+		return nil
+	}
+	return latest
+}
+
+func partsSize(parts []*data.PartInfo) int64 {
+	var size int64
+	for _, part := range parts {
+		size += part.Size
+	}
+	return size
+}
+
+func partsMapSize(parts map[int]*data.PartInfo) int64 {
+	var size int64
+	for _, part := range parts {
+		size += part.Size
+	}
+	return size
+}
@@ -0,0 +1,208 @@
+package layer
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
+	cid "github.com/TrueCloudLab/frostfs-sdk-go/container/id"
+	"github.com/TrueCloudLab/frostfs-sdk-go/user"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBucketUsage(t *testing.T) {
+	tc := prepareContext(t)
+	err := tc.layer.PutBucketSettings(tc.ctx, &PutSettingsParams{
+		BktInfo:  tc.bktInfo,
+		Settings: &data.BucketSettings{Versioning: data.VersioningEnabled},
+	})
+	require.NoError(t, err)
+
+	checkUsage := func(expected data.BucketUsage) {
+		usages := tc.layer.BucketsUsage()
+		require.Len(t, usages, 1)
+		require.Equal(t, expected, usages[0].Usage)
+
+		usage, err := tc.layer.GetBucketUsage(tc.ctx, tc.bktInfo)
+		require.NoError(t, err)
+		require.Equal(t, expected, *usage)
+
+		usage, err = tc.layer.ReconcileBucketUsage(tc.ctx, tc.bktInfo)
+		require.NoError(t, err)
+		require.Equal(t, expected, *usage)
+	}
+
+	_, err = tc.layer.GetBucketUsage(tc.ctx, tc.bktInfo)
+	require.NoError(t, err)
+	checkUsage(data.BucketUsage{})
+
+	tc.putObject([]byte("content1"))
+	tc.putObject([]byte("content22"))
+	checkUsage(data.BucketUsage{Bytes: 17, Objects: 1, Versions: 2})
+
+	tc.deleteObject(tc.obj, "", &data.BucketSettings{Versioning: data.VersioningEnabled})
+	checkUsage(data.BucketUsage{Bytes: 17, Versions: 2, DeleteMarkers: 1})
+
+	uploadInfo := &UploadInfoParams{UploadID: "upload", Bkt: tc.bktInfo, Key: "multipart"}
+	err = tc.layer.CreateMultipartUpload(tc.ctx, &CreateMultipartParams{Info: uploadInfo})
+	require.NoError(t, err)
+
+	for i := 1; i <= 2; i++ {
+		_, err = tc.layer.UploadPart(tc.ctx, &UploadPartParams{
+			Info:       uploadInfo,
+			PartNumber: i,
+			Size:       3,
+			Reader:     bytes.NewReader([]byte("abc")),
+		})
+		require.NoError(t, err)
+	}
+	checkUsage(data.BucketUsage{Bytes: 17, Versions: 2, DeleteMarkers: 1, MultipartBytes: 6})
+
+	_, err = tc.layer.UploadPart(tc.ctx, &UploadPartParams{
+		Info:       uploadInfo,
+		PartNumber: 1,
+		Size:       4,
+		Reader:     bytes.NewReader([]byte("abcd")),
+	})
+	require.NoError(t, err)
+	checkUsage(data.BucketUsage{Bytes: 17, Versions: 2, DeleteMarkers: 1, MultipartBytes: 7})
+
+	err = tc.layer.AbortMultipartUpload(tc.ctx, uploadInfo)
+	require.NoError(t, err)
+	checkUsage(data.BucketUsage{Bytes: 17, Versions: 2, DeleteMarkers: 1})
+}
+
+type usageTreeService struct {
+	TreeService
+	getVersions int
+	// beforeAdd is called once before the next version is added.
+	beforeAdd func()
+}
+
+func (u *usageTreeService) GetVersions(ctx context.Context, bktInfo *data.BucketInfo, objectName string) ([]*data.NodeVersion, error) {
+	u.getVersions++
+	return u.TreeService.GetVersions(ctx, bktInfo, objectName)
+}
+
+func (u *usageTreeService) AddVersion(ctx context.Context, bktInfo *data.BucketInfo, version *data.NodeVersion) (uint64, error) {
+	if hook := u.beforeAdd; hook != nil {
+		u.beforeAdd = nil
+		hook()
+	}
+	return u.TreeService.AddVersion(ctx, bktInfo, version)
+}
+
+func TestBucketUsageUnversioned(t *testing.T) {
+	tc := prepareContext(t)
+	settings := &data.BucketSettings{Versioning: data.VersioningUnversioned}
+	treeService := &usageTreeService{TreeService: tc.layer.(*layer).treeService}
+	tc.layer.(*layer).treeService = treeService
+
+	_, err := tc.layer.GetBucketUsage(tc.ctx, tc.bktInfo)
+	require.NoError(t, err)
+
+	checkUsage := func(expected data.BucketUsage) {
+		require.Equal(t, expected, tc.layer.BucketsUsage()[0].Usage)
+		usage, err := tc.layer.ReconcileBucketUsage(tc.ctx, tc.bktInfo)
+		require.NoError(t, err)
+		require.Equal(t, expected, *usage)
+	}
+
+	tc.obj = "a"
+	tc.putObject([]byte("content1"))
+	src := tc.putObject([]byte("content22"))
+	// the usage is changed by the added version and the replaced one, versions aren't listed
+	require.Zero(t, treeService.getVersions)
+	checkUsage(data.BucketUsage{Bytes: 9, Objects: 1, Versions: 1})
+
+	tc.copyObject(src, "b", make(map[string]string))
+	checkUsage(data.BucketUsage{Bytes: 18, Objects: 2, Versions: 2})
+
+	_, err = tc.layer.RenameObject(tc.ctx, &RenameObjectParams{
+		BktInfo:   tc.bktInfo,
+		Settings:  settings,
+		SrcObject: "b",
+		DstObject: "a",
+	})
+	require.NoError(t, err)
+	checkUsage(data.BucketUsage{Bytes: 9, Objects: 1, Versions: 1})
+
+	tc.deleteObject("a", "", settings)
+	checkUsage(data.BucketUsage{})
+}
+
+func TestBucketUsageInterleavedPut(t *testing.T) {
+	tc := prepareContext(t)
+	settings := &data.BucketSettings{Versioning: data.VersioningEnabled}
+	err := tc.layer.PutBucketSettings(tc.ctx, &PutSettingsParams{BktInfo: tc.bktInfo, Settings: settings})
+	require.NoError(t, err)
+	treeService := &usageTreeService{TreeService: tc.layer.(*layer).treeService}
+	tc.layer.(*layer).treeService = treeService
+
+	_, err = tc.layer.GetBucketUsage(tc.ctx, tc.bktInfo)
+	require.NoError(t, err)
+
+	// the version added by another request meanwhile isn't counted twice
+	treeService.beforeAdd = func() { tc.putObject([]byte("content22")) }
+	tc.putObject([]byte("content1"))
+
+	usage := tc.layer.BucketsUsage()[0].Usage
+	require.Equal(t, int64(17), usage.Bytes)
+	require.Equal(t, int64(2), usage.Versions)
+
+	// both requests count the new object, the drift is fixed by reconciliation
+	reconciled, err := tc.layer.ReconcileBucketUsage(tc.ctx, tc.bktInfo)
+	require.NoError(t, err)
+	require.Equal(t, data.BucketUsage{Bytes: 17, Objects: 1, Versions: 2}, *reconciled)
+
+	// removal of the latest version makes the previous one the latest
+	versions := tc.listVersions()
+	tc.deleteObject(tc.obj, versions.Version[0].ObjectInfo.VersionID(), settings)
+	require.Equal(t, data.BucketUsage{Bytes: 9, Objects: 1, Versions: 1}, tc.layer.BucketsUsage()[0].Usage)
+}
+
+func TestBucketUsageFlush(t *testing.T) {
+	tc := prepareContext(t)
+	treeService := tc.layer.(*layer).treeService
+
+	_, err := tc.layer.GetBucketUsage(tc.ctx, tc.bktInfo)
+	require.NoError(t, err)
+
+	tc.putObject([]byte("content"))
+	expected := data.BucketUsage{Bytes: 7, Objects: 1, Versions: 1}
+
+	stored, err := treeService.GetBucketUsage(tc.ctx, tc.bktInfo)
+	require.NoError(t, err)
+	require.Equal(t, data.BucketUsage{}, *stored)
+	require.Equal(t, expected, tc.layer.BucketsUsage()[0].Usage)
+
+	tc.layer.FlushUsage(tc.ctx)
+	stored, err = treeService.GetBucketUsage(tc.ctx, tc.bktInfo)
+	require.NoError(t, err)
+	require.Equal(t, expected, *stored)
+	require.Equal(t, expected, tc.layer.BucketsUsage()[0].Usage)
+}
+
+func TestLoadUsageOfAllBuckets(t *testing.T) {
+	tc := prepareContext(t)
+	tc.layer.(*layer).usageOwners = []user.ID{tc.bktInfo.Owner}
+	tc.testFrostFS.containers[tc.bktInfo.CID.EncodeToString()].SetOwner(tc.bktInfo.Owner)
+
+	cnrID, err := tc.testFrostFS.CreateContainer(tc.ctx, PrmContainerCreate{
+		Creator: tc.bktInfo.Owner,
+		Name:    "not-served",
+	})
+	require.NoError(t, err)
+
+	tc.layer.LoadUsage(tc.ctx)
+
+	usages := tc.layer.BucketsUsage()
+	require.Len(t, usages, 2)
+
+	names := make(map[string]cid.ID, len(usages))
+	for _, info := range usages {
+		names[info.Bucket.Name] = info.Bucket.CID
+	}
+	require.Equal(t, cnrID, names["not-served"])
+}
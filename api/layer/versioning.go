@@ -2,61 +2,113 @@ package layer
 
 import (
 	"context"
-	"sort"
+	"fmt"
+	"sync"
 
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
+	"github.com/TrueCloudLab/frostfs-s3-gw/internal/tracing"
+	"github.com/panjf2000/ants/v2"
+	"go.uber.org/zap"
 )
 
-func (n *layer) ListObjectVersions(ctx context.Context, p *ListObjectVersionsParams) (*ListObjectVersionsInfo, error) {
-	var (
-		allObjects = make([]*data.ExtendedObjectInfo, 0, p.MaxKeys)
-		res        = &ListObjectVersionsInfo{}
-	)
+func (n *layer) ListObjectVersions(ctx context.Context, p *ListObjectVersionsParams) (_ *ListObjectVersionsInfo, err error) {
+	ctx, span := tracing.StartSpan(ctx, "layer.ListObjectVersions")
+	defer func() { tracing.End(span, err) }()
 
-	versions, err := n.getAllObjectsVersions(ctx, p.BktInfo, p.Prefix, p.Delimiter)
+	res := &ListObjectVersionsInfo{
+		KeyMarker:       p.KeyMarker,
+		VersionIDMarker: p.VersionIDMarker,
+	}
+
+	if p.MaxKeys == 0 {
+		return res, nil
+	}
+
+	allObjects, err := n.listAllVersions(ctx, p)
 	if err != nil {
 		return nil, err
 	}
 
-	sortedNames := make([]string, 0, len(versions))
-	for k := range versions {
-		sortedNames = append(sortedNames, k)
+	if len(allObjects) > p.MaxKeys {
+		res.IsTruncated = true
+		allObjects = allObjects[:p.MaxKeys]
 	}
-	sort.Strings(sortedNames)
 
-	for _, name := range sortedNames {
-		sortedVersions := versions[name]
-		sort.Slice(sortedVersions, func(i, j int) bool {
-			return sortedVersions[j].NodeVersion.Timestamp < sortedVersions[i].NodeVersion.Timestamp // sort in reverse order
-		})
+	if err = n.resolveVersions(ctx, p.BktInfo, allObjects); err != nil {
+		return nil, err
+	}
 
-		for i, version := range sortedVersions {
-			version.IsLatest = i == 0
-			allObjects = append(allObjects, version)
+	if res.IsTruncated {
+		last := allObjects[len(allObjects)-1]
+		res.NextKeyMarker = last.ObjectInfo.Name
+		if !last.ObjectInfo.IsDir {
+			res.NextVersionIDMarker = last.Version()
 		}
 	}
 
-	for i, obj := range allObjects {
-		if obj.ObjectInfo.Name >= p.KeyMarker && obj.ObjectInfo.VersionID() >= p.VersionIDMarker {
-			allObjects = allObjects[i:]
-			break
+	res.CommonPrefixes, allObjects = triageExtendedObjects(allObjects)
+	res.Version, res.DeleteMarker = triageVersions(allObjects)
+	return res, nil
+}
+
+// resolveVersions fills object infos of the listed versions, objects are fetched in parallel.
+// Results are set to the versions directly since different versions can reference the same object.
+func (n *layer) resolveVersions(ctx context.Context, bktInfo *data.BucketInfo, versions []*data.ExtendedObjectInfo) error {
+	unresolved := make([]*data.ExtendedObjectInfo, 0, len(versions))
+
+	for _, version := range versions {
+		if version.ObjectInfo != nil { // common prefix
+			continue
 		}
+
+		node := version.NodeVersion
+		if node.IsDeleteMarker() { // delete marker does not match any object in FrostFS
+			version.ObjectInfo = &data.ObjectInfo{
+				ID:             node.OID,
+				CID:            bktInfo.CID,
+				Bucket:         bktInfo.Name,
+				Name:           node.FilePath,
+				Owner:          node.DeleteMarker.Owner,
+				Created:        node.DeleteMarker.Created,
+				IsDeleteMarker: true,
+			}
+			continue
+		}
+
+		unresolved = append(unresolved, version)
 	}
 
-	res.CommonPrefixes, allObjects = triageExtendedObjects(allObjects)
+	if len(unresolved) == 0 {
+		return nil
+	}
 
-	if len(allObjects) > p.MaxKeys {
-		res.IsTruncated = true
-		res.NextKeyMarker = allObjects[p.MaxKeys].ObjectInfo.Name
-		res.NextVersionIDMarker = allObjects[p.MaxKeys].ObjectInfo.VersionID()
+	pool, err := ants.NewPool(2, ants.WithLogger(&logWrapper{n.log}))
+	if err != nil {
+		return fmt.Errorf("failed to init worker pool: %w", err)
+	}
+	defer pool.Release()
+
+	var wg sync.WaitGroup
+	for _, version := range unresolved {
+		version := version
+		wg.Add(1)
+		if err = pool.Submit(func() {
+			defer wg.Done()
+			version.ObjectInfo = n.objectInfoFromObjectsCacheOrFrostFS(ctx, bktInfo, version.NodeVersion, "", "")
+		}); err != nil {
+			wg.Done()
+			n.log.Warn("failed to submit task to pool", zap.Error(err))
+		}
+	}
+	wg.Wait()
 
-		allObjects = allObjects[:p.MaxKeys]
-		res.KeyMarker = allObjects[p.MaxKeys-1].ObjectInfo.Name
-		res.VersionIDMarker = allObjects[p.MaxKeys-1].ObjectInfo.VersionID()
+	for _, version := range unresolved {
+		if version.ObjectInfo == nil {
+			version.ObjectInfo = getPartialObjectInfo(bktInfo, version.NodeVersion)
+		}
 	}
 
-	res.Version, res.DeleteMarker = triageVersions(allObjects)
-	return res, nil
+	return nil
 }
 
 func triageVersions(objVersions []*data.ExtendedObjectInfo) ([]*data.ExtendedObjectInfo, []*data.ExtendedObjectInfo) {
@@ -44,25 +44,25 @@ func (t RequestType) String() string {
 
 func RequestTypeFromAPI(api string) RequestType {
 	switch api {
-	case "Options", "HeadObject", "HeadBucket":
+	case "Options", "TusOptions", "HeadObject", "TusHead", "HeadBucket":
 		return HEADRequest
 	case "CreateMultipartUpload", "UploadPartCopy", "UploadPart", "CompleteMultipartUpload",
-		"PutObjectACL", "PutObjectTagging", "CopyObject", "PutObjectRetention", "PutObjectLegalHold",
-		"PutObject", "PutBucketCors", "PutBucketACL", "PutBucketLifecycle", "PutBucketEncryption",
+		"PutObjectACL", "PutObjectTagging", "CopyObject", "RenameObject", "PutObjectRetention", "PutObjectLegalHold",
+		"PutObject", "AppendObject", "PutBucketCors", "PutBucketACL", "PutBucketLifecycle", "PutBucketEncryption",
 		"PutBucketPolicy", "PutBucketObjectLockConfig", "PutBucketTagging", "PutBucketVersioning",
-		"PutBucketNotification", "CreateBucket", "PostObject":
+		"PutBucketNotification", "CreateBucket", "PostObject", "TusCreate", "TusPatch":
 		return PUTRequest
 	case "ListObjectParts", "ListMultipartUploads", "ListObjectsV2M", "ListObjectsV2", "ListBucketVersions",
-		"ListObjectsV1", "ListBuckets":
+		"ListObjectsV1", "SearchObjects", "ListBuckets":
 		return LISTRequest
 	case "GetObjectACL", "GetObjectTagging", "SelectObjectContent", "GetObjectRetention", "getobjectlegalhold",
 		"GetObjectAttributes", "GetObject", "GetBucketLocation", "GetBucketPolicy",
 		"GetBucketLifecycle", "GetBucketEncryption", "GetBucketCors", "GetBucketACL",
 		"GetBucketWebsite", "GetBucketAccelerate", "GetBucketRequestPayment", "GetBucketLogging",
 		"GetBucketReplication", "GetBucketTagging", "GetBucketObjectLockConfig",
-		"GetBucketVersioning", "GetBucketNotification", "ListenBucketNotification":
+		"GetBucketVersioning", "GetBucketNotification", "ListenBucketNotification", "GetArchive":
 		return GETRequest
-	case "AbortMultipartUpload", "DeleteObjectTagging", "DeleteObject", "DeleteBucketCors",
+	case "AbortMultipartUpload", "TusDelete", "DeleteObjectTagging", "DeleteObject", "DeleteBucketCors",
 		"DeleteBucketWebsite", "DeleteBucketTagging", "DeleteMultipleObjects", "DeleteBucketPolicy",
 		"DeleteBucketLifecycle", "DeleteBucketEncryption", "DeleteBucket":
 		return DELETERequest
@@ -83,6 +83,18 @@ type (
 		Update(user, bucket, cnrID string, reqType RequestType, in, out uint64)
 	}
 
+	// RequestsStat observes duration and time to first byte of requests.
+	// Bucket is empty if the request has no bucket or the bucket doesn't exist.
+	RequestsStat interface {
+		ObserveRequest(api, bucket string, status int, duration, ttfb time.Duration)
+	}
+
+	// Metrics collects statistics of requests.
+	Metrics interface {
+		UsersStat
+		RequestsStat
+	}
+
 	// HTTPStats holds statistics information about
 	// HTTP requests made by all clients.
 	HTTPStats struct {
@@ -110,6 +122,9 @@ type (
 
 		statusCode int
 		startTime  time.Time
+
+		// firstByteTime is a time of the first written header or payload byte.
+		firstByteTime time.Time
 	}
 )
 
@@ -171,7 +186,7 @@ func collectHTTPMetrics(ch chan<- prometheus.Metric) {
 type CIDResolveFunc func(ctx context.Context, reqInfo *ReqInfo) (cnrID string)
 
 // Stats is a handler that update metrics.
-func Stats(f http.HandlerFunc, resolveCID CIDResolveFunc, usersStat UsersStat) http.HandlerFunc {
+func Stats(f http.HandlerFunc, resolveCID CIDResolveFunc, metrics Metrics) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		reqInfo := GetReqInfo(r.Context())
 
@@ -193,15 +208,28 @@ func Stats(f http.HandlerFunc, resolveCID CIDResolveFunc, usersStat UsersStat) h
 		// Time duration in secs since the call started.
 		// We don't need to do nanosecond precision here
 		// simply for the fact that it is not human-readable.
-		durationSecs := time.Since(statsWriter.startTime).Seconds()
+		duration := time.Since(statsWriter.startTime)
+		durationSecs := duration.Seconds()
 
 		user := resolveUser(r.Context())
 		cnrID := resolveCID(r.Context(), reqInfo)
-		usersStat.Update(user, reqInfo.BucketName, cnrID, RequestTypeFromAPI(reqInfo.API), in.countBytes, out.countBytes)
+		metrics.Update(user, reqInfo.BucketName, cnrID, RequestTypeFromAPI(reqInfo.API), in.countBytes, out.countBytes)
+
+		ttfb := duration
+		if !statsWriter.firstByteTime.IsZero() {
+			ttfb = statsWriter.firstByteTime.Sub(statsWriter.startTime)
+		}
 
 		code := statsWriter.statusCode
 		// A successful request has a 2xx response code
 		successReq := code >= http.StatusOK && code < http.StatusMultipleChoices
+
+		// requests to missing buckets mustn't take slots of bucket labels
+		bucket := reqInfo.BucketName
+		if cnrID == "" && (reqInfo.API != "CreateBucket" || !successReq && code != 0) {
+			bucket = ""
+		}
+		metrics.ObserveRequest(reqInfo.API, bucket, code, duration, ttfb)
 		if !strings.HasSuffix(r.URL.Path, systemPath) {
 			httpStatsMetric.totalS3Requests.Inc(reqInfo.API)
 			if !successReq && code != 0 {
@@ -275,10 +303,17 @@ func (st *HTTPStats) getOutputBytes() uint64 {
 func (w *responseWrapper) WriteHeader(code int) {
 	w.Do(func() {
 		w.statusCode = code
+		w.firstByteTime = time.Now()
 		w.ResponseWriter.WriteHeader(code)
 	})
 }
 
+// Write -- writes payload, status code is 200 if it hasn't been written.
+func (w *responseWrapper) Write(p []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	return w.ResponseWriter.Write(p)
+}
+
 // Flush -- calls the underlying Flush.
 func (w *responseWrapper) Flush() {
 	if f, ok := w.ResponseWriter.(http.Flusher); ok {
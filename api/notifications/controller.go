@@ -131,6 +131,14 @@ func NewController(p *Options, l *zap.Logger) (*Controller, error) {
 	}, nil
 }
 
+// Healthcheck returns an error if the controller isn't connected to NATS server.
+func (c *Controller) Healthcheck(context.Context) error {
+	if !c.taskQueueConnection.IsConnected() {
+		return fmt.Errorf("nats connection status: %s", c.taskQueueConnection.Status())
+	}
+	return nil
+}
+
 func (c *Controller) Subscribe(ctx context.Context, topic string, handler layer.MsgHandler) error {
 	ch := make(chan *nats.Msg, 1)
 
@@ -180,6 +188,30 @@ func (c *Controller) Listen(ctx context.Context) {
 	}
 }
 
+// SubscribeBroadcast subscribes to the subject without JetStream, so every
+// subscribed gateway receives all messages and they aren't persisted.
+func (c *Controller) SubscribeBroadcast(ctx context.Context, subject string, handler layer.MsgHandler) error {
+	_, err := c.taskQueueConnection.Subscribe(subject, func(msg *nats.Msg) {
+		if err := handler.HandleMessage(ctx, msg); err != nil {
+			c.logger.Error("could not handle broadcast message", zap.String("subject", subject), zap.Error(err))
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("could not subscribe: %w", err)
+	}
+
+	return nil
+}
+
+// Publish sends the message to subscribers of the subject without JetStream.
+func (c *Controller) Publish(subject string, data []byte) error {
+	if err := c.taskQueueConnection.Publish(subject, data); err != nil {
+		return fmt.Errorf("couldn't publish message: %w", err)
+	}
+
+	return nil
+}
+
 func (c *Controller) SendNotifications(topics map[string]string, p *handler.SendNotificationParams) error {
 	event := prepareEvent(p)
 
@@ -4,11 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/url"
 	"sync"
 
 	"github.com/TrueCloudLab/frostfs-sdk-go/container"
 	cid "github.com/TrueCloudLab/frostfs-sdk-go/container/id"
 	"github.com/TrueCloudLab/frostfs-sdk-go/ns"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient"
 )
 
 const (
@@ -39,8 +41,10 @@ type BucketResolver struct {
 }
 
 type Resolver struct {
-	Name    string
-	resolve func(context.Context, string) (cid.ID, error)
+	Name        string
+	resolve     func(context.Context, string) (cid.ID, error)
+	healthcheck func(context.Context) error
+	close       func()
 }
 
 func (r *Resolver) SetResolveFunc(fn func(context.Context, string) (cid.ID, error)) {
@@ -51,6 +55,27 @@ func (r *Resolver) Resolve(ctx context.Context, name string) (cid.ID, error) {
 	return r.resolve(ctx, name)
 }
 
+// SetHealthcheckFunc sets function to check availability of the resolver backend.
+func (r *Resolver) SetHealthcheckFunc(fn func(context.Context) error) {
+	r.healthcheck = fn
+}
+
+// Healthcheck checks that the resolver backend is available.
+// Resolver without healthcheck function is always considered available.
+func (r *Resolver) Healthcheck(ctx context.Context) error {
+	if r.healthcheck == nil {
+		return nil
+	}
+	return r.healthcheck(ctx)
+}
+
+// Close releases resources of the resolver backend, the resolver must not be used after that.
+func (r *Resolver) Close() {
+	if r.close != nil {
+		r.close()
+	}
+}
+
 func NewBucketResolver(resolverNames []string, cfg *Config) (*BucketResolver, error) {
 	resolvers, err := createResolvers(resolverNames, cfg)
 	if err != nil {
@@ -100,6 +125,29 @@ func (r *BucketResolver) Resolve(ctx context.Context, bktName string) (cnrID cid
 	return cnrID, ErrNoResolvers
 }
 
+// Healthcheck returns an error if none of the resolvers is available.
+// Absence of resolvers isn't considered as an error.
+func (r *BucketResolver) Healthcheck(ctx context.Context) (err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, resolver := range r.resolvers {
+		resolverErr := resolver.Healthcheck(ctx)
+		if resolverErr == nil {
+			return nil
+		}
+
+		resolverErr = fmt.Errorf("%s: %w", resolver.Name, resolverErr)
+		if err == nil {
+			err = resolverErr
+		} else {
+			err = fmt.Errorf("%s: %w", err.Error(), resolverErr)
+		}
+	}
+
+	return err
+}
+
 func (r *BucketResolver) UpdateResolvers(resolverNames []string, cfg *Config) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -113,6 +161,10 @@ func (r *BucketResolver) UpdateResolvers(resolverNames []string, cfg *Config) er
 		return err
 	}
 
+	// resolvers are replaced under the lock, so old ones aren't used anymore
+	for _, resolver := range r.resolvers {
+		resolver.Close()
+	}
 	r.resolvers = resolvers
 
 	return nil
@@ -163,9 +215,17 @@ func NewDNSResolver(frostFS FrostFS) (*Resolver, error) {
 		return cnrID, nil
 	}
 
+	healthcheckFunc := func(ctx context.Context) error {
+		if _, err := frostFS.SystemDNS(ctx); err != nil {
+			return fmt.Errorf("read system DNS parameter of the FrostFS: %w", err)
+		}
+		return nil
+	}
+
 	return &Resolver{
-		Name:    DNSResolver,
-		resolve: resolveFunc,
+		Name:        DNSResolver,
+		resolve:     resolveFunc,
+		healthcheck: healthcheckFunc,
 	}, nil
 }
 
@@ -191,8 +251,58 @@ func NewNNSResolver(address string) (*Resolver, error) {
 		return cnrID, nil
 	}
 
+	rpcCli, err := newRPCClient(address)
+	if err != nil {
+		return nil, err
+	}
+
+	healthcheckFunc := func(ctx context.Context) error {
+		// the client is bound to its own context, so the request is abandoned on ctx cancellation
+		res := make(chan error, 1)
+		go func() {
+			_, err := rpcCli.GetBlockCount()
+			res <- err
+		}()
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("get block count from %s: %w", address, ctx.Err())
+		case err := <-res:
+			if err != nil {
+				return fmt.Errorf("get block count from %s: %w", address, err)
+			}
+			return nil
+		}
+	}
+
 	return &Resolver{
-		Name:    NNSResolver,
-		resolve: resolveFunc,
+		Name:        NNSResolver,
+		resolve:     resolveFunc,
+		healthcheck: healthcheckFunc,
+		close:       rpcCli.Close,
 	}, nil
 }
+
+type rpcClient interface {
+	GetBlockCount() (uint32, error)
+	Close()
+}
+
+// newRPCClient creates client to check availability of the RPC node.
+// The same schemes as in ns.NNS are supported.
+func newRPCClient(address string) (rpcClient, error) {
+	uri, err := url.Parse(address)
+	if err == nil && (uri.Scheme == "ws" || uri.Scheme == "wss") {
+		cli, err := rpcclient.NewWS(context.Background(), address, rpcclient.Options{})
+		if err != nil {
+			return nil, fmt.Errorf("create Neo WebSocket client: %w", err)
+		}
+		return cli, nil
+	}
+
+	cli, err := rpcclient.New(context.Background(), address, rpcclient.Options{})
+	if err != nil {
+		return nil, fmt.Errorf("create Neo HTTP client: %w", err)
+	}
+	return cli, nil
+}
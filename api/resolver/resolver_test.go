@@ -0,0 +1,30 @@
+package resolver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type frostFSMock struct{}
+
+func (frostFSMock) SystemDNS(context.Context) (string, error) {
+	return "frostfs", nil
+}
+
+func TestUpdateResolversClosesOld(t *testing.T) {
+	var closed bool
+	r := &BucketResolver{resolvers: []*Resolver{{
+		Name:  NNSResolver,
+		close: func() { closed = true },
+	}}}
+
+	cfg := &Config{FrostFS: frostFSMock{}}
+	require.NoError(t, r.UpdateResolvers([]string{NNSResolver}, cfg))
+	require.False(t, closed)
+
+	require.NoError(t, r.UpdateResolvers([]string{DNSResolver}, cfg))
+	require.True(t, closed)
+	require.NoError(t, r.Healthcheck(context.Background()))
+}
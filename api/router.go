@@ -7,8 +7,12 @@ import (
 
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/auth"
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
+	"github.com/TrueCloudLab/frostfs-s3-gw/internal/tracing"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/metadata"
 )
@@ -28,9 +32,11 @@ type (
 		GetObjectHandler(http.ResponseWriter, *http.Request)
 		GetObjectAttributesHandler(http.ResponseWriter, *http.Request)
 		CopyObjectHandler(http.ResponseWriter, *http.Request)
+		RenameObjectHandler(http.ResponseWriter, *http.Request)
 		PutObjectRetentionHandler(http.ResponseWriter, *http.Request)
 		PutObjectLegalHoldHandler(http.ResponseWriter, *http.Request)
 		PutObjectHandler(http.ResponseWriter, *http.Request)
+		AppendObjectHandler(http.ResponseWriter, *http.Request)
 		DeleteObjectHandler(http.ResponseWriter, *http.Request)
 		GetBucketLocationHandler(http.ResponseWriter, *http.Request)
 		GetBucketPolicyHandler(http.ResponseWriter, *http.Request)
@@ -56,6 +62,8 @@ type (
 		ListObjectsV2MHandler(http.ResponseWriter, *http.Request)
 		ListObjectsV2Handler(http.ResponseWriter, *http.Request)
 		ListBucketObjectVersionsHandler(http.ResponseWriter, *http.Request)
+		GetArchiveHandler(http.ResponseWriter, *http.Request)
+		SearchObjectsHandler(http.ResponseWriter, *http.Request)
 		ListObjectsV1Handler(http.ResponseWriter, *http.Request)
 		PutBucketLifecycleHandler(http.ResponseWriter, *http.Request)
 		PutBucketEncryptionHandler(http.ResponseWriter, *http.Request)
@@ -81,7 +89,13 @@ type (
 		CompleteMultipartUploadHandler(http.ResponseWriter, *http.Request)
 		AbortMultipartUploadHandler(http.ResponseWriter, *http.Request)
 		ListPartsHandler(w http.ResponseWriter, r *http.Request)
+		TusOptionsHandler(http.ResponseWriter, *http.Request)
+		TusCreateHandler(http.ResponseWriter, *http.Request)
+		TusHeadHandler(http.ResponseWriter, *http.Request)
+		TusPatchHandler(http.ResponseWriter, *http.Request)
+		TusDeleteHandler(http.ResponseWriter, *http.Request)
 		ListMultipartUploadsHandler(http.ResponseWriter, *http.Request)
+		STSHandler(http.ResponseWriter, *http.Request)
 
 		ResolveBucket(ctx context.Context, bucket string) (*data.BucketInfo, error)
 	}
@@ -138,6 +152,37 @@ func setRequestID(h http.Handler) http.Handler {
 	})
 }
 
+// traceRequest starts a span for S3 request continuing the trace from
+// traceparent header if it's present.
+func traceRequest(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqInfo := GetReqInfo(r.Context())
+
+		ctx, span := tracing.StartSpan(tracing.Extract(r.Context(), r.Header), "S3 "+reqInfo.API,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("request_id", reqInfo.RequestID),
+				attribute.String("http.method", r.Method),
+				attribute.String("http.host", r.Host),
+				attribute.String("bucket", reqInfo.BucketName),
+				attribute.String("object", reqInfo.ObjectName),
+			))
+		defer span.End()
+
+		lw := &logResponseWriter{ResponseWriter: w}
+		h.ServeHTTP(lw, r.WithContext(ctx))
+
+		status := lw.statusCode
+		if status == 0 {
+			status = http.StatusOK
+		}
+		span.SetAttributes(attribute.Int("http.status_code", status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	})
+}
+
 func appendCORS(handler Handler) mux.MiddlewareFunc {
 	return func(h http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -151,9 +196,9 @@ func appendCORS(handler Handler) mux.MiddlewareFunc {
 type BucketResolveFunc func(ctx context.Context, bucket string) (*data.BucketInfo, error)
 
 // metricsMiddleware wraps http handler for api with basic statistics collection.
-func metricsMiddleware(log *zap.Logger, resolveBucket BucketResolveFunc, usersStat UsersStat) mux.MiddlewareFunc {
+func metricsMiddleware(log *zap.Logger, resolveBucket BucketResolveFunc, metrics Metrics) mux.MiddlewareFunc {
 	return func(h http.Handler) http.Handler {
-		return Stats(h.ServeHTTP, resolveCID(log, resolveBucket), usersStat)
+		return Stats(h.ServeHTTP, resolveCID(log, resolveBucket), metrics)
 	}
 }
 
@@ -191,14 +236,20 @@ func logSuccessResponse(l *zap.Logger) mux.MiddlewareFunc {
 				return
 			}
 
-			l.Info("call method",
+			fields := []zap.Field{
 				zap.Int("status", lw.statusCode),
 				zap.String("host", r.Host),
 				zap.String("request_id", GetRequestID(r.Context())),
 				zap.String("method", mux.CurrentRoute(r).GetName()),
 				zap.String("bucket", reqInfo.BucketName),
 				zap.String("object", reqInfo.ObjectName),
-				zap.String("description", http.StatusText(lw.statusCode)))
+				zap.String("description", http.StatusText(lw.statusCode)),
+			}
+			if reqInfo.Subject != "" {
+				fields = append(fields, zap.String("subject", reqInfo.Subject))
+			}
+
+			l.Info("call method", fields...)
 		})
 	}
 }
@@ -223,10 +274,10 @@ func setErrorAPI(apiName string, h http.Handler) http.Handler {
 }
 
 // attachErrorHandler set NotFoundHandler and MethodNotAllowedHandler for mux.Router.
-func attachErrorHandler(api *mux.Router, log *zap.Logger, h Handler, center auth.Center, usersStat UsersStat) {
+func attachErrorHandler(api *mux.Router, log *zap.Logger, h Handler, center auth.Center, metrics Metrics) {
 	middlewares := []mux.MiddlewareFunc{
 		AuthMiddleware(log, center),
-		metricsMiddleware(log, h.ResolveBucket, usersStat),
+		metricsMiddleware(log, h.ResolveBucket, metrics),
 	}
 
 	var errorHandler http.Handler = http.HandlerFunc(errorResponseHandler)
@@ -241,23 +292,25 @@ func attachErrorHandler(api *mux.Router, log *zap.Logger, h Handler, center auth
 
 // Attach adds S3 API handlers from h to r for domains with m client limit using
 // center authentication and log logger.
-func Attach(r *mux.Router, domains []string, m MaxClients, h Handler, center auth.Center, log *zap.Logger, usersStat UsersStat) {
+func Attach(r *mux.Router, domains []string, m MaxClients, h Handler, center auth.Center, log *zap.Logger, metrics Metrics) {
 	api := r.PathPrefix(SlashSeparator).Subrouter()
 
 	api.Use(
 		// -- prepare request
 		setRequestID,
 
+		traceRequest,
+
 		// Attach user authentication for all S3 routes.
 		AuthMiddleware(log, center),
 
-		metricsMiddleware(log, h.ResolveBucket, usersStat),
+		metricsMiddleware(log, h.ResolveBucket, metrics),
 
 		// -- logging error requests
 		logSuccessResponse(log),
 	)
 
-	attachErrorHandler(api, log, h, center, usersStat)
+	attachErrorHandler(api, log, h, center, metrics)
 
 	buckets := make([]*mux.Router, 0, len(domains)+1)
 	buckets = append(buckets, api.PathPrefix("/{bucket}").Subrouter())
@@ -273,9 +326,38 @@ func Attach(r *mux.Router, domains []string, m MaxClients, h Handler, center aut
 			// -- append CORS headers to a response for
 			appendCORS(h),
 		)
+		// TusOptions
+		bucket.Methods(http.MethodOptions).HandlerFunc(
+			m.Handle(h.TusOptionsHandler)).
+			Queries("tus", "").
+			Name("TusOptions")
 		bucket.Methods(http.MethodOptions).HandlerFunc(
 			m.Handle(h.Preflight)).
 			Name("Options")
+		// TusHead
+		bucket.Methods(http.MethodHead).Path("/{object:.+}").HandlerFunc(
+			m.Handle(h.TusHeadHandler)).
+			Queries("tus", "{tus:.+}").
+			Name("TusHead")
+		// TusPatch
+		bucket.Methods(http.MethodPatch).Path("/{object:.+}").HandlerFunc(
+			m.Handle(h.TusPatchHandler)).
+			Queries("tus", "{tus:.+}").
+			Name("TusPatch")
+		// TusDelete
+		bucket.Methods(http.MethodDelete).Path("/{object:.+}").HandlerFunc(
+			m.Handle(h.TusDeleteHandler)).
+			Queries("tus", "{tus:.+}").
+			Name("TusDelete")
+		// TusCreate
+		bucket.Methods(http.MethodPost).Path("/{object:.+}").HandlerFunc(
+			m.Handle(h.TusCreateHandler)).
+			Queries("tus", "").
+			Name("TusCreate")
+		bucket.Methods(http.MethodPost).HandlerFunc(
+			m.Handle(h.TusCreateHandler)).
+			Queries("tus", "").
+			Name("TusCreate")
 		bucket.Methods(http.MethodHead).Path("/{object:.+}").HandlerFunc(
 			m.Handle(h.HeadObjectHandler)).
 			Name("HeadObject")
@@ -363,6 +445,10 @@ func Attach(r *mux.Router, domains []string, m MaxClients, h Handler, center aut
 		bucket.Methods(http.MethodGet).Path("/{object:.+}").HandlerFunc(
 			m.Handle(h.GetObjectHandler)).
 			Name("GetObject")
+		// RenameObject
+		bucket.Methods(http.MethodPut).Path("/{object:.+}").Headers(FrostFSRenameSource, "").HandlerFunc(
+			m.Handle(h.RenameObjectHandler)).
+			Name("RenameObject")
 		// CopyObject
 		bucket.Methods(http.MethodPut).Path("/{object:.+}").Headers(hdrAmzCopySource, "").HandlerFunc(
 			m.Handle(h.CopyObjectHandler)).
@@ -377,6 +463,14 @@ func Attach(r *mux.Router, domains []string, m MaxClients, h Handler, center aut
 			m.Handle(h.PutObjectLegalHoldHandler)).
 			Queries("legal-hold", "").
 			Name("PutObjectLegalHold")
+		// AppendObject
+		bucket.Methods(http.MethodPut).Path("/{object:.+}").HandlerFunc(
+			m.Handle(h.AppendObjectHandler)).
+			Queries("append", "").
+			Name("AppendObject")
+		bucket.Methods(http.MethodPut).Path("/{object:.+}").Headers(AmzWriteOffsetBytes, "").HandlerFunc(
+			m.Handle(h.AppendObjectHandler)).
+			Name("AppendObject")
 
 		// PutObject
 		bucket.Methods(http.MethodPut).Path("/{object:.+}").HandlerFunc(
@@ -506,6 +600,16 @@ func Attach(r *mux.Router, domains []string, m MaxClients, h Handler, center aut
 			m.Handle(h.ListBucketObjectVersionsHandler)).
 			Queries("versions", "").
 			Name("ListBucketVersions")
+		// GetArchive
+		bucket.Methods(http.MethodGet).HandlerFunc(
+			m.Handle(h.GetArchiveHandler)).
+			Queries("archive", "{archive:.*}").
+			Name("GetArchive")
+		// SearchObjects
+		bucket.Methods(http.MethodGet).HandlerFunc(
+			m.Handle(h.SearchObjectsHandler)).
+			Queries("search", "").
+			Name("SearchObjects")
 		// ListObjectsV1 (Legacy)
 		bucket.Methods(http.MethodGet).HandlerFunc(
 			m.Handle(h.ListObjectsV1Handler)).
@@ -596,4 +700,9 @@ func Attach(r *mux.Router, domains []string, m MaxClients, h Handler, center aut
 	api.Methods(http.MethodGet).Path(SlashSeparator + SlashSeparator).HandlerFunc(
 		m.Handle(h.ListBucketsHandler)).
 		Name("ListBuckets")
+
+	// STS
+	api.Methods(http.MethodPost).Path(SlashSeparator).HandlerFunc(
+		m.Handle(h.STSHandler)).
+		Name("STS")
 }
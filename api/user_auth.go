@@ -3,9 +3,12 @@ package api
 import (
 	"context"
 	"net/http"
+	"net/url"
+	"strings"
 
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/auth"
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/errors"
+	"github.com/TrueCloudLab/frostfs-s3-gw/creds/tokens"
 	"github.com/gorilla/mux"
 	"go.uber.org/zap"
 )
@@ -19,6 +22,19 @@ var BoxData = KeyWrapper("__context_box_key")
 // ClientTime is an ID used to store client time.Time in a context.
 var ClientTime = KeyWrapper("__context_client_time")
 
+// SessionClaims is an ID used to store tokens.SessionClaims of temporary credentials in a context.
+var SessionClaims = KeyWrapper("__context_session_claims")
+
+// AccessKeyID is an ID used to store the access key ID of authenticated credentials in a context.
+var AccessKeyID = KeyWrapper("__context_access_key_id")
+
+// tusUploadRoutes are routes of the resumable upload which can be authenticated by the upload token.
+var tusUploadRoutes = map[string]struct{}{
+	"TusHead":   {},
+	"TusPatch":  {},
+	"TusDelete": {},
+}
+
 // AuthMiddleware adds user authentication via center to router using log for logging.
 func AuthMiddleware(log *zap.Logger, center auth.Center) mux.MiddlewareFunc {
 	return func(h http.Handler) http.Handler {
@@ -38,13 +54,65 @@ func AuthMiddleware(log *zap.Logger, center auth.Center) mux.MiddlewareFunc {
 					return
 				}
 			} else {
+				if box.UploadClaims != nil && !checkUploadScope(r, box.UploadClaims) {
+					log.Error("request is out of upload token scope",
+						zap.String("bucket", box.UploadClaims.Bucket),
+						zap.String("object", box.UploadClaims.Key),
+						zap.String("upload_id", box.UploadClaims.UploadID))
+					WriteErrorResponse(w, GetReqInfo(r.Context()), errors.GetAPIError(errors.ErrAccessDenied))
+					return
+				}
+
 				ctx = context.WithValue(r.Context(), BoxData, box.AccessBox)
+				ctx = context.WithValue(ctx, AccessKeyID, box.AccessKeyID)
 				if !box.ClientTime.IsZero() {
 					ctx = context.WithValue(ctx, ClientTime, box.ClientTime)
 				}
+				if box.SessionClaims != nil {
+					ctx = context.WithValue(ctx, SessionClaims, box.SessionClaims)
+					reqInfo := GetReqInfo(r.Context())
+					reqInfo.Subject = box.SessionClaims.Subject
+					if bucket, ok := checkBucketScope(r, box.SessionClaims.BucketPrefix); !ok {
+						log.Error("bucket is out of credentials scope",
+							zap.String("bucket", bucket),
+							zap.String("subject", reqInfo.Subject))
+						WriteErrorResponse(w, reqInfo, errors.GetAPIError(errors.ErrAccessDenied))
+						return
+					}
+				}
 			}
 
 			h.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
+
+// checkBucketScope checks that the request bucket and the bucket of the copy source
+// (CopyObject, UploadPartCopy) start with the prefix. The bucket out of scope is returned otherwise.
+func checkBucketScope(r *http.Request, prefix string) (string, bool) {
+	if bucket := GetReqInfo(r.Context()).BucketName; bucket != "" && !strings.HasPrefix(bucket, prefix) {
+		return bucket, false
+	}
+
+	src := r.Header.Get(AmzCopySource)
+	if src == "" {
+		return "", true
+	}
+	if u, err := url.Parse(src); err == nil {
+		src = u.Path
+	}
+	bucket := strings.SplitN(strings.TrimPrefix(src, "/"), "/", 2)[0]
+
+	return bucket, strings.HasPrefix(bucket, prefix)
+}
+
+// checkUploadScope checks that the request is the request to the resumable upload the upload token is issued for.
+func checkUploadScope(r *http.Request, claims *tokens.UploadClaims) bool {
+	reqInfo := GetReqInfo(r.Context())
+	if _, ok := tusUploadRoutes[reqInfo.API]; !ok {
+		return false
+	}
+
+	return reqInfo.BucketName == claims.Bucket && reqInfo.ObjectName == claims.Key &&
+		r.URL.Query().Get("tus") == claims.UploadID
+}
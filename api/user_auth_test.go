@@ -0,0 +1,68 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TrueCloudLab/frostfs-s3-gw/creds/tokens"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckBucketScope(t *testing.T) {
+	for _, tc := range []struct {
+		bucket     string
+		copySource string
+		outOfScope string
+		ok         bool
+	}{
+		{ok: true},
+		{bucket: "team-bucket", ok: true},
+		{bucket: "other-bucket", outOfScope: "other-bucket"},
+		{bucket: "team-bucket", copySource: "/team-src/obj?versionId=1", ok: true},
+		{bucket: "team-bucket", copySource: "other-src/obj", outOfScope: "other-src"},
+		{bucket: "team-bucket", copySource: "/other-src/dir%2Fobj", outOfScope: "other-src"},
+	} {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPut, "/", nil)
+		if tc.copySource != "" {
+			r.Header.Set(AmzCopySource, tc.copySource)
+		}
+		reqInfo := NewReqInfo(w, r, ObjectRequest{Bucket: tc.bucket})
+		r = r.WithContext(SetReqInfo(r.Context(), reqInfo))
+
+		bucket, ok := checkBucketScope(r, "team-")
+		require.Equal(t, tc.ok, ok, tc)
+		if !ok {
+			require.Equal(t, tc.outOfScope, bucket, tc)
+		}
+	}
+}
+
+func TestCheckUploadScope(t *testing.T) {
+	claims := &tokens.UploadClaims{Bucket: "bucket", Key: "dir/object", UploadID: "upload"}
+
+	for _, tc := range []struct {
+		api    string
+		bucket string
+		object string
+		query  string
+		ok     bool
+	}{
+		{api: "TusPatch", bucket: "bucket", object: "dir/object", query: "?tus=upload", ok: true},
+		{api: "TusHead", bucket: "bucket", object: "dir/object", query: "?tus=upload", ok: true},
+		{api: "TusDelete", bucket: "bucket", object: "dir/object", query: "?tus=upload", ok: true},
+		{api: "GetObject", bucket: "bucket", object: "dir/object", query: "?tus=upload"},
+		{api: "TusCreate", bucket: "bucket", object: "dir/object", query: "?tus="},
+		{api: "TusPatch", bucket: "other", object: "dir/object", query: "?tus=upload"},
+		{api: "TusPatch", bucket: "bucket", object: "dir/other", query: "?tus=upload"},
+		{api: "TusPatch", bucket: "bucket", object: "dir/object", query: "?tus=other"},
+	} {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPatch, "/"+tc.query, nil)
+		reqInfo := NewReqInfo(w, r, ObjectRequest{Bucket: tc.bucket, Object: tc.object, Method: tc.api})
+		r = r.WithContext(SetReqInfo(r.Context(), reqInfo))
+
+		require.Equal(t, tc.ok, checkUploadScope(r, claims), tc)
+	}
+}
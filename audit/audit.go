@@ -0,0 +1,317 @@
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"go.uber.org/zap"
+)
+
+type (
+	// Event describes a mutating operation.
+	Event struct {
+		Time      time.Time `json:"time"`
+		API       string    `json:"api"`
+		Actor     string    `json:"actor,omitempty"`
+		SourceIP  string    `json:"source_ip,omitempty"`
+		RequestID string    `json:"request_id"`
+		Bucket    string    `json:"bucket,omitempty"`
+		Object    string    `json:"object,omitempty"`
+		VersionID string    `json:"version_id,omitempty"`
+		// Before and After are short summaries of the changed state.
+		Before string `json:"before,omitempty"`
+		After  string `json:"after,omitempty"`
+	}
+
+	// Record is an Event linked to the previous record of the chain.
+	Record struct {
+		Seq      uint64 `json:"seq"`
+		PrevHash string `json:"prev_hash"`
+		Event
+		// Hash is a hex encoded SHA-256 of the record without Hash field.
+		Hash string `json:"hash,omitempty"`
+	}
+
+	// Segment is a sealed part of the chain. Signature is made by the gateway key
+	// over the hash of the last record.
+	Segment struct {
+		PublicKey string   `json:"public_key"`
+		Signature string   `json:"signature"`
+		Records   []Record `json:"records"`
+	}
+
+	// Storage saves sealed segments.
+	Storage interface {
+		// Put saves segment payload.
+		Put(ctx context.Context, firstSeq, lastSeq uint64, payload []byte) error
+		// List returns payloads of all segments of the chain identified by the public key.
+		List(ctx context.Context, chain *keys.PublicKey) ([][]byte, error)
+	}
+
+	// Config contains parameters of Logger.
+	Config struct {
+		Key     *keys.PrivateKey
+		Storage Storage
+		// MaxRecords is a number of records which triggers sealing before SealInterval is over.
+		MaxRecords   int
+		SealInterval time.Duration
+	}
+
+	// Logger appends events to the hash chain and periodically seals them to the storage.
+	Logger struct {
+		log *zap.Logger
+		cfg Config
+
+		mu       sync.Mutex
+		seq      uint64
+		lastHash string
+		pending  []Record
+
+		sealMu sync.Mutex
+		sealCh chan struct{}
+	}
+)
+
+const (
+	// DefaultMaxRecords is a default number of records in a segment.
+	DefaultMaxRecords = 1000
+	// DefaultSealInterval is a default interval to seal records.
+	DefaultSealInterval = time.Minute
+)
+
+// genesisHash is a previous hash of the first record of the chain.
+var genesisHash = hex.EncodeToString(make([]byte, sha256.Size))
+
+// ComputeHash calculates hash of the record.
+func (r Record) ComputeHash() string {
+	r.Hash = ""
+	data, err := json.Marshal(r)
+	if err != nil {
+		// record contains only marshalable fields
+		panic(err)
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// NewLogger creates Logger which continues the chain of the gateway key found in the storage.
+func NewLogger(ctx context.Context, log *zap.Logger, cfg Config) (*Logger, error) {
+	if cfg.MaxRecords <= 0 {
+		cfg.MaxRecords = DefaultMaxRecords
+	}
+	if cfg.SealInterval <= 0 {
+		cfg.SealInterval = DefaultSealInterval
+	}
+
+	l := &Logger{
+		log:      log,
+		cfg:      cfg,
+		lastHash: genesisHash,
+		sealCh:   make(chan struct{}, 1),
+	}
+
+	payloads, err := cfg.Storage.List(ctx, cfg.Key.PublicKey())
+	if err != nil {
+		return nil, fmt.Errorf("list audit segments: %w", err)
+	}
+
+	for _, payload := range payloads {
+		var seg Segment
+		if err = json.Unmarshal(payload, &seg); err != nil {
+			return nil, fmt.Errorf("decode audit segment: %w", err)
+		}
+		if len(seg.Records) == 0 {
+			continue
+		}
+
+		last := seg.Records[len(seg.Records)-1]
+		if last.Seq > l.seq {
+			l.seq = last.Seq
+			l.lastHash = last.Hash
+		}
+	}
+
+	return l, nil
+}
+
+// Audit appends event to the chain.
+func (l *Logger) Audit(_ context.Context, event *Event) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	event.Time = event.Time.UTC()
+
+	l.mu.Lock()
+	l.seq++
+	rec := Record{
+		Seq:      l.seq,
+		PrevHash: l.lastHash,
+		Event:    *event,
+	}
+	rec.Hash = rec.ComputeHash()
+	l.lastHash = rec.Hash
+	l.pending = append(l.pending, rec)
+	full := len(l.pending) >= l.cfg.MaxRecords
+	l.mu.Unlock()
+
+	if full {
+		select {
+		case l.sealCh <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Run seals records periodically until context is done.
+func (l *Logger) Run(ctx context.Context) {
+	ticker := time.NewTicker(l.cfg.SealInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-l.sealCh:
+		}
+
+		if err := l.Seal(ctx); err != nil {
+			l.log.Error("couldn't seal audit records", zap.Error(err))
+		}
+	}
+}
+
+// Seal saves pending records to the storage as a signed segment.
+// Records are kept pending if they can't be saved.
+func (l *Logger) Seal(ctx context.Context) error {
+	l.sealMu.Lock()
+	defer l.sealMu.Unlock()
+
+	l.mu.Lock()
+	records := l.pending
+	l.pending = nil
+	l.mu.Unlock()
+
+	if len(records) == 0 {
+		return nil
+	}
+
+	if err := l.put(ctx, records); err != nil {
+		l.mu.Lock()
+		l.pending = append(records, l.pending...)
+		l.mu.Unlock()
+		return err
+	}
+
+	l.log.Debug("audit records are sealed",
+		zap.Uint64("first", records[0].Seq), zap.Uint64("last", records[len(records)-1].Seq))
+
+	return nil
+}
+
+func (l *Logger) put(ctx context.Context, records []Record) error {
+	seg, err := NewSegment(l.cfg.Key, records)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(seg)
+	if err != nil {
+		return fmt.Errorf("marshal audit segment: %w", err)
+	}
+
+	if err = l.cfg.Storage.Put(ctx, records[0].Seq, records[len(records)-1].Seq, payload); err != nil {
+		return fmt.Errorf("put audit segment: %w", err)
+	}
+
+	return nil
+}
+
+// NewSegment creates segment of records signed by the key.
+func NewSegment(key *keys.PrivateKey, records []Record) (*Segment, error) {
+	if len(records) == 0 {
+		return nil, errors.New("empty segment")
+	}
+
+	lastHash, err := hex.DecodeString(records[len(records)-1].Hash)
+	if err != nil {
+		return nil, fmt.Errorf("invalid record hash: %w", err)
+	}
+
+	return &Segment{
+		PublicKey: hex.EncodeToString(key.PublicKey().Bytes()),
+		Signature: hex.EncodeToString(key.Sign(lastHash)),
+		Records:   records,
+	}, nil
+}
+
+// Verify checks that segments form a continuous chain of records starting from the first one,
+// hashes of all records are valid and segments are signed by the chain key.
+func Verify(chain *keys.PublicKey, segments []*Segment) error {
+	sort.Slice(segments, func(i, j int) bool {
+		return firstSeq(segments[i]) < firstSeq(segments[j])
+	})
+
+	var (
+		seq      uint64
+		lastHash = genesisHash
+		chainKey = hex.EncodeToString(chain.Bytes())
+	)
+
+	for _, seg := range segments {
+		if len(seg.Records) == 0 {
+			return errors.New("empty segment")
+		}
+
+		if seg.PublicKey != chainKey {
+			return fmt.Errorf("segment %d: unexpected public key %s", firstSeq(seg), seg.PublicKey)
+		}
+
+		for _, rec := range seg.Records {
+			if rec.Seq != seq+1 {
+				return fmt.Errorf("gap in chain: expected record %d, got %d", seq+1, rec.Seq)
+			}
+			if rec.PrevHash != lastHash {
+				return fmt.Errorf("record %d: previous hash mismatch", rec.Seq)
+			}
+			if rec.ComputeHash() != rec.Hash {
+				return fmt.Errorf("record %d: hash mismatch", rec.Seq)
+			}
+
+			seq, lastHash = rec.Seq, rec.Hash
+		}
+
+		signature, err := hex.DecodeString(seg.Signature)
+		if err != nil {
+			return fmt.Errorf("segment %d: invalid signature: %w", firstSeq(seg), err)
+		}
+
+		hash, err := hex.DecodeString(lastHash)
+		if err != nil {
+			return fmt.Errorf("segment %d: invalid hash: %w", firstSeq(seg), err)
+		}
+
+		digest := sha256.Sum256(hash)
+		if !chain.Verify(signature, digest[:]) {
+			return fmt.Errorf("segment %d: invalid signature", firstSeq(seg))
+		}
+	}
+
+	return nil
+}
+
+func firstSeq(seg *Segment) uint64 {
+	if len(seg.Records) == 0 {
+		return 0
+	}
+	return seg.Records[0].Seq
+}
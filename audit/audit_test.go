@@ -0,0 +1,127 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type storageMock struct {
+	payloads [][]byte
+	err      error
+}
+
+func (s *storageMock) Put(_ context.Context, _, _ uint64, payload []byte) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.payloads = append(s.payloads, payload)
+	return nil
+}
+
+func (s *storageMock) List(context.Context, *keys.PublicKey) ([][]byte, error) {
+	return s.payloads, nil
+}
+
+func (s *storageMock) segments(t *testing.T) []*Segment {
+	res := make([]*Segment, len(s.payloads))
+	for i := range s.payloads {
+		res[i] = new(Segment)
+		require.NoError(t, json.Unmarshal(s.payloads[i], res[i]))
+	}
+	return res
+}
+
+func newTestLogger(t *testing.T, key *keys.PrivateKey, storage Storage) *Logger {
+	l, err := NewLogger(context.Background(), zap.NewNop(), Config{Key: key, Storage: storage})
+	require.NoError(t, err)
+	return l
+}
+
+func auditEvents(l *Logger, from, to int) {
+	for i := from; i < to; i++ {
+		l.Audit(context.Background(), &Event{
+			API:       "PutObject",
+			Actor:     "owner",
+			RequestID: strconv.Itoa(i),
+			Bucket:    "bucket",
+			Object:    "obj" + strconv.Itoa(i),
+			After:     "version=" + strconv.Itoa(i),
+		})
+	}
+}
+
+func TestChain(t *testing.T) {
+	ctx := context.Background()
+	key, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	storage := &storageMock{}
+
+	l := newTestLogger(t, key, storage)
+	auditEvents(l, 0, 3)
+	require.NoError(t, l.Seal(ctx))
+	auditEvents(l, 3, 5)
+	require.NoError(t, l.Seal(ctx))
+	require.NoError(t, l.Seal(ctx)) // no records, no segment
+
+	// restarted gateway continues the chain
+	l = newTestLogger(t, key, storage)
+	auditEvents(l, 5, 6)
+	require.NoError(t, l.Seal(ctx))
+
+	require.Len(t, storage.payloads, 3)
+	require.NoError(t, Verify(key.PublicKey(), storage.segments(t)))
+
+	t.Run("tampered record", func(t *testing.T) {
+		segments := storage.segments(t)
+		segments[1].Records[0].Actor = "another"
+		require.ErrorContains(t, Verify(key.PublicKey(), segments), "record 4: hash mismatch")
+	})
+
+	t.Run("rehashed record", func(t *testing.T) {
+		segments := storage.segments(t)
+		segments[1].Records[1].After = "version=another"
+		segments[1].Records[1].Hash = segments[1].Records[1].ComputeHash()
+		require.ErrorContains(t, Verify(key.PublicKey(), segments), "invalid signature")
+	})
+
+	t.Run("missed segment", func(t *testing.T) {
+		segments := storage.segments(t)
+		segments = append(segments[:1], segments[2:]...)
+		require.ErrorContains(t, Verify(key.PublicKey(), segments), "gap in chain: expected record 4, got 6")
+	})
+
+	t.Run("another key", func(t *testing.T) {
+		anotherKey, err := keys.NewPrivateKey()
+		require.NoError(t, err)
+		require.ErrorContains(t, Verify(anotherKey.PublicKey(), storage.segments(t)), "unexpected public key")
+	})
+}
+
+func TestSealFailure(t *testing.T) {
+	ctx := context.Background()
+	key, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	storage := &storageMock{}
+
+	l := newTestLogger(t, key, storage)
+	auditEvents(l, 0, 2)
+
+	storage.err = errors.New("unavailable")
+	require.Error(t, l.Seal(ctx))
+
+	storage.err = nil
+	auditEvents(l, 2, 3)
+	require.NoError(t, l.Seal(ctx))
+
+	segments := storage.segments(t)
+	require.Len(t, segments, 1)
+	require.Len(t, segments[0].Records, 3)
+	require.NoError(t, Verify(key.PublicKey(), segments))
+}
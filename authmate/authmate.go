@@ -118,6 +118,33 @@ type (
 		SecretAddress  string
 		GatePrivateKey *keys.PrivateKey
 	}
+
+	// IssueTemporarySecretOptions contains options for passing to Agent.IssueTemporarySecret method.
+	// If GatesData is set, its tokens are packed into the access box as is,
+	// otherwise new tokens are signed by FrostFSKey.
+	IssueTemporarySecretOptions struct {
+		Container         cid.ID
+		Owner             user.ID
+		FrostFSKey        *keys.PrivateKey
+		GatesPublicKeys   []*keys.PublicKey
+		GatesData         []*accessbox.GateData
+		Policies          []*accessbox.ContainerPolicy
+		EACLRules         []byte
+		SessionTokenRules []byte
+		SkipSessionRules  bool
+		Lifetime          time.Duration
+		// Subject and BucketPrefix are put into the session token of the credentials.
+		Subject      string
+		BucketPrefix string
+	}
+
+	// TemporarySecret contains temporary credentials issued by Agent.IssueTemporarySecret method.
+	TemporarySecret struct {
+		AccessKeyID     string
+		SecretAccessKey string
+		SessionToken    string
+		Expiration      time.Time
+	}
 )
 
 // lifetimeOptions holds FrostFS epochs, iat -- epoch which the token was issued at, exp -- epoch when the token expires.
@@ -288,6 +315,79 @@ func (a *Agent) IssueSecret(ctx context.Context, w io.Writer, options *IssueSecr
 	return nil
 }
 
+// IssueTemporarySecret creates a short-lived access box, puts it in the FrostFS network
+// and returns temporary credentials with a session token bound to them.
+func (a *Agent) IssueTemporarySecret(ctx context.Context, options *IssueTemporarySecretOptions) (*TemporarySecret, error) {
+	var (
+		err      error
+		lifetime lifetimeOptions
+	)
+
+	expiration := time.Now().Add(options.Lifetime)
+	lifetime.Iat, lifetime.Exp, err = a.frostFS.TimeToEpoch(ctx, expiration)
+	if err != nil {
+		return nil, fmt.Errorf("fetch time to epoch: %w", err)
+	}
+
+	gatesData := options.GatesData
+	if len(gatesData) == 0 {
+		gatesData, err = createTokens(&IssueSecretOptions{
+			FrostFSKey:        options.FrostFSKey,
+			GatesPublicKeys:   options.GatesPublicKeys,
+			EACLRules:         options.EACLRules,
+			SessionTokenRules: options.SessionTokenRules,
+			SkipSessionRules:  options.SkipSessionRules,
+		}, lifetime)
+		if err != nil {
+			return nil, fmt.Errorf("create tokens: %w", err)
+		}
+	}
+
+	gatesKeys := make([]*keys.PublicKey, len(gatesData))
+	for i, gateData := range gatesData {
+		gatesKeys[i] = gateData.GateKey
+	}
+
+	box, secrets, err := accessbox.PackTokens(gatesData)
+	if err != nil {
+		return nil, fmt.Errorf("pack tokens: %w", err)
+	}
+	// the box secret is never returned, so the credentials are used only with the session token
+	box.Temporary = true
+
+	for _, policy := range options.Policies {
+		box.ContainerPolicy = append(box.ContainerPolicy, &accessbox.AccessBox_ContainerPolicy{
+			LocationConstraint: policy.LocationConstraint,
+			Policy:             policy.Policy.Marshal(),
+		})
+	}
+
+	addr, err := tokens.
+		New(a.frostFS, secrets.EphemeralKey, cache.DefaultAccessBoxConfig(a.log)).
+		Put(ctx, options.Container, options.Owner, box, lifetime.Exp, gatesKeys...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to put temporary access box: %w", err)
+	}
+
+	sessionToken, secretAccessKey, err := tokens.NewSessionToken(secrets.AccessKey, tokens.SessionClaims{
+		Expiration:   expiration.Unix(),
+		Subject:      options.Subject,
+		BucketPrefix: options.BucketPrefix,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create session token: %w", err)
+	}
+
+	objID := addr.Object()
+
+	return &TemporarySecret{
+		AccessKeyID:     addr.Container().EncodeToString() + "0" + objID.EncodeToString(),
+		SecretAccessKey: secretAccessKey,
+		SessionToken:    sessionToken,
+		Expiration:      expiration,
+	}, nil
+}
+
 // ObtainSecret receives an existing secret access key from FrostFS and
 // writes to io.Writer the secret access key.
 func (a *Agent) ObtainSecret(ctx context.Context, w io.Writer, options *ObtainSecretOptions) error {
@@ -13,7 +13,9 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/TrueCloudLab/frostfs-s3-gw/admin"
 	"github.com/TrueCloudLab/frostfs-s3-gw/api"
+	"github.com/TrueCloudLab/frostfs-s3-gw/audit"
 	"github.com/TrueCloudLab/frostfs-s3-gw/authmate"
 	"github.com/TrueCloudLab/frostfs-s3-gw/internal/frostfs"
 	"github.com/TrueCloudLab/frostfs-s3-gw/internal/version"
@@ -38,8 +40,9 @@ const (
 	poolStreamTimeout      = 10 * time.Second
 
 	// a month.
-	defaultLifetime          = 30 * 24 * time.Hour
-	defaultPresignedLifetime = 12 * time.Hour
+	defaultLifetime           = 30 * 24 * time.Hour
+	defaultPresignedLifetime  = 12 * time.Hour
+	defaultAdminTokenLifetime = time.Hour
 )
 
 type PoolConfig struct {
@@ -77,6 +80,7 @@ var (
 	containerPolicies        string
 	awcCliCredFile           string
 	timeoutFlag              time.Duration
+	gatePublicKeyFlag        string
 
 	// pool timeouts flag.
 	poolDialTimeoutFlag        time.Duration
@@ -176,6 +180,53 @@ func appCommands() []*cli.Command {
 		issueSecret(),
 		obtainSecret(),
 		generatePresignedURL(),
+		generateAdminToken(),
+		verifyAudit(),
+	}
+}
+
+func generateAdminToken() *cli.Command {
+	return &cli.Command{
+		Name:  "generate-admin-token",
+		Usage: "Generate a token to access admin API of the gateway",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "wallet",
+				Value:       "",
+				Usage:       "path to the wallet of the gateway",
+				Required:    true,
+				Destination: &walletPathFlag,
+			},
+			&cli.StringFlag{
+				Name:        "address",
+				Value:       "",
+				Usage:       "address of wallet account",
+				Required:    false,
+				Destination: &accountAddressFlag,
+			},
+			&cli.DurationFlag{
+				Name:        "lifetime",
+				Usage:       `Lifetime of the token`,
+				Required:    false,
+				Destination: &lifetimeFlag,
+				Value:       defaultAdminTokenLifetime,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			password := wallet.GetPassword(viper.GetViper(), envWalletPassphrase)
+			key, err := wallet.GetKeyFromPath(walletPathFlag, accountAddressFlag, password)
+			if err != nil {
+				return cli.Exit(fmt.Sprintf("failed to load frostfs private key: %s", err), 1)
+			}
+
+			token, err := admin.NewToken(key, lifetimeFlag)
+			if err != nil {
+				return cli.Exit(fmt.Sprintf("failed to generate admin token: %s", err), 2)
+			}
+
+			fmt.Println(token)
+			return nil
+		},
 	}
 }
 
@@ -683,7 +734,114 @@ func obtainSecret() *cli.Command {
 	return command
 }
 
+func verifyAudit() *cli.Command {
+	return &cli.Command{
+		Name:  "verify-audit",
+		Usage: "Verify audit log of the gateway stored in FrostFS",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "wallet",
+				Value:       "",
+				Usage:       "path to the wallet",
+				Required:    true,
+				Destination: &walletPathFlag,
+			},
+			&cli.StringFlag{
+				Name:        "address",
+				Value:       "",
+				Usage:       "address of wallet account",
+				Required:    false,
+				Destination: &accountAddressFlag,
+			},
+			&cli.StringFlag{
+				Name:        "peer",
+				Value:       "",
+				Usage:       "address of frostfs peer to connect to",
+				Required:    true,
+				Destination: &peerAddressFlag,
+			},
+			&cli.StringFlag{
+				Name:        "container-id",
+				Usage:       "id of the container with audit log",
+				Required:    true,
+				Destination: &containerIDFlag,
+			},
+			&cli.StringFlag{
+				Name:        "gate-public-key",
+				Usage:       "public key of the gateway which audit log is verified",
+				Required:    true,
+				Destination: &gatePublicKeyFlag,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			ctx, log := prepare()
+
+			password := wallet.GetPassword(viper.GetViper(), envWalletPassphrase)
+			key, err := wallet.GetKeyFromPath(walletPathFlag, accountAddressFlag, password)
+			if err != nil {
+				return cli.Exit(fmt.Sprintf("failed to load frostfs private key: %s", err), 1)
+			}
+
+			var cnrID cid.ID
+			if err = cnrID.DecodeString(containerIDFlag); err != nil {
+				return cli.Exit(fmt.Sprintf("failed to parse audit container id: %s", err), 1)
+			}
+
+			gateKey, err := keys.NewPublicKeyFromString(gatePublicKeyFlag)
+			if err != nil {
+				return cli.Exit(fmt.Sprintf("failed to parse gate public key: %s", err), 1)
+			}
+
+			ctx, cancel := context.WithTimeout(ctx, timeoutFlag)
+			defer cancel()
+
+			p, err := createPool(ctx, log, PoolConfig{
+				Key:                &key.PrivateKey,
+				Address:            peerAddressFlag,
+				DialTimeout:        poolDialTimeout,
+				HealthcheckTimeout: poolHealthcheckTimeout,
+				StreamTimeout:      poolStreamTimeout,
+				RebalanceInterval:  poolRebalanceInterval,
+			})
+			if err != nil {
+				return cli.Exit(fmt.Sprintf("failed to create FrostFS component: %s", err), 2)
+			}
+
+			payloads, err := frostfs.NewAuditStorage(p, cnrID, key).List(ctx, gateKey)
+			if err != nil {
+				return cli.Exit(fmt.Sprintf("failed to fetch audit log: %s", err), 3)
+			}
+
+			var records int
+			segments := make([]*audit.Segment, len(payloads))
+			for i := range payloads {
+				segments[i] = new(audit.Segment)
+				if err = json.Unmarshal(payloads[i], segments[i]); err != nil {
+					return cli.Exit(fmt.Sprintf("failed to decode audit segment: %s", err), 3)
+				}
+				records += len(segments[i].Records)
+			}
+
+			if err = audit.Verify(gateKey, segments); err != nil {
+				return cli.Exit(fmt.Sprintf("audit log is invalid: %s", err), 4)
+			}
+
+			fmt.Printf("audit log is valid: %d segments, %d records\n", len(segments), records)
+			return nil
+		},
+	}
+}
+
 func createFrostFS(ctx context.Context, log *zap.Logger, cfg PoolConfig) (authmate.FrostFS, error) {
+	p, err := createPool(ctx, log, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return frostfs.NewAuthmateFrostFS(p), nil
+}
+
+func createPool(ctx context.Context, log *zap.Logger, cfg PoolConfig) (*pool.Pool, error) {
 	log.Debug("prepare connection pool")
 
 	var prm pool.InitParameters
@@ -703,5 +861,5 @@ func createFrostFS(ctx context.Context, log *zap.Logger, cfg PoolConfig) (authma
 		return nil, fmt.Errorf("dial pool: %w", err)
 	}
 
-	return frostfs.NewAuthmateFrostFS(p), nil
+	return p, nil
 }
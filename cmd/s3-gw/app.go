@@ -15,17 +15,24 @@ import (
 
 	"github.com/TrueCloudLab/frostfs-s3-gw/api"
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/auth"
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/auth/oidc"
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/cache"
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/handler"
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/layer"
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/notifications"
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/resolver"
+	"github.com/TrueCloudLab/frostfs-s3-gw/audit"
+	"github.com/TrueCloudLab/frostfs-s3-gw/authmate"
+	"github.com/TrueCloudLab/frostfs-s3-gw/health"
 	"github.com/TrueCloudLab/frostfs-s3-gw/internal/frostfs"
+	"github.com/TrueCloudLab/frostfs-s3-gw/internal/tracing"
 	"github.com/TrueCloudLab/frostfs-s3-gw/internal/version"
 	"github.com/TrueCloudLab/frostfs-s3-gw/internal/wallet"
 	"github.com/TrueCloudLab/frostfs-s3-gw/metrics"
+	cid "github.com/TrueCloudLab/frostfs-sdk-go/container/id"
 	"github.com/TrueCloudLab/frostfs-sdk-go/netmap"
 	"github.com/TrueCloudLab/frostfs-sdk-go/pool"
+	"github.com/TrueCloudLab/frostfs-sdk-go/user"
 	"github.com/gorilla/mux"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/spf13/viper"
@@ -43,15 +50,24 @@ type (
 		nc   *notifications.Controller
 		obj  layer.Client
 		api  api.Handler
+		tree *frostfs.TreeClient
+		// caches collects statistics of all gateway caches.
+		caches *cache.Observer
+		// audit is nil if audit log is disabled.
+		audit *audit.Logger
 
 		servers []Server
 
 		metrics        *metrics.AppMetrics
+		health         *health.Checker
 		bucketResolver *resolver.BucketResolver
 		services       []*Service
 		settings       *appSettings
 		maxClients     api.MaxClients
 
+		// tracingShutdown flushes pending spans.
+		tracingShutdown func(context.Context) error
+
 		webDone chan struct{}
 		wrkDone chan struct{}
 	}
@@ -59,6 +75,9 @@ type (
 	appSettings struct {
 		logLevel zap.AtomicLevel
 		policies *placementPolicy
+		stsRoles *stsRoles
+		// webIdentity is nil if there are no trusted identity providers.
+		webIdentity *oidc.Verifier
 	}
 
 	Logger struct {
@@ -71,20 +90,28 @@ type (
 		defaultPolicy netmap.PlacementPolicy
 		regionMap     map[string]netmap.PlacementPolicy
 	}
+
+	stsRoles struct {
+		mu    sync.RWMutex
+		roles map[string]*handler.STSRole
+	}
 )
 
 func newApp(ctx context.Context, log *Logger, v *viper.Viper) *App {
 	conns, key := getPool(ctx, log.logger, v)
 
+	caches := cache.NewObserver(int64(v.GetSizeInBytes(cfgCacheMemoryBudget)))
+
 	// prepare auth center
-	ctr := auth.New(frostfs.NewAuthmateFrostFS(conns), key, v.GetStringSlice(cfgAllowedAccessKeyIDPrefixes), getAccessBoxCacheConfig(v, log.logger))
+	ctr := auth.New(frostfs.NewAuthmateFrostFS(conns), key, v.GetStringSlice(cfgAllowedAccessKeyIDPrefixes), getAccessBoxCacheConfig(v, log.logger, caches))
 
 	app := &App{
-		ctr:  ctr,
-		log:  log.logger,
-		cfg:  v,
-		pool: conns,
-		key:  key,
+		ctr:    ctr,
+		log:    log.logger,
+		cfg:    v,
+		pool:   conns,
+		key:    key,
+		caches: caches,
 
 		webDone: make(chan struct{}, 1),
 		wrkDone: make(chan struct{}, 1),
@@ -99,20 +126,31 @@ func newApp(ctx context.Context, log *Logger, v *viper.Viper) *App {
 }
 
 func (a *App) init(ctx context.Context) {
-	a.initAPI(ctx)
+	a.initTracing()
 	a.initMetrics()
+	a.initAPI(ctx)
+	a.initHealth()
 	a.initServers(ctx)
 }
 
 func (a *App) initLayer(ctx context.Context) {
 	a.initResolver()
 
-	treeServiceEndpoint := a.cfg.GetString(cfgTreeServiceEndpoint)
-	treeService, err := frostfs.NewTreeClient(ctx, treeServiceEndpoint, a.key)
+	treeEndpoints := fetchTreeEndpoints(a.log, a.cfg)
+	treeService, err := frostfs.NewTreeClient(ctx, frostfs.TreeClientConfig{
+		Endpoints:            treeEndpoints,
+		HealthcheckTimeout:   getHealthcheckTimeout(a.cfg),
+		RebalanceInterval:    getRebalanceInterval(a.cfg),
+		Key:                  a.key,
+		Log:                  a.log,
+		Metrics:              a.metrics,
+		SlowRequestThreshold: a.cfg.GetDuration(cfgTreeSlowRequestThreshold),
+	})
 	if err != nil {
 		a.log.Fatal("failed to create tree service", zap.Error(err))
 	}
-	a.log.Info("init tree service", zap.String("endpoint", treeServiceEndpoint))
+	a.tree = treeService
+	a.log.Info("init tree service", zap.Int("endpoints", len(treeEndpoints)))
 
 	// prepare random key for anonymous requests
 	randomKey, err := keys.NewPrivateKey()
@@ -121,16 +159,22 @@ func (a *App) initLayer(ctx context.Context) {
 	}
 
 	layerCfg := &layer.Config{
-		Caches: getCacheOptions(a.cfg, a.log),
+		Caches: getCacheOptions(a.cfg, a.log, a.caches),
 		AnonKey: layer.AnonymousKey{
 			Key: randomKey,
 		},
-		Resolver:    a.bucketResolver,
-		TreeService: treeService,
+		Resolver:     a.bucketResolver,
+		TreeService:  treeService,
+		Metrics:      a.metrics,
+		PayloadCache: a.initPayloadCache(),
+
+		AppendMaxSegments: a.cfg.GetInt(cfgAppendMaxSegments),
+		UsageOwners:       getUsageOwners(a.cfg, a.log),
 	}
 
 	// prepare object layer
 	a.obj = layer.NewLayer(a.log, frostfs.NewFrostFS(a.pool), layerCfg)
+	a.metrics.RegisterUsage(a.obj)
 
 	if a.cfg.GetBool(cfgEnableNATS) {
 		nopts := getNotificationsOptions(a.cfg, a.log)
@@ -142,6 +186,13 @@ func (a *App) initLayer(ctx context.Context) {
 		if err = a.obj.Initialize(ctx, a.nc); err != nil {
 			a.log.Fatal("couldn't initialize layer", zap.Error(err))
 		}
+
+		if subject := a.cfg.GetString(cfgNATSCacheInvalidationSubject); subject != "" {
+			if err = a.obj.InitializeCacheInvalidation(ctx, a.nc, subject); err != nil {
+				a.log.Fatal("couldn't initialize cache invalidation", zap.Error(err))
+			}
+			a.log.Info("cache invalidation is enabled", zap.String("subject", subject))
+		}
 	}
 }
 
@@ -151,9 +202,15 @@ func newAppSettings(log *Logger, v *viper.Viper) *appSettings {
 		log.logger.Fatal("failed to create new policy mapping", zap.Error(err))
 	}
 
+	roles, err := fetchSTSRoles(v)
+	if err != nil {
+		log.logger.Fatal("failed to fetch sts roles", zap.Error(err))
+	}
+
 	return &appSettings{
 		logLevel: log.lvl,
 		policies: policies,
+		stsRoles: newSTSRoles(roles),
 	}
 }
 
@@ -168,11 +225,40 @@ func getDefaultPolicyValue(v *viper.Viper) string {
 
 func (a *App) initAPI(ctx context.Context) {
 	a.initLayer(ctx)
+	a.initAudit(ctx)
 	a.initHandler()
 }
 
+func (a *App) initTracing() {
+	cfg := tracing.Config{
+		Enabled:       a.cfg.GetBool(cfgTracingEnabled),
+		Exporter:      a.cfg.GetString(cfgTracingExporter),
+		Endpoint:      a.cfg.GetString(cfgTracingEndpoint),
+		File:          a.cfg.GetString(cfgTracingFile),
+		SamplingRatio: a.cfg.GetFloat64(cfgTracingSamplingRatio),
+		Timeout:       a.cfg.GetDuration(cfgTracingTimeout),
+		Service:       "frostfs-s3-gw",
+		Version:       version.Version,
+	}
+
+	var err error
+	if a.tracingShutdown, err = tracing.Setup(cfg); err != nil {
+		a.log.Fatal("failed to init tracing", zap.Error(err))
+	}
+
+	if cfg.Enabled {
+		a.log.Info("tracing is enabled", zap.String("exporter", cfg.Exporter))
+	}
+}
+
 func (a *App) initMetrics() {
-	a.metrics = metrics.NewAppMetrics(a.log, frostfs.NewPoolStatistic(a.pool), a.cfg.GetBool(cfgPrometheusEnabled))
+	requestsCfg := metrics.RequestsConfig{
+		DurationBuckets: fetchDurationBuckets(a.log, a.cfg),
+		BucketLabel:     a.cfg.GetBool(cfgPrometheusBucketLabel),
+		MaxBucketLabels: a.cfg.GetInt(cfgPrometheusMaxBucketLabels),
+	}
+	a.metrics = metrics.NewAppMetrics(a.log, frostfs.NewPoolStatistic(a.pool), requestsCfg, a.cfg.GetBool(cfgPrometheusEnabled))
+	a.metrics.RegisterCaches(a.caches)
 }
 
 func (a *App) initResolver() {
@@ -216,6 +302,24 @@ func newMaxClients(cfg *viper.Viper) api.MaxClients {
 	return api.NewMaxClientsMiddleware(maxClientsCount, maxClientsDeadline)
 }
 
+// getHealthcheckTimeout returns timeout of healthchecks of storage nodes and tree service endpoints.
+func getHealthcheckTimeout(cfg *viper.Viper) time.Duration {
+	healthCheckTimeout := cfg.GetDuration(cfgHealthcheckTimeout)
+	if healthCheckTimeout <= 0 {
+		healthCheckTimeout = defaultHealthcheckTimeout
+	}
+	return healthCheckTimeout
+}
+
+// getRebalanceInterval returns interval of healthchecks of storage nodes and tree service endpoints.
+func getRebalanceInterval(cfg *viper.Viper) time.Duration {
+	rebalanceInterval := cfg.GetDuration(cfgRebalanceInterval)
+	if rebalanceInterval <= 0 {
+		rebalanceInterval = defaultRebalanceInterval
+	}
+	return rebalanceInterval
+}
+
 func getPool(ctx context.Context, logger *zap.Logger, cfg *viper.Viper) (*pool.Pool, *keys.PrivateKey) {
 	var prm pool.InitParameters
 
@@ -244,17 +348,8 @@ func getPool(ctx context.Context, logger *zap.Logger, cfg *viper.Viper) (*pool.P
 	}
 	prm.SetNodeStreamTimeout(streamTimeout)
 
-	healthCheckTimeout := cfg.GetDuration(cfgHealthcheckTimeout)
-	if healthCheckTimeout <= 0 {
-		healthCheckTimeout = defaultHealthcheckTimeout
-	}
-	prm.SetHealthcheckTimeout(healthCheckTimeout)
-
-	rebalanceInterval := cfg.GetDuration(cfgRebalanceInterval)
-	if rebalanceInterval <= 0 {
-		rebalanceInterval = defaultRebalanceInterval
-	}
-	prm.SetClientRebalanceInterval(rebalanceInterval)
+	prm.SetHealthcheckTimeout(getHealthcheckTimeout(cfg))
+	prm.SetClientRebalanceInterval(getRebalanceInterval(cfg))
 
 	errorThreshold := cfg.GetUint32(cfgPoolErrorThreshold)
 	if errorThreshold <= 0 {
@@ -332,6 +427,23 @@ func (p *placementPolicy) update(defaultPolicy string, regionPolicyFilepath stri
 	return nil
 }
 
+func newSTSRoles(roles map[string]*handler.STSRole) *stsRoles {
+	return &stsRoles{roles: roles}
+}
+
+func (r *stsRoles) Role(name string) (*handler.STSRole, bool) {
+	r.mu.RLock()
+	role, ok := r.roles[name]
+	r.mu.RUnlock()
+	return role, ok
+}
+
+func (r *stsRoles) update(roles map[string]*handler.STSRole) {
+	r.mu.Lock()
+	r.roles = roles
+	r.mu.Unlock()
+}
+
 func remove(list []string, element string) []string {
 	for i, item := range list {
 		if item == element {
@@ -377,6 +489,8 @@ func (a *App) Serve(ctx context.Context) {
 	srv.ErrorLog = zap.NewStdLog(a.log)
 
 	a.startServices()
+	a.runAudit(ctx)
+	go a.runUsage(ctx)
 
 	for i := range a.servers {
 		go func(i int) {
@@ -401,14 +515,23 @@ LOOP:
 		}
 	}
 
+	a.drain()
+
 	ctx, cancel := shutdownContext()
 	defer cancel()
 
 	a.log.Info("stopping server", zap.Error(srv.Shutdown(ctx)))
 
+	a.sealAudit(ctx)
+	a.flushUsage(ctx)
+
 	a.metrics.Shutdown()
 	a.stopServices()
 
+	if err := a.tracingShutdown(ctx); err != nil {
+		a.log.Warn("failed to flush traces", zap.Error(err))
+	}
+
 	close(a.webDone)
 }
 
@@ -457,6 +580,18 @@ func (a *App) updateSettings() {
 	if err := a.settings.policies.update(getDefaultPolicyValue(a.cfg), a.cfg.GetString(cfgPolicyRegionMapFile)); err != nil {
 		a.log.Warn("policies won't be updated", zap.Error(err))
 	}
+
+	if roles, err := fetchSTSRoles(a.cfg); err != nil {
+		a.log.Warn("sts roles won't be updated", zap.Error(err))
+	} else {
+		a.settings.stsRoles.update(roles)
+	}
+
+	if a.settings.webIdentity != nil {
+		if err := a.settings.webIdentity.Update(fetchWebIdentityProviders(a.cfg)); err != nil {
+			a.log.Warn("web identity providers won't be updated", zap.Error(err))
+		}
+	}
 }
 
 func (a *App) startServices() {
@@ -469,6 +604,18 @@ func (a *App) startServices() {
 	prometheusService := NewPrometheusService(a.cfg, a.log, a.metrics.Handler())
 	a.services = append(a.services, prometheusService)
 	go prometheusService.Start()
+
+	healthService := NewHealthService(a.cfg, a.log, health.NewHandler(a.log, a.health))
+	a.services = append(a.services, healthService)
+	go healthService.Start()
+
+	adminService, err := NewAdminService(a.cfg, a.log, a.adminHandler())
+	if err != nil {
+		a.log.Error("couldn't start admin service", zap.Error(err))
+		return
+	}
+	a.services = append(a.services, adminService)
+	go adminService.Start()
 }
 
 func (a *App) initServers(ctx context.Context) {
@@ -556,7 +703,7 @@ func getNotificationsOptions(v *viper.Viper, l *zap.Logger) *notifications.Optio
 	return &cfg
 }
 
-func getCacheOptions(v *viper.Viper, l *zap.Logger) *layer.CachesConfig {
+func getCacheOptions(v *viper.Viper, l *zap.Logger, observer *cache.Observer) *layer.CachesConfig {
 	cacheCfg := layer.DefaultCachesConfigs(l)
 
 	cacheCfg.Objects.Lifetime = getLifetime(v, l, cfgObjectsCacheLifetime, cacheCfg.Objects.Lifetime)
@@ -577,6 +724,11 @@ func getCacheOptions(v *viper.Viper, l *zap.Logger) *layer.CachesConfig {
 	cacheCfg.AccessControl.Lifetime = getLifetime(v, l, cfgAccessControlCacheLifetime, cacheCfg.AccessControl.Lifetime)
 	cacheCfg.AccessControl.Size = getSize(v, l, cfgAccessControlCacheSize, cacheCfg.AccessControl.Size)
 
+	for _, cfg := range []*cache.Config{cacheCfg.Objects, cacheCfg.ObjectsList, cacheCfg.Buckets,
+		cacheCfg.Names, cacheCfg.System, cacheCfg.AccessControl} {
+		cfg.Observer = observer
+	}
+
 	return cacheCfg
 }
 
@@ -610,11 +762,34 @@ func getSize(v *viper.Viper, l *zap.Logger, cfgEntry string, defaultValue int) i
 	return defaultValue
 }
 
-func getAccessBoxCacheConfig(v *viper.Viper, l *zap.Logger) *cache.Config {
+func (a *App) initPayloadCache() *cache.PayloadCache {
+	dir := a.cfg.GetString(cfgPayloadCacheDir)
+	if dir == "" {
+		return nil
+	}
+
+	payloadCache, err := cache.NewPayloadCache(&cache.PayloadConfig{
+		Dir:           dir,
+		MaxSize:       uint64(a.cfg.GetSizeInBytes(cfgPayloadCacheMaxSize)),
+		MaxObjectSize: uint64(a.cfg.GetSizeInBytes(cfgPayloadCacheMaxObjectSize)),
+		Logger:        a.log,
+	})
+	if err != nil {
+		a.log.Fatal("failed to init payload cache", zap.Error(err))
+	}
+
+	a.log.Info("payload cache is enabled", zap.String("dir", dir),
+		zap.Uint64("size", payloadCache.Size()))
+
+	return payloadCache
+}
+
+func getAccessBoxCacheConfig(v *viper.Viper, l *zap.Logger, observer *cache.Observer) *cache.Config {
 	cacheCfg := cache.DefaultAccessBoxConfig(l)
 
 	cacheCfg.Lifetime = getLifetime(v, l, cfgAccessBoxCacheLifetime, cacheCfg.Lifetime)
 	cacheCfg.Size = getSize(v, l, cfgAccessBoxCacheSize, cacheCfg.Size)
+	cacheCfg.Observer = observer
 
 	return cacheCfg
 }
@@ -625,6 +800,7 @@ func (a *App) initHandler() {
 		DefaultMaxAge:      handler.DefaultMaxAge,
 		NotificatorEnabled: a.cfg.GetBool(cfgEnableNATS),
 		CopiesNumber:       handler.DefaultCopiesNumber,
+		TusTokenLifetime:   a.cfg.GetDuration(cfgTusTokenLifetime),
 	}
 
 	if a.cfg.IsSet(cfgDefaultMaxAge) {
@@ -642,6 +818,14 @@ func (a *App) initHandler() {
 		cfg.CopiesNumber = val
 	}
 
+	if a.cfg.GetBool(cfgSTSEnabled) {
+		cfg.STS = a.getSTSConfig()
+	}
+
+	if a.audit != nil {
+		cfg.Auditor = a.audit
+	}
+
 	var err error
 	a.api, err = handler.New(a.log, a.obj, a.nc, cfg)
 	if err != nil {
@@ -649,6 +833,46 @@ func (a *App) initHandler() {
 	}
 }
 
+func (a *App) getSTSConfig() *handler.STSConfig {
+	var cnrID cid.ID
+	if err := cnrID.DecodeString(a.cfg.GetString(cfgSTSContainerID)); err != nil {
+		a.log.Fatal("invalid sts container id", zap.String("parameter", cfgSTSContainerID), zap.Error(err))
+	}
+
+	gatesKeys := []*keys.PublicKey{a.key.PublicKey()}
+	for _, keyStr := range a.cfg.GetStringSlice(cfgSTSGatesPublicKeys) {
+		key, err := keys.NewPublicKeyFromString(keyStr)
+		if err != nil {
+			a.log.Fatal("invalid sts gate public key", zap.String("key", keyStr), zap.Error(err))
+		}
+		gatesKeys = append(gatesKeys, key)
+	}
+
+	var owner user.ID
+	user.IDFromKey(&owner, a.key.PrivateKey.PublicKey)
+
+	stsCfg := &handler.STSConfig{
+		Issuer:          authmate.New(a.log, frostfs.NewAuthmateFrostFS(a.pool)),
+		Roles:           a.settings.stsRoles,
+		Container:       cnrID,
+		Owner:           owner,
+		GatesPublicKeys: gatesKeys,
+		DefaultDuration: getLifetime(a.cfg, a.log, cfgSTSDefaultDuration, handler.DefaultSTSDuration),
+		MaxDuration:     getLifetime(a.cfg, a.log, cfgSTSMaxDuration, handler.DefaultSTSMaxDuration),
+	}
+
+	if providers := fetchWebIdentityProviders(a.cfg); len(providers) > 0 {
+		verifier, err := oidc.NewVerifier(providers)
+		if err != nil {
+			a.log.Fatal("could not load web identity providers", zap.Error(err))
+		}
+		a.settings.webIdentity = verifier
+		stsCfg.WebIdentity = verifier
+	}
+
+	return stsCfg
+}
+
 func readRegionMap(filePath string) (map[string]string, error) {
 	regionMap := make(map[string]string)
 
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/TrueCloudLab/frostfs-s3-gw/admin"
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/cache"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// NewAdminService creates a new service for admin API.
+func NewAdminService(v *viper.Viper, log *zap.Logger, handler http.Handler) (*Service, error) {
+	service := &Service{
+		Server: &http.Server{
+			Addr:    v.GetString(cfgAdminAddress),
+			Handler: handler,
+		},
+		enabled:     v.GetBool(cfgAdminEnabled),
+		serviceType: "Admin",
+		log:         log.With(zap.String("service", "Admin")),
+	}
+
+	if !service.enabled || !v.GetBool(cfgAdminTLSEnabled) {
+		return service, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(v.GetString(cfgAdminTLSCertFile), v.GetString(cfgAdminTLSKeyFile))
+	if err != nil {
+		return nil, fmt.Errorf("load tls key pair: %w", err)
+	}
+	service.TLSConfig = &tls.Config{Certificates: []tls.Certificate{cert}}
+
+	if caFile := v.GetString(cfgAdminTLSClientCA); caFile != "" {
+		caCert, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("read client ca: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("invalid client ca '%s'", caFile)
+		}
+
+		// Clients without certificate can still use admin token.
+		service.TLSConfig.ClientCAs = pool
+		service.TLSConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+
+	return service, nil
+}
+
+func (a *App) adminHandler() http.Handler {
+	return admin.NewHandler(a.log, &admin.Config{
+		Key:      a.key.PublicKey(),
+		Layer:    a.obj,
+		Gateway:  a,
+		LogLevel: a.settings.logLevel,
+	})
+}
+
+// Settings implements admin.Gateway interface.
+func (a *App) Settings() map[string]interface{} {
+	return a.cfg.AllSettings()
+}
+
+// CacheStats implements admin.Gateway interface.
+func (a *App) CacheStats(topKeys int) []cache.Stats {
+	return a.caches.Stats(topKeys)
+}
+
+// ReloadResolvers implements admin.Gateway interface.
+func (a *App) ReloadResolvers() error {
+	return a.bucketResolver.UpdateResolvers(a.getResolverConfig())
+}
+
+// ReloadTLS implements admin.Gateway interface.
+func (a *App) ReloadTLS() error {
+	return a.updateServers()
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+
+	"github.com/TrueCloudLab/frostfs-s3-gw/audit"
+	"github.com/TrueCloudLab/frostfs-s3-gw/internal/frostfs"
+	cid "github.com/TrueCloudLab/frostfs-sdk-go/container/id"
+	"go.uber.org/zap"
+)
+
+func (a *App) initAudit(ctx context.Context) {
+	if !a.cfg.GetBool(cfgAuditEnabled) {
+		return
+	}
+
+	var cnrID cid.ID
+	if err := cnrID.DecodeString(a.cfg.GetString(cfgAuditContainerID)); err != nil {
+		a.log.Fatal("invalid audit container id", zap.String("parameter", cfgAuditContainerID), zap.Error(err))
+	}
+
+	var err error
+	a.audit, err = audit.NewLogger(ctx, a.log, audit.Config{
+		Key:          a.key,
+		Storage:      frostfs.NewAuditStorage(a.pool, cnrID, a.key),
+		MaxRecords:   a.cfg.GetInt(cfgAuditMaxRecords),
+		SealInterval: a.cfg.GetDuration(cfgAuditSealInterval),
+	})
+	if err != nil {
+		a.log.Fatal("failed to init audit log", zap.Error(err))
+	}
+
+	a.log.Info("audit log is enabled", zap.Stringer("container_id", cnrID))
+}
+
+// runAudit starts periodic sealing of the audit records until context is done.
+func (a *App) runAudit(ctx context.Context) {
+	if a.audit != nil {
+		go a.audit.Run(ctx)
+	}
+}
+
+// sealAudit saves the audit records collected since the last sealing.
+func (a *App) sealAudit(ctx context.Context) {
+	if a.audit == nil {
+		return
+	}
+
+	if err := a.audit.Seal(ctx); err != nil {
+		a.log.Error("failed to seal audit records", zap.Error(err))
+	}
+}
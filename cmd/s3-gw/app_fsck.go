@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/layer"
+	"github.com/TrueCloudLab/frostfs-s3-gw/internal/frostfs"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// runFsck checks consistency of the bucket specified by the fsck flag, prints the report
+// and returns exit code: 0 if the bucket is consistent, 2 if inconsistencies are found.
+func runFsck(ctx context.Context, log *zap.Logger, v *viper.Viper) int {
+	conns, key := getPool(ctx, log, v)
+	defer conns.Close()
+
+	treeService, err := frostfs.NewTreeClient(ctx, frostfs.TreeClientConfig{
+		Endpoints:          fetchTreeEndpoints(log, v),
+		HealthcheckTimeout: getHealthcheckTimeout(v),
+		RebalanceInterval:  getRebalanceInterval(v),
+		Key:                key,
+		Log:                log,
+	})
+	if err != nil {
+		log.Fatal("failed to create tree service", zap.Error(err))
+	}
+	defer treeService.Close()
+
+	a := &App{log: log, cfg: v, pool: conns, key: key}
+	a.initResolver()
+
+	// requests are signed with the gateway key, so it must have access to the container
+	obj := layer.NewLayer(log, frostfs.NewFrostFS(conns), &layer.Config{
+		Caches:      layer.DefaultCachesConfigs(log),
+		AnonKey:     layer.AnonymousKey{Key: key},
+		Resolver:    a.bucketResolver,
+		TreeService: treeService,
+	})
+
+	bktInfo, err := obj.GetBucketInfo(ctx, v.GetString(cmdFsck))
+	if err != nil {
+		log.Fatal("couldn't get bucket info", zap.String("bucket", v.GetString(cmdFsck)), zap.Error(err))
+	}
+
+	report, err := obj.Fsck(ctx, &layer.FsckParams{
+		BktInfo:       bktInfo,
+		GracePeriod:   v.GetDuration(cmdFsckGracePeriod),
+		RestoreNodes:  v.GetBool(cmdFsckRestoreNodes),
+		DeleteOrphans: v.GetBool(cmdFsckDeleteOrphans),
+	})
+	if err != nil {
+		log.Fatal("couldn't check bucket", zap.String("bucket", bktInfo.Name), zap.Stringer("cid", bktInfo.CID), zap.Error(err))
+	}
+
+	printFsckReport(bktInfo.Name, bktInfo.CID.EncodeToString(), report)
+
+	if len(report.Orphans) != 0 || len(report.Dangling) != 0 || len(report.DuplicateLatest) != 0 {
+		return 2
+	}
+	return 0
+}
+
+func printFsckReport(bucket, cnrID string, report *layer.FsckReport) {
+	w := os.Stdout
+
+	fmt.Fprintf(w, "bucket: %s (%s)\n", bucket, cnrID)
+	fmt.Fprintf(w, "objects: %d, version nodes: %d\n", report.Objects, report.Versions)
+
+	for _, orphan := range report.Orphans {
+		fmt.Fprintf(w, "orphan object: %s '%s' created %s\n", orphan.ID.EncodeToString(), orphan.Name, orphan.Created.UTC().Format(time.RFC3339))
+	}
+	for _, ref := range report.Dangling {
+		fmt.Fprintf(w, "dangling %s node %d of '%s': object %s not found\n", ref.Kind, ref.NodeID, ref.Name, ref.OID.EncodeToString())
+	}
+	for _, name := range report.DuplicateLatest {
+		fmt.Fprintf(w, "several latest versions: '%s'\n", name)
+	}
+	for _, restored := range report.Restored {
+		fmt.Fprintf(w, "restored version node: %s '%s'\n", restored.ID.EncodeToString(), restored.Name)
+	}
+	for _, deleted := range report.Deleted {
+		fmt.Fprintf(w, "deleted orphan object: %s '%s'\n", deleted.ID.EncodeToString(), deleted.Name)
+	}
+
+	fmt.Fprintf(w, "orphans: %d, dangling: %d, several latest: %d, restored: %d, deleted: %d\n",
+		len(report.Orphans), len(report.Dangling), len(report.DuplicateLatest), len(report.Restored), len(report.Deleted))
+}
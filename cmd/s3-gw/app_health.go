@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/TrueCloudLab/frostfs-s3-gw/health"
+	"github.com/TrueCloudLab/frostfs-s3-gw/internal/frostfs"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// NewHealthService creates a new service for liveness and readiness probes.
+func NewHealthService(v *viper.Viper, log *zap.Logger, handler http.Handler) *Service {
+	return &Service{
+		Server: &http.Server{
+			Addr:    v.GetString(cfgHealthAddress),
+			Handler: handler,
+		},
+		enabled:     v.GetBool(cfgHealthEnabled),
+		serviceType: "Health",
+		log:         log.With(zap.String("service", "Health")),
+	}
+}
+
+func (a *App) initHealth() {
+	a.health = health.NewChecker(a.cfg.GetDuration(cfgHealthTimeout))
+
+	poolStat := frostfs.NewPoolStatistic(a.pool)
+	errorThreshold := a.cfg.GetUint32(cfgPoolErrorThreshold)
+	if errorThreshold <= 0 {
+		errorThreshold = defaultPoolErrorThreshold
+	}
+	a.health.Register("frostfs", func(context.Context) error {
+		return poolStat.Healthcheck(errorThreshold)
+	})
+
+	a.health.Register("tree", a.tree.Healthcheck)
+	a.health.Register("resolver", a.bucketResolver.Healthcheck)
+	if a.nc != nil {
+		a.health.Register("nats", a.nc.Healthcheck)
+	}
+}
+
+// drain makes readiness probe fail and waits for load balancers
+// to stop sending new requests before servers are closed.
+func (a *App) drain() {
+	a.health.SetDraining()
+
+	if !a.cfg.GetBool(cfgHealthEnabled) {
+		return
+	}
+
+	timeout := a.cfg.GetDuration(cfgHealthDrainTimeout)
+	if timeout <= 0 {
+		return
+	}
+
+	a.log.Info("draining before shutdown", zap.Duration("timeout", timeout))
+	time.Sleep(timeout)
+}
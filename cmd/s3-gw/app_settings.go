@@ -1,6 +1,7 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path"
@@ -10,9 +11,20 @@ import (
 	"strings"
 	"time"
 
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/auth/oidc"
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/cache"
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/handler"
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/layer"
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/resolver"
+	"github.com/TrueCloudLab/frostfs-s3-gw/audit"
+	"github.com/TrueCloudLab/frostfs-s3-gw/health"
+	"github.com/TrueCloudLab/frostfs-s3-gw/internal/frostfs"
+	"github.com/TrueCloudLab/frostfs-s3-gw/internal/tracing"
 	"github.com/TrueCloudLab/frostfs-s3-gw/internal/version"
+	"github.com/TrueCloudLab/frostfs-s3-gw/internal/wallet"
+	"github.com/TrueCloudLab/frostfs-s3-gw/metrics"
 	"github.com/TrueCloudLab/frostfs-sdk-go/pool"
+	"github.com/TrueCloudLab/frostfs-sdk-go/user"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
@@ -25,6 +37,16 @@ const (
 	defaultConnectTimeout     = 10 * time.Second
 	defaultStreamTimeout      = 10 * time.Second
 	defaultShutdownTimeout    = 15 * time.Second
+	defaultHealthDrainTimeout = 5 * time.Second
+
+	defaultUsageReconcileInterval = 24 * time.Hour
+	defaultUsageFlushInterval     = 10 * time.Second
+
+	defaultFsckGracePeriod = time.Hour
+
+	defaultMaxBucketLabels = 100
+
+	defaultTreeSlowRequestThreshold = time.Second
 
 	defaultPoolErrorThreshold uint32 = 100
 
@@ -71,6 +93,12 @@ const ( // Settings.
 	cfgAccessBoxCacheSize         = "cache.accessbox.size"
 	cfgAccessControlCacheLifetime = "cache.accesscontrol.lifetime"
 	cfgAccessControlCacheSize     = "cache.accesscontrol.size"
+	cfgCacheMemoryBudget          = "cache.memory_budget"
+
+	// Payload cache.
+	cfgPayloadCacheDir           = "cache.payload.dir"
+	cfgPayloadCacheMaxSize       = "cache.payload.max_size"
+	cfgPayloadCacheMaxObjectSize = "cache.payload.max_object_size"
 
 	// NATS.
 	cfgEnableNATS             = "nats.enabled"
@@ -80,6 +108,9 @@ const ( // Settings.
 	cfgNATSAuthPrivateKeyFile = "nats.key_file"
 	cfgNATSRootCAFiles        = "nats.root_ca"
 
+	// Cache invalidation between gateways.
+	cfgNATSCacheInvalidationSubject = "nats.cache_invalidation_subject"
+
 	// Policy.
 	cfgPolicyDefault       = "placement_policy.default"
 	cfgPolicyRegionMapFile = "placement_policy.region_mapping"
@@ -97,12 +128,41 @@ const ( // Settings.
 	cfgPProfEnabled      = "pprof.enabled"
 	cfgPProfAddress      = "pprof.address"
 
+	// Request latency metrics.
+	cfgPrometheusDurationBuckets = "prometheus.duration_buckets"
+	cfgPrometheusBucketLabel     = "prometheus.bucket_label"
+	cfgPrometheusMaxBucketLabels = "prometheus.max_bucket_labels"
+
+	// Admin API.
+	cfgAdminEnabled     = "admin.enabled"
+	cfgAdminAddress     = "admin.address"
+	cfgAdminTLSEnabled  = "admin.tls.enabled"
+	cfgAdminTLSCertFile = "admin.tls.cert_file"
+	cfgAdminTLSKeyFile  = "admin.tls.key_file"
+	cfgAdminTLSClientCA = "admin.tls.client_ca"
+
+	// Tracing.
+	cfgTracingEnabled       = "tracing.enabled"
+	cfgTracingExporter      = "tracing.exporter"
+	cfgTracingEndpoint      = "tracing.endpoint"
+	cfgTracingFile          = "tracing.file"
+	cfgTracingSamplingRatio = "tracing.sampling_ratio"
+	cfgTracingTimeout       = "tracing.timeout"
+
+	// Health probes.
+	cfgHealthEnabled      = "health.enabled"
+	cfgHealthAddress      = "health.address"
+	cfgHealthTimeout      = "health.timeout"
+	cfgHealthDrainTimeout = "health.drain_timeout"
+
 	cfgListenDomains = "listen_domains"
 
 	// Peers.
 	cfgPeers = "peers"
 
-	cfgTreeServiceEndpoint = "tree.service"
+	cfgTreeServiceEndpoint      = "tree.service"
+	cfgTreeServiceEndpoints     = "tree.endpoints"
+	cfgTreeSlowRequestThreshold = "tree.slow_request_threshold"
 
 	// NeoGo.
 	cfgRPCEndpoint = "rpc_endpoint"
@@ -123,6 +183,12 @@ const ( // Settings.
 
 	cmdListenAddress = "listen_address"
 
+	// Consistency check of the bucket.
+	cmdFsck              = "fsck"
+	cmdFsckRestoreNodes  = "fsck-restore-nodes"
+	cmdFsckDeleteOrphans = "fsck-delete-orphans"
+	cmdFsckGracePeriod   = "fsck-grace-period"
+
 	// Configuration of parameters of requests to FrostFS.
 	// Number of the object copies to consider PUT to FrostFS successful.
 	cfgSetCopiesNumber = "frostfs.set_copies_number"
@@ -130,6 +196,32 @@ const ( // Settings.
 	// List of allowed AccessKeyID prefixes.
 	cfgAllowedAccessKeyIDPrefixes = "allowed_access_key_id_prefixes"
 
+	// STS.
+	cfgSTSEnabled         = "sts.enabled"
+	cfgSTSContainerID     = "sts.container_id"
+	cfgSTSDefaultDuration = "sts.default_duration"
+	cfgSTSMaxDuration     = "sts.max_duration"
+	cfgSTSGatesPublicKeys = "sts.gates_public_keys"
+	cfgSTSRoles           = "sts.roles"
+	cfgSTSWebIdentity     = "sts.web_identity.providers"
+
+	// Audit log.
+	cfgAuditEnabled      = "audit.enabled"
+	cfgAuditContainerID  = "audit.container_id"
+	cfgAuditSealInterval = "audit.seal_interval"
+	cfgAuditMaxRecords   = "audit.max_records"
+
+	// Bucket usage.
+	cfgUsageReconcileInterval = "usage.reconcile_interval"
+	cfgUsageFlushInterval     = "usage.flush_interval"
+	cfgUsageOwners            = "usage.owners"
+
+	// Append API.
+	cfgAppendMaxSegments = "append.max_segments"
+
+	// tus resumable uploads.
+	cfgTusTokenLifetime = "tus.token_lifetime"
+
 	// envPrefix is an environment variables prefix used for configuration.
 	envPrefix = "S3_GW"
 )
@@ -137,10 +229,16 @@ const ( // Settings.
 var ignore = map[string]struct{}{
 	cfgApplicationBuildTime: {},
 
-	cfgPeers: {},
+	cfgPeers:                {},
+	cfgTreeServiceEndpoints: {},
 
 	cmdHelp:    {},
 	cmdVersion: {},
+
+	cmdFsck:              {},
+	cmdFsckRestoreNodes:  {},
+	cmdFsckDeleteOrphans: {},
+	cmdFsckGracePeriod:   {},
 }
 
 func fetchPeers(l *zap.Logger, v *viper.Viper) []pool.NodeParam {
@@ -172,6 +270,41 @@ func fetchPeers(l *zap.Logger, v *viper.Viper) []pool.NodeParam {
 	return nodes
 }
 
+func fetchTreeEndpoints(l *zap.Logger, v *viper.Viper) []frostfs.TreeEndpoint {
+	var endpoints []frostfs.TreeEndpoint
+	for i := 0; ; i++ {
+		key := cfgTreeServiceEndpoints + "." + strconv.Itoa(i) + "."
+		address := v.GetString(key + "address")
+		weight := v.GetFloat64(key + "weight")
+		priority := v.GetInt(key + "priority")
+
+		if address == "" {
+			break
+		}
+		if weight <= 0 { // unspecified or wrong
+			weight = 1
+		}
+		if priority <= 0 { // unspecified or wrong
+			priority = 1
+		}
+
+		endpoints = append(endpoints, frostfs.TreeEndpoint{Address: address, Priority: priority, Weight: weight})
+
+		l.Info("added tree service endpoint",
+			zap.String("address", address),
+			zap.Int("priority", priority),
+			zap.Float64("weight", weight))
+	}
+
+	if len(endpoints) == 0 {
+		if address := v.GetString(cfgTreeServiceEndpoint); address != "" {
+			endpoints = append(endpoints, frostfs.TreeEndpoint{Address: address, Priority: 1, Weight: 1})
+		}
+	}
+
+	return endpoints
+}
+
 func fetchServers(v *viper.Viper) []ServerInfo {
 	var servers []ServerInfo
 
@@ -194,6 +327,120 @@ func fetchServers(v *viper.Viper) []ServerInfo {
 	return servers
 }
 
+func fetchDurationBuckets(l *zap.Logger, v *viper.Viper) []float64 {
+	values := v.GetStringSlice(cfgPrometheusDurationBuckets)
+	if len(values) == 0 {
+		return metrics.DefaultDurationBuckets
+	}
+
+	buckets := make([]float64, len(values))
+	for i, value := range values {
+		bucket, err := strconv.ParseFloat(value, 64)
+		if err != nil || bucket <= 0 || i > 0 && bucket <= buckets[i-1] {
+			l.Error("invalid duration buckets, default ones are used",
+				zap.Strings("buckets", values), zap.Float64s("default", metrics.DefaultDurationBuckets))
+			return metrics.DefaultDurationBuckets
+		}
+		buckets[i] = bucket
+	}
+
+	return buckets
+}
+
+func fetchSTSRoles(v *viper.Viper) (map[string]*handler.STSRole, error) {
+	roles := make(map[string]*handler.STSRole)
+
+	for i := 0; ; i++ {
+		key := cfgSTSRoles + "." + strconv.Itoa(i) + "."
+
+		name := v.GetString(key + "name")
+		if name == "" {
+			break
+		}
+
+		password := wallet.GetPassword(v, key+cfgWalletPassphrase)
+		roleKey, err := wallet.GetKeyFromPath(v.GetString(key+cfgWalletPath), v.GetString(key+cfgWalletAddress), password)
+		if err != nil {
+			return nil, fmt.Errorf("could not load key of role '%s': %w", name, err)
+		}
+
+		role := &handler.STSRole{
+			Name:        name,
+			Key:         roleKey,
+			MaxDuration: v.GetDuration(key + "max_duration"),
+		}
+
+		for _, userStr := range v.GetStringSlice(key + "allowed_users") {
+			var id user.ID
+			if err = id.DecodeString(userStr); err != nil {
+				return nil, fmt.Errorf("invalid allowed user '%s' of role '%s': %w", userStr, name, err)
+			}
+			role.AllowedUsers = append(role.AllowedUsers, id)
+		}
+
+		if role.EACLRules, err = readJSONRules(v.GetString(key + "bearer_rules")); err != nil {
+			return nil, fmt.Errorf("invalid bearer rules of role '%s': %w", name, err)
+		}
+
+		if sessionRules := v.GetString(key + "session_rules"); sessionRules == "none" {
+			role.SkipSessionRules = true
+		} else if role.SessionTokenRules, err = readJSONRules(sessionRules); err != nil {
+			return nil, fmt.Errorf("invalid session rules of role '%s': %w", name, err)
+		}
+
+		if v.GetBool(key + "web_identity.enabled") {
+			role.WebIdentity = &handler.STSWebIdentity{
+				Claims:            v.GetStringMapString(key + "web_identity.claims"),
+				BucketPrefixClaim: v.GetString(key + "web_identity.bucket_prefix_claim"),
+			}
+		}
+
+		roles[name] = role
+	}
+
+	return roles, nil
+}
+
+func fetchWebIdentityProviders(v *viper.Viper) []oidc.ProviderConfig {
+	var providers []oidc.ProviderConfig
+
+	for i := 0; ; i++ {
+		key := cfgSTSWebIdentity + "." + strconv.Itoa(i) + "."
+
+		provider := oidc.ProviderConfig{
+			Issuer:   v.GetString(key + "issuer"),
+			Audience: v.GetStringSlice(key + "audience"),
+			JWKSPath: v.GetString(key + "jwks"),
+		}
+
+		if provider.Issuer == "" {
+			break
+		}
+
+		providers = append(providers, provider)
+	}
+
+	return providers
+}
+
+// readJSONRules reads rules from a file. Empty path means no rules.
+func readJSONRules(filePath string) ([]byte, error) {
+	if filePath == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read file '%s': %w", filePath, err)
+	}
+
+	if !json.Valid(data) {
+		return nil, fmt.Errorf("file '%s' contains invalid json", filePath)
+	}
+
+	return data, nil
+}
+
 func newSettings() *viper.Viper {
 	v := viper.New()
 
@@ -237,6 +484,11 @@ func newSettings() *viper.Viper {
 
 	domains := flags.StringSliceP(cfgListenDomains, "d", nil, "set domains to be listened")
 
+	flags.String(cmdFsck, "", "check consistency of the tree service and objects of the bucket (name or container id) and exit")
+	flags.Bool(cmdFsckRestoreNodes, false, "re-create version nodes for orphaned objects during the check")
+	flags.Bool(cmdFsckDeleteOrphans, false, "delete orphaned objects (which weren't restored) during the check")
+	flags.Duration(cmdFsckGracePeriod, defaultFsckGracePeriod, "objects created within this period are never considered orphaned")
+
 	// set defaults:
 
 	// logger:
@@ -248,6 +500,37 @@ func newSettings() *viper.Viper {
 
 	v.SetDefault(cfgPProfAddress, "localhost:8085")
 	v.SetDefault(cfgPrometheusAddress, "localhost:8086")
+	v.SetDefault(cfgPrometheusMaxBucketLabels, defaultMaxBucketLabels)
+	v.SetDefault(cfgAdminAddress, "localhost:8087")
+	v.SetDefault(cfgHealthAddress, "localhost:8088")
+	v.SetDefault(cfgHealthTimeout, health.DefaultTimeout)
+	v.SetDefault(cfgHealthDrainTimeout, defaultHealthDrainTimeout)
+
+	// tracing:
+	v.SetDefault(cfgTracingExporter, tracing.ExporterOTLP)
+	v.SetDefault(cfgTracingSamplingRatio, 1.0)
+	v.SetDefault(cfgTracingTimeout, tracing.DefaultTimeout)
+
+	// audit:
+	v.SetDefault(cfgAuditSealInterval, audit.DefaultSealInterval)
+	v.SetDefault(cfgAuditMaxRecords, audit.DefaultMaxRecords)
+
+	// payload cache:
+	v.SetDefault(cfgPayloadCacheMaxSize, cache.DefaultPayloadCacheMaxSize)
+	v.SetDefault(cfgPayloadCacheMaxObjectSize, cache.DefaultPayloadCacheMaxObjectSize)
+
+	// usage:
+	v.SetDefault(cfgUsageReconcileInterval, defaultUsageReconcileInterval)
+	v.SetDefault(cfgUsageFlushInterval, defaultUsageFlushInterval)
+
+	// append:
+	v.SetDefault(cfgAppendMaxSegments, layer.DefaultAppendMaxSegments)
+
+	// tus:
+	v.SetDefault(cfgTusTokenLifetime, handler.DefaultTusTokenLifetime)
+
+	// tree:
+	v.SetDefault(cfgTreeSlowRequestThreshold, defaultTreeSlowRequestThreshold)
 
 	// Bind flags
 	if err := bindFlags(v, flags); err != nil {
@@ -310,6 +593,15 @@ func newSettings() *viper.Viper {
 		fmt.Printf("%s_%s_[N]_ADDRESS = string\n", envPrefix, strings.ToUpper(cfgPeers))
 		fmt.Printf("%s_%s_[N]_WEIGHT = 0..1 (float)\n", envPrefix, strings.ToUpper(cfgPeers))
 
+		fmt.Println()
+		fmt.Println("Tree endpoints preset:")
+		fmt.Println()
+
+		treeEndpoints := strings.ToUpper(strings.Replace(cfgTreeServiceEndpoints, ".", "_", -1))
+		fmt.Printf("%s_%s_[N]_ADDRESS = string\n", envPrefix, treeEndpoints)
+		fmt.Printf("%s_%s_[N]_PRIORITY = int\n", envPrefix, treeEndpoints)
+		fmt.Printf("%s_%s_[N]_WEIGHT = 0..1 (float)\n", envPrefix, treeEndpoints)
+
 		os.Exit(0)
 	case versionFlag != nil && *versionFlag:
 		fmt.Printf("FrostFS S3 Gateway\nVersion: %s\nGoVersion: %s\n", version.Version, runtime.Version())
@@ -361,6 +653,19 @@ func bindFlags(v *viper.Viper, flags *pflag.FlagSet) error {
 		return err
 	}
 
+	if err := v.BindPFlag(cmdFsck, flags.Lookup(cmdFsck)); err != nil {
+		return err
+	}
+	if err := v.BindPFlag(cmdFsckRestoreNodes, flags.Lookup(cmdFsckRestoreNodes)); err != nil {
+		return err
+	}
+	if err := v.BindPFlag(cmdFsckDeleteOrphans, flags.Lookup(cmdFsckDeleteOrphans)); err != nil {
+		return err
+	}
+	if err := v.BindPFlag(cmdFsckGracePeriod, flags.Lookup(cmdFsckGracePeriod)); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag(cfgServer+".0.address", flags.Lookup(cmdListenAddress)); err != nil {
 		return err
 	}
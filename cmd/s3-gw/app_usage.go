@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/TrueCloudLab/frostfs-sdk-go/user"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// getUsageOwners returns owners whose buckets are accounted in addition to the served ones.
+func getUsageOwners(v *viper.Viper, l *zap.Logger) []user.ID {
+	var owners []user.ID
+	for _, ownerStr := range v.GetStringSlice(cfgUsageOwners) {
+		var owner user.ID
+		if err := owner.DecodeString(ownerStr); err != nil {
+			l.Fatal("invalid usage owner", zap.String("owner", ownerStr), zap.Error(err))
+		}
+		owners = append(owners, owner)
+	}
+	return owners
+}
+
+// runUsage loads usage of all buckets and starts periodic flushing and reconciliation of bucket usage.
+func (a *App) runUsage(ctx context.Context) {
+	flushInterval := a.cfg.GetDuration(cfgUsageFlushInterval)
+	if flushInterval <= 0 {
+		flushInterval = defaultUsageFlushInterval
+	}
+
+	var reconcile <-chan time.Time
+	if interval := a.cfg.GetDuration(cfgUsageReconcileInterval); interval > 0 {
+		ticker := time.NewTicker(interval)
+		reconcile = ticker.C
+		defer ticker.Stop()
+	} else {
+		a.log.Info("bucket usage reconciliation is disabled")
+	}
+
+	a.obj.LoadUsage(ctx)
+
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			a.obj.FlushUsage(ctx)
+		case <-reconcile:
+			a.log.Debug("reconcile bucket usage")
+			a.obj.ReconcileUsage(ctx)
+		}
+	}
+}
+
+// flushUsage stores bucket usage changes which aren't flushed yet.
+func (a *App) flushUsage(ctx context.Context) {
+	a.obj.FlushUsage(ctx)
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"os"
 	"os/signal"
 	"syscall"
 )
@@ -12,6 +13,10 @@ func main() {
 	v := newSettings()
 	l := newLogger(v)
 
+	if v.GetString(cmdFsck) != "" {
+		os.Exit(runFsck(g, l.logger, v))
+	}
+
 	a := newApp(g, l, v)
 
 	go a.Serve(g)
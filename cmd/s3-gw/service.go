@@ -19,7 +19,12 @@ type Service struct {
 func (ms *Service) Start() {
 	if ms.enabled {
 		ms.log.Info("service is running", zap.String("endpoint", ms.Addr))
-		err := ms.ListenAndServe()
+		var err error
+		if ms.TLSConfig != nil {
+			err = ms.ListenAndServeTLS("", "")
+		} else {
+			err = ms.ListenAndServe()
+		}
 		if err != nil && err != http.ErrServerClosed {
 			ms.log.Warn("service couldn't start on configured port")
 		}
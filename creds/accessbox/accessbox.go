@@ -23,6 +23,8 @@ import (
 type Box struct {
 	Gate     *GateData
 	Policies []*ContainerPolicy
+	// Temporary is set for boxes of temporary credentials which are used only with a session token.
+	Temporary bool
 }
 
 // ContainerPolicy represents friendly AccessBox_ContainerPolicy.
@@ -167,8 +169,9 @@ func (x *AccessBox) GetBox(owner *keys.PrivateKey) (*Box, error) {
 	}
 
 	return &Box{
-		Gate:     tokens,
-		Policies: policy,
+		Gate:      tokens,
+		Policies:  policy,
+		Temporary: x.Temporary,
 	}, nil
 }
 
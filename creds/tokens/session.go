@@ -0,0 +1,69 @@
+package tokens
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrInvalidSessionToken is returned when session token is malformed.
+	ErrInvalidSessionToken = errors.New("invalid session token")
+	// ErrExpiredSessionToken is returned when session token lifetime is over.
+	ErrExpiredSessionToken = errors.New("session token is expired")
+)
+
+// SessionClaims contains restrictions of temporary credentials carried by a session token.
+type SessionClaims struct {
+	// Expiration is a unix timestamp after which credentials are invalid.
+	Expiration int64 `json:"exp"`
+	// Subject is an identity the credentials were issued for.
+	Subject string `json:"sub,omitempty"`
+	// BucketPrefix restricts buckets that can be accessed with the credentials.
+	BucketPrefix string `json:"bucket_prefix,omitempty"`
+}
+
+// NewSessionToken forms a session token for temporary credentials and returns it
+// with a secret access key derived from the secret of the access box and the token.
+// The access box secret isn't returned, so the credentials can't be used without
+// the token, and the token can't be changed without invalidating the derived secret.
+func NewSessionToken(boxSecret string, claims SessionClaims) (token string, secretAccessKey string, err error) {
+	raw, err := json.Marshal(claims)
+	if err != nil {
+		return "", "", err
+	}
+
+	token = base64.RawURLEncoding.EncodeToString(raw)
+	return token, DeriveSessionSecret(boxSecret, token), nil
+}
+
+// ParseSessionToken decodes the session token and checks that it isn't expired at the moment now.
+// The token authenticity is checked by request signature made with the derived secret.
+func ParseSessionToken(token string, now time.Time) (*SessionClaims, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidSessionToken
+	}
+
+	var claims SessionClaims
+	if err = json.Unmarshal(raw, &claims); err != nil || claims.Expiration == 0 {
+		return nil, ErrInvalidSessionToken
+	}
+
+	if now.Unix() > claims.Expiration {
+		return nil, ErrExpiredSessionToken
+	}
+
+	return &claims, nil
+}
+
+// DeriveSessionSecret returns a secret access key of temporary credentials with the session token.
+func DeriveSessionSecret(boxSecret, token string) string {
+	mac := hmac.New(sha256.New, []byte(boxSecret))
+	mac.Write([]byte(token))
+	return hex.EncodeToString(mac.Sum(nil))
+}
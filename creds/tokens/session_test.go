@@ -0,0 +1,48 @@
+package tokens
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionToken(t *testing.T) {
+	now := time.Now()
+	claims := SessionClaims{
+		Expiration:   now.Add(time.Hour).Unix(),
+		Subject:      "subject",
+		BucketPrefix: "prefix-",
+	}
+
+	token, secret, err := NewSessionToken("box-secret", claims)
+	require.NoError(t, err)
+	require.Equal(t, secret, DeriveSessionSecret("box-secret", token))
+
+	t.Run("valid", func(t *testing.T) {
+		parsed, err := ParseSessionToken(token, now)
+		require.NoError(t, err)
+		require.Equal(t, claims, *parsed)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		_, err := ParseSessionToken(token, now.Add(2*time.Hour))
+		require.ErrorIs(t, err, ErrExpiredSessionToken)
+	})
+
+	t.Run("another secret", func(t *testing.T) {
+		require.NotEqual(t, secret, DeriveSessionSecret("another-secret", token))
+
+		anotherToken, _, err := NewSessionToken("box-secret", SessionClaims{Expiration: claims.Expiration})
+		require.NoError(t, err)
+		require.NotEqual(t, secret, DeriveSessionSecret("box-secret", anotherToken))
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		_, err := ParseSessionToken("invalid token", now)
+		require.ErrorIs(t, err, ErrInvalidSessionToken)
+
+		_, err = ParseSessionToken(token[:len(token)-2], now)
+		require.ErrorIs(t, err, ErrInvalidSessionToken)
+	})
+}
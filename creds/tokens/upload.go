@@ -0,0 +1,86 @@
+package tokens
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidUploadToken is returned when upload token is malformed or its signature doesn't match.
+	ErrInvalidUploadToken = errors.New("invalid upload token")
+	// ErrExpiredUploadToken is returned when upload token lifetime is over.
+	ErrExpiredUploadToken = errors.New("upload token is expired")
+)
+
+// UploadClaims contains the scope of an upload token: the only resumable upload which can be accessed
+// with the token instead of the request signature.
+type UploadClaims struct {
+	// AccessKeyID is the access key ID of credentials the upload was created with.
+	AccessKeyID string `json:"akid"`
+	Bucket      string `json:"bkt"`
+	Key         string `json:"key"`
+	UploadID    string `json:"upl"`
+	// Expiration is a unix timestamp after which the token is invalid.
+	Expiration int64 `json:"exp"`
+	// Session contains restrictions of temporary credentials the upload was created with.
+	Session *SessionClaims `json:"ses,omitempty"`
+}
+
+// NewUploadToken forms an upload token signed with the secret of the access box, so the token
+// can't be changed or forged by the client even if the access box secret is derived (temporary credentials).
+func NewUploadToken(boxSecret string, claims UploadClaims) (string, error) {
+	raw, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	payload := base64.RawURLEncoding.EncodeToString(raw)
+	return payload + "." + uploadTokenSignature(boxSecret, payload), nil
+}
+
+// ParseUploadToken decodes the upload token and checks that it isn't expired at the moment now.
+// The token signature must be checked by CheckUploadToken with the access box of the claims.
+func ParseUploadToken(token string, now time.Time) (*UploadClaims, error) {
+	payload, _, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrInvalidUploadToken
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, ErrInvalidUploadToken
+	}
+
+	var claims UploadClaims
+	if err = json.Unmarshal(raw, &claims); err != nil || claims.Expiration == 0 || claims.AccessKeyID == "" {
+		return nil, ErrInvalidUploadToken
+	}
+
+	if now.Unix() > claims.Expiration {
+		return nil, ErrExpiredUploadToken
+	}
+
+	return &claims, nil
+}
+
+// CheckUploadToken checks that the upload token is signed with the secret of the access box.
+func CheckUploadToken(boxSecret, token string) error {
+	payload, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(uploadTokenSignature(boxSecret, payload))) {
+		return ErrInvalidUploadToken
+	}
+	return nil
+}
+
+func uploadTokenSignature(boxSecret, payload string) string {
+	mac := hmac.New(sha256.New, []byte(boxSecret))
+	mac.Write([]byte("upload:"))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,67 @@
+package tokens
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUploadToken(t *testing.T) {
+	now := time.Now()
+	claims := UploadClaims{
+		AccessKeyID: "access-key-id",
+		Bucket:      "bucket",
+		Key:         "dir/object",
+		UploadID:    "upload-id",
+		Expiration:  now.Add(time.Hour).Unix(),
+		Session:     &SessionClaims{Expiration: now.Add(time.Hour).Unix(), Subject: "subject"},
+	}
+
+	token, err := NewUploadToken("box-secret", claims)
+	require.NoError(t, err)
+
+	t.Run("valid", func(t *testing.T) {
+		parsed, err := ParseUploadToken(token, now)
+		require.NoError(t, err)
+		require.Equal(t, claims, *parsed)
+		require.NoError(t, CheckUploadToken("box-secret", token))
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		_, err := ParseUploadToken(token, now.Add(2*time.Hour))
+		require.ErrorIs(t, err, ErrExpiredUploadToken)
+	})
+
+	t.Run("another secret", func(t *testing.T) {
+		require.ErrorIs(t, CheckUploadToken("another-secret", token), ErrInvalidUploadToken)
+	})
+
+	t.Run("changed claims", func(t *testing.T) {
+		changed := claims
+		changed.UploadID = "another-upload-id"
+		anotherToken, err := NewUploadToken("another-secret", changed)
+		require.NoError(t, err)
+
+		_, signature, _ := strings.Cut(token, ".")
+		payload, _, _ := strings.Cut(anotherToken, ".")
+		require.ErrorIs(t, CheckUploadToken("box-secret", payload+"."+signature), ErrInvalidUploadToken)
+	})
+
+	t.Run("session secret", func(t *testing.T) {
+		sessionToken, derived, err := NewSessionToken("box-secret", SessionClaims{Expiration: claims.Expiration})
+		require.NoError(t, err)
+		require.ErrorIs(t, CheckUploadToken("box-secret", sessionToken+"."+derived), ErrInvalidUploadToken)
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		_, err := ParseUploadToken("invalid token", now)
+		require.ErrorIs(t, err, ErrInvalidUploadToken)
+
+		_, err = ParseUploadToken(token[2:], now)
+		require.ErrorIs(t, err, ErrInvalidUploadToken)
+
+		require.ErrorIs(t, CheckUploadToken("box-secret", "invalid token"), ErrInvalidUploadToken)
+	})
+}
@@ -0,0 +1,192 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type (
+	// Check returns an error if the dependency isn't available.
+	Check func(ctx context.Context) error
+
+	// Checker runs readiness checks of the gateway dependencies.
+	Checker struct {
+		timeout  time.Duration
+		draining uint32
+
+		mu     sync.RWMutex
+		checks []namedCheck
+	}
+
+	namedCheck struct {
+		name  string
+		check Check
+	}
+
+	// Report is a result of readiness probe.
+	Report struct {
+		Status string        `json:"status"`
+		Checks []CheckResult `json:"checks,omitempty"`
+	}
+
+	// CheckResult is a result of a single dependency check.
+	CheckResult struct {
+		Name    string `json:"name"`
+		Status  string `json:"status"`
+		Latency string `json:"latency"`
+		Error   string `json:"error,omitempty"`
+	}
+)
+
+const (
+	// StatusOK means that check passed.
+	StatusOK = "ok"
+	// StatusFail means that check failed.
+	StatusFail = "fail"
+	// StatusDraining means that the gateway is shutting down and mustn't receive new requests.
+	StatusDraining = "draining"
+
+	// DefaultTimeout is a default timeout of all readiness checks.
+	DefaultTimeout = 5 * time.Second
+)
+
+// NewChecker creates Checker which limits readiness checks by timeout.
+func NewChecker(timeout time.Duration) *Checker {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	return &Checker{timeout: timeout}
+}
+
+// Register adds named readiness check. Check with the same name is replaced.
+func (c *Checker) Register(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i := range c.checks {
+		if c.checks[i].name == name {
+			c.checks[i].check = check
+			return
+		}
+	}
+
+	c.checks = append(c.checks, namedCheck{name: name, check: check})
+}
+
+// Unregister removes named readiness check.
+func (c *Checker) Unregister(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i := range c.checks {
+		if c.checks[i].name == name {
+			c.checks = append(c.checks[:i], c.checks[i+1:]...)
+			return
+		}
+	}
+}
+
+// SetDraining marks the gateway as not ready regardless of dependencies state.
+func (c *Checker) SetDraining() {
+	atomic.StoreUint32(&c.draining, 1)
+}
+
+// Draining returns true if SetDraining was called.
+func (c *Checker) Draining() bool {
+	return atomic.LoadUint32(&c.draining) == 1
+}
+
+// Ready runs all checks concurrently and returns report.
+func (c *Checker) Ready(ctx context.Context) Report {
+	if c.Draining() {
+		return Report{Status: StatusDraining}
+	}
+
+	c.mu.RLock()
+	checks := make([]namedCheck, len(c.checks))
+	copy(checks, c.checks)
+	c.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	report := Report{
+		Status: StatusOK,
+		Checks: make([]CheckResult, len(checks)),
+	}
+
+	var wg sync.WaitGroup
+	for i := range checks {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			report.Checks[i] = runCheck(ctx, checks[i])
+		}(i)
+	}
+	wg.Wait()
+
+	for _, res := range report.Checks {
+		if res.Status != StatusOK {
+			report.Status = StatusFail
+			break
+		}
+	}
+
+	return report
+}
+
+func runCheck(ctx context.Context, check namedCheck) CheckResult {
+	res := CheckResult{
+		Name:   check.name,
+		Status: StatusOK,
+	}
+
+	start := time.Now()
+	err := check.check(ctx)
+	res.Latency = time.Since(start).String()
+	if err != nil {
+		res.Status = StatusFail
+		res.Error = err.Error()
+	}
+
+	return res
+}
+
+// NewHandler creates handler with liveness (/healthz) and readiness (/readyz) probes.
+// Readiness probe responds with 503 status code if any check failed or the gateway is draining.
+func NewHandler(log *zap.Logger, checker *Checker) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		writeReport(log, w, http.StatusOK, Report{Status: StatusOK})
+	})
+
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		report := checker.Ready(r.Context())
+
+		status := http.StatusOK
+		if report.Status != StatusOK {
+			status = http.StatusServiceUnavailable
+			log.Warn("gateway isn't ready", zap.Any("report", report))
+		}
+
+		writeReport(log, w, status, report)
+	})
+
+	return mux
+}
+
+func writeReport(log *zap.Logger, w http.ResponseWriter, status int, report Report) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		log.Error("could not write health response", zap.Error(err))
+	}
+}
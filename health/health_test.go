@@ -0,0 +1,68 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestHandler(t *testing.T) {
+	checker := NewChecker(time.Second)
+	h := NewHandler(zap.NewNop(), checker)
+
+	probe := func(path string) (int, Report) {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+		var report Report
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&report))
+		return w.Code, report
+	}
+
+	checker.Register("frostfs", func(context.Context) error { return nil })
+
+	code, report := probe("/readyz")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, StatusOK, report.Status)
+	require.Len(t, report.Checks, 1)
+	require.NotEmpty(t, report.Checks[0].Latency)
+
+	checker.Register("tree", func(context.Context) error { return errors.New("unavailable") })
+
+	code, report = probe("/readyz")
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Equal(t, StatusFail, report.Status)
+	require.Equal(t, CheckResult{Name: "tree", Status: StatusFail, Latency: report.Checks[1].Latency, Error: "unavailable"}, report.Checks[1])
+
+	checker.Unregister("tree")
+	code, _ = probe("/readyz")
+	require.Equal(t, http.StatusOK, code)
+
+	checker.SetDraining()
+	code, report = probe("/readyz")
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Equal(t, StatusDraining, report.Status)
+
+	code, report = probe("/healthz")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, StatusOK, report.Status)
+}
+
+func TestCheckTimeout(t *testing.T) {
+	checker := NewChecker(10 * time.Millisecond)
+	checker.Register("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	report := checker.Ready(context.Background())
+	require.Equal(t, StatusFail, report.Status)
+	require.Equal(t, context.DeadlineExceeded.Error(), report.Checks[0].Error)
+}
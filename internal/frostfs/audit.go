@@ -0,0 +1,114 @@
+package frostfs
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/layer"
+	cid "github.com/TrueCloudLab/frostfs-sdk-go/container/id"
+	"github.com/TrueCloudLab/frostfs-sdk-go/object"
+	oid "github.com/TrueCloudLab/frostfs-sdk-go/object/id"
+	"github.com/TrueCloudLab/frostfs-sdk-go/pool"
+	"github.com/TrueCloudLab/frostfs-sdk-go/user"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+)
+
+// Attributes of audit segment objects.
+const (
+	AttributeAuditChain    = "S3-Audit-Chain"
+	AttributeAuditFirstSeq = "S3-Audit-First-Seq"
+	AttributeAuditLastSeq  = "S3-Audit-Last-Seq"
+)
+
+// AuditStorage is a mediator which implements audit.Storage through pool.Pool.
+// Every segment is stored as a separate object in the container.
+type AuditStorage struct {
+	frostFS   *FrostFS
+	container cid.ID
+	key       *keys.PrivateKey
+	owner     user.ID
+}
+
+// NewAuditStorage creates new AuditStorage using provided pool.Pool. Segments are saved
+// to the container on behalf of the key.
+func NewAuditStorage(p *pool.Pool, container cid.ID, key *keys.PrivateKey) *AuditStorage {
+	var owner user.ID
+	user.IDFromKey(&owner, key.PrivateKey.PublicKey)
+
+	return &AuditStorage{
+		frostFS:   NewFrostFS(p),
+		container: container,
+		key:       key,
+		owner:     owner,
+	}
+}
+
+// Put implements audit.Storage interface method.
+func (x *AuditStorage) Put(ctx context.Context, firstSeq, lastSeq uint64, payload []byte) error {
+	_, err := x.frostFS.CreateObject(ctx, layer.PrmObjectCreate{
+		PrmAuth:   layer.PrmAuth{PrivateKey: &x.key.PrivateKey},
+		Container: x.container,
+		Creator:   x.owner,
+		Attributes: [][2]string{
+			{AttributeAuditChain, hex.EncodeToString(x.key.PublicKey().Bytes())},
+			{AttributeAuditFirstSeq, strconv.FormatUint(firstSeq, 10)},
+			{AttributeAuditLastSeq, strconv.FormatUint(lastSeq, 10)},
+		},
+		PayloadSize: uint64(len(payload)),
+		Payload:     bytes.NewReader(payload),
+	})
+	return err
+}
+
+// List implements audit.Storage interface method.
+func (x *AuditStorage) List(ctx context.Context, chain *keys.PublicKey) ([][]byte, error) {
+	filters := object.NewSearchFilters()
+	filters.AddRootFilter()
+	filters.AddFilter(AttributeAuditChain, hex.EncodeToString(chain.Bytes()), object.MatchStringEqual)
+
+	var prm pool.PrmObjectSearch
+	prm.SetContainerID(x.container)
+	prm.SetFilters(filters)
+	prm.UseKey(&x.key.PrivateKey)
+
+	res, err := x.frostFS.pool.SearchObjects(ctx, prm)
+	if err != nil {
+		return nil, fmt.Errorf("search audit segments via connection pool: %w", err)
+	}
+	defer res.Close()
+
+	var ids []oid.ID
+	if err = res.Iterate(func(id oid.ID) bool {
+		ids = append(ids, id)
+		return false
+	}); err != nil {
+		return nil, fmt.Errorf("iterate audit segments: %w", err)
+	}
+
+	payloads := make([][]byte, 0, len(ids))
+	for _, id := range ids {
+		obj, err := x.frostFS.ReadObject(ctx, layer.PrmObjectRead{
+			PrmAuth:     layer.PrmAuth{PrivateKey: &x.key.PrivateKey},
+			Container:   x.container,
+			Object:      id,
+			WithPayload: true,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("read audit segment '%s': %w", id.EncodeToString(), err)
+		}
+
+		payload, err := io.ReadAll(obj.Payload)
+		obj.Payload.Close()
+		if err != nil {
+			return nil, fmt.Errorf("read audit segment '%s' payload: %w", id.EncodeToString(), err)
+		}
+
+		payloads = append(payloads, payload)
+	}
+
+	return payloads, nil
+}
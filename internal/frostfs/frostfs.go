@@ -14,6 +14,7 @@ import (
 	"github.com/TrueCloudLab/frostfs-s3-gw/api/layer"
 	"github.com/TrueCloudLab/frostfs-s3-gw/authmate"
 	"github.com/TrueCloudLab/frostfs-s3-gw/creds/tokens"
+	"github.com/TrueCloudLab/frostfs-s3-gw/internal/tracing"
 	apistatus "github.com/TrueCloudLab/frostfs-sdk-go/client/status"
 	"github.com/TrueCloudLab/frostfs-sdk-go/container"
 	"github.com/TrueCloudLab/frostfs-sdk-go/container/acl"
@@ -52,7 +53,10 @@ func NewFrostFS(p *pool.Pool) *FrostFS {
 }
 
 // TimeToEpoch implements frostfs.FrostFS interface method.
-func (x *FrostFS) TimeToEpoch(ctx context.Context, now, futureTime time.Time) (uint64, uint64, error) {
+func (x *FrostFS) TimeToEpoch(ctx context.Context, now, futureTime time.Time) (_ uint64, _ uint64, err error) {
+	ctx, span := tracing.StartSpan(ctx, "frostfs.TimeToEpoch")
+	defer func() { tracing.End(span, err) }()
+
 	dur := futureTime.Sub(now)
 	if dur < 0 {
 		return 0, 0, fmt.Errorf("time '%s' must be in the future (after %s)",
@@ -88,7 +92,10 @@ func (x *FrostFS) TimeToEpoch(ctx context.Context, now, futureTime time.Time) (u
 }
 
 // Container implements frostfs.FrostFS interface method.
-func (x *FrostFS) Container(ctx context.Context, idCnr cid.ID) (*container.Container, error) {
+func (x *FrostFS) Container(ctx context.Context, idCnr cid.ID) (_ *container.Container, err error) {
+	ctx, span := tracing.StartSpan(ctx, "frostfs.Container")
+	defer func() { tracing.End(span, err) }()
+
 	var prm pool.PrmContainerGet
 	prm.SetContainerID(idCnr)
 
@@ -105,7 +112,10 @@ var basicACLZero acl.Basic
 // CreateContainer implements frostfs.FrostFS interface method.
 //
 // If prm.BasicACL is zero, 'eacl-public-read-write' is used.
-func (x *FrostFS) CreateContainer(ctx context.Context, prm layer.PrmContainerCreate) (cid.ID, error) {
+func (x *FrostFS) CreateContainer(ctx context.Context, prm layer.PrmContainerCreate) (_ cid.ID, err error) {
+	ctx, span := tracing.StartSpan(ctx, "frostfs.CreateContainer")
+	defer func() { tracing.End(span, err) }()
+
 	if prm.BasicACL == basicACLZero {
 		prm.BasicACL = acl.PublicRWExtended
 	}
@@ -134,7 +144,7 @@ func (x *FrostFS) CreateContainer(ctx context.Context, prm layer.PrmContainerCre
 		cnr.SetAttribute(prm.AdditionalAttributes[i][0], prm.AdditionalAttributes[i][1])
 	}
 
-	err := pool.SyncContainerWithNetwork(ctx, &cnr, x.pool)
+	err = pool.SyncContainerWithNetwork(ctx, &cnr, x.pool)
 	if err != nil {
 		return cid.ID{}, fmt.Errorf("sync container with the network state: %w", err)
 	}
@@ -157,7 +167,10 @@ func (x *FrostFS) CreateContainer(ctx context.Context, prm layer.PrmContainerCre
 }
 
 // UserContainers implements frostfs.FrostFS interface method.
-func (x *FrostFS) UserContainers(ctx context.Context, id user.ID) ([]cid.ID, error) {
+func (x *FrostFS) UserContainers(ctx context.Context, id user.ID) (_ []cid.ID, err error) {
+	ctx, span := tracing.StartSpan(ctx, "frostfs.UserContainers")
+	defer func() { tracing.End(span, err) }()
+
 	var prm pool.PrmContainerList
 	prm.SetOwnerID(id)
 
@@ -170,7 +183,10 @@ func (x *FrostFS) UserContainers(ctx context.Context, id user.ID) ([]cid.ID, err
 }
 
 // SetContainerEACL implements frostfs.FrostFS interface method.
-func (x *FrostFS) SetContainerEACL(ctx context.Context, table eacl.Table, sessionToken *session.Container) error {
+func (x *FrostFS) SetContainerEACL(ctx context.Context, table eacl.Table, sessionToken *session.Container) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "frostfs.SetContainerEACL")
+	defer func() { tracing.End(span, err) }()
+
 	var prm pool.PrmContainerSetEACL
 	prm.SetTable(table)
 	prm.SetWaitParams(x.await)
@@ -179,7 +195,7 @@ func (x *FrostFS) SetContainerEACL(ctx context.Context, table eacl.Table, sessio
 		prm.WithinSession(*sessionToken)
 	}
 
-	err := x.pool.SetEACL(ctx, prm)
+	err = x.pool.SetEACL(ctx, prm)
 	if err != nil {
 		return fmt.Errorf("save eACL via connection pool: %w", err)
 	}
@@ -188,7 +204,10 @@ func (x *FrostFS) SetContainerEACL(ctx context.Context, table eacl.Table, sessio
 }
 
 // ContainerEACL implements frostfs.FrostFS interface method.
-func (x *FrostFS) ContainerEACL(ctx context.Context, id cid.ID) (*eacl.Table, error) {
+func (x *FrostFS) ContainerEACL(ctx context.Context, id cid.ID) (_ *eacl.Table, err error) {
+	ctx, span := tracing.StartSpan(ctx, "frostfs.ContainerEACL")
+	defer func() { tracing.End(span, err) }()
+
 	var prm pool.PrmContainerEACL
 	prm.SetContainerID(id)
 
@@ -201,7 +220,10 @@ func (x *FrostFS) ContainerEACL(ctx context.Context, id cid.ID) (*eacl.Table, er
 }
 
 // DeleteContainer implements frostfs.FrostFS interface method.
-func (x *FrostFS) DeleteContainer(ctx context.Context, id cid.ID, token *session.Container) error {
+func (x *FrostFS) DeleteContainer(ctx context.Context, id cid.ID, token *session.Container) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "frostfs.DeleteContainer")
+	defer func() { tracing.End(span, err) }()
+
 	var prm pool.PrmContainerDelete
 	prm.SetContainerID(id)
 	prm.SetWaitParams(x.await)
@@ -210,7 +232,7 @@ func (x *FrostFS) DeleteContainer(ctx context.Context, id cid.ID, token *session
 		prm.SetSessionToken(*token)
 	}
 
-	err := x.pool.DeleteContainer(ctx, prm)
+	err = x.pool.DeleteContainer(ctx, prm)
 	if err != nil {
 		return fmt.Errorf("delete container via connection pool: %w", err)
 	}
@@ -219,7 +241,10 @@ func (x *FrostFS) DeleteContainer(ctx context.Context, id cid.ID, token *session
 }
 
 // CreateObject implements frostfs.FrostFS interface method.
-func (x *FrostFS) CreateObject(ctx context.Context, prm layer.PrmObjectCreate) (oid.ID, error) {
+func (x *FrostFS) CreateObject(ctx context.Context, prm layer.PrmObjectCreate) (_ oid.ID, err error) {
+	ctx, span := tracing.StartSpan(ctx, "frostfs.CreateObject")
+	defer func() { tracing.End(span, err) }()
+
 	attrNum := len(prm.Attributes) + 1 // + creation time
 
 	if prm.Filepath != "" {
@@ -307,7 +332,10 @@ func (x payloadReader) Read(p []byte) (int, error) {
 }
 
 // ReadObject implements frostfs.FrostFS interface method.
-func (x *FrostFS) ReadObject(ctx context.Context, prm layer.PrmObjectRead) (*layer.ObjectPart, error) {
+func (x *FrostFS) ReadObject(ctx context.Context, prm layer.PrmObjectRead) (_ *layer.ObjectPart, err error) {
+	ctx, span := tracing.StartSpan(ctx, "frostfs.ReadObject")
+	defer func() { tracing.End(span, err) }()
+
 	var addr oid.Address
 	addr.SetContainer(prm.Container)
 	addr.SetObject(prm.Object)
@@ -408,7 +436,10 @@ func (x *FrostFS) ReadObject(ctx context.Context, prm layer.PrmObjectRead) (*lay
 }
 
 // DeleteObject implements frostfs.FrostFS interface method.
-func (x *FrostFS) DeleteObject(ctx context.Context, prm layer.PrmObjectDelete) error {
+func (x *FrostFS) DeleteObject(ctx context.Context, prm layer.PrmObjectDelete) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "frostfs.DeleteObject")
+	defer func() { tracing.End(span, err) }()
+
 	var addr oid.Address
 	addr.SetContainer(prm.Container)
 	addr.SetObject(prm.Object)
@@ -422,7 +453,7 @@ func (x *FrostFS) DeleteObject(ctx context.Context, prm layer.PrmObjectDelete) e
 		prmDelete.UseKey(prm.PrivateKey)
 	}
 
-	err := x.pool.DeleteObject(ctx, prmDelete)
+	err = x.pool.DeleteObject(ctx, prmDelete)
 	if err != nil {
 		if reason, ok := isErrAccessDenied(err); ok {
 			return fmt.Errorf("%w: %s", layer.ErrAccessDenied, reason)
@@ -434,6 +465,58 @@ func (x *FrostFS) DeleteObject(ctx context.Context, prm layer.PrmObjectDelete) e
 	return nil
 }
 
+// SearchObjects implements frostfs.FrostFS interface method.
+func (x *FrostFS) SearchObjects(ctx context.Context, prm layer.PrmObjectSearch) (_ []oid.ID, err error) {
+	ctx, span := tracing.StartSpan(ctx, "frostfs.SearchObjects")
+	defer func() { tracing.End(span, err) }()
+
+	var filters object.SearchFilters
+	filters.AddRootFilter()
+	filters.AddTypeFilter(object.MatchStringEqual, object.TypeRegular)
+	for _, filter := range prm.Filters {
+		if filter.Exists {
+			// any value has the empty prefix
+			filters.AddFilter(filter.Key, "", object.MatchCommonPrefix)
+		} else {
+			filters.AddFilter(filter.Key, filter.Value, object.MatchStringEqual)
+		}
+	}
+
+	var prmSearch pool.PrmObjectSearch
+	prmSearch.SetContainerID(prm.Container)
+	prmSearch.SetFilters(filters)
+
+	if prm.BearerToken != nil {
+		prmSearch.UseBearer(*prm.BearerToken)
+	} else {
+		prmSearch.UseKey(prm.PrivateKey)
+	}
+
+	res, err := x.pool.SearchObjects(ctx, prmSearch)
+	if err != nil {
+		if reason, ok := isErrAccessDenied(err); ok {
+			return nil, fmt.Errorf("%w: %s", layer.ErrAccessDenied, reason)
+		}
+
+		return nil, fmt.Errorf("search objects via connection pool: %w", err)
+	}
+	defer res.Close()
+
+	var ids []oid.ID
+	if err = res.Iterate(func(id oid.ID) bool {
+		ids = append(ids, id)
+		return false
+	}); err != nil {
+		if reason, ok := isErrAccessDenied(err); ok {
+			return nil, fmt.Errorf("%w: %s", layer.ErrAccessDenied, reason)
+		}
+
+		return nil, fmt.Errorf("iterate found objects: %w", err)
+	}
+
+	return ids, nil
+}
+
 func isErrAccessDenied(err error) (string, bool) {
 	unwrappedErr := errors.Unwrap(err)
 	for unwrappedErr != nil {
@@ -558,3 +641,20 @@ func NewPoolStatistic(p *pool.Pool) *PoolStatistic {
 func (x *PoolStatistic) Statistic() pool.Statistic {
 	return x.pool.Statistic()
 }
+
+// Healthcheck returns an error if all nodes of the pool have reached errorThreshold
+// of the current errors and are considered unhealthy.
+func (x *PoolStatistic) Healthcheck(errorThreshold uint32) error {
+	nodes := x.pool.Statistic().Nodes()
+	if len(nodes) == 0 {
+		return errors.New("no nodes in pool")
+	}
+
+	for _, node := range nodes {
+		if node.CurrentErrors() < errorThreshold {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("all %d nodes reached error threshold %d", len(nodes), errorThreshold)
+}
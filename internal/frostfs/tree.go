@@ -2,6 +2,7 @@ package frostfs
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -18,15 +19,38 @@ import (
 	oid "github.com/TrueCloudLab/frostfs-sdk-go/object/id"
 	"github.com/TrueCloudLab/frostfs-sdk-go/user"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"go.uber.org/zap"
 )
 
 type (
 	TreeClient struct {
-		key     *keys.PrivateKey
-		conn    *grpc.ClientConn
-		service tree.TreeServiceClient
+		key       *keys.PrivateKey
+		endpoints []*treeEndpoint
+		cancel    context.CancelFunc
+
+		log                *zap.Logger
+		metrics            TreeMetrics
+		slowThreshold      time.Duration
+		healthcheckTimeout time.Duration
+	}
+
+	// TreeClientConfig contains parameters of TreeClient.
+	TreeClientConfig struct {
+		// Address is an endpoint of the tree service. It's used if Endpoints are empty.
+		Address string
+		// Endpoints are nodes of the tree service, the client fails over between them.
+		Endpoints []TreeEndpoint
+		// HealthcheckTimeout is a timeout of the endpoint healthcheck.
+		HealthcheckTimeout time.Duration
+		// RebalanceInterval is an interval of endpoints healthchecks. Zero disables healthchecks,
+		// so unhealthy endpoints are used again only if all endpoints are unhealthy.
+		RebalanceInterval time.Duration
+		Key               *keys.PrivateKey
+		Log               *zap.Logger
+		// Metrics is optional, requests aren't observed if it's nil.
+		Metrics TreeMetrics
+		// SlowRequestThreshold is a duration of requests which are logged as slow. Zero disables logging.
+		SlowRequestThreshold time.Duration
 	}
 
 	TreeNode struct {
@@ -67,15 +91,31 @@ const (
 	untilDateKV    = "UntilDate"
 	isComplianceKV = "IsCompliance"
 
+	// keys for bucket usage.
+	usageBytesKV          = "Bytes"
+	usageObjectsKV        = "Objects"
+	usageVersionsKV       = "Versions"
+	usageDeleteMarkersKV  = "DeleteMarkers"
+	usageMultipartBytesKV = "MultipartBytes"
+
 	// keys for delete marker nodes.
 	isDeleteMarkerKV = "IsDeleteMarker"
 	ownerKV          = "Owner"
 	createdKV        = "Created"
 
+	// keys for nodes of versions which reference the payload of another version.
+	isCopyKV  = "IsCopy"
+	headersKV = "Headers"
+
+	// keys for appended object.
+	segmentsKV = "Segments"
+
 	settingsFileName      = "bucket-settings"
 	notifConfFileName     = "bucket-notifications"
 	corsFilename          = "bucket-cors"
 	bucketTaggingFilename = "bucket-tagging"
+	usageFileName         = "bucket-usage"
+	objectReferencesPath  = "object-references"
 
 	// versionTree -- ID of a tree with object versions.
 	versionTree = "version"
@@ -90,23 +130,62 @@ const (
 	maxGetSubTreeDepth = 0 // means all subTree
 )
 
-// NewTreeClient creates instance of TreeClient using provided address and create grpc connection.
-func NewTreeClient(ctx context.Context, addr string, key *keys.PrivateKey) (*TreeClient, error) {
-	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+// NewTreeClient creates instance of TreeClient using provided endpoints and create grpc connections.
+// At least one endpoint must be healthy.
+func NewTreeClient(ctx context.Context, cfg TreeClientConfig) (*TreeClient, error) {
+	endpoints := cfg.Endpoints
+	if len(endpoints) == 0 && cfg.Address != "" {
+		endpoints = []TreeEndpoint{{Address: cfg.Address, Priority: 1, Weight: 1}}
+	}
+	if len(endpoints) == 0 {
+		return nil, errNoTreeEndpoints
+	}
+
+	dialed, err := dialTreeEndpoints(endpoints)
 	if err != nil {
-		return nil, fmt.Errorf("did not connect: %v", err)
+		return nil, err
 	}
 
-	c := tree.NewTreeServiceClient(conn)
-	if _, err = c.Healthcheck(ctx, &tree.HealthcheckRequest{}); err != nil {
-		return nil, fmt.Errorf("healthcheck: %w", err)
+	treeClient := &TreeClient{
+		key:       cfg.Key,
+		endpoints: dialed,
+
+		log:                cfg.Log,
+		metrics:            cfg.Metrics,
+		slowThreshold:      cfg.SlowRequestThreshold,
+		healthcheckTimeout: cfg.HealthcheckTimeout,
+	}
+	if treeClient.log == nil {
+		treeClient.log = zap.NewNop()
+	}
+	if treeClient.metrics == nil {
+		treeClient.metrics = noTreeMetrics{}
+	}
+	if treeClient.healthcheckTimeout <= 0 {
+		treeClient.healthcheckTimeout = defaultTreeHealthcheckTimeout
+	}
+
+	if err = treeClient.Healthcheck(ctx); err != nil {
+		_ = treeClient.Close()
+		return nil, err
+	}
+
+	if cfg.RebalanceInterval > 0 {
+		var rebalanceCtx context.Context
+		rebalanceCtx, treeClient.cancel = context.WithCancel(context.Background())
+		go treeClient.startRebalance(rebalanceCtx, cfg.RebalanceInterval)
 	}
 
-	return &TreeClient{
-		key:     key,
-		conn:    conn,
-		service: c,
-	}, nil
+	return treeClient, nil
+}
+
+// Healthcheck checks connectivity to the tree service endpoints.
+// It fails only if there is no healthy endpoint.
+func (c *TreeClient) Healthcheck(ctx context.Context) error {
+	if c.checkEndpoints(ctx) == 0 {
+		return errors.New("healthcheck: no healthy tree service endpoints")
+	}
+	return nil
 }
 
 type NodeResponse interface {
@@ -183,26 +262,45 @@ func newNodeVersionFromTreeNode(filePath string, treeNode *TreeNode) *data.NodeV
 	}
 
 	if isDeleteMarker {
-		var created time.Time
-		if createdStr, ok := treeNode.Get(createdKV); ok {
-			if utcMilli, err := strconv.ParseInt(createdStr, 10, 64); err == nil {
-				created = time.UnixMilli(utcMilli)
-			}
-		}
-
-		var owner user.ID
-		if ownerStr, ok := treeNode.Get(ownerKV); ok {
-			_ = owner.DecodeString(ownerStr)
-		}
-
+		created, owner := treeNode.createdAndOwner()
 		version.DeleteMarker = &data.DeleteMarkerInfo{
 			Created: created,
 			Owner:   owner,
 		}
+	} else if _, isCopy := treeNode.Get(isCopyKV); isCopy {
+		created, owner := treeNode.createdAndOwner()
+		version.Copy = &data.CopyInfo{
+			Created: created,
+			Owner:   owner,
+		}
+		if headers, ok := treeNode.Get(headersKV); ok {
+			_ = json.Unmarshal([]byte(headers), &version.Copy.Headers)
+		}
 	}
+
+	if segments, ok := treeNode.Get(segmentsKV); ok {
+		version.Segments, _ = data.ParseSegments(segments)
+	}
+
 	return version
 }
 
+func (n *TreeNode) createdAndOwner() (time.Time, user.ID) {
+	var created time.Time
+	if createdStr, ok := n.Get(createdKV); ok {
+		if utcMilli, err := strconv.ParseInt(createdStr, 10, 64); err == nil {
+			created = time.UnixMilli(utcMilli)
+		}
+	}
+
+	var owner user.ID
+	if ownerStr, ok := n.Get(ownerKV); ok {
+		_ = owner.DecodeString(ownerStr)
+	}
+
+	return created, owner
+}
+
 func newMultipartInfo(node NodeResponse) (*data.MultipartInfo, error) {
 	multipartInfo := &data.MultipartInfo{
 		ID:   node.GetNodeId(),
@@ -308,6 +406,50 @@ func (c *TreeClient) PutSettingsNode(ctx context.Context, bktInfo *data.BucketIn
 	return c.moveNode(ctx, bktInfo, systemTree, node.ID, 0, meta)
 }
 
+func (c *TreeClient) GetBucketUsage(ctx context.Context, bktInfo *data.BucketInfo) (*data.BucketUsage, error) {
+	keysToReturn := []string{usageBytesKV, usageObjectsKV, usageVersionsKV, usageDeleteMarkersKV, usageMultipartBytesKV}
+	node, err := c.getSystemNode(ctx, bktInfo, []string{usageFileName}, keysToReturn)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get node: %w", err)
+	}
+
+	usage := new(data.BucketUsage)
+	for key, val := range map[string]*int64{
+		usageBytesKV:          &usage.Bytes,
+		usageObjectsKV:        &usage.Objects,
+		usageVersionsKV:       &usage.Versions,
+		usageDeleteMarkersKV:  &usage.DeleteMarkers,
+		usageMultipartBytesKV: &usage.MultipartBytes,
+	} {
+		value, ok := node.Get(key)
+		if !ok {
+			continue
+		}
+		if *val, err = strconv.ParseInt(value, 10, 64); err != nil {
+			return nil, fmt.Errorf("usage node: invalid %s: %w", key, err)
+		}
+	}
+
+	return usage, nil
+}
+
+func (c *TreeClient) PutBucketUsage(ctx context.Context, bktInfo *data.BucketInfo, usage *data.BucketUsage) error {
+	node, err := c.getSystemNode(ctx, bktInfo, []string{usageFileName}, []string{})
+	isErrNotFound := errors.Is(err, layer.ErrNodeNotFound)
+	if err != nil && !isErrNotFound {
+		return fmt.Errorf("couldn't get node: %w", err)
+	}
+
+	meta := metaFromUsage(usage)
+
+	if isErrNotFound {
+		_, err = c.addNode(ctx, bktInfo, systemTree, 0, meta)
+		return err
+	}
+
+	return c.moveNode(ctx, bktInfo, systemTree, node.ID, 0, meta)
+}
+
 func (c *TreeClient) GetNotificationConfigurationNode(ctx context.Context, bktInfo *data.BucketInfo) (oid.ID, error) {
 	node, err := c.getSystemNode(ctx, bktInfo, []string{notifConfFileName}, []string{oidKV})
 	if err != nil {
@@ -381,6 +523,36 @@ func (c *TreeClient) DeleteBucketCORS(ctx context.Context, bktInfo *data.BucketI
 	return oid.ID{}, layer.ErrNoNodeToRemove
 }
 
+func (c *TreeClient) AddObjectReference(ctx context.Context, bktInfo *data.BucketInfo, objID oid.ID) error {
+	_, err := c.addNodeByPath(ctx, bktInfo, systemTree, []string{objectReferencesPath}, map[string]string{fileNameKV: objID.EncodeToString()})
+	return err
+}
+
+func (c *TreeClient) GetObjectReferences(ctx context.Context, bktInfo *data.BucketInfo, objID oid.ID) ([]uint64, error) {
+	nodes, err := c.getNodes(ctx, &getNodesParams{
+		BktInfo: bktInfo,
+		TreeID:  systemTree,
+		Path:    []string{objectReferencesPath, objID.EncodeToString()},
+	})
+	if err != nil {
+		if errors.Is(err, layer.ErrNodeNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	result := make([]uint64, len(nodes))
+	for i, node := range nodes {
+		result[i] = node.GetNodeId()
+	}
+
+	return result, nil
+}
+
+func (c *TreeClient) RemoveObjectReference(ctx context.Context, bktInfo *data.BucketInfo, nodeID uint64) error {
+	return c.removeNode(ctx, bktInfo, systemTree, nodeID)
+}
+
 func (c *TreeClient) GetObjectTagging(ctx context.Context, bktInfo *data.BucketInfo, objVersion *data.NodeVersion) (map[string]string, error) {
 	tagNode, err := c.getTreeNode(ctx, bktInfo, objVersion.ID, isTagKV)
 	if err != nil {
@@ -536,7 +708,7 @@ func (c *TreeClient) GetVersions(ctx context.Context, bktInfo *data.BucketInfo,
 }
 
 func (c *TreeClient) GetLatestVersion(ctx context.Context, bktInfo *data.BucketInfo, objectName string) (*data.NodeVersion, error) {
-	meta := []string{oidKV, isUnversionedKV, isDeleteMarkerKV, etagKV, sizeKV}
+	meta := []string{oidKV, isUnversionedKV, isDeleteMarkerKV, etagKV, sizeKV, isCopyKV, createdKV, ownerKV, headersKV, segmentsKV}
 	path := pathFromName(objectName)
 
 	p := &getNodesParams{
@@ -564,8 +736,66 @@ func pathFromName(objectName string) []string {
 	return strings.Split(objectName, separator)
 }
 
-func (c *TreeClient) GetLatestVersionsByPrefix(ctx context.Context, bktInfo *data.BucketInfo, prefix string) ([]*data.NodeVersion, error) {
-	return c.getVersionsByPrefix(ctx, bktInfo, prefix, true)
+func (c *TreeClient) GetDirectory(ctx context.Context, bktInfo *data.BucketInfo, path string) (*data.TreeDirectory, error) {
+	if path == "" {
+		return &data.TreeDirectory{}, nil
+	}
+
+	nodeID, err := c.getPrefixNodeID(ctx, bktInfo, versionTree, pathFromName(path))
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.TreeDirectory{ID: nodeID, Path: path + separator}, nil
+}
+
+func (c *TreeClient) GetDirectoryChildren(ctx context.Context, bktInfo *data.BucketInfo, dir *data.TreeDirectory, latestOnly bool) ([]*data.NodeVersion, []*data.TreeDirectory, error) {
+	subTree, err := c.getSubTree(ctx, bktInfo, versionTree, dir.ID, 2)
+	if err != nil {
+		if errors.Is(err, layer.ErrNodeNotFound) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+
+	var (
+		emptyOID oid.ID
+		dirs     []*data.TreeDirectory
+		versions []*data.NodeVersion
+		latest   = make(map[string]*data.NodeVersion, len(subTree))
+	)
+
+	for _, node := range subTree {
+		if node.GetNodeId() == dir.ID {
+			continue
+		}
+
+		treeNode, fileName, err := parseTreeNode(node)
+		if err != nil {
+			continue
+		}
+
+		if isIntermediate(node) {
+			dirs = append(dirs, &data.TreeDirectory{ID: treeNode.ID, Path: dir.Path + fileName + separator})
+			continue
+		}
+
+		if treeNode.ObjID.Equals(emptyOID) {
+			continue
+		}
+
+		if !latestOnly {
+			versions = append(versions, newNodeVersionFromTreeNode(dir.Path+fileName, treeNode))
+		} else if version, ok := latest[fileName]; !ok || version.Timestamp <= treeNode.TimeStamp {
+			latest[fileName] = newNodeVersionFromTreeNode(dir.Path+fileName, treeNode)
+		}
+	}
+
+	for _, version := range latest {
+		versions = append(versions, version)
+	}
+
+	return versions, dirs, nil
 }
 
 func (c *TreeClient) determinePrefixNode(ctx context.Context, bktInfo *data.BucketInfo, treeID, prefix string) (uint64, string, error) {
@@ -822,10 +1052,161 @@ func (c *TreeClient) AddVersion(ctx context.Context, bktInfo *data.BucketInfo, v
 	return c.addVersion(ctx, bktInfo, versionTree, version)
 }
 
+func (c *TreeClient) AddVersions(ctx context.Context, bktInfo *data.BucketInfo, versions []*data.NodeVersion) ([]*data.NodeVersion, error) {
+	var (
+		dirs      []string
+		dirGroups = make(map[string][]*data.NodeVersion)
+		replaced  []*data.NodeVersion
+	)
+	for _, version := range versions {
+		path := pathFromName(version.FilePath)
+		dir := strings.Join(path[:len(path)-1], separator)
+		if _, ok := dirGroups[dir]; !ok {
+			dirs = append(dirs, dir)
+		}
+		dirGroups[dir] = append(dirGroups[dir], version)
+	}
+
+	for _, dir := range dirs {
+		var path []string
+		if len(dir) != 0 {
+			path = pathFromName(dir)
+		}
+
+		parentID, err := c.ensureDirectory(ctx, bktInfo, path)
+		if err != nil {
+			return replaced, err
+		}
+
+		// only names of the batch are looked up, so adding of many batches to the same
+		// directory doesn't fetch the whole directory every time
+		unversioned := make(map[string]*data.NodeVersion)
+		for _, version := range dirGroups[dir] {
+			meta, err := versionMeta(version)
+			if err != nil {
+				return replaced, err
+			}
+
+			fileName := meta[fileNameKV]
+			if version.IsUnversioned {
+				node, ok := unversioned[fileName]
+				if !ok {
+					if node, err = c.getUnversioned(ctx, bktInfo, versionTree, version.FilePath); err != nil && !errors.Is(err, layer.ErrNodeNotFound) {
+						return replaced, err
+					}
+				}
+
+				if node != nil {
+					if err = c.moveNode(ctx, bktInfo, versionTree, node.ID, parentID, meta); err != nil {
+						return replaced, err
+					}
+					if err = c.clearOutdatedVersionInfo(ctx, bktInfo, versionTree, node.ID); err != nil {
+						return replaced, err
+					}
+					version.ID = node.ID
+					unversioned[fileName] = version
+					replaced = append(replaced, node)
+					continue
+				}
+			}
+
+			if version.ID, err = c.addNode(ctx, bktInfo, versionTree, parentID, meta); err != nil {
+				return replaced, err
+			}
+			if version.IsUnversioned {
+				unversioned[fileName] = version
+			}
+		}
+	}
+
+	return replaced, nil
+}
+
+func (c *TreeClient) UpdateVersion(ctx context.Context, bktInfo *data.BucketInfo, version *data.NodeVersion) error {
+	meta, err := versionMeta(version)
+	if err != nil {
+		return err
+	}
+
+	return c.moveNode(ctx, bktInfo, versionTree, version.ID, version.ParenID, meta)
+}
+
 func (c *TreeClient) RemoveVersion(ctx context.Context, bktInfo *data.BucketInfo, id uint64) error {
 	return c.removeNode(ctx, bktInfo, versionTree, id)
 }
 
+func (c *TreeClient) MoveVersions(ctx context.Context, bktInfo *data.BucketInfo, versions []*data.NodeVersion, newName string) error {
+	if len(versions) == 0 {
+		return nil
+	}
+
+	// move replaces all attributes of the node, so the current ones are fetched to be preserved
+	nodes, err := c.getNodes(ctx, &getNodesParams{
+		BktInfo:  bktInfo,
+		TreeID:   versionTree,
+		Path:     pathFromName(versions[0].FilePath),
+		AllAttrs: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	metas := make(map[uint64]map[string]string, len(nodes))
+	for _, node := range nodes {
+		meta := make(map[string]string, len(node.GetMeta()))
+		for _, kv := range node.GetMeta() {
+			meta[kv.GetKey()] = string(kv.GetValue())
+		}
+		metas[node.GetNodeId()] = meta
+	}
+
+	path := pathFromName(newName)
+	parentID, err := c.ensureDirectory(ctx, bktInfo, path[:len(path)-1])
+	if err != nil {
+		return err
+	}
+
+	for _, version := range versions {
+		meta, ok := metas[version.ID]
+		if !ok {
+			return fmt.Errorf("%w: version node %d of '%s'", layer.ErrNodeNotFound, version.ID, version.FilePath)
+		}
+		meta[fileNameKV] = path[len(path)-1]
+
+		if err = c.moveNode(ctx, bktInfo, versionTree, version.ID, parentID, meta); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *TreeClient) MoveDirectory(ctx context.Context, bktInfo *data.BucketInfo, dir *data.TreeDirectory, newPath string) error {
+	path := pathFromName(newPath)
+	parentID, err := c.ensureDirectory(ctx, bktInfo, path[:len(path)-1])
+	if err != nil {
+		return err
+	}
+
+	return c.moveNode(ctx, bktInfo, versionTree, dir.ID, parentID, map[string]string{fileNameKV: path[len(path)-1]})
+}
+
+// ensureDirectory returns id of the intermediate node of the version tree with the provided path
+// and creates it (with all missing parents) if it doesn't exist.
+func (c *TreeClient) ensureDirectory(ctx context.Context, bktInfo *data.BucketInfo, path []string) (uint64, error) {
+	if len(path) == 0 {
+		return 0, nil
+	}
+
+	nodeID, err := c.getPrefixNodeID(ctx, bktInfo, versionTree, path)
+	if err == nil || !errors.Is(err, layer.ErrNodeNotFound) {
+		return nodeID, err
+	}
+
+	// node with the only FileName attribute is an intermediate one
+	return c.addNodeByPath(ctx, bktInfo, versionTree, path[:len(path)-1], map[string]string{fileNameKV: path[len(path)-1]})
+}
+
 func (c *TreeClient) CreateMultipartUpload(ctx context.Context, bktInfo *data.BucketInfo, info *data.MultipartInfo) error {
 	path := pathFromName(info.Key)
 	meta := metaFromMultipart(info, path[len(path)-1])
@@ -897,10 +1278,10 @@ func (c *TreeClient) GetMultipartUpload(ctx context.Context, bktInfo *data.Bucke
 	return nil, layer.ErrNodeNotFound
 }
 
-func (c *TreeClient) AddPart(ctx context.Context, bktInfo *data.BucketInfo, multipartNodeID uint64, info *data.PartInfo) (oldObjIDToDelete oid.ID, err error) {
+func (c *TreeClient) AddPart(ctx context.Context, bktInfo *data.BucketInfo, multipartNodeID uint64, info *data.PartInfo) (oldPartToDelete *data.PartInfo, err error) {
 	parts, err := c.getSubTree(ctx, bktInfo, systemTree, multipartNodeID, 2)
 	if err != nil {
-		return oid.ID{}, err
+		return nil, err
 	}
 
 	meta := map[string]string{
@@ -922,19 +1303,19 @@ func (c *TreeClient) AddPart(ctx context.Context, bktInfo *data.BucketInfo, mult
 		}
 		if partInfo.Number == info.Number {
 			foundPartID = part.GetNodeId()
-			oldObjIDToDelete = partInfo.OID
+			oldPartToDelete = partInfo
 			break
 		}
 	}
 
-	if foundPartID != multipartNodeID {
+	if oldPartToDelete == nil {
 		if _, err = c.addNode(ctx, bktInfo, systemTree, multipartNodeID, meta); err != nil {
-			return oid.ID{}, err
+			return nil, err
 		}
-		return oid.ID{}, layer.ErrNoNodeToRemove
+		return nil, layer.ErrNoNodeToRemove
 	}
 
-	return oldObjIDToDelete, c.moveNode(ctx, bktInfo, systemTree, foundPartID, multipartNodeID, meta)
+	return oldPartToDelete, c.moveNode(ctx, bktInfo, systemTree, foundPartID, multipartNodeID, meta)
 }
 
 func (c *TreeClient) GetParts(ctx context.Context, bktInfo *data.BucketInfo, multipartNodeID uint64) ([]*data.PartInfo, error) {
@@ -1035,14 +1416,47 @@ func (c *TreeClient) GetObjectTaggingAndLock(ctx context.Context, bktInfo *data.
 }
 
 func (c *TreeClient) Close() error {
-	if c.conn != nil {
-		return c.conn.Close()
+	if c.cancel != nil {
+		c.cancel()
 	}
 
-	return nil
+	var err error
+	for _, e := range c.endpoints {
+		if closeErr := e.conn.Close(); closeErr != nil {
+			err = closeErr
+		}
+	}
+
+	return err
 }
 
 func (c *TreeClient) addVersion(ctx context.Context, bktInfo *data.BucketInfo, treeID string, version *data.NodeVersion) (uint64, error) {
+	meta, err := versionMeta(version)
+	if err != nil {
+		return 0, err
+	}
+
+	if version.IsUnversioned {
+		node, err := c.getUnversioned(ctx, bktInfo, treeID, version.FilePath)
+		if err == nil {
+			if err = c.moveNode(ctx, bktInfo, treeID, node.ID, node.ParenID, meta); err != nil {
+				return 0, err
+			}
+
+			return node.ID, c.clearOutdatedVersionInfo(ctx, bktInfo, treeID, node.ID)
+		}
+
+		if !errors.Is(err, layer.ErrNodeNotFound) {
+			return 0, err
+		}
+	}
+
+	path := pathFromName(version.FilePath)
+	return c.addNodeByPath(ctx, bktInfo, treeID, path[:len(path)-1], meta)
+}
+
+// versionMeta forms attributes of the version tree node.
+func versionMeta(version *data.NodeVersion) (map[string]string, error) {
 	path := pathFromName(version.FilePath)
 	meta := map[string]string{
 		oidKV:      version.OID.EncodeToString(),
@@ -1062,24 +1476,26 @@ func (c *TreeClient) addVersion(ctx context.Context, bktInfo *data.BucketInfo, t
 		meta[createdKV] = strconv.FormatInt(version.DeleteMarker.Created.UTC().UnixMilli(), 10)
 	}
 
-	if version.IsUnversioned {
-		meta[isUnversionedKV] = "true"
-
-		node, err := c.getUnversioned(ctx, bktInfo, treeID, version.FilePath)
-		if err == nil {
-			if err = c.moveNode(ctx, bktInfo, treeID, node.ID, node.ParenID, meta); err != nil {
-				return 0, err
-			}
-
-			return node.ID, c.clearOutdatedVersionInfo(ctx, bktInfo, treeID, node.ID)
+	if version.Copy != nil {
+		headers, err := json.Marshal(version.Copy.Headers)
+		if err != nil {
+			return nil, fmt.Errorf("marshal headers: %w", err)
 		}
+		meta[isCopyKV] = "true"
+		meta[ownerKV] = version.Copy.Owner.EncodeToString()
+		meta[createdKV] = strconv.FormatInt(version.Copy.Created.UTC().UnixMilli(), 10)
+		meta[headersKV] = string(headers)
+	}
 
-		if !errors.Is(err, layer.ErrNodeNotFound) {
-			return 0, err
-		}
+	if len(version.Segments) != 0 {
+		meta[segmentsKV] = data.SegmentsToString(version.Segments)
 	}
 
-	return c.addNodeByPath(ctx, bktInfo, treeID, path[:len(path)-1], meta)
+	if version.IsUnversioned {
+		meta[isUnversionedKV] = "true"
+	}
+
+	return meta, nil
 }
 
 func (c *TreeClient) clearOutdatedVersionInfo(ctx context.Context, bktInfo *data.BucketInfo, treeID string, nodeID uint64) error {
@@ -1095,7 +1511,7 @@ func (c *TreeClient) clearOutdatedVersionInfo(ctx context.Context, bktInfo *data
 }
 
 func (c *TreeClient) getVersions(ctx context.Context, bktInfo *data.BucketInfo, treeID, filepath string, onlyUnversioned bool) ([]*data.NodeVersion, error) {
-	keysToReturn := []string{oidKV, isUnversionedKV, isDeleteMarkerKV, etagKV, sizeKV}
+	keysToReturn := []string{oidKV, isUnversionedKV, isDeleteMarkerKV, etagKV, sizeKV, isCopyKV, createdKV, ownerKV, headersKV, segmentsKV}
 	path := pathFromName(filepath)
 	p := &getNodesParams{
 		BktInfo:    bktInfo,
@@ -1150,20 +1566,30 @@ func (c *TreeClient) getSubTree(ctx context.Context, bktInfo *data.BucketInfo, t
 		return nil, err
 	}
 
-	cli, err := c.service.GetSubTree(ctx, request)
-	if err != nil {
-		return nil, handleError("failed to get sub tree client", err)
-	}
-
 	var subtree []*tree.GetSubTreeResponse_Body
-	for {
-		resp, err := cli.Recv()
-		if err == io.EOF {
-			break
-		} else if err != nil {
-			return nil, handleError("failed to get sub tree", err)
+
+	finish := c.startRequest(treeMethodGetSubTree, bktInfo, treeID)
+	err := c.request(ctx, treeMethodGetSubTree, func(service tree.TreeServiceClient) error {
+		subtree = nil
+
+		cli, err := service.GetSubTree(ctx, request)
+		if err != nil {
+			return err
+		}
+
+		for {
+			resp, err := cli.Recv()
+			if err == io.EOF {
+				return nil
+			} else if err != nil {
+				return err
+			}
+			subtree = append(subtree, resp.Body)
 		}
-		subtree = append(subtree, resp.Body)
+	})
+	finish(err)
+	if err != nil {
+		return nil, handleError("failed to get sub tree", err)
 	}
 
 	return subtree, nil
@@ -1179,6 +1605,17 @@ func metaFromSettings(settings *data.BucketSettings) map[string]string {
 	return results
 }
 
+func metaFromUsage(usage *data.BucketUsage) map[string]string {
+	return map[string]string{
+		fileNameKV:            usageFileName,
+		usageBytesKV:          strconv.FormatInt(usage.Bytes, 10),
+		usageObjectsKV:        strconv.FormatInt(usage.Objects, 10),
+		usageVersionsKV:       strconv.FormatInt(usage.Versions, 10),
+		usageDeleteMarkersKV:  strconv.FormatInt(usage.DeleteMarkers, 10),
+		usageMultipartBytesKV: strconv.FormatInt(usage.MultipartBytes, 10),
+	}
+}
+
 func metaFromMultipart(info *data.MultipartInfo, fileName string) map[string]string {
 	info.Meta[fileNameKV] = fileName
 	info.Meta[uploadIDKV] = info.UploadID
@@ -1242,7 +1679,13 @@ func (c *TreeClient) getNodes(ctx context.Context, p *getNodesParams) ([]*tree.G
 		return nil, err
 	}
 
-	resp, err := c.service.GetNodeByPath(ctx, request)
+	finish := c.startRequest(treeMethodGetNodeByPath, p.BktInfo, p.TreeID)
+	var resp *tree.GetNodeByPathResponse
+	err := c.request(ctx, treeMethodGetNodeByPath, func(service tree.TreeServiceClient) (err error) {
+		resp, err = service.GetNodeByPath(ctx, request)
+		return err
+	})
+	finish(err)
 	if err != nil {
 		return nil, handleError("failed to get node by path", err)
 	}
@@ -1251,9 +1694,10 @@ func (c *TreeClient) getNodes(ctx context.Context, p *getNodesParams) ([]*tree.G
 }
 
 func handleError(msg string, err error) error {
-	if strings.Contains(err.Error(), "not found") {
+	switch classifyTreeError(err) {
+	case TreeErrorNotFound:
 		return fmt.Errorf("%w: %s", layer.ErrNodeNotFound, err.Error())
-	} else if strings.Contains(err.Error(), "is denied by") {
+	case TreeErrorAccessDenied:
 		return fmt.Errorf("%w: %s", layer.ErrNodeAccessDenied, err.Error())
 	}
 	return fmt.Errorf("%s: %w", msg, err)
@@ -1289,7 +1733,13 @@ func (c *TreeClient) addNode(ctx context.Context, bktInfo *data.BucketInfo, tree
 		return 0, err
 	}
 
-	resp, err := c.service.Add(ctx, request)
+	finish := c.startRequest(treeMethodAdd, bktInfo, treeID)
+	var resp *tree.AddResponse
+	err := c.request(ctx, treeMethodAdd, func(service tree.TreeServiceClient) (err error) {
+		resp, err = service.Add(ctx, request)
+		return err
+	})
+	finish(err)
 	if err != nil {
 		return 0, handleError("failed to add node", err)
 	}
@@ -1318,7 +1768,13 @@ func (c *TreeClient) addNodeByPath(ctx context.Context, bktInfo *data.BucketInfo
 		return 0, err
 	}
 
-	resp, err := c.service.AddByPath(ctx, request)
+	finish := c.startRequest(treeMethodAddByPath, bktInfo, treeID)
+	var resp *tree.AddByPathResponse
+	err := c.request(ctx, treeMethodAddByPath, func(service tree.TreeServiceClient) (err error) {
+		resp, err = service.AddByPath(ctx, request)
+		return err
+	})
+	finish(err)
 	if err != nil {
 		return 0, handleError("failed to add node by path", err)
 	}
@@ -1355,7 +1811,13 @@ func (c *TreeClient) moveNode(ctx context.Context, bktInfo *data.BucketInfo, tre
 		return err
 	}
 
-	if _, err := c.service.Move(ctx, request); err != nil {
+	finish := c.startRequest(treeMethodMove, bktInfo, treeID)
+	err := c.request(ctx, treeMethodMove, func(service tree.TreeServiceClient) error {
+		_, err := service.Move(ctx, request)
+		return err
+	})
+	finish(err)
+	if err != nil {
 		return handleError("failed to move node", err)
 	}
 
@@ -1380,7 +1842,13 @@ func (c *TreeClient) removeNode(ctx context.Context, bktInfo *data.BucketInfo, t
 		return err
 	}
 
-	if _, err := c.service.Remove(ctx, request); err != nil {
+	finish := c.startRequest(treeMethodRemove, bktInfo, treeID)
+	err := c.request(ctx, treeMethodRemove, func(service tree.TreeServiceClient) error {
+		_, err := service.Remove(ctx, request)
+		return err
+	})
+	finish(err)
+	if err != nil {
 		return handleError("failed to remove node", err)
 	}
 
@@ -0,0 +1,96 @@
+package frostfs
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TreeMetrics collects statistics of tree service requests.
+type TreeMetrics interface {
+	// TreeRequestStarted is called right before the request.
+	TreeRequestStarted(method string)
+	// TreeRequestFinished is called after the request. Error class is empty for successful requests.
+	TreeRequestFinished(method, errClass string, duration time.Duration)
+	// TreeEndpointHealth is called after the healthcheck or failed request of the tree service endpoint.
+	TreeEndpointHealth(endpoint string, healthy bool)
+}
+
+// Methods of the tree service.
+const (
+	treeMethodGetNodeByPath = "GetNodeByPath"
+	treeMethodGetSubTree    = "GetSubTree"
+	treeMethodAdd           = "Add"
+	treeMethodAddByPath     = "AddByPath"
+	treeMethodMove          = "Move"
+	treeMethodRemove        = "Remove"
+)
+
+// Classes of tree service errors.
+const (
+	TreeErrorNotFound     = "not_found"
+	TreeErrorAccessDenied = "access_denied"
+	TreeErrorTransport    = "transport"
+	TreeErrorOther        = "other"
+)
+
+type noTreeMetrics struct{}
+
+func (noTreeMetrics) TreeRequestStarted(string) {}
+
+func (noTreeMetrics) TreeRequestFinished(string, string, time.Duration) {}
+
+func (noTreeMetrics) TreeEndpointHealth(string, bool) {}
+
+// startRequest must be called right before the request to the tree service.
+// The returned function must be called with the request error when it's finished.
+func (c *TreeClient) startRequest(method string, bktInfo *data.BucketInfo, treeID string) func(error) {
+	start := time.Now()
+	c.metrics.TreeRequestStarted(method)
+
+	return func(err error) {
+		duration := time.Since(start)
+		c.metrics.TreeRequestFinished(method, classifyTreeError(err), duration)
+
+		if c.slowThreshold > 0 && duration >= c.slowThreshold {
+			c.log.Warn("slow tree request",
+				zap.String("method", method),
+				zap.Stringer("cid", bktInfo.CID),
+				zap.String("tree_id", treeID),
+				zap.Duration("duration", duration),
+				zap.Error(err))
+		}
+	}
+}
+
+// classifyTreeError returns class of the error returned by the tree service
+// or empty string if there is no error.
+func classifyTreeError(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		return TreeErrorNotFound
+	case strings.Contains(err.Error(), "is denied by"):
+		return TreeErrorAccessDenied
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return TreeErrorTransport
+	}
+
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Canceled, codes.ResourceExhausted:
+		return TreeErrorTransport
+	default:
+		return TreeErrorOther
+	}
+}
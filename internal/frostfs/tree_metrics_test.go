@@ -0,0 +1,28 @@
+package frostfs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestClassifyTreeError(t *testing.T) {
+	for _, tc := range []struct {
+		err      error
+		expected string
+	}{
+		{err: nil, expected: ""},
+		{err: status.Error(codes.Unknown, "tree not found"), expected: TreeErrorNotFound},
+		{err: status.Error(codes.Unknown, "access to operation is denied by basic ACL check"), expected: TreeErrorAccessDenied},
+		{err: status.Error(codes.Unavailable, "connection refused"), expected: TreeErrorTransport},
+		{err: fmt.Errorf("recv: %w", context.DeadlineExceeded), expected: TreeErrorTransport},
+		{err: errors.New("invalid signature"), expected: TreeErrorOther},
+	} {
+		require.Equal(t, tc.expected, classifyTreeError(tc.err), tc.err)
+	}
+}
@@ -0,0 +1,207 @@
+package frostfs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/TrueCloudLab/frostfs-s3-gw/internal/frostfs/services/tree"
+	"github.com/TrueCloudLab/frostfs-s3-gw/internal/tracing"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+type (
+	// TreeEndpoint is an address of the tree service node.
+	// Endpoints with the lower priority value are used first, requests are
+	// distributed between endpoints of the same priority according to their weights.
+	TreeEndpoint struct {
+		Address  string
+		Priority int
+		Weight   float64
+	}
+
+	treeEndpoint struct {
+		TreeEndpoint
+		conn    *grpc.ClientConn
+		service tree.TreeServiceClient
+		healthy int32
+	}
+)
+
+const (
+	defaultTreeHealthcheckTimeout = 15 * time.Second
+)
+
+var errNoTreeEndpoints = errors.New("no tree service endpoints")
+
+func (e *treeEndpoint) isHealthy() bool {
+	return atomic.LoadInt32(&e.healthy) == 1
+}
+
+func dialTreeEndpoints(endpoints []TreeEndpoint) ([]*treeEndpoint, error) {
+	result := make([]*treeEndpoint, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		conn, err := grpc.Dial(endpoint.Address,
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithUnaryInterceptor(tracing.UnaryClientInterceptor()),
+			grpc.WithStreamInterceptor(tracing.StreamClientInterceptor()),
+		)
+		if err != nil {
+			for _, e := range result {
+				_ = e.conn.Close()
+			}
+			return nil, fmt.Errorf("did not connect to '%s': %v", endpoint.Address, err)
+		}
+
+		if endpoint.Weight <= 0 {
+			endpoint.Weight = 1
+		}
+
+		result = append(result, &treeEndpoint{
+			TreeEndpoint: endpoint,
+			conn:         conn,
+			service:      tree.NewTreeServiceClient(conn),
+		})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Priority < result[j].Priority
+	})
+
+	return result, nil
+}
+
+func (c *TreeClient) setHealthy(e *treeEndpoint, healthy bool, err error) {
+	var value int32
+	if healthy {
+		value = 1
+	}
+
+	if atomic.SwapInt32(&e.healthy, value) != value {
+		if healthy {
+			c.log.Info("tree service endpoint is healthy", zap.String("endpoint", e.Address))
+		} else {
+			c.log.Warn("tree service endpoint is unhealthy", zap.String("endpoint", e.Address), zap.Error(err))
+		}
+	}
+	c.metrics.TreeEndpointHealth(e.Address, healthy)
+}
+
+// checkEndpoints runs healthcheck of all endpoints and returns the number of healthy ones.
+func (c *TreeClient) checkEndpoints(ctx context.Context) int {
+	var (
+		wg      sync.WaitGroup
+		healthy int32
+	)
+
+	for _, e := range c.endpoints {
+		wg.Add(1)
+		go func(e *treeEndpoint) {
+			defer wg.Done()
+
+			checkCtx, cancel := context.WithTimeout(ctx, c.healthcheckTimeout)
+			defer cancel()
+
+			_, err := e.service.Healthcheck(checkCtx, &tree.HealthcheckRequest{})
+			c.setHealthy(e, err == nil, err)
+			if err == nil {
+				atomic.AddInt32(&healthy, 1)
+			}
+		}(e)
+	}
+	wg.Wait()
+
+	return int(healthy)
+}
+
+func (c *TreeClient) startRebalance(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.checkEndpoints(ctx)
+		}
+	}
+}
+
+// endpointsOrder returns healthy endpoints in order they should be tried:
+// by priority and randomly according to weights inside the same priority.
+// If there are no healthy endpoints all of them are returned.
+func (c *TreeClient) endpointsOrder() []*treeEndpoint {
+	result := make([]*treeEndpoint, 0, len(c.endpoints))
+	for _, e := range c.endpoints {
+		if e.isHealthy() {
+			result = append(result, e)
+		}
+	}
+	if len(result) == 0 {
+		result = append(result, c.endpoints...)
+	}
+
+	for start := 0; start < len(result); {
+		end := start + 1
+		for end < len(result) && result[end].Priority == result[start].Priority {
+			end++
+		}
+		shuffleByWeight(result[start:end])
+		start = end
+	}
+
+	return result
+}
+
+// shuffleByWeight orders endpoints randomly, endpoints with greater weight are more likely to be first.
+func shuffleByWeight(endpoints []*treeEndpoint) {
+	for i := 0; i < len(endpoints)-1; i++ {
+		var total float64
+		for _, e := range endpoints[i:] {
+			total += e.Weight
+		}
+
+		point := rand.Float64() * total
+		for j := i; j < len(endpoints); j++ {
+			if point -= endpoints[j].Weight; point < 0 || j == len(endpoints)-1 {
+				endpoints[i], endpoints[j] = endpoints[j], endpoints[i]
+				break
+			}
+		}
+	}
+}
+
+// request runs the function with clients of tree service endpoints until it succeeds.
+// The next endpoint is used only if the request fails because of transport error,
+// such endpoint is considered unhealthy until the next successful healthcheck.
+// Modifying requests are repeated only if the endpoint is unavailable: after a timeout
+// the change could have been applied, so it mustn't be made twice.
+func (c *TreeClient) request(ctx context.Context, method string, f func(service tree.TreeServiceClient) error) error {
+	err := errNoTreeEndpoints
+	for _, e := range c.endpointsOrder() {
+		if err = f(e.service); err == nil || classifyTreeError(err) != TreeErrorTransport || ctx.Err() != nil {
+			return err
+		}
+		c.setHealthy(e, false, err)
+
+		if !isReadOnlyTreeMethod(method) && status.Code(err) != codes.Unavailable {
+			return err
+		}
+	}
+
+	return err
+}
+
+func isReadOnlyTreeMethod(method string) bool {
+	return method == treeMethodGetNodeByPath || method == treeMethodGetSubTree
+}
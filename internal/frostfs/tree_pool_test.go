@@ -0,0 +1,133 @@
+package frostfs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/TrueCloudLab/frostfs-s3-gw/internal/frostfs/services/tree"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type healthcheckTreeService struct {
+	tree.TreeServiceClient
+	err error
+}
+
+func (s *healthcheckTreeService) Healthcheck(context.Context, *tree.HealthcheckRequest, ...grpc.CallOption) (*tree.HealthcheckResponse, error) {
+	return &tree.HealthcheckResponse{}, s.err
+}
+
+type healthMetrics struct {
+	noTreeMetrics
+	health map[string]bool
+}
+
+func (m *healthMetrics) TreeEndpointHealth(endpoint string, healthy bool) {
+	m.health[endpoint] = healthy
+}
+
+func TestTreeClientFailover(t *testing.T) {
+	services := []*healthcheckTreeService{{}, {}, {}}
+	metrics := &healthMetrics{health: make(map[string]bool)}
+	c := &TreeClient{
+		log:                zap.NewNop(),
+		metrics:            metrics,
+		healthcheckTimeout: time.Second,
+	}
+	for i, endpoint := range []TreeEndpoint{
+		{Address: "node1", Priority: 1, Weight: 1},
+		{Address: "node2", Priority: 2, Weight: 1},
+		{Address: "node3", Priority: 2, Weight: 1},
+	} {
+		c.endpoints = append(c.endpoints, &treeEndpoint{TreeEndpoint: endpoint, service: services[i]})
+	}
+
+	ctx := context.Background()
+	require.NoError(t, c.Healthcheck(ctx))
+	require.Equal(t, map[string]bool{"node1": true, "node2": true, "node3": true}, metrics.health)
+
+	var used []tree.TreeServiceClient
+	methodRequestErr := func(method string, errs ...error) error {
+		used = used[:0]
+		return c.request(ctx, method, func(service tree.TreeServiceClient) error {
+			used = append(used, service)
+			err := errs[0]
+			errs = errs[1:]
+			return err
+		})
+	}
+	requestErr := func(errs ...error) error {
+		return methodRequestErr(treeMethodGetSubTree, errs...)
+	}
+
+	t.Run("endpoint with the highest priority is used first", func(t *testing.T) {
+		for i := 0; i < 10; i++ {
+			require.NoError(t, requestErr(nil))
+			require.Len(t, used, 1)
+			require.Same(t, services[0], used[0])
+		}
+	})
+
+	t.Run("logical error is returned as is", func(t *testing.T) {
+		notFound := status.Error(codes.Unknown, "tree not found")
+		require.ErrorIs(t, requestErr(notFound), notFound)
+		require.Len(t, used, 1)
+		require.True(t, c.endpoints[0].isHealthy())
+	})
+
+	t.Run("failover on transport error", func(t *testing.T) {
+		require.NoError(t, requestErr(status.Error(codes.Unavailable, "connection refused"), nil))
+		require.Len(t, used, 2)
+		require.Same(t, services[0], used[0])
+		require.False(t, c.endpoints[0].isHealthy())
+		require.False(t, metrics.health["node1"])
+
+		// unhealthy endpoint isn't used until healthcheck
+		require.NoError(t, requestErr(nil))
+		require.NotSame(t, services[0], used[0])
+	})
+
+	t.Run("all endpoints are tried", func(t *testing.T) {
+		unavailable := status.Error(codes.Unavailable, "connection refused")
+		require.ErrorIs(t, requestErr(unavailable, unavailable, unavailable), unavailable)
+		require.Len(t, used, 2)
+
+		// all endpoints are used if there are no healthy ones
+		require.NoError(t, requestErr(unavailable, unavailable, nil))
+		require.Len(t, used, 3)
+	})
+
+	t.Run("modifying request isn't repeated after timeout", func(t *testing.T) {
+		require.NoError(t, c.Healthcheck(ctx))
+
+		timeout := status.Error(codes.DeadlineExceeded, "context deadline exceeded")
+		require.ErrorIs(t, methodRequestErr(treeMethodAdd, timeout, nil), timeout)
+		require.Len(t, used, 1)
+		require.False(t, c.endpoints[0].isHealthy())
+
+		require.NoError(t, methodRequestErr(treeMethodMove, status.Error(codes.Unavailable, "connection refused"), nil))
+		require.Len(t, used, 2)
+
+		// read-only request is repeated
+		require.NoError(t, c.Healthcheck(ctx))
+		require.NoError(t, requestErr(timeout, nil))
+		require.Len(t, used, 2)
+	})
+
+	t.Run("healthcheck", func(t *testing.T) {
+		services[1].err = errors.New("unhealthy")
+		services[2].err = errors.New("unhealthy")
+		require.NoError(t, c.Healthcheck(ctx))
+		require.True(t, c.endpoints[0].isHealthy())
+		require.False(t, c.endpoints[1].isHealthy())
+
+		services[0].err = errors.New("unhealthy")
+		require.Error(t, c.Healthcheck(ctx))
+	})
+}
@@ -121,3 +121,23 @@ func TestHandleError(t *testing.T) {
 		})
 	}
 }
+
+func TestNodeVersionCopyInfo(t *testing.T) {
+	treeNode := &TreeNode{
+		ID: 1,
+		Meta: map[string]string{
+			isCopyKV:  "true",
+			createdKV: "1672531200000",
+			headersKV: `{"Content-Type":"text/plain","key":"val"}`,
+		},
+	}
+
+	version := newNodeVersionFromTreeNode("obj", treeNode)
+	require.False(t, version.IsDeleteMarker())
+	require.NotNil(t, version.Copy)
+	require.Equal(t, int64(1672531200), version.Copy.Created.Unix())
+	require.Equal(t, map[string]string{"Content-Type": "text/plain", "key": "val"}, version.Copy.Headers)
+
+	delete(treeNode.Meta, isCopyKV)
+	require.Nil(t, newNodeVersionFromTreeNode("obj", treeNode).Copy)
+}
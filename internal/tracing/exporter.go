@@ -0,0 +1,87 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"sync"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// newOTLPClient creates client of OTLP receiver. Endpoint is a URL, 'http' scheme
+// means insecure connection, the path is used only by OTLP/HTTP client.
+func newOTLPClient(exporter, endpoint string, cfg Config) (otlptrace.Client, error) {
+	if endpoint == "" {
+		return nil, fmt.Errorf("endpoint of %s exporter must not be empty", exporter)
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid endpoint of %s exporter: '%s'", exporter, endpoint)
+	}
+	insecure := u.Scheme == "http"
+
+	if exporter == ExporterOTLPGRPC {
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(u.Host), otlptracegrpc.WithTimeout(cfg.Timeout)}
+		if insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		return otlptracegrpc.NewClient(opts...), nil
+	}
+
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(u.Host), otlptracehttp.WithTimeout(cfg.Timeout)}
+	if u.Path != "" {
+		opts = append(opts, otlptracehttp.WithURLPath(u.Path))
+	}
+	if insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	return otlptracehttp.NewClient(opts...), nil
+}
+
+// newWriterExporter creates exporter which writes every batch of spans as a separate line
+// of OTLP ExportTraceServiceRequest in protobuf JSON mapping.
+func newWriterExporter(w io.WriteCloser) (sdktrace.SpanExporter, error) {
+	return otlptrace.New(context.Background(), &writerClient{w: w})
+}
+
+// writerClient implements otlptrace.Client, spans are transformed to OTLP by otlptrace exporter.
+type writerClient struct {
+	mu sync.Mutex
+	w  io.WriteCloser
+}
+
+// Start implements otlptrace.Client.
+func (c *writerClient) Start(context.Context) error {
+	return nil
+}
+
+// Stop implements otlptrace.Client.
+func (c *writerClient) Stop(context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.w.Close()
+}
+
+// UploadTraces implements otlptrace.Client.
+func (c *writerClient) UploadTraces(_ context.Context, spans []*tracepb.ResourceSpans) error {
+	body, err := protojson.Marshal(&coltracepb.ExportTraceServiceRequest{ResourceSpans: spans})
+	if err != nil {
+		return fmt.Errorf("marshal spans: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, err = c.w.Write(append(body, '\n'))
+	return err
+}
@@ -0,0 +1,130 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Config contains parameters of tracing.
+type Config struct {
+	Enabled bool
+	// Exporter is one of ExporterOTLP, ExporterOTLPGRPC, ExporterStdout, ExporterFile.
+	Exporter string
+	// Endpoint is a URL of OTLP traces receiver, e.g. http://localhost:4318/v1/traces
+	// for OTLP/HTTP or http://localhost:4317 for OTLP/gRPC.
+	Endpoint string
+	// File is a path to the file for ExporterFile.
+	File string
+	// SamplingRatio is a fraction of new traces to be sampled. Traces started by
+	// the caller are sampled according to the incoming traceparent.
+	SamplingRatio float64
+	Timeout       time.Duration
+	Service       string
+	Version       string
+}
+
+const (
+	// ExporterOTLP exports spans to OTLP/HTTP receiver.
+	ExporterOTLP = "otlp"
+	// ExporterOTLPGRPC exports spans to OTLP/gRPC receiver.
+	ExporterOTLPGRPC = "otlp_grpc"
+	// ExporterStdout writes spans to the standard output.
+	ExporterStdout = "stdout"
+	// ExporterFile writes spans to the file.
+	ExporterFile = "file"
+
+	// DefaultTimeout is a default timeout to export a batch of spans.
+	DefaultTimeout = 10 * time.Second
+
+	instrumentationName = "github.com/TrueCloudLab/frostfs-s3-gw"
+)
+
+// Setup configures global tracer provider and propagator. Returned function
+// flushes pending spans and must be called on shutdown.
+func Setup(cfg Config) (func(context.Context) error, error) {
+	if !cfg.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+
+	exporter, err := newExporter(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter, sdktrace.WithExportTimeout(cfg.Timeout)),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SamplingRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", cfg.Service),
+			attribute.String("service.version", cfg.Version),
+		)),
+	)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return provider.Shutdown, nil
+}
+
+func newExporter(cfg Config) (sdktrace.SpanExporter, error) {
+	switch cfg.Exporter {
+	case ExporterOTLP, ExporterOTLPGRPC:
+		client, err := newOTLPClient(cfg.Exporter, cfg.Endpoint, cfg)
+		if err != nil {
+			return nil, err
+		}
+		return otlptrace.New(context.Background(), client)
+	case ExporterStdout:
+		return newWriterExporter(nopCloser{os.Stdout})
+	case ExporterFile:
+		f, err := os.OpenFile(cfg.File, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o640)
+		if err != nil {
+			return nil, fmt.Errorf("open tracing file: %w", err)
+		}
+		return newWriterExporter(f)
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter: '%s'", cfg.Exporter)
+	}
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error { return nil }
+
+// StartSpan starts a new span with the global tracer provider.
+func StartSpan(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, opts...)
+}
+
+// End sets error status if err isn't nil and ends the span.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Extract reads trace context propagated in HTTP headers.
+func Extract(ctx context.Context, header http.Header) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(header))
+}
@@ -0,0 +1,159 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+type bufferCloser struct {
+	bytes.Buffer
+}
+
+func (bufferCloser) Close() error { return nil }
+
+func setTestProvider(t *testing.T, exporter sdktrace.SpanExporter) *sdktrace.TracerProvider {
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(trace.NewNoopTracerProvider())
+	})
+	return provider
+}
+
+func TestWriterExporter(t *testing.T) {
+	buf := &bufferCloser{}
+	exporter, err := newWriterExporter(buf)
+	require.NoError(t, err)
+	provider := setTestProvider(t, exporter)
+
+	ctx, parent := StartSpan(context.Background(), "parent", trace.WithAttributes(attribute.String("request_id", "id")))
+	_, child := StartSpan(ctx, "child")
+	End(child, errors.New("some error"))
+	parent.End()
+	require.NoError(t, provider.Shutdown(context.Background()))
+
+	var spans []*tracepb.Span
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		var req coltracepb.ExportTraceServiceRequest
+		require.NoError(t, protojson.Unmarshal(line, &req))
+		require.Len(t, req.ResourceSpans, 1)
+		spans = append(spans, req.ResourceSpans[0].ScopeSpans[0].Spans...)
+	}
+
+	require.Len(t, spans, 2)
+	require.Equal(t, "child", spans[0].Name)
+	require.Equal(t, tracepb.Status_STATUS_CODE_ERROR, spans[0].Status.Code)
+	require.Equal(t, "some error", spans[0].Status.Message)
+	require.Equal(t, spans[1].SpanId, spans[0].ParentSpanId)
+	require.Equal(t, spans[1].TraceId, spans[0].TraceId)
+
+	require.Equal(t, "parent", spans[1].Name)
+	require.Empty(t, spans[1].ParentSpanId)
+	require.Equal(t, "request_id", spans[1].Attributes[0].Key)
+	require.Equal(t, "id", spans[1].Attributes[0].Value.GetStringValue())
+}
+
+func TestOTLPExporter(t *testing.T) {
+	var received coltracepb.ExportTraceServiceRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v1/traces", r.URL.Path)
+		require.Equal(t, "application/x-protobuf", r.Header.Get("Content-Type"))
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, proto.Unmarshal(body, &received))
+	}))
+	defer srv.Close()
+
+	exporter, err := newExporter(Config{Exporter: ExporterOTLP, Endpoint: srv.URL + "/v1/traces", Timeout: time.Second})
+	require.NoError(t, err)
+	provider := setTestProvider(t, exporter)
+
+	_, span := StartSpan(context.Background(), "span")
+	span.End()
+	require.NoError(t, provider.Shutdown(context.Background()))
+
+	require.Len(t, received.ResourceSpans, 1)
+	require.Equal(t, "span", received.ResourceSpans[0].ScopeSpans[0].Spans[0].Name)
+
+	for _, endpoint := range []string{"", "localhost:4318"} {
+		_, err = newExporter(Config{Exporter: ExporterOTLPGRPC, Endpoint: endpoint})
+		require.Error(t, err, endpoint)
+	}
+}
+
+type testClientStream struct {
+	grpc.ClientStream
+}
+
+func TestStreamClientInterceptor(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	setTestProvider(t, exporter)
+
+	interceptor := StreamClientInterceptor()
+	streamer := func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+		return testClientStream{}, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	_, err := interceptor(ctx, &grpc.StreamDesc{}, nil, "/tree.TreeService/GetSubTree", streamer)
+	require.NoError(t, err)
+	require.Empty(t, exporter.GetSpans())
+
+	// stream isn't read till the end, but the span is ended on cancellation
+	cancel()
+	require.Eventually(t, func() bool { return len(exporter.GetSpans()) == 1 }, time.Second, 10*time.Millisecond)
+	require.Equal(t, codes.Error, exporter.GetSpans()[0].Status.Code)
+}
+
+func TestPropagation(t *testing.T) {
+	exporter, err := newWriterExporter(&bufferCloser{})
+	require.NoError(t, err)
+	setTestProvider(t, exporter)
+
+	const (
+		traceID     = "4bf92f3577b34da6a3ce929d0e0e4736"
+		traceparent = "00-" + traceID + "-00f067aa0ba902b7-01"
+	)
+
+	hdr := http.Header{}
+	hdr.Set("traceparent", traceparent)
+	ctx, span := StartSpan(Extract(context.Background(), hdr), "server")
+	defer span.End()
+	require.Equal(t, traceID, span.SpanContext().TraceID().String())
+
+	ctx, rpcSpan := startRPCSpan(ctx, "/tree.TreeService/GetSubTree")
+	defer rpcSpan.End()
+
+	md, ok := metadata.FromOutgoingContext(ctx)
+	require.True(t, ok)
+	require.Contains(t, md.Get("traceparent")[0], traceID)
+	require.Contains(t, md.Get("traceparent")[0], rpcSpan.SpanContext().SpanID().String())
+}
+
+func TestSplitMethod(t *testing.T) {
+	service, method := splitMethod("/tree.TreeService/GetSubTree")
+	require.Equal(t, "tree.TreeService", service)
+	require.Equal(t, "GetSubTree", method)
+}
@@ -3,6 +3,7 @@ package metrics
 import (
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/TrueCloudLab/frostfs-s3-gw/api"
 	"github.com/TrueCloudLab/frostfs-s3-gw/internal/frostfs"
@@ -16,13 +17,13 @@ type AppMetrics struct {
 	enabled bool
 }
 
-func NewAppMetrics(logger *zap.Logger, poolStatistics *frostfs.PoolStatistic, enabled bool) *AppMetrics {
+func NewAppMetrics(logger *zap.Logger, poolStatistics *frostfs.PoolStatistic, requestsCfg RequestsConfig, enabled bool) *AppMetrics {
 	if !enabled {
 		logger.Warn("metrics are disabled")
 	}
 	return &AppMetrics{
 		logger:  logger,
-		gate:    NewGateMetrics(poolStatistics),
+		gate:    NewGateMetrics(poolStatistics, requestsCfg),
 		enabled: enabled,
 	}
 }
@@ -72,3 +73,50 @@ func (m *AppMetrics) Update(user, bucket, cnrID string, reqType api.RequestType,
 
 	m.gate.Billing.apiStat.Update(user, bucket, cnrID, reqType, in, out)
 }
+
+func (m *AppMetrics) ObserveRequest(api, bucket string, status int, duration, ttfb time.Duration) {
+	if !m.isEnabled() {
+		return
+	}
+
+	m.gate.Requests.observeRequest(api, bucket, status, duration, ttfb)
+}
+
+func (m *AppMetrics) ObserveOperation(operation string, duration time.Duration) {
+	if !m.isEnabled() {
+		return
+	}
+
+	m.gate.Requests.observeOperation(operation, duration)
+}
+
+func (m *AppMetrics) TreeRequestStarted(method string) {
+	m.gate.Tree.started(method)
+}
+
+func (m *AppMetrics) TreeRequestFinished(method, errClass string, duration time.Duration) {
+	m.gate.Tree.finished(method)
+	if !m.isEnabled() {
+		return
+	}
+
+	m.gate.Tree.observe(method, errClass, duration)
+}
+
+func (m *AppMetrics) TreeEndpointHealth(endpoint string, healthy bool) {
+	m.gate.Tree.setHealth(endpoint, healthy)
+}
+
+// RegisterUsage registers collector of bucket usage metrics.
+func (m *AppMetrics) RegisterUsage(scraper UsageScraper) {
+	m.mu.Lock()
+	m.gate.RegisterUsage(scraper)
+	m.mu.Unlock()
+}
+
+// RegisterCaches registers collector of cache metrics.
+func (m *AppMetrics) RegisterCaches(scraper CacheScraper) {
+	m.mu.Lock()
+	m.gate.RegisterCaches(scraper)
+	m.mu.Unlock()
+}
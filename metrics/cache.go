@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/cache"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const cacheSubsystem = "cache"
+
+type CacheScraper interface {
+	Stats(topKeys int) []cache.Stats
+}
+
+type cacheMetricsCollector struct {
+	cacheScraper CacheScraper
+	hits         *prometheus.Desc
+	misses       *prometheus.Desc
+	evictions    *prometheus.Desc
+	entries      *prometheus.Desc
+	bytes        *prometheus.Desc
+}
+
+func newCacheMetricsCollector(scraper CacheScraper) *cacheMetricsCollector {
+	newDesc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, cacheSubsystem, name), help, []string{"cache"}, nil)
+	}
+
+	return &cacheMetricsCollector{
+		cacheScraper: scraper,
+		hits:         newDesc("hits_total", "Number of cache hits"),
+		misses:       newDesc("misses_total", "Number of cache misses"),
+		evictions:    newDesc("evictions_total", "Number of entries evicted from cache by size limit, memory budget or expiration"),
+		entries:      newDesc("entries", "Number of entries in cache"),
+		bytes:        newDesc("bytes", "Estimated size of cache entries"),
+	}
+}
+
+func (m *cacheMetricsCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, stat := range m.cacheScraper.Stats(0) {
+		ch <- prometheus.MustNewConstMetric(m.hits, prometheus.CounterValue, float64(stat.Hits), stat.Name)
+		ch <- prometheus.MustNewConstMetric(m.misses, prometheus.CounterValue, float64(stat.Misses), stat.Name)
+		ch <- prometheus.MustNewConstMetric(m.evictions, prometheus.CounterValue, float64(stat.Evictions), stat.Name)
+		ch <- prometheus.MustNewConstMetric(m.entries, prometheus.GaugeValue, float64(stat.Entries), stat.Name)
+		ch <- prometheus.MustNewConstMetric(m.bytes, prometheus.GaugeValue, float64(stat.Bytes), stat.Name)
+	}
+}
+
+func (m *cacheMetricsCollector) Describe(descs chan<- *prometheus.Desc) {
+	descs <- m.hits
+	descs <- m.misses
+	descs <- m.evictions
+	descs <- m.entries
+	descs <- m.bytes
+}
+
+func (m *cacheMetricsCollector) register() {
+	prometheus.MustRegister(m)
+}
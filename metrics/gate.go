@@ -15,12 +15,16 @@ type StatisticScraper interface {
 }
 
 type GateMetrics struct {
-	State   stateMetrics
-	Pool    poolMetricsCollector
-	Billing *billingMetrics
+	State    stateMetrics
+	Pool     poolMetricsCollector
+	Billing  *billingMetrics
+	Requests *requestMetrics
+	Tree     *treeMetrics
+	Usage    *usageMetricsCollector
+	Caches   *cacheMetricsCollector
 }
 
-func NewGateMetrics(scraper StatisticScraper) *GateMetrics {
+func NewGateMetrics(scraper StatisticScraper, requestsCfg RequestsConfig) *GateMetrics {
 	stateMetric := newStateMetrics()
 	stateMetric.register()
 
@@ -30,17 +34,45 @@ func NewGateMetrics(scraper StatisticScraper) *GateMetrics {
 	billingMetric := newBillingMetrics()
 	billingMetric.register()
 
+	requestMetric := newRequestMetrics(requestsCfg)
+	requestMetric.register()
+
+	treeMetric := newTreeMetrics(requestsCfg.DurationBuckets)
+	treeMetric.register()
+
 	return &GateMetrics{
-		State:   *stateMetric,
-		Pool:    *poolMetric,
-		Billing: billingMetric,
+		State:    *stateMetric,
+		Pool:     *poolMetric,
+		Billing:  billingMetric,
+		Requests: requestMetric,
+		Tree:     treeMetric,
 	}
 }
 
+// RegisterUsage registers collector of bucket usage metrics.
+func (g *GateMetrics) RegisterUsage(scraper UsageScraper) {
+	g.Usage = newUsageMetricsCollector(scraper)
+	g.Usage.register()
+}
+
+// RegisterCaches registers collector of cache metrics.
+func (g *GateMetrics) RegisterCaches(scraper CacheScraper) {
+	g.Caches = newCacheMetricsCollector(scraper)
+	g.Caches.register()
+}
+
 func (g *GateMetrics) Unregister() {
 	g.State.unregister()
 	prometheus.Unregister(&g.Pool)
 	g.Billing.unregister()
+	g.Requests.unregister()
+	g.Tree.unregister()
+	if g.Usage != nil {
+		prometheus.Unregister(g.Usage)
+	}
+	if g.Caches != nil {
+		prometheus.Unregister(g.Caches)
+	}
 }
 
 func (g *GateMetrics) Handler() http.Handler {
@@ -0,0 +1,153 @@
+package metrics
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	requestSubsystem = "request"
+	layerSubsystem   = "layer"
+
+	// otherBucketsLabel is a bucket label of requests to buckets over the limit of distinct labels.
+	otherBucketsLabel = "other"
+)
+
+// DefaultDurationBuckets are default buckets of latency histograms (in seconds).
+var DefaultDurationBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30}
+
+type (
+	// RequestsConfig contains configuration of request and layer latency histograms.
+	RequestsConfig struct {
+		// DurationBuckets are buckets of latency histograms, DefaultDurationBuckets are used if empty.
+		DurationBuckets []float64
+		// BucketLabel enables bucket label of request histograms.
+		BucketLabel bool
+		// MaxBucketLabels limits number of distinct bucket labels, the rest of buckets
+		// are labelled as "other". Zero means no limit.
+		MaxBucketLabels int
+	}
+
+	requestMetrics struct {
+		bucketLabel bool
+		buckets     bucketLabels
+
+		duration          *prometheus.HistogramVec
+		ttfb              *prometheus.HistogramVec
+		operationDuration *prometheus.HistogramVec
+	}
+
+	bucketLabels struct {
+		mu    sync.Mutex
+		max   int
+		known map[string]struct{}
+	}
+)
+
+func newRequestMetrics(cfg RequestsConfig) *requestMetrics {
+	buckets := cfg.DurationBuckets
+	if len(buckets) == 0 {
+		buckets = DefaultDurationBuckets
+	}
+
+	labels := []string{"api", "status"}
+	if cfg.BucketLabel {
+		labels = append(labels, "bucket")
+	}
+
+	return &requestMetrics{
+		bucketLabel: cfg.BucketLabel,
+		buckets: bucketLabels{
+			max:   cfg.MaxBucketLabels,
+			known: make(map[string]struct{}),
+		},
+		duration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Subsystem: requestSubsystem,
+				Name:      "duration_seconds",
+				Help:      "Duration of S3 requests",
+				Buckets:   buckets,
+			},
+			labels,
+		),
+		ttfb: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Subsystem: requestSubsystem,
+				Name:      "ttfb_seconds",
+				Help:      "Time to first byte of S3 responses",
+				Buckets:   buckets,
+			},
+			labels,
+		),
+		operationDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Subsystem: layerSubsystem,
+				Name:      "operation_duration_seconds",
+				Help:      "Duration of storage operations made while serving S3 requests",
+				Buckets:   buckets,
+			},
+			[]string{"operation"},
+		),
+	}
+}
+
+func (m *requestMetrics) register() {
+	prometheus.MustRegister(m.duration)
+	prometheus.MustRegister(m.ttfb)
+	prometheus.MustRegister(m.operationDuration)
+}
+
+func (m *requestMetrics) unregister() {
+	prometheus.Unregister(m.duration)
+	prometheus.Unregister(m.ttfb)
+	prometheus.Unregister(m.operationDuration)
+}
+
+func (m *requestMetrics) observeRequest(api, bucket string, status int, duration, ttfb time.Duration) {
+	labels := []string{api, statusClass(status)}
+	if m.bucketLabel {
+		labels = append(labels, m.buckets.label(bucket))
+	}
+
+	m.duration.WithLabelValues(labels...).Observe(duration.Seconds())
+	m.ttfb.WithLabelValues(labels...).Observe(ttfb.Seconds())
+}
+
+func (m *requestMetrics) observeOperation(operation string, duration time.Duration) {
+	m.operationDuration.WithLabelValues(operation).Observe(duration.Seconds())
+}
+
+// label returns bucket label respecting the limit of distinct labels.
+// Empty bucket (request without existing bucket) doesn't take a slot.
+func (b *bucketLabels) label(bucket string) string {
+	if bucket == "" {
+		return ""
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.known[bucket]; ok {
+		return bucket
+	}
+	if b.max > 0 && len(b.known) >= b.max {
+		return otherBucketsLabel
+	}
+	b.known[bucket] = struct{}{}
+	return bucket
+}
+
+// statusClass returns class of HTTP status code, e.g. 2xx. Requests without
+// explicitly written status are answered with 200.
+func statusClass(status int) string {
+	if status == 0 {
+		return "2xx"
+	}
+	return strconv.Itoa(status/100) + "xx"
+}
@@ -0,0 +1,32 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestMetrics(t *testing.T) {
+	m := newRequestMetrics(RequestsConfig{BucketLabel: true, MaxBucketLabels: 2})
+
+	m.observeRequest("GetObject", "bucket1", 200, time.Second, time.Millisecond)
+	m.observeRequest("GetObject", "bucket2", 0, time.Second, time.Millisecond)
+	m.observeRequest("GetObject", "bucket3", 404, time.Second, time.Millisecond)
+	m.observeRequest("GetObject", "bucket4", 503, time.Second, time.Millisecond)
+	m.observeRequest("GetObject", "bucket1", 200, time.Second, time.Millisecond)
+
+	require.Equal(t, 4, testutil.CollectAndCount(m.duration))
+	require.Equal(t, 4, testutil.CollectAndCount(m.ttfb))
+	require.Equal(t, otherBucketsLabel, m.buckets.label("bucket5"))
+	require.Equal(t, "bucket2", m.buckets.label("bucket2"))
+
+	// requests without resolved bucket don't take slots
+	m = newRequestMetrics(RequestsConfig{BucketLabel: true, MaxBucketLabels: 1})
+	m.observeRequest("GetObject", "", 404, time.Second, time.Millisecond)
+	require.Equal(t, "bucket1", m.buckets.label("bucket1"))
+
+	m.observeOperation("tree_lookup", time.Millisecond)
+	require.Equal(t, 1, testutil.CollectAndCount(m.operationDuration))
+}
@@ -0,0 +1,114 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const treeSubsystem = "tree"
+
+type treeMetrics struct {
+	requests *prometheus.CounterVec
+	errors   *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	inFlight *prometheus.GaugeVec
+	health   *prometheus.GaugeVec
+}
+
+func newTreeMetrics(buckets []float64) *treeMetrics {
+	if len(buckets) == 0 {
+		buckets = DefaultDurationBuckets
+	}
+
+	return &treeMetrics{
+		requests: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: treeSubsystem,
+				Name:      "requests_total",
+				Help:      "Total number of requests to tree service",
+			},
+			[]string{"method"},
+		),
+		errors: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: treeSubsystem,
+				Name:      "errors_total",
+				Help:      "Total number of failed requests to tree service by error class",
+			},
+			[]string{"method", "class"},
+		),
+		duration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Subsystem: treeSubsystem,
+				Name:      "request_duration_seconds",
+				Help:      "Duration of requests to tree service",
+				Buckets:   buckets,
+			},
+			[]string{"method"},
+		),
+		inFlight: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Subsystem: treeSubsystem,
+				Name:      "requests_in_flight",
+				Help:      "Number of requests to tree service in progress",
+			},
+			[]string{"method"},
+		),
+		health: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Subsystem: treeSubsystem,
+				Name:      "endpoint_health",
+				Help:      "Health of tree service endpoints (1 - healthy, 0 - unhealthy)",
+			},
+			[]string{"endpoint"},
+		),
+	}
+}
+
+func (m *treeMetrics) register() {
+	prometheus.MustRegister(m.requests)
+	prometheus.MustRegister(m.errors)
+	prometheus.MustRegister(m.duration)
+	prometheus.MustRegister(m.inFlight)
+	prometheus.MustRegister(m.health)
+}
+
+func (m *treeMetrics) unregister() {
+	prometheus.Unregister(m.requests)
+	prometheus.Unregister(m.errors)
+	prometheus.Unregister(m.duration)
+	prometheus.Unregister(m.inFlight)
+	prometheus.Unregister(m.health)
+}
+
+func (m *treeMetrics) started(method string) {
+	m.inFlight.WithLabelValues(method).Inc()
+}
+
+// finished is called for every started request, so in-flight gauge is consistent
+// even if metrics are disabled in between.
+func (m *treeMetrics) finished(method string) {
+	m.inFlight.WithLabelValues(method).Dec()
+}
+
+func (m *treeMetrics) observe(method, errClass string, duration time.Duration) {
+	m.requests.WithLabelValues(method).Inc()
+	if errClass != "" {
+		m.errors.WithLabelValues(method, errClass).Inc()
+	}
+	m.duration.WithLabelValues(method).Observe(duration.Seconds())
+}
+
+func (m *treeMetrics) setHealth(endpoint string, healthy bool) {
+	var value float64
+	if healthy {
+		value = 1
+	}
+	m.health.WithLabelValues(endpoint).Set(value)
+}
@@ -0,0 +1,85 @@
+package metrics
+
+import (
+	"github.com/TrueCloudLab/frostfs-s3-gw/api/data"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const usageSubsystem = "usage"
+
+type UsageScraper interface {
+	BucketsUsage() []*data.BucketUsageInfo
+}
+
+type usageMetricsCollector struct {
+	usageScraper   UsageScraper
+	bytes          *prometheus.GaugeVec
+	objects        *prometheus.GaugeVec
+	versions       *prometheus.GaugeVec
+	deleteMarkers  *prometheus.GaugeVec
+	multipartBytes *prometheus.GaugeVec
+}
+
+func newUsageMetricsCollector(scraper UsageScraper) *usageMetricsCollector {
+	newGaugeVec := func(name, help string) *prometheus.GaugeVec {
+		return prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Subsystem: usageSubsystem,
+				Name:      name,
+				Help:      help,
+			},
+			[]string{
+				"bucket",
+				"cid",
+			},
+		)
+	}
+
+	return &usageMetricsCollector{
+		usageScraper:   scraper,
+		bytes:          newGaugeVec("bytes", "Logical size of all object versions in bucket"),
+		objects:        newGaugeVec("objects", "Number of objects in bucket which latest version isn't a delete marker"),
+		versions:       newGaugeVec("versions", "Number of object versions in bucket excluding delete markers"),
+		deleteMarkers:  newGaugeVec("delete_markers", "Number of delete markers in bucket"),
+		multipartBytes: newGaugeVec("multipart_bytes", "Size of uploaded parts of in-progress multipart uploads in bucket"),
+	}
+}
+
+func (m *usageMetricsCollector) Collect(ch chan<- prometheus.Metric) {
+	m.updateUsage()
+	m.bytes.Collect(ch)
+	m.objects.Collect(ch)
+	m.versions.Collect(ch)
+	m.deleteMarkers.Collect(ch)
+	m.multipartBytes.Collect(ch)
+}
+
+func (m *usageMetricsCollector) Describe(descs chan<- *prometheus.Desc) {
+	m.bytes.Describe(descs)
+	m.objects.Describe(descs)
+	m.versions.Describe(descs)
+	m.deleteMarkers.Describe(descs)
+	m.multipartBytes.Describe(descs)
+}
+
+func (m *usageMetricsCollector) register() {
+	prometheus.MustRegister(m)
+}
+
+func (m *usageMetricsCollector) updateUsage() {
+	m.bytes.Reset()
+	m.objects.Reset()
+	m.versions.Reset()
+	m.deleteMarkers.Reset()
+	m.multipartBytes.Reset()
+
+	for _, info := range m.usageScraper.BucketsUsage() {
+		bucket, cnrID := info.Bucket.Name, info.Bucket.CID.EncodeToString()
+		m.bytes.WithLabelValues(bucket, cnrID).Set(float64(info.Usage.Bytes))
+		m.objects.WithLabelValues(bucket, cnrID).Set(float64(info.Usage.Objects))
+		m.versions.WithLabelValues(bucket, cnrID).Set(float64(info.Usage.Versions))
+		m.deleteMarkers.WithLabelValues(bucket, cnrID).Set(float64(info.Usage.DeleteMarkers))
+		m.multipartBytes.WithLabelValues(bucket, cnrID).Set(float64(info.Usage.MultipartBytes))
+	}
+}